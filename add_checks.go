@@ -0,0 +1,186 @@
+package qbittorrent
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// WithDuplicateCheck refuses the add with ErrAlreadyExists when the torrent is already on the server, which
+// qBittorrent otherwise ignores silently. The infohash is computed from the .torrent file or taken from the
+// magnet link; URLs of .torrent files cannot be checked and are added as usual.
+func WithDuplicateCheck(check bool) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.CheckDuplicates = &check
+	}
+}
+
+// WithContentPathCheck refuses the add with ErrContentPathCollision when the files of the torrent would land
+// in the content path of a torrent already on the server, where qBittorrent would silently mix the files of
+// both. The torrent's content path is derived from the save path given with WithSavePath, else the
+// category's or the default save path, and from the content layout. Magnet links and URLs cannot be checked.
+func WithContentPathCheck(check bool) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.CheckContentPath = &check
+	}
+}
+
+// checkContentPath reports whether WithContentPathCheck is enabled
+func (o *TorrentsAddOptions) checkContentPath() bool {
+	return o.CheckContentPath != nil && *o.CheckContentPath
+}
+
+// checkContentPath returns ErrContentPathCollision if a torrent other than meta's uses a content path meta
+// would be saved to, or one inside it or containing it
+func (c *Client) checkContentPath(meta *Metainfo, o *TorrentsAddOptions) error {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return err
+	}
+	savePath, _ := prefs["save_path"].(string)
+	if o.SavePath != nil {
+		savePath = *o.SavePath
+	} else if o.Category != nil && *o.Category != "" {
+		categories, err := c.TorrentsCategories()
+		if err != nil {
+			return err
+		}
+		if dir, _ := categories[*o.Category]["savePath"].(string); dir != "" {
+			if !path.IsAbs(dir) && !filepath.IsAbs(dir) {
+				dir = path.Join(savePath, dir)
+			}
+			savePath = dir
+		}
+	}
+	layout := ContentLayoutOriginal
+	switch {
+	case o.ContentLayout != nil:
+		layout = *o.ContentLayout
+	case prefs["torrent_content_layout"] != nil:
+		value, _ := prefs["torrent_content_layout"].(string)
+		layout = ContentLayout(value)
+	case prefs["create_subfolder_enabled"] == false:
+		layout = ContentLayoutNoSubfolder
+	}
+
+	paths := contentPaths(meta, savePath, layout)
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return err
+	}
+	for _, torrent := range torrents {
+		if torrent.Hash == meta.InfoHash || torrent.ContentPath == "" {
+			continue
+		}
+		existing := cleanContentPath(torrent.ContentPath)
+		for _, p := range paths {
+			if p == existing || strings.HasPrefix(existing, p+"/") || strings.HasPrefix(p, existing+"/") {
+				return fmt.Errorf("%w: %s is used by %s (%s)", ErrContentPathCollision, torrent.ContentPath, torrent.Hash, torrent.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// contentPaths returns the files or folders meta creates directly in savePath with layout, the same way
+// SetContentLayout renames files
+func contentPaths(meta *Metainfo, savePath string, layout ContentLayout) []string {
+	files := make([]TorrentFile, len(meta.Files))
+	for i, f := range meta.Files {
+		files[i] = TorrentFile{Name: f.Path}
+	}
+	root := rootFolder(files)
+
+	var paths []string
+	for _, file := range files {
+		name := file.Name
+		switch {
+		case layout == ContentLayoutNoSubfolder && root != "":
+			name = strings.TrimPrefix(name, root+"/")
+		case layout == ContentLayoutSubfolder && root == "":
+			name = strings.TrimSuffix(meta.Name, path.Ext(meta.Name)) + "/" + name
+		}
+		top, _, _ := strings.Cut(name, "/")
+		p := cleanContentPath(savePath + "/" + top)
+		if !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// cleanContentPath normalizes a path for comparisons, using slashes as separators
+func cleanContentPath(p string) string {
+	return path.Clean(strings.ReplaceAll(p, "\\", "/"))
+}
+
+// checkAddResponse returns ErrAddFailed if the body of a torrents/add response reports a failure: "Fails."
+// on older servers, a JSON summary with failures on newer ones
+func checkAddResponse(body []byte) error {
+	text := strings.TrimSpace(string(body))
+	if text == "Fails." {
+		return ErrAddFailed
+	}
+	if strings.HasPrefix(text, "{") {
+		var summary struct {
+			FailureCount int `json:"failure_count"`
+		}
+		if json.Unmarshal(body, &summary) == nil && summary.FailureCount > 0 {
+			return fmt.Errorf("%w: %d of the torrents failed", ErrAddFailed, summary.FailureCount)
+		}
+	}
+	return nil
+}
+
+// checkNotExists returns ErrAlreadyExists if any of hashes is on the server
+func (c *Client) checkNotExists(hashes []InfoHash) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	query := make([]string, len(hashes))
+	for i, hash := range hashes {
+		query[i] = string(hash)
+	}
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: query})
+	if err != nil {
+		return err
+	}
+	if len(torrents) > 0 {
+		return fmt.Errorf("%w: %s (%s)", ErrAlreadyExists, torrents[0].Hash, torrents[0].Name)
+	}
+	return nil
+}
+
+// magnetInfoHash returns the v1 infohash of a magnet link, hex or base32 encoded in it, in lower case hex
+func magnetInfoHash(link string) (InfoHash, bool) {
+	if !strings.HasPrefix(strings.ToLower(link), "magnet:?") {
+		return "", false
+	}
+	query, err := url.ParseQuery(link[len("magnet:?"):])
+	if err != nil {
+		return "", false
+	}
+	for _, xt := range query["xt"] {
+		hash, ok := strings.CutPrefix(strings.ToLower(xt), "urn:btih:")
+		if !ok {
+			continue
+		}
+		switch len(hash) {
+		case 40:
+			if _, err := hex.DecodeString(hash); err == nil {
+				return InfoHash(hash), true
+			}
+		case 32:
+			if raw, err := base32.StdEncoding.DecodeString(strings.ToUpper(hash)); err == nil {
+				return InfoHash(hex.EncodeToString(raw)), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,156 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTorrentsAdd_Fails(t *testing.T) {
+	for _, body := range []string{"Fails.", `{"success_count":0,"failure_count":1,"pending_count":0}`} {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+		if err := client.TorrentsAdd("a.torrent", []byte("invalid")); !errors.Is(err, ErrAddFailed) {
+			t.Errorf("%s: expected ErrAddFailed, got %v", body, err)
+		}
+		if err := client.TorrentsAddURLs([]string{"https://example.org/a.torrent"}); !errors.Is(err, ErrAddFailed) {
+			t.Errorf("%s: expected ErrAddFailed, got %v", body, err)
+		}
+		mockServer.Close()
+	}
+}
+
+func TestTorrentsAdd_DuplicateCheck(t *testing.T) {
+	torrent := makeTorrent("data", map[string]int64{"file.bin": 1000})
+	meta, err := ParseMetainfo(torrent)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var added int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			if strings.Contains(r.URL.Query().Get("hashes"), string(meta.InfoHash)) {
+				w.Write([]byte(`[{"hash":"` + string(meta.InfoHash) + `","name":"data"}]`))
+				return
+			}
+			w.Write([]byte(`[]`))
+		case "/api/v2/torrents/add":
+			added++
+			w.Write([]byte("Ok."))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	err = client.TorrentsAddWithOptions("data.torrent", torrent, WithDuplicateCheck(true))
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	err = client.TorrentsAddURLs([]string{"magnet:?xt=urn:btih:" + strings.ToUpper(string(meta.InfoHash))}, WithDuplicateCheck(true))
+	if !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists for the magnet link, got %v", err)
+	}
+	if added != 0 {
+		t.Errorf("expected no add requests, got %d", added)
+	}
+
+	other := "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567"
+	if err := client.TorrentsAddURLs([]string{other}, WithDuplicateCheck(true)); err != nil || added != 1 {
+		t.Errorf("expected the new torrent to be added, got %v", err)
+	}
+}
+
+func TestTorrentsAdd_ContentPathCheck(t *testing.T) {
+	torrent := makeTorrent("data", map[string]int64{"a.bin": 1000, "b.bin": 10})
+
+	var added int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"save_path":"/downloads","torrent_content_layout":"Original"}`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"tv"}}`))
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"other","name":"data","content_path":"/downloads/data"},` +
+				`{"hash":"single","name":"a.bin","content_path":"/downloads/tv/a.bin"}]`))
+		case "/api/v2/torrents/add":
+			added++
+			w.Write([]byte("Ok."))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	err := client.TorrentsAddWithOptions("data.torrent", torrent, WithContentPathCheck(true))
+	if !errors.Is(err, ErrContentPathCollision) {
+		t.Errorf("expected ErrContentPathCollision, got %v", err)
+	}
+	err = client.TorrentsAddWithOptions("data.torrent", torrent, WithContentPathCheck(true), WithCategory("tv"),
+		WithContentLayout(ContentLayoutNoSubfolder))
+	if !errors.Is(err, ErrContentPathCollision) {
+		t.Errorf("expected ErrContentPathCollision without subfolder, got %v", err)
+	}
+	if added != 0 {
+		t.Errorf("expected no add, got %d", added)
+	}
+
+	for _, opts := range [][]TorrentAddOption{
+		{WithSavePath("/downloads/other")},
+		{WithCategory("tv")},
+		{WithSavePath("/downloads/"), WithContentLayout(ContentLayoutNoSubfolder)},
+	} {
+		if err := client.TorrentsAddWithOptions("data.torrent", torrent, append(opts, WithContentPathCheck(true))...); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	}
+	if added != 3 {
+		t.Errorf("expected 3 adds, got %d", added)
+	}
+}
+
+func TestContentPaths(t *testing.T) {
+	multi := &Metainfo{Name: "data", Files: []MetainfoFile{{Path: "data/a.bin"}, {Path: "data/sub/b.bin"}}}
+	single := &Metainfo{Name: "film.mkv", Files: []MetainfoFile{{Path: "film.mkv"}}}
+
+	tests := []struct {
+		meta   *Metainfo
+		layout ContentLayout
+		want   []string
+	}{
+		{multi, ContentLayoutOriginal, []string{"/d/data"}},
+		{multi, ContentLayoutSubfolder, []string{"/d/data"}},
+		{multi, ContentLayoutNoSubfolder, []string{"/d/a.bin", "/d/sub"}},
+		{single, ContentLayoutOriginal, []string{"/d/film.mkv"}},
+		{single, ContentLayoutSubfolder, []string{"/d/film"}},
+	}
+	for _, tt := range tests {
+		if got := contentPaths(tt.meta, "/d/", tt.layout); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("contentPaths(%s, %s) = %v, want %v", tt.meta.Name, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestMagnetInfoHash(t *testing.T) {
+	tests := map[string]InfoHash{
+		"magnet:?xt=urn:btih:0123456789ABCDEF0123456789ABCDEF01234567&dn=a": "0123456789abcdef0123456789abcdef01234567",
+		"magnet:?dn=a&xt=urn:btih:AERUKZ4JVPG66AJDIVTYTK6N54ASGRLH":         "0123456789abcdef0123456789abcdef01234567",
+	}
+	for link, want := range tests {
+		if got, ok := magnetInfoHash(link); !ok || got != want {
+			t.Errorf("magnetInfoHash(%q) = %q, %v, want %q", link, got, ok, want)
+		}
+	}
+	for _, link := range []string{"https://example.org/a.torrent", "magnet:?xt=urn:btmh:1220abcd"} {
+		if _, ok := magnetInfoHash(link); ok {
+			t.Errorf("expected no infohash for %q", link)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// AllTorrents selects every torrent in the hashes parameter of the endpoints that accept it. Prefer the
+// All methods, such as PauseAll, to passing it around: they make the intent explicit.
+const AllTorrents InfoHash = "all"
+
+// TorrentsRecheck rechecks the data of the specified torrents. Multiple hashes are separated by "|".
+func (c *Client) TorrentsRecheck(hashes string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+
+	_, err := c.doPostValues("/api/v2/torrents/recheck", data)
+	if err != nil {
+		return fmt.Errorf("Recheck error: %w", err)
+	}
+	return nil
+}
+
+// PauseAll pauses every torrent, stopping them on qBittorrent 5.x
+func (c *Client) PauseAll() error {
+	return c.TorrentsPause(string(AllTorrents))
+}
+
+// ResumeAll resumes every torrent, starting them on qBittorrent 5.x
+func (c *Client) ResumeAll() error {
+	return c.TorrentsResume(string(AllTorrents))
+}
+
+// RecheckAll rechecks the data of every torrent. This reads all downloaded data from disk.
+func (c *Client) RecheckAll() error {
+	return c.TorrentsRecheck(string(AllTorrents))
+}
+
+// ReannounceAll re-announces every torrent to its trackers
+func (c *Client) ReannounceAll() error {
+	return c.TorrentsReannounce(string(AllTorrents))
+}
@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestAllTorrents(t *testing.T) {
+	all := url.Values{"hashes": {"all"}}
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":          {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/app/webapiVersion":   {statusCode: http.StatusOK, responseBody: "2.11.2"},
+		"/api/v2/torrents/stop":       {statusCode: http.StatusOK},
+		"/api/v2/torrents/start":      {statusCode: http.StatusOK},
+		"/api/v2/torrents/recheck":    {statusCode: http.StatusOK},
+		"/api/v2/torrents/reannounce": {statusCode: http.StatusOK},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "GET", url: "/api/v2/app/webapiVersion"},
+		{method: "POST", url: "/api/v2/torrents/stop", params: all},
+		{method: "POST", url: "/api/v2/torrents/start", params: all},
+		{method: "POST", url: "/api/v2/torrents/recheck", params: all},
+		{method: "POST", url: "/api/v2/torrents/reannounce", params: all},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, fn := range []func() error{client.PauseAll, client.ResumeAll, client.RecheckAll, client.ReannounceAll} {
+		if err := fn(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
@@ -0,0 +1,117 @@
+package qbittorrent
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate go run ./internal/apigen -spec apispec/webapi.json -out api_generated.go
+
+// The interfaces below group the Web API bindings of Client by API area, so code depending on a subset of
+// the API can accept a narrow interface and be tested with a fake. Higher level helpers built on the
+// bindings, such as ExportAll or CrossSeed, are not part of them. LogAPI, RSSAPI and SearchAPI are generated
+// with their bindings from apispec/webapi.json.
+
+// AuthAPI is the authentication part of the Web API
+type AuthAPI interface {
+	AuthLogin() error
+}
+
+// AppAPI is the application part of the Web API
+type AppAPI interface {
+	AppPreferences() (Preferences, error)
+	AppSetPreferences(prefs Preferences) error
+	AppVersion() (string, error)
+	AppWebAPIVersion() (string, error)
+	AppNetworkInterfaces() ([]NetworkInterface, error)
+	AppNetworkInterfaceAddresses(iface string) ([]string, error)
+	AppCookies() ([]Cookie, error)
+	AppSetCookies(cookies []Cookie) error
+	AppAddCookies(cookies ...Cookie) error
+}
+
+// TorrentsAPI is the torrent management part of the Web API
+type TorrentsAPI interface {
+	TorrentsInfo(params ...*TorrentsInfoParams) ([]TorrentInfo, error)
+	TorrentsInfoEach(fn func(TorrentInfo) error, params ...*TorrentsInfoParams) error
+	TorrentsTrackers(hash string) ([]TrackerInfo, error)
+	TorrentsEditTracker(hash, origURL, newURL string) error
+	TorrentsFiles(hash string) ([]TorrentFile, error)
+	TorrentsProperties(hash string) (TorrentProperties, error)
+	TorrentsPieceStates(hash string) ([]int, error)
+	TorrentsPieceHashes(hash string) ([]string, error)
+	TorrentsSetFilePriority(hash string, indexes []int, priority int) error
+	TorrentsRenameFile(hash, oldPath, newPath string) error
+	TorrentsRenameFolder(hash, oldPath, newPath string) error
+	TorrentsExport(hash string) ([]byte, error)
+	TorrentsExportTo(ctx context.Context, hash string, w io.Writer) (int64, error)
+	TorrentsExportMany(ctx context.Context, hashes []InfoHash) (map[InfoHash][]byte, error)
+	// Deprecated: use TorrentsExport
+	TorrentsDownload(infohash string) ([]byte, error)
+
+	TorrentsAdd(torrentFile string, fileData []byte) error
+	TorrentsAddWithOptions(torrentFile string, fileData []byte, opts ...TorrentAddOption) error
+	TorrentsAddURLs(urls []string, opts ...TorrentAddOption) error
+	TorrentsDelete(infohash string) error
+	TorrentsRemove(hashes string, deleteFiles bool) error
+
+	TorrentsPause(hashes string) error
+	TorrentsResume(hashes string) error
+	TorrentsStop(hashes string) error
+	TorrentsStart(hashes string) error
+	TorrentsReannounce(hashes string) error
+	TorrentsRecheck(hashes string) error
+	TorrentsSetLocation(hashes, location string) error
+	TorrentsSetShareLimits(hashes string, ratioLimit float64, seedingTimeLimit int64) error
+	TorrentsSetShareLimitsInactive(hashes string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64) error
+	SetForceStart(hash string, value bool) error
+	TorrentsDownloadLimit(hashes string) (map[InfoHash]int64, error)
+	TorrentsUploadLimit(hashes string) (map[InfoHash]int64, error)
+	TorrentsSetDownloadLimit(hashes string, limit int64) error
+	TorrentsSetUploadLimit(hashes string, limit int64) error
+
+	TorrentsCategories() (map[string]Category, error)
+	TorrentsCreateCategory(category, savePath string) error
+	TorrentsEditCategory(category, savePath string) error
+	TorrentsRemoveCategories(categories []string) error
+	TorrentsSetCategory(hashes, category string) error
+	TorrentsSetAutoManagement(hashes string, enable bool) error
+	TorrentsToggleSequentialDownload(hashes string) error
+	TorrentsToggleFirstLastPiecePrio(hashes string) error
+
+	TorrentsGetAllTags() ([]string, error)
+	TorrentsGetTags(hashes string) (map[InfoHash][]string, error)
+	TorrentsCreateTags(tags []string) error
+	TorrentsDeleteTags(tags []string) error
+	TorrentsAddTags(hashes string, tags []string) error
+	TorrentsRemoveTags(hashes string, tags []string) error
+	TorrentsSetTags(hashes string, tags []string) error
+}
+
+// SyncAPI is the incremental synchronization part of the Web API
+type SyncAPI interface {
+	SyncMainData(rid int) (*MainData, error)
+	SyncTorrentPeers(hash string, rid int) (*TorrentPeers, error)
+}
+
+// TransferAPI is the global transfer part of the Web API
+type TransferAPI interface {
+	TransferSpeedLimitsMode() (bool, error)
+	TransferToggleSpeedLimitsMode() error
+	TransferInfo() (*TransferInfo, error)
+	TransferBanPeers(peers []string) error
+}
+
+// API is the complete Web API as implemented by Client
+type API interface {
+	AuthAPI
+	AppAPI
+	TorrentsAPI
+	SyncAPI
+	TransferAPI
+	LogAPI
+	RSSAPI
+	SearchAPI
+}
+
+var _ API = (*Client)(nil)
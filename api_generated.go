@@ -0,0 +1,426 @@
+// Code generated by apigen from apispec/webapi.json. DO NOT EDIT.
+
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// LogAPI is the log part of the Web API
+type LogAPI interface {
+	LogMain(normal bool, info bool, warning bool, critical bool, lastKnownID int64) ([]LogEntry, error)
+	LogPeers(lastKnownID int64) ([]PeerLogEntry, error)
+}
+
+// RSSAPI is the RSS part of the Web API
+type RSSAPI interface {
+	RSSAddFolder(path string) error
+	RSSAddFeed(feedURL string, path string) error
+	RSSRemoveItem(path string) error
+	RSSMoveItem(itemPath string, destPath string) error
+	RSSItems(withData bool) (map[string]json.RawMessage, error)
+	RSSRefreshItem(itemPath string) error
+	RSSMarkAsRead(itemPath string, articleID string) error
+	RSSSetRule(ruleName string, ruleDef string) error
+	RSSRenameRule(ruleName string, newRuleName string) error
+	RSSRemoveRule(ruleName string) error
+	RSSRules() (map[string]json.RawMessage, error)
+	RSSMatchingArticles(ruleName string) (map[string][]string, error)
+}
+
+// SearchAPI is the search part of the Web API
+type SearchAPI interface {
+	SearchStart(pattern string, plugins []string, category string) (*SearchJob, error)
+	SearchStop(id int) error
+	SearchStatus(id int) ([]SearchStatus, error)
+	SearchResults(id int, limit int, offset int) (*SearchResults, error)
+	SearchDelete(id int) error
+	SearchPlugins() ([]SearchPlugin, error)
+	SearchEnablePlugin(names []string, enable bool) error
+	SearchUpdatePlugins() error
+}
+
+// LogEntry is a message of the main log as returned by LogMain
+type LogEntry struct {
+	ID        int64  `json:"id"`
+	Message   string `json:"message"`
+	Timestamp int64  `json:"timestamp"` // unix milliseconds
+	Type      int    `json:"type"`      // 1 normal, 2 info, 4 warning, 8 critical
+}
+
+// PeerLogEntry is a message of the peer log as returned by LogPeers
+type PeerLogEntry struct {
+	ID        int64  `json:"id"`
+	IP        string `json:"ip"`
+	Timestamp int64  `json:"timestamp"` // unix milliseconds
+	Blocked   bool   `json:"blocked"`
+	Reason    string `json:"reason"`
+}
+
+// SearchJob identifies a search started with SearchStart
+type SearchJob struct {
+	ID int `json:"id"`
+}
+
+// SearchStatus is the state of a search job as returned by SearchStatus
+type SearchStatus struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"` // "Running" or "Stopped"
+	Total  int    `json:"total"`  // number of results so far
+}
+
+// SearchResult is a torrent found by a search
+type SearchResult struct {
+	DescrLink   string `json:"descrLink"`
+	FileName    string `json:"fileName"`
+	FileSize    int64  `json:"fileSize"` // bytes, -1 if unknown
+	FileURL     string `json:"fileUrl"`  // torrent or magnet link
+	NumLeechers int64  `json:"nbLeechers"`
+	NumSeeders  int64  `json:"nbSeeders"`
+	SiteURL     string `json:"siteUrl"`
+}
+
+// SearchResults is a page of the results of a search job as returned by SearchResults
+type SearchResults struct {
+	Results []SearchResult `json:"results"`
+	Status  string         `json:"status"` // "Running" or "Stopped"
+	Total   int            `json:"total"`
+}
+
+// SearchPlugin is an installed search plugin as returned by SearchPlugins
+type SearchPlugin struct {
+	Enabled             bool            `json:"enabled"`
+	FullName            string          `json:"fullName"`
+	Name                string          `json:"name"`
+	SupportedCategories json.RawMessage `json:"supportedCategories"` // names, or id and name objects on newer versions
+	URL                 string          `json:"url"`
+	Version             string          `json:"version"`
+}
+
+// LogMain retrieves the main log messages of the selected severities with an ID above lastKnownID, -1 for all
+func (c *Client) LogMain(normal bool, info bool, warning bool, critical bool, lastKnownID int64) ([]LogEntry, error) {
+	params := url.Values{}
+	params.Set("normal", strconv.FormatBool(normal))
+	params.Set("info", strconv.FormatBool(info))
+	params.Set("warning", strconv.FormatBool(warning))
+	params.Set("critical", strconv.FormatBool(critical))
+	params.Set("last_known_id", strconv.FormatInt(lastKnownID, 10))
+
+	respData, err := c.doGet("/api/v2/log/main", params)
+	if err != nil {
+		return nil, fmt.Errorf("LogMain error: %w", err)
+	}
+
+	var result []LogEntry
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode LogMain response: %v", err)
+	}
+	return result, nil
+}
+
+// LogPeers retrieves the peer log messages with an ID above lastKnownID, -1 for all
+func (c *Client) LogPeers(lastKnownID int64) ([]PeerLogEntry, error) {
+	params := url.Values{}
+	params.Set("last_known_id", strconv.FormatInt(lastKnownID, 10))
+
+	respData, err := c.doGet("/api/v2/log/peers", params)
+	if err != nil {
+		return nil, fmt.Errorf("LogPeers error: %w", err)
+	}
+
+	var result []PeerLogEntry
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode LogPeers response: %v", err)
+	}
+	return result, nil
+}
+
+// RSSAddFolder creates an RSS folder, path separating nested folders with "\"
+func (c *Client) RSSAddFolder(path string) error {
+	data := url.Values{}
+	data.Set("path", path)
+
+	_, err := c.doPostValues("/api/v2/rss/addFolder", data)
+	if err != nil {
+		return fmt.Errorf("RSSAddFolder error: %w", err)
+	}
+	return nil
+}
+
+// RSSAddFeed subscribes to the feed at feedURL, stored at path, by default at the top level under its title
+func (c *Client) RSSAddFeed(feedURL string, path string) error {
+	data := url.Values{}
+	data.Set("url", feedURL)
+	if path != "" {
+		data.Set("path", path)
+	}
+
+	_, err := c.doPostValues("/api/v2/rss/addFeed", data)
+	if err != nil {
+		return fmt.Errorf("RSSAddFeed error: %w", err)
+	}
+	return nil
+}
+
+// RSSRemoveItem removes an RSS feed or folder
+func (c *Client) RSSRemoveItem(path string) error {
+	data := url.Values{}
+	data.Set("path", path)
+
+	_, err := c.doPostValues("/api/v2/rss/removeItem", data)
+	if err != nil {
+		return fmt.Errorf("RSSRemoveItem error: %w", err)
+	}
+	return nil
+}
+
+// RSSMoveItem moves or renames an RSS feed or folder
+func (c *Client) RSSMoveItem(itemPath string, destPath string) error {
+	data := url.Values{}
+	data.Set("itemPath", itemPath)
+	data.Set("destPath", destPath)
+
+	_, err := c.doPostValues("/api/v2/rss/moveItem", data)
+	if err != nil {
+		return fmt.Errorf("RSSMoveItem error: %w", err)
+	}
+	return nil
+}
+
+// RSSItems retrieves the tree of RSS folders and feeds, including their articles if withData is set
+func (c *Client) RSSItems(withData bool) (map[string]json.RawMessage, error) {
+	params := url.Values{}
+	params.Set("withData", strconv.FormatBool(withData))
+
+	respData, err := c.doGet("/api/v2/rss/items", params)
+	if err != nil {
+		return nil, fmt.Errorf("RSSItems error: %w", err)
+	}
+
+	var result map[string]json.RawMessage
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode RSSItems response: %v", err)
+	}
+	return result, nil
+}
+
+// RSSRefreshItem refreshes an RSS feed or the feeds of a folder
+func (c *Client) RSSRefreshItem(itemPath string) error {
+	data := url.Values{}
+	data.Set("itemPath", itemPath)
+
+	_, err := c.doPostValues("/api/v2/rss/refreshItem", data)
+	if err != nil {
+		return fmt.Errorf("RSSRefreshItem error: %w", err)
+	}
+	return nil
+}
+
+// RSSMarkAsRead marks an article, or every article of the item if articleID is empty, as read
+func (c *Client) RSSMarkAsRead(itemPath string, articleID string) error {
+	data := url.Values{}
+	data.Set("itemPath", itemPath)
+	if articleID != "" {
+		data.Set("articleId", articleID)
+	}
+
+	_, err := c.doPostValues("/api/v2/rss/markAsRead", data)
+	if err != nil {
+		return fmt.Errorf("RSSMarkAsRead error: %w", err)
+	}
+	return nil
+}
+
+// RSSSetRule creates or replaces an auto-downloading rule, ruleDef being its JSON definition
+func (c *Client) RSSSetRule(ruleName string, ruleDef string) error {
+	data := url.Values{}
+	data.Set("ruleName", ruleName)
+	data.Set("ruleDef", ruleDef)
+
+	_, err := c.doPostValues("/api/v2/rss/setRule", data)
+	if err != nil {
+		return fmt.Errorf("RSSSetRule error: %w", err)
+	}
+	return nil
+}
+
+// RSSRenameRule renames an auto-downloading rule
+func (c *Client) RSSRenameRule(ruleName string, newRuleName string) error {
+	data := url.Values{}
+	data.Set("ruleName", ruleName)
+	data.Set("newRuleName", newRuleName)
+
+	_, err := c.doPostValues("/api/v2/rss/renameRule", data)
+	if err != nil {
+		return fmt.Errorf("RSSRenameRule error: %w", err)
+	}
+	return nil
+}
+
+// RSSRemoveRule removes an auto-downloading rule
+func (c *Client) RSSRemoveRule(ruleName string) error {
+	data := url.Values{}
+	data.Set("ruleName", ruleName)
+
+	_, err := c.doPostValues("/api/v2/rss/removeRule", data)
+	if err != nil {
+		return fmt.Errorf("RSSRemoveRule error: %w", err)
+	}
+	return nil
+}
+
+// RSSRules retrieves the auto-downloading rules, keyed by name
+func (c *Client) RSSRules() (map[string]json.RawMessage, error) {
+	respData, err := c.doGet("/api/v2/rss/rules", nil)
+	if err != nil {
+		return nil, fmt.Errorf("RSSRules error: %w", err)
+	}
+
+	var result map[string]json.RawMessage
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode RSSRules response: %v", err)
+	}
+	return result, nil
+}
+
+// RSSMatchingArticles retrieves the titles of the articles matching an auto-downloading rule, keyed by feed
+func (c *Client) RSSMatchingArticles(ruleName string) (map[string][]string, error) {
+	params := url.Values{}
+	params.Set("ruleName", ruleName)
+
+	respData, err := c.doGet("/api/v2/rss/matchingArticles", params)
+	if err != nil {
+		return nil, fmt.Errorf("RSSMatchingArticles error: %w", err)
+	}
+
+	var result map[string][]string
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode RSSMatchingArticles response: %v", err)
+	}
+	return result, nil
+}
+
+// SearchStart starts searching for pattern with plugins, "all" or "enabled" selecting several, in category, e.g. "all"
+func (c *Client) SearchStart(pattern string, plugins []string, category string) (*SearchJob, error) {
+	data := url.Values{}
+	data.Set("pattern", pattern)
+	data.Set("plugins", strings.Join(plugins, "|"))
+	data.Set("category", category)
+
+	respData, err := c.doPostValues("/api/v2/search/start", data)
+	if err != nil {
+		return nil, fmt.Errorf("SearchStart error: %w", err)
+	}
+
+	var result SearchJob
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode SearchStart response: %v", err)
+	}
+	return &result, nil
+}
+
+// SearchStop stops a search job
+func (c *Client) SearchStop(id int) error {
+	data := url.Values{}
+	data.Set("id", strconv.Itoa(id))
+
+	_, err := c.doPostValues("/api/v2/search/stop", data)
+	if err != nil {
+		return fmt.Errorf("SearchStop error: %w", err)
+	}
+	return nil
+}
+
+// SearchStatus retrieves the state of a search job, or of every job if id is 0
+func (c *Client) SearchStatus(id int) ([]SearchStatus, error) {
+	params := url.Values{}
+	if id != 0 {
+		params.Set("id", strconv.Itoa(id))
+	}
+
+	respData, err := c.doGet("/api/v2/search/status", params)
+	if err != nil {
+		return nil, fmt.Errorf("SearchStatus error: %w", err)
+	}
+
+	var result []SearchStatus
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode SearchStatus response: %v", err)
+	}
+	return result, nil
+}
+
+// SearchResults retrieves up to limit results of a search job from offset, a limit of 0 meaning all
+func (c *Client) SearchResults(id int, limit int, offset int) (*SearchResults, error) {
+	params := url.Values{}
+	params.Set("id", strconv.Itoa(id))
+	if limit != 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if offset != 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+
+	respData, err := c.doGet("/api/v2/search/results", params)
+	if err != nil {
+		return nil, fmt.Errorf("SearchResults error: %w", err)
+	}
+
+	var result SearchResults
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode SearchResults response: %v", err)
+	}
+	return &result, nil
+}
+
+// SearchDelete stops a search job if it is running and deletes it with its results
+func (c *Client) SearchDelete(id int) error {
+	data := url.Values{}
+	data.Set("id", strconv.Itoa(id))
+
+	_, err := c.doPostValues("/api/v2/search/delete", data)
+	if err != nil {
+		return fmt.Errorf("SearchDelete error: %w", err)
+	}
+	return nil
+}
+
+// SearchPlugins retrieves the installed search plugins
+func (c *Client) SearchPlugins() ([]SearchPlugin, error) {
+	respData, err := c.doGet("/api/v2/search/plugins", nil)
+	if err != nil {
+		return nil, fmt.Errorf("SearchPlugins error: %w", err)
+	}
+
+	var result []SearchPlugin
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode SearchPlugins response: %v", err)
+	}
+	return result, nil
+}
+
+// SearchEnablePlugin enables or disables search plugins
+func (c *Client) SearchEnablePlugin(names []string, enable bool) error {
+	data := url.Values{}
+	data.Set("names", strings.Join(names, "|"))
+	data.Set("enable", strconv.FormatBool(enable))
+
+	_, err := c.doPostValues("/api/v2/search/enablePlugin", data)
+	if err != nil {
+		return fmt.Errorf("SearchEnablePlugin error: %w", err)
+	}
+	return nil
+}
+
+// SearchUpdatePlugins updates the installed search plugins
+func (c *Client) SearchUpdatePlugins() error {
+	_, err := c.doPostValues("/api/v2/search/updatePlugins", nil)
+	if err != nil {
+		return fmt.Errorf("SearchUpdatePlugins error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestGeneratedBindings(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":     {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/search/start":   {statusCode: http.StatusOK, responseBody: `{"id":12}`},
+		"/api/v2/search/results": {statusCode: http.StatusOK, responseBody: `{"results":[{"fileName":"a","fileSize":10}],"status":"Running","total":1}`},
+		"/api/v2/rss/addFeed":    {statusCode: http.StatusOK},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "POST", url: "/api/v2/search/start", params: url.Values{"pattern": {"ubuntu"}, "plugins": {"a|b"}, "category": {"all"}}},
+		{method: "GET", url: "/api/v2/search/results"},
+		{method: "POST", url: "/api/v2/rss/addFeed", params: url.Values{"url": {"https://example.org/feed"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	job, err := client.SearchStart("ubuntu", []string{"a", "b"}, "all")
+	if err != nil || job.ID != 12 {
+		t.Fatalf("unexpected job %+v, %v", job, err)
+	}
+	results, err := client.SearchResults(job.ID, 0, 0)
+	if err != nil || results.Total != 1 || results.Results[0].FileName != "a" {
+		t.Fatalf("unexpected results %+v, %v", results, err)
+	}
+	if err := client.RSSAddFeed("https://example.org/feed", ""); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
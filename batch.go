@@ -0,0 +1,170 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Batch runs a per-torrent operation over many hashes with bounded parallelism.
+// Create one with Client.Batch, configure it with the With methods and run it with Do.
+type Batch struct {
+	client      *Client
+	ctx         context.Context
+	hashes      []InfoHash
+	concurrency int
+	onProgress  func(done, total int, hash InfoHash, err error)
+}
+
+// BatchError reports the hashes for which a Batch operation failed
+type BatchError struct {
+	Failed    map[InfoHash]error // the error of every failed hash
+	Succeeded []InfoHash         // the hashes that succeeded, in input order
+}
+
+func (e *BatchError) Error() string {
+	hashes := make([]InfoHash, 0, len(e.Failed))
+	for hash := range e.Failed {
+		hashes = append(hashes, hash)
+	}
+	sortHashes(hashes)
+
+	msgs := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		msgs = append(msgs, fmt.Sprintf("%s: %v", hash, e.Failed[hash]))
+	}
+	return fmt.Sprintf("batch failed for %d of %d torrents: %s",
+		len(e.Failed), len(e.Failed)+len(e.Succeeded), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual errors, so errors.Is and errors.As match any of them
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, err := range e.Failed {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Batch prepares an operation over hashes, run with the default concurrency of 4
+func (c *Client) Batch(ctx context.Context, hashes []InfoHash) *Batch {
+	return &Batch{client: c, ctx: ctx, hashes: hashes, concurrency: 4}
+}
+
+// WithConcurrency sets the number of hashes processed at the same time
+func (b *Batch) WithConcurrency(n int) *Batch {
+	if n > 0 {
+		b.concurrency = n
+	}
+	return b
+}
+
+// WithProgress sets a function called after each hash was processed. It may be called concurrently.
+func (b *Batch) WithProgress(fn func(done, total int, hash InfoHash, err error)) *Batch {
+	b.onProgress = fn
+	return b
+}
+
+// Do calls fn for every hash and waits for all calls to finish. Failures do not stop the batch;
+// if any call failed the returned error is a *BatchError. Hashes not started before the context
+// is cancelled fail with the context's error.
+func (b *Batch) Do(fn func(hash InfoHash) error) error {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		done   int
+		failed = make(map[InfoHash]error)
+		ok     = make([]bool, len(b.hashes))
+	)
+	record := func(i int, hash InfoHash, err error) {
+		mu.Lock()
+		done++
+		if err != nil {
+			failed[hash] = err
+		} else {
+			ok[i] = true
+		}
+		progress := done
+		mu.Unlock()
+
+		if b.onProgress != nil {
+			b.onProgress(progress, len(b.hashes), hash, err)
+		}
+	}
+
+	sem := make(chan struct{}, b.concurrency)
+	for i, hash := range b.hashes {
+		select {
+		case <-b.ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if err := b.ctx.Err(); err != nil {
+			record(i, hash, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, hash InfoHash) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			record(i, hash, fn(hash))
+		}(i, hash)
+	}
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+	batchErr := &BatchError{Failed: failed}
+	for i, hash := range b.hashes {
+		if ok[i] {
+			batchErr.Succeeded = append(batchErr.Succeeded, hash)
+		}
+	}
+	return batchErr
+}
+
+// Export writes the .torrent file of every hash into dir as "<hash>.torrent"
+func (b *Batch) Export(dir string) error {
+	return b.Do(func(hash InfoHash) error {
+		return b.client.exportTo(b.ctx, TorrentInfo{Hash: hash}, filepath.Join(dir, string(hash)+".torrent"))
+	})
+}
+
+// ExportData fetches the .torrent file of every hash. Hashes whose file could not be fetched are missing
+// from the result and reported in the *BatchError.
+func (b *Batch) ExportData() (map[InfoHash][]byte, error) {
+	var mu sync.Mutex
+	result := make(map[InfoHash][]byte, len(b.hashes))
+	err := b.Do(func(hash InfoHash) error {
+		data, err := b.client.exportBytes(b.ctx, string(hash))
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result[hash] = data
+		mu.Unlock()
+		return nil
+	})
+	return result, err
+}
+
+// Trackers fetches the trackers of every hash. Hashes whose trackers could not be fetched are missing
+// from the result and reported in the *BatchError.
+func (b *Batch) Trackers() (map[InfoHash][]TrackerInfo, error) {
+	var mu sync.Mutex
+	result := make(map[InfoHash][]TrackerInfo, len(b.hashes))
+	err := b.Do(func(hash InfoHash) error {
+		trackers, err := b.client.TorrentsTrackers(string(hash))
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		result[hash] = trackers
+		mu.Unlock()
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,130 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBatch_Do(t *testing.T) {
+	errBoom := errors.New("boom")
+	hashes := []InfoHash{"a", "b", "c", "d", "e", "f"}
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	var progress []int
+	client := &Client{}
+	err := client.Batch(context.Background(), hashes).
+		WithConcurrency(2).
+		WithProgress(func(done, total int, hash InfoHash, err error) {
+			mu.Lock()
+			progress = append(progress, done)
+			mu.Unlock()
+		}).
+		Do(func(hash InfoHash) error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+					break
+				}
+			}
+			if hash == "b" || hash == "e" {
+				return errBoom
+			}
+			return nil
+		})
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a *BatchError, got %v", err)
+	}
+	if len(batchErr.Failed) != 2 || batchErr.Failed["b"] != errBoom || batchErr.Failed["e"] != errBoom {
+		t.Errorf("unexpected failures %v", batchErr.Failed)
+	}
+	if want := []InfoHash{"a", "c", "d", "f"}; !reflect.DeepEqual(batchErr.Succeeded, want) {
+		t.Errorf("expected succeeded %v, got %v", want, batchErr.Succeeded)
+	}
+	if !errors.Is(err, errBoom) {
+		t.Error("expected errors.Is to match the individual errors")
+	}
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent calls, got %d", maxRunning)
+	}
+	if len(progress) != len(hashes) {
+		t.Errorf("expected %d progress calls, got %d", len(hashes), len(progress))
+	}
+}
+
+func TestBatch_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	err := (&Client{}).Batch(ctx, []InfoHash{"a", "b"}).Do(func(hash InfoHash) error {
+		called = true
+		return nil
+	})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 2 || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected every hash to fail with context.Canceled, got %v", err)
+	}
+	if called {
+		t.Error("expected no calls after cancellation")
+	}
+}
+
+func TestBatch_ExportAndTrackers(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		hash := r.Form.Get("hash")
+		if hash == "missing" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v2/torrents/export":
+			w.Write([]byte("torrent " + hash))
+		case "/api/v2/torrents/trackers":
+			w.Write([]byte(`[{"url":"https://tracker.example.org/` + hash + `","status":2}]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	hashes := []InfoHash{"a", "missing"}
+
+	dir := t.TempDir()
+	err := client.Batch(context.Background(), hashes).Export(dir)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed["missing"] == nil {
+		t.Fatalf("expected missing to fail, got %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "a.torrent")); err != nil || string(data) != "torrent a" {
+		t.Errorf("expected a.torrent to be written, got %q, %v", data, err)
+	}
+
+	exports, err := client.TorrentsExportMany(context.Background(), hashes)
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 1 {
+		t.Fatalf("expected missing to fail, got %v", err)
+	}
+	if !reflect.DeepEqual(exports, map[InfoHash][]byte{"a": []byte("torrent a")}) {
+		t.Errorf("unexpected exports %q", exports)
+	}
+
+	trackers, err := client.Batch(context.Background(), hashes).Trackers()
+	if !errors.As(err, &batchErr) || len(batchErr.Failed) != 1 {
+		t.Fatalf("expected missing to fail, got %v", err)
+	}
+	if len(trackers) != 1 || trackers["a"][0].URL != "https://tracker.example.org/a" {
+		t.Errorf("unexpected trackers %v", trackers)
+	}
+}
@@ -0,0 +1,117 @@
+package qbittorrent
+
+import (
+	"net/url"
+	"sync"
+	"time"
+)
+
+// cacheableEndpoints are the read endpoints whose responses WithCache caches. Endpoints returning live data
+// such as sync/maindata, transfer/info or logs are always requested.
+var cacheableEndpoints = map[string]bool{
+	"/api/v2/app/preferences":             true,
+	"/api/v2/app/version":                 true,
+	"/api/v2/app/webapiVersion":           true,
+	"/api/v2/app/networkInterfaceList":    true,
+	"/api/v2/app/networkInterfaceAddress": true,
+	"/api/v2/torrents/info":               true,
+	"/api/v2/torrents/trackers":           true,
+	"/api/v2/torrents/files":              true,
+	"/api/v2/torrents/pieceHashes":        true,
+	"/api/v2/torrents/categories":         true,
+	"/api/v2/torrents/tags":               true,
+	"/api/v2/transfer/speedLimitsMode":    true,
+	"/api/v2/rss/items":                   true,
+	"/api/v2/rss/rules":                   true,
+	"/api/v2/search/plugins":              true,
+}
+
+// cacheSweepSize is the number of entries above which expired ones are removed when adding another
+const cacheSweepSize = 256
+
+// WithCache caches the responses of idempotent reads, such as TorrentsInfo, AppPreferences and
+// TorrentsCategories, for ttl, so that handlers of a UI calling the library concurrently do not multiply
+// identical requests. Every POST made through the client empties the cache, since it may have changed
+// anything; changes made by other clients or qBittorrent itself are seen once the entries expired. Use
+// InvalidateCache to drop the entries earlier.
+func WithCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			c.cache = nil
+			return
+		}
+		c.cache = &responseCache{ttl: ttl, now: time.Now, entries: make(map[string]cacheEntry)}
+	}
+}
+
+// InvalidateCache drops the responses cached by WithCache. It does nothing for clients without a cache.
+func (c *Client) InvalidateCache() {
+	if c.cache != nil {
+		c.cache.invalidate()
+	}
+}
+
+type cacheEntry struct {
+	data    []byte
+	expires time.Time
+}
+
+// responseCache holds response bodies keyed by endpoint and query
+type responseCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	gen     uint64 // incremented by invalidate
+	entries map[string]cacheEntry
+}
+
+// cacheKey returns the key of a request, or "" if its response is not cached
+func cacheKey(endpoint string, query url.Values) string {
+	if !cacheableEndpoints[endpoint] {
+		return ""
+	}
+	return endpoint + "?" + query.Encode()
+}
+
+// get returns the cached response for key, if any. Otherwise it returns the generation to pass to put, so
+// that a response requested before an invalidation is not stored after it.
+func (rc *responseCache) get(key string) ([]byte, uint64, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[key]
+	if ok && rc.now().Before(entry.expires) {
+		return entry.data, 0, true
+	}
+	if ok {
+		delete(rc.entries, key)
+	}
+	return nil, rc.gen, false
+}
+
+// put stores a response requested in generation gen
+func (rc *responseCache) put(key string, gen uint64, data []byte) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if gen != rc.gen {
+		return
+	}
+	now := rc.now()
+	if len(rc.entries) >= cacheSweepSize {
+		for k, entry := range rc.entries {
+			if !now.Before(entry.expires) {
+				delete(rc.entries, k)
+			}
+		}
+	}
+	rc.entries[key] = cacheEntry{data: data, expires: now.Add(rc.ttl)}
+}
+
+func (rc *responseCache) invalidate() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.gen++
+	clear(rc.entries)
+}
@@ -0,0 +1,92 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	requests := map[string]int{}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"abc","name":"a"}]`))
+		case "/api/v2/sync/maindata":
+			w.Write([]byte(`{"rid":1}`))
+		default:
+			w.Write([]byte("Ok."))
+		}
+	}))
+	defer mockServer.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithCache(time.Minute)(client)
+	client.cache.now = func() time.Time { return now }
+
+	info := func() {
+		t.Helper()
+		torrents, err := client.TorrentsInfo()
+		if err != nil || len(torrents) != 1 || torrents[0].Name != "a" {
+			t.Fatalf("unexpected torrents %v, %v", torrents, err)
+		}
+	}
+	info()
+	info()
+	if requests["GET /api/v2/torrents/info"] != 1 {
+		t.Errorf("expected 1 request, got %v", requests)
+	}
+
+	// Other queries are cached separately
+	if _, err := client.TorrentsInfo(&TorrentsInfoParams{Category: "movies"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if requests["GET /api/v2/torrents/info"] != 2 {
+		t.Errorf("expected 2 requests, got %v", requests)
+	}
+
+	// A POST invalidates the cache
+	if err := client.TorrentsAddTags("abc", []string{"x"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	info()
+	if requests["GET /api/v2/torrents/info"] != 3 {
+		t.Errorf("expected 3 requests, got %v", requests)
+	}
+
+	// Entries expire after the ttl
+	now = now.Add(time.Minute)
+	info()
+	if requests["GET /api/v2/torrents/info"] != 4 {
+		t.Errorf("expected 4 requests, got %v", requests)
+	}
+
+	client.InvalidateCache()
+	info()
+	if requests["GET /api/v2/torrents/info"] != 5 {
+		t.Errorf("expected 5 requests, got %v", requests)
+	}
+
+	// Live data is never cached
+	for range 2 {
+		if _, err := client.SyncMainData(0); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if requests["GET /api/v2/sync/maindata"] != 2 {
+		t.Errorf("expected 2 requests, got %v", requests)
+	}
+}
+
+func TestResponseCache_Generation(t *testing.T) {
+	cache := &responseCache{ttl: time.Minute, now: time.Now, entries: make(map[string]cacheEntry)}
+	_, gen, _ := cache.get("key")
+	cache.invalidate()
+	cache.put("key", gen, []byte("stale"))
+	if _, _, ok := cache.get("key"); ok {
+		t.Error("expected a response requested before an invalidation not to be cached")
+	}
+}
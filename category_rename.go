@@ -0,0 +1,115 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CategoryMoveOptions controls how CategoryRename and CategoryMerge treat torrents under automatic
+// torrent management, whose data qBittorrent moves to the save path of their new category
+type CategoryMoveOptions struct {
+	// KeepFiles disables automatic management of the moved torrents before their category is changed,
+	// leaving their data in place
+	KeepFiles bool
+}
+
+// CategoryRename renames a category, which qBittorrent has no endpoint for. The new category is created
+// with the save path of the old one, so automatically managed torrents keep their data where it is, the
+// torrents are moved over and the old category is removed. Categories with subcategories, such as "tv" with
+// "tv/anime", are refused, as removing them also removes the subcategories and uncategorizes their torrents.
+func (c *Client) CategoryRename(oldName, newName string, opts *CategoryMoveOptions) error {
+	categories, err := c.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("CategoryRename error: %w", err)
+	}
+	old, ok := categories[oldName]
+	if !ok {
+		return fmt.Errorf("CategoryRename error: category %q does not exist", oldName)
+	}
+	if err := checkNoSubcategories(categories, oldName); err != nil {
+		return fmt.Errorf("CategoryRename error: %w", err)
+	}
+	if _, ok := categories[newName]; ok {
+		return fmt.Errorf("CategoryRename error: category %q: %w", newName, ErrAlreadyExists)
+	}
+	if err := c.TorrentsCreateCategory(newName, categorySavePath(old)); err != nil {
+		return fmt.Errorf("CategoryRename error: %w", err)
+	}
+	if err := c.moveCategory(oldName, newName, opts); err != nil {
+		return fmt.Errorf("CategoryRename error: %w", err)
+	}
+	return nil
+}
+
+// CategoryMerge moves all torrents of category from to the existing category to and removes from.
+// Automatically managed torrents have their data moved to the save path of to unless opts.KeepFiles is set.
+// Like CategoryRename, it refuses to remove a category with subcategories.
+func (c *Client) CategoryMerge(from, to string, opts *CategoryMoveOptions) error {
+	categories, err := c.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("CategoryMerge error: %w", err)
+	}
+	for _, name := range []string{from, to} {
+		if _, ok := categories[name]; !ok {
+			return fmt.Errorf("CategoryMerge error: category %q does not exist", name)
+		}
+	}
+	if from == to {
+		return nil
+	}
+	if err := checkNoSubcategories(categories, from); err != nil {
+		return fmt.Errorf("CategoryMerge error: %w", err)
+	}
+	if err := c.moveCategory(from, to, opts); err != nil {
+		return fmt.Errorf("CategoryMerge error: %w", err)
+	}
+	return nil
+}
+
+// checkNoSubcategories returns an error if name has subcategories among categories
+func checkNoSubcategories(categories map[string]Category, name string) error {
+	var children []string
+	for category := range categories {
+		if strings.HasPrefix(category, name+"/") {
+			children = append(children, category)
+		}
+	}
+	if len(children) == 0 {
+		return nil
+	}
+	sort.Strings(children)
+	return fmt.Errorf("category %q has subcategories %s, which removing it would delete", name,
+		strings.Join(children, ", "))
+}
+
+// moveCategory moves the torrents of category from to category to and removes from once all were moved
+func (c *Client) moveCategory(from, to string, opts *CategoryMoveOptions) error {
+	torrents, err := c.GetTorrentsByCategory(from)
+	if err != nil {
+		return err
+	}
+	if len(torrents) > 0 {
+		if opts != nil && opts.KeepFiles {
+			var managed []InfoHash
+			for _, torrent := range torrents {
+				if torrent.AutoTMM {
+					managed = append(managed, torrent.Hash)
+				}
+			}
+			if len(managed) > 0 {
+				if err := c.TorrentsSetAutoManagement(joinHashes(managed), false); err != nil {
+					return err
+				}
+			}
+		}
+		hashes := make([]InfoHash, len(torrents))
+		for i, torrent := range torrents {
+			hashes[i] = torrent.Hash
+		}
+		if err := c.TorrentsSetCategory(joinHashes(hashes), to); err != nil {
+			return err
+		}
+	}
+	return c.TorrentsRemoveCategories([]string{from})
+}
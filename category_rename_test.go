@@ -0,0 +1,91 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCategoryRenameAndMerge(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/tv"},"shows":{"name":"shows","savePath":"/shows"}}`))
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"aaa","category":"tv","auto_tmm":true},{"hash":"bbb","category":"tv"}]`))
+		default:
+			requests = append(requests, r.URL.Path+" "+r.Form.Encode())
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if err := client.CategoryRename("tv", "series", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantRequests := []string{
+		"/api/v2/torrents/createCategory category=series&savePath=%2Ftv",
+		"/api/v2/torrents/setCategory category=series&hashes=aaa%7Cbbb",
+		"/api/v2/torrents/removeCategories categories=tv",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+
+	requests = nil
+	if err := client.CategoryMerge("tv", "shows", &CategoryMoveOptions{KeepFiles: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantRequests = []string{
+		"/api/v2/torrents/setAutoManagement enable=false&hashes=aaa",
+		"/api/v2/torrents/setCategory category=shows&hashes=aaa%7Cbbb",
+		"/api/v2/torrents/removeCategories categories=tv",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+
+	if err := client.CategoryRename("tv", "shows", nil); !errors.Is(err, ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := client.CategoryMerge("tv", "missing", nil); err == nil {
+		t.Error("expected an error for a missing category")
+	}
+}
+
+func TestCategoryRenameWithSubcategories(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/tv"},"tv/anime":{"name":"tv/anime","savePath":"/tv/anime"},` +
+				`"shows":{"name":"shows","savePath":"/shows"}}`))
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"aaa","category":"tv"},{"hash":"bbb","category":"tv/anime"}]`))
+		default:
+			requests = append(requests, r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if err := client.CategoryRename("tv", "series", nil); err == nil || !strings.Contains(err.Error(), "tv/anime") {
+		t.Errorf("expected an error naming tv/anime, got %v", err)
+	}
+	if err := client.CategoryMerge("tv", "shows", nil); err == nil || !strings.Contains(err.Error(), "tv/anime") {
+		t.Errorf("expected an error naming tv/anime, got %v", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected no changes, got %v", requests)
+	}
+
+	// Subcategories themselves have no children and can be renamed
+	if err := client.CategoryRename("tv/anime", "anime", nil); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
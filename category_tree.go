@@ -0,0 +1,148 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// With subcategories enabled (ServerState.UseSubcategories), category names are "/" separated paths such
+// as "tv/anime" and a category is the parent of every category below it. The helpers below work on those
+// paths client-side; the server itself still matches categories by exact name.
+
+// CategoryNode is a category in a category tree
+type CategoryNode struct {
+	Name     string // full path, e.g. "tv/anime"
+	SavePath string
+	Implicit bool // the category does not exist on the server but has subcategories that do
+	Children []*CategoryNode
+}
+
+// Base returns the last path segment of the category name, e.g. "anime" for "tv/anime"
+func (n *CategoryNode) Base() string {
+	return n.Name[strings.LastIndex(n.Name, "/")+1:]
+}
+
+// Walk calls fn for n and every category below it, parents before children
+func (n *CategoryNode) Walk(fn func(node *CategoryNode)) {
+	fn(n)
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// BuildCategoryTree arranges categories, as returned by TorrentsCategories, into trees by their
+// "/" separated names. Missing intermediate categories are added as Implicit nodes. Roots and children
+// are sorted by name.
+func BuildCategoryTree(categories map[string]Category) []*CategoryNode {
+	nodes := make(map[string]*CategoryNode)
+	var roots []*CategoryNode
+
+	var node func(name string) *CategoryNode
+	node = func(name string) *CategoryNode {
+		if n, ok := nodes[name]; ok {
+			return n
+		}
+		n := &CategoryNode{Name: name, Implicit: true}
+		nodes[name] = n
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			parent := node(name[:i])
+			parent.Children = append(parent.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+		return n
+	}
+
+	for name, category := range categories {
+		n := node(name)
+		n.Implicit = false
+		n.SavePath, _ = category["savePath"].(string)
+	}
+
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*CategoryNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+// CategoryTree retrieves the categories arranged into trees, see BuildCategoryTree
+func (c *Client) CategoryTree() ([]*CategoryNode, error) {
+	categories, err := c.TorrentsCategories()
+	if err != nil {
+		return nil, err
+	}
+	return BuildCategoryTree(categories), nil
+}
+
+// InCategoryTree reports whether category is root or one of its subcategories
+func InCategoryTree(category, root string) bool {
+	return category == root || strings.HasPrefix(category, root+"/")
+}
+
+// FilterByCategoryTree returns the torrents in root or one of its subcategories
+func FilterByCategoryTree(torrents []TorrentInfo, root string) []TorrentInfo {
+	var out []TorrentInfo
+	for _, torrent := range torrents {
+		if InCategoryTree(torrent.Category, root) {
+			out = append(out, torrent)
+		}
+	}
+	return out
+}
+
+// TorrentsInCategoryTree retrieves the torrents in root or one of its subcategories
+func (c *Client) TorrentsInCategoryTree(root string) ([]TorrentInfo, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsInCategoryTree error: %w", err)
+	}
+	return FilterByCategoryTree(torrents, root), nil
+}
+
+// CreateNestedCategory creates the category path, e.g. "tv/anime", along with any missing parent
+// categories. savePath applies to the category itself, parents are created with the default save path.
+// Existing categories are left untouched.
+func (c *Client) CreateNestedCategory(path, savePath string) error {
+	if err := validateCategoryPath(path); err != nil {
+		return fmt.Errorf("CreateNestedCategory error: %w", err)
+	}
+	existing, err := c.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("CreateNestedCategory error: %w", err)
+	}
+
+	segments := strings.Split(path, "/")
+	for i := range segments {
+		name := strings.Join(segments[:i+1], "/")
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		categorySavePath := ""
+		if name == path {
+			categorySavePath = savePath
+		}
+		if err := c.TorrentsCreateCategory(name, categorySavePath); err != nil {
+			return fmt.Errorf("CreateNestedCategory error: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateCategoryPath rejects names qBittorrent would refuse or normalize, such as "tv//anime" or "/tv"
+func validateCategoryPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("empty category name")
+	}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.TrimSpace(segment) == "" {
+			return fmt.Errorf("invalid category name %q", path)
+		}
+	}
+	return nil
+}
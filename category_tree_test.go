@@ -0,0 +1,87 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	roots := BuildCategoryTree(map[string]Category{
+		"tv":             {"name": "tv", "savePath": "/tv"},
+		"tv/anime":       {"name": "tv/anime", "savePath": "/tv/anime"},
+		"tv/docs":        {"name": "tv/docs", "savePath": ""},
+		"movies/4k/hdr":  {"name": "movies/4k/hdr", "savePath": "/hdr"},
+		"music":          {"name": "music", "savePath": ""},
+		"tv/anime/older": {"name": "tv/anime/older", "savePath": ""},
+	})
+
+	var names []string
+	var implicit []string
+	for _, root := range roots {
+		root.Walk(func(n *CategoryNode) {
+			names = append(names, n.Name)
+			if n.Implicit {
+				implicit = append(implicit, n.Name)
+			}
+		})
+	}
+	wantNames := []string{"movies", "movies/4k", "movies/4k/hdr", "music", "tv", "tv/anime", "tv/anime/older", "tv/docs"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("expected %v, got %v", wantNames, names)
+	}
+	if want := []string{"movies", "movies/4k"}; !reflect.DeepEqual(implicit, want) {
+		t.Errorf("expected implicit %v, got %v", want, implicit)
+	}
+	if anime := roots[2].Children[0]; anime.Base() != "anime" || anime.SavePath != "/tv/anime" {
+		t.Errorf("unexpected node %+v", anime)
+	}
+}
+
+func TestFilterByCategoryTree(t *testing.T) {
+	torrents := []TorrentInfo{
+		{Hash: "a", Category: "tv"},
+		{Hash: "b", Category: "tv/anime"},
+		{Hash: "c", Category: "tvshows"},
+		{Hash: "d", Category: ""},
+	}
+	var hashes []InfoHash
+	for _, torrent := range FilterByCategoryTree(torrents, "tv") {
+		hashes = append(hashes, torrent.Hash)
+	}
+	if want := []InfoHash{"a", "b"}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("expected %v, got %v", want, hashes)
+	}
+}
+
+func TestClient_CreateNestedCategory(t *testing.T) {
+	var created []url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/tv"}}`))
+		case "/api/v2/torrents/createCategory":
+			r.ParseForm()
+			created = append(created, r.PostForm)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	if err := client.CreateNestedCategory("tv/anime/older", "/archive"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []url.Values{
+		{"category": {"tv/anime"}, "savePath": {""}},
+		{"category": {"tv/anime/older"}, "savePath": {"/archive"}},
+	}
+	if !reflect.DeepEqual(created, want) {
+		t.Errorf("expected %v, got %v", want, created)
+	}
+
+	if err := client.CreateNestedCategory("tv//anime", ""); err == nil {
+		t.Error("expected an error for an empty segment")
+	}
+}
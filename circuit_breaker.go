@@ -0,0 +1,92 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops sending requests to a server that failed to respond several times in a row, such as a
+// seedbox that is rebooting, so that pollers neither hammer it nor flood their logs. The zero value
+// disables it.
+type CircuitBreaker struct {
+	Failures int           // consecutive failures opening the circuit, 0 disables the breaker
+	Cooldown time.Duration // how long requests fail fast once open, default 30s
+}
+
+// WithCircuitBreaker fails requests with ErrCircuitOpen for the cooldown once Failures requests in a row
+// failed with a network error or a 502, 503 or 504 response. After the cooldown one request is let through:
+// the circuit closes again if it succeeds and stays open for another cooldown otherwise.
+func WithCircuitBreaker(breaker CircuitBreaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(breaker)
+	}
+}
+
+// circuitBreaker is the state of a CircuitBreaker
+type circuitBreaker struct {
+	mu        sync.Mutex
+	failures  int
+	cooldown  time.Duration
+	failed    int       // consecutive failures
+	openUntil time.Time // zero while closed
+	probing   bool      // the request let through after the cooldown is in flight
+	now       func() time.Time
+}
+
+// newCircuitBreaker returns the state for breaker, or nil if it is disabled
+func newCircuitBreaker(breaker CircuitBreaker) *circuitBreaker {
+	if breaker.Failures <= 0 {
+		return nil
+	}
+	cooldown := breaker.Cooldown
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{failures: breaker.Failures, cooldown: cooldown, now: time.Now}
+}
+
+// allow returns ErrCircuitOpen unless a request may be sent
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return nil
+	}
+	if remaining := b.openUntil.Sub(b.now()); remaining > 0 || b.probing {
+		return fmt.Errorf("%w: retrying in %s", ErrCircuitOpen, max(remaining, 0).Round(time.Second))
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the state with the outcome of req, which was allowed by allow. Requests cancelled by their
+// context do not count.
+func (b *circuitBreaker) record(req *http.Request, resp *http.Response, err error) {
+	failed := err != nil
+	if resp != nil {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			failed = true
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err != nil && req.Context().Err() != nil {
+		return
+	}
+	if !failed {
+		b.failed = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.failed++
+	if b.failed >= b.failures || !b.openUntil.IsZero() {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
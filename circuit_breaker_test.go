@@ -0,0 +1,81 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	requests := 0
+	status := http.StatusBadGateway
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(status)
+	}))
+	defer mockServer.Close()
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithCircuitBreaker(CircuitBreaker{Failures: 3, Cooldown: time.Minute})(client)
+	client.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.doGet("/api/v2/app/version", nil); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("request %d: expected a status error, got %v", i, err)
+		}
+	}
+	if _, err := client.doGet("/api/v2/app/version", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+
+	// The probe after the cooldown fails and opens the circuit again
+	now = now.Add(time.Minute)
+	if _, err := client.doGet("/api/v2/app/version", nil); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected the probe to reach the server, got %v", err)
+	}
+	if _, err := client.doGet("/api/v2/app/version", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	// A successful probe closes it
+	now = now.Add(time.Minute)
+	status = http.StatusOK
+	for i := 0; i < 2; i++ {
+		if _, err := client.doGet("/api/v2/app/version", nil); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if requests != 6 {
+		t.Errorf("expected 6 requests, got %d", requests)
+	}
+}
+
+func TestCircuitBreaker_Probe(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(CircuitBreaker{Failures: 1})
+	b.now = func() time.Time { return now }
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	b.record(req, nil, errors.New("connection refused"))
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	now = now.Add(30 * time.Second)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected the probe to be allowed, got %v", err)
+	}
+	// Only one probe at a time
+	if err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen during the probe, got %v", err)
+	}
+
+	if newCircuitBreaker(CircuitBreaker{}) != nil {
+		t.Error("expected the zero value to disable the breaker")
+	}
+}
@@ -0,0 +1,140 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// CleanerConfig selects the policies a Cleaner applies. Policies with zero values are disabled.
+type CleanerConfig struct {
+	ErroredOlderThan   time.Duration // remove torrents in an error state that were added longer ago than this, if known
+	RemoveUnregistered bool          // remove torrents whose trackers report them as unregistered
+	RemoveUnusedTags   bool          // delete tags that no torrent carries once the removals are done
+	DeleteFiles        bool          // delete the downloaded data of removed torrents
+}
+
+// CleanupTorrent is a torrent a Cleaner plans to remove
+type CleanupTorrent struct {
+	Hash   InfoHash
+	Name   string
+	Reason string
+}
+
+// CleanupReport lists what a Cleaner would remove. It is produced by Plan and consumed by Execute.
+type CleanupReport struct {
+	Torrents []CleanupTorrent
+	Tags     []string
+}
+
+// Cleaner removes errored and unregistered torrents and unused tags
+type Cleaner struct {
+	client TorrentsAPI
+	cfg    CleanerConfig
+	now    func() time.Time
+}
+
+// NewCleaner creates a Cleaner for the given client
+func NewCleaner(client TorrentsAPI, cfg CleanerConfig) *Cleaner {
+	return &Cleaner{client: client, cfg: cfg, now: time.Now}
+}
+
+// Plan performs a dry run and returns what Execute would remove
+func (c *Cleaner) Plan(ctx context.Context) (*CleanupReport, error) {
+	torrents, err := c.client.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("Cleaner error: %w", err)
+	}
+
+	report := &CleanupReport{}
+	removed := make(map[InfoHash]struct{})
+	now := c.now()
+	for _, torrent := range torrents {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		reason := ""
+		// Torrents whose addition time is unknown are never old enough
+		if c.cfg.ErroredOlderThan > 0 && (torrent.State == "error" || torrent.State == "missingFiles") &&
+			torrent.AddedOn > 0 && now.Sub(torrent.AddedAt()) > c.cfg.ErroredOlderThan {
+			reason = fmt.Sprintf("added more than %s ago and in state %s", c.cfg.ErroredOlderThan, torrent.State)
+		}
+		if reason == "" && c.cfg.RemoveUnregistered {
+			trackers, err := c.client.TorrentsTrackers(string(torrent.Hash))
+			if err != nil {
+				return nil, fmt.Errorf("Cleaner error: %w", err)
+			}
+			if msg, ok := unregisteredMessage(trackers); ok {
+				reason = "unregistered: " + msg
+			}
+		}
+		if reason != "" {
+			report.Torrents = append(report.Torrents, CleanupTorrent{Hash: torrent.Hash, Name: torrent.Name, Reason: reason})
+			removed[torrent.Hash] = struct{}{}
+		}
+	}
+
+	if c.cfg.RemoveUnusedTags {
+		tags, err := c.client.TorrentsGetAllTags()
+		if err != nil {
+			return nil, fmt.Errorf("Cleaner error: %w", err)
+		}
+		used := make(map[string]struct{})
+		for _, torrent := range torrents {
+			if _, ok := removed[torrent.Hash]; ok {
+				continue
+			}
+			for _, tag := range torrent.Tags {
+				used[tag] = struct{}{}
+			}
+		}
+		for _, tag := range tags {
+			if _, ok := used[tag]; !ok {
+				report.Tags = append(report.Tags, tag)
+			}
+		}
+		sort.Strings(report.Tags)
+	}
+
+	return report, nil
+}
+
+// Execute removes the torrents and tags listed in report
+func (c *Cleaner) Execute(ctx context.Context, report *CleanupReport) error {
+	for _, torrent := range report.Torrents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.client.TorrentsRemove(string(torrent.Hash), c.cfg.DeleteFiles); err != nil {
+			return fmt.Errorf("Cleaner error: %w", err)
+		}
+	}
+	for _, tag := range report.Tags {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.client.TorrentsDeleteTags([]string{tag}); err != nil {
+			return fmt.Errorf("Cleaner error: %w", err)
+		}
+	}
+	return nil
+}
+
+// unregisteredMessage returns the tracker message if no real tracker works and one reports the torrent as unregistered
+func unregisteredMessage(trackers []TrackerInfo) (string, bool) {
+	msg := ""
+	for _, tracker := range trackers {
+		if isPseudoTracker(tracker.URL) {
+			continue
+		}
+		if tracker.Status == TrackerWorking {
+			return "", false
+		}
+		if msg == "" && isUnregisteredMessage(tracker.Msg) {
+			msg = tracker.Msg
+		}
+	}
+	return msg, msg != ""
+}
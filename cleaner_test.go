@@ -0,0 +1,131 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCleaner_PlanAndExecute(t *testing.T) {
+	var removed, deletedTags []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"errored","name":"a","state":"error","added_on":1000,"tags":"old"},
+				{"hash":"fresherror","name":"b","state":"error","added_on":1699999000,"tags":""},
+				{"hash":"unreg","name":"c","state":"stalledUP","added_on":1000,"tags":"keep"},
+				{"hash":"healthy","name":"d","state":"uploading","added_on":1000,"tags":"keep"}
+			]`))
+		case "/api/v2/torrents/trackers":
+			if r.URL.Query().Get("hash") == "unreg" {
+				w.Write([]byte(`[{"url":"http://t","status":4,"msg":"Unregistered torrent"}]`))
+			} else {
+				w.Write([]byte(`[{"url":"http://t","status":2}]`))
+			}
+		case "/api/v2/torrents/tags":
+			w.Write([]byte(`["keep","old","unused"]`))
+		case "/api/v2/torrents/delete":
+			r.ParseForm()
+			if r.PostForm.Get("deleteFiles") != "false" {
+				t.Errorf("expected deleteFiles=false, got %s", r.PostForm.Get("deleteFiles"))
+			}
+			removed = append(removed, r.PostForm.Get("hashes"))
+		case "/api/v2/torrents/deleteTags":
+			r.ParseForm()
+			deletedTags = append(deletedTags, r.PostForm.Get("tags"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	cleaner := NewCleaner(client, CleanerConfig{
+		ErroredOlderThan:   7 * 24 * time.Hour,
+		RemoveUnregistered: true,
+		RemoveUnusedTags:   true,
+	})
+	cleaner.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	report, err := cleaner.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var hashes []InfoHash
+	for _, torrent := range report.Torrents {
+		hashes = append(hashes, torrent.Hash)
+	}
+	if !reflect.DeepEqual(hashes, []InfoHash{"errored", "unreg"}) {
+		t.Errorf("expected [errored unreg], got %v", hashes)
+	}
+	if report.Torrents[0].Reason != "added more than 168h0m0s ago and in state error" {
+		t.Errorf("unexpected reason %q", report.Torrents[0].Reason)
+	}
+	if report.Torrents[1].Reason != "unregistered: Unregistered torrent" {
+		t.Errorf("unexpected reason %q", report.Torrents[1].Reason)
+	}
+	// "old" is only carried by a torrent that is going to be removed
+	if !reflect.DeepEqual(report.Tags, []string{"old", "unused"}) {
+		t.Errorf("expected [old unused], got %v", report.Tags)
+	}
+	if len(removed) != 0 || len(deletedTags) != 0 {
+		t.Fatalf("Plan must not modify anything")
+	}
+
+	if err := cleaner.Execute(context.Background(), report); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(removed, []string{"errored", "unreg"}) {
+		t.Errorf("expected removals [errored unreg], got %v", removed)
+	}
+	if !reflect.DeepEqual(deletedTags, []string{"old", "unused"}) {
+		t.Errorf("expected deleted tags [old unused], got %v", deletedTags)
+	}
+}
+
+// fakeTorrents is a TorrentsAPI serving a fixed torrent list and recording removals, any other call panics
+type fakeTorrents struct {
+	TorrentsAPI
+	torrents []TorrentInfo
+	removed  []string
+}
+
+func (f *fakeTorrents) TorrentsInfo(params ...*TorrentsInfoParams) ([]TorrentInfo, error) {
+	return f.torrents, nil
+}
+
+func (f *fakeTorrents) TorrentsRemove(hashes string, deleteFiles bool) error {
+	f.removed = append(f.removed, hashes)
+	return nil
+}
+
+func TestCleaner_FakeAPI(t *testing.T) {
+	fake := &fakeTorrents{torrents: []TorrentInfo{
+		{Hash: "errored", State: "error", AddedOn: 1000},
+		{Hash: "healthy", State: "uploading", AddedOn: 1000},
+		{Hash: "unknown", State: "error"}, // no addition time, not treated as old
+	}}
+	cleaner := NewCleaner(fake, CleanerConfig{ErroredOlderThan: time.Hour})
+	cleaner.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	report, err := cleaner.Plan(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := cleaner.Execute(context.Background(), report); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(fake.removed, []string{"errored"}) {
+		t.Errorf("expected errored to be removed, got %v", fake.removed)
+	}
+}
@@ -2,52 +2,87 @@ package qbittorrent
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 type InfoHash string
 
 // Client is used to interact with the qBittorrent API
 type Client struct {
-	username string
-	password string
-	client   *http.Client
-	baseURL  string
-	sid      string // store the SID cookie
-	mu       sync.RWMutex
+	username  string
+	password  string
+	client    *http.Client
+	baseURL   string
+	sid       string // store the SID cookie
+	sidName   string // name of the session cookie, "SID" unless the server uses another one
+	noAuth    bool   // never log in, for servers that bypass authentication
+	loggedIn  bool   // a login succeeded, guarded by mu
+	lazyLogin bool   // log in on the first request instead of in the constructor
+	mu        sync.RWMutex
+
+	sessionRefresh bool          // log in again before the session expires, see WithSessionRefresh
+	sessionMargin  time.Duration // how long before the expiry to log in again
+	sessionTimeout time.Duration // the server's session timeout, 0 until known, guarded by mu
+	lastActivity   time.Time     // time of the last request the session was used for, guarded by mu
+
+	maxResponseSize int64 // limit of buffered response bodies, 0 for DefaultMaxResponseSize, negative for none
+
+	timeout          time.Duration            // default per-request timeout, 0 means none
+	endpointTimeouts map[string]time.Duration // per-endpoint overrides of timeout
+
+	stateNormalization StateNormalization // how states renamed in qBittorrent 5.x are reported
+	apiVersion         *APIVersion        // cached Web API version, nil until fetched
+	strictDecoding     bool               // fail on JSON fields of unexpected types, see WithStrictDecoding
+
+	correlationID     string // sent with every request, see WithCorrelationID
+	correlationHeader string // header of the correlation ID, "" for DefaultCorrelationIDHeader
+
+	debugLog *log.Logger     // logs every request when set, see WithDebugLogger
+	retry    RetryPolicy     // zero value: no retries
+	limiter  *rateLimiter    // nil: no rate limit
+	breaker  *circuitBreaker // nil: no circuit breaker, see WithCircuitBreaker
+
+	cache   *responseCache // nil: responses are not cached, see WithCache
+	flights *flightGroup   // nil: concurrent GET requests are not coalesced, see WithRequestCoalescing
 }
 
 // TorrentInfo represents the structured information of a torrent from the qBittorrent API
 type TorrentInfo struct {
-	AddedOn            int64    `json:"added_on"`
+	AddedOn            int64    `json:"added_on"` // unix seconds, see AddedAt
 	AmountLeft         int64    `json:"amount_left"`
 	AutoTMM            bool     `json:"auto_tmm"`
 	Availability       float64  `json:"availability"`
 	Category           string   `json:"category"`
 	Completed          int64    `json:"completed"`
-	CompletionOn       int64    `json:"completion_on"`
+	CompletionOn       int64    `json:"completion_on"` // unix seconds, see CompletedAt
 	ContentPath        string   `json:"content_path"`
 	DLLimit            int64    `json:"dl_limit"`
 	DLSpeed            int64    `json:"dlspeed"`
 	Downloaded         int64    `json:"downloaded"`
 	DownloadedSession  int64    `json:"downloaded_session"`
-	ETA                int64    `json:"eta"`
+	ETA                int64    `json:"eta"` // seconds, see ETADuration
 	FirstLastPiecePrio bool     `json:"f_l_piece_prio"`
 	ForceStart         bool     `json:"force_start"`
 	Hash               InfoHash `json:"hash"`
+	InfohashV1         InfoHash `json:"infohash_v1"` // empty for v2 only torrents
+	InfohashV2         InfoHash `json:"infohash_v2"` // empty for v1 only torrents
 	IsPrivate          bool     `json:"isPrivate"`
-	LastActivity       int64    `json:"last_activity"`
+	LastActivity       int64    `json:"last_activity"` // unix seconds, see LastActivityAt
 	MagnetURI          string   `json:"magnet_uri"`
 	MaxRatio           float64  `json:"max_ratio"`
-	MaxSeedingTime     int64    `json:"max_seeding_time"`
+	MaxSeedingTime     int64    `json:"max_seeding_time"` // minutes, see MaxSeedingDuration
 	Name               string   `json:"name"`
 	NumComplete        int64    `json:"num_complete"`
 	NumIncomplete      int64    `json:"num_incomplete"`
@@ -58,28 +93,33 @@ type TorrentInfo struct {
 	Ratio              float64  `json:"ratio"`
 	RatioLimit         float64  `json:"ratio_limit"`
 	SavePath           string   `json:"save_path"`
-	SeedingTime        int64    `json:"seeding_time"`
+	SeedingTime        int64    `json:"seeding_time"` // seconds, see SeedingDuration
 	SeedingTimeLimit   int64    `json:"seeding_time_limit"`
-	SeenComplete       int64    `json:"seen_complete"`
+	SeenComplete       int64    `json:"seen_complete"` // unix seconds, see SeenCompleteAt
 	SequentialDownload bool     `json:"seq_dl"`
 	Size               int64    `json:"size"`
 	State              string   `json:"state"`
 	SuperSeeding       bool     `json:"super_seeding"`
 	Tags               []string `json:"-"`
-	TimeActive         int64    `json:"time_active"`
+	TimeActive         int64    `json:"time_active"` // seconds, see ActiveDuration
 	TotalSize          int64    `json:"total_size"`
 	Tracker            string   `json:"tracker"`
 	UpLimit            int64    `json:"up_limit"`
 	Uploaded           int64    `json:"uploaded"`
 	UploadedSession    int64    `json:"uploaded_session"`
 	UpSpeed            int64    `json:"upspeed"`
+
+	InactiveSeedingTimeLimit int64 `json:"inactive_seeding_time_limit"` // minutes, qBittorrent 4.6 and newer
+
+	Extras Extras `json:"-"` // fields unknown to the library, nil if there are none
 }
 
-// UnmarshalJSON custom unmarshaller for TorrentInfo to handle Tags
+// UnmarshalJSON custom unmarshaller for TorrentInfo to handle Tags.
+// Fields missing from data are left untouched, so partial sync updates can be applied onto a previous value.
 func (t *TorrentInfo) UnmarshalJSON(data []byte) error {
 	type Alias TorrentInfo
 	aux := &struct {
-		RawTags string `json:"tags"`
+		RawTags *string `json:"tags"`
 		*Alias
 	}{
 		Alias: (*Alias)(t),
@@ -87,21 +127,54 @@ func (t *TorrentInfo) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	if aux.RawTags == "" {
+	switch {
+	case aux.RawTags != nil:
+		t.Tags = splitTags(*aux.RawTags)
+	case t.Tags == nil:
 		t.Tags = []string{}
-	} else {
-		t.Tags = strings.Split(aux.RawTags, ",")
 	}
+	extras, err := collectExtras(data, torrentInfoFields(), t.Extras, "tags")
+	if err != nil {
+		return err
+	}
+	t.Extras = extras
 	return nil
 }
 
 // TrackerInfo represents a tracker info for a torrent
 type TrackerInfo struct {
-	URL      string `json:"url"`
-	Status   int    `json:"status"`
-	Tier     int    `json:"tier"`
-	NumPeers int    `json:"num_peers"`
-	Msg      string `json:"msg"`
+	URL      string        `json:"url"`
+	Status   TrackerStatus `json:"status"`
+	Tier     int           `json:"tier"`
+	NumPeers int           `json:"num_peers"`
+	Msg      string        `json:"msg"`
+}
+
+// TrackerStatus is the announce status of a tracker
+type TrackerStatus int
+
+const (
+	TrackerDisabled     TrackerStatus = iota // used for DHT, PeX and LSD entries
+	TrackerNotContacted                      // not contacted yet
+	TrackerWorking                           // contacted and working
+	TrackerUpdating                          // announce in progress
+	TrackerNotWorking                        // contacted but the last announce failed
+)
+
+func (s TrackerStatus) String() string {
+	switch s {
+	case TrackerDisabled:
+		return "disabled"
+	case TrackerNotContacted:
+		return "not contacted"
+	case TrackerWorking:
+		return "working"
+	case TrackerUpdating:
+		return "updating"
+	case TrackerNotWorking:
+		return "not working"
+	}
+	return fmt.Sprintf("TrackerStatus(%d)", int(s))
 }
 
 type Category map[string]interface{} // no idea what this should be, category=CategoryName&savePath=/path/to/dir
@@ -123,31 +196,48 @@ type MainData struct {
 }
 
 type ServerState struct {
-	AllTimeDL            int64  `json:"alltime_dl"`
-	AllTimeUL            int64  `json:"alltime_ul"`
-	AverageTimeQueue     int    `json:"average_time_queue"`
-	ConnectionStatus     string `json:"connection_status"`
-	DHTNodes             int    `json:"dht_nodes"`
-	DLInfoData           int64  `json:"dl_info_data"`
-	DLInfoSpeed          int    `json:"dl_info_speed"`
-	DLRateLimit          int    `json:"dl_rate_limit"`
-	FreeSpaceOnDisk      int64  `json:"free_space_on_disk"`
-	GlobalRatio          string `json:"global_ratio"`
-	QueuedIOJobs         int    `json:"queued_io_jobs"`
-	Queueing             bool   `json:"queueing"`
-	ReadCacheHits        string `json:"read_cache_hits"`
-	ReadCacheOverload    string `json:"read_cache_overload"`
-	RefreshInterval      int    `json:"refresh_interval"`
-	TotalBuffersSize     int64  `json:"total_buffers_size"`
-	TotalPeerConnections int    `json:"total_peer_connections"`
-	TotalQueuedSize      int64  `json:"total_queued_size"`
-	TotalWastedSession   int64  `json:"total_wasted_session"`
-	UpInfoData           int64  `json:"up_info_data"`
-	UpInfoSpeed          int    `json:"up_info_speed"`
-	UpRateLimit          int    `json:"up_rate_limit"`
-	UseAltSpeedLimits    bool   `json:"use_alt_speed_limits"`
-	UseSubcategories     bool   `json:"use_subcategories"`
-	WriteCacheOverload   string `json:"write_cache_overload"`
+	AllTimeDL            int64            `json:"alltime_dl"`
+	AllTimeUL            int64            `json:"alltime_ul"`
+	AverageTimeQueue     int              `json:"average_time_queue"`
+	ConnectionStatus     ConnectionStatus `json:"connection_status"`
+	DHTNodes             int              `json:"dht_nodes"`
+	DLInfoData           int64            `json:"dl_info_data"`
+	DLInfoSpeed          int              `json:"dl_info_speed"`
+	DLRateLimit          int              `json:"dl_rate_limit"`
+	FreeSpaceOnDisk      int64            `json:"free_space_on_disk"`
+	GlobalRatio          string           `json:"global_ratio"`
+	QueuedIOJobs         int              `json:"queued_io_jobs"`
+	Queueing             bool             `json:"queueing"`
+	ReadCacheHits        string           `json:"read_cache_hits"`
+	ReadCacheOverload    string           `json:"read_cache_overload"`
+	RefreshInterval      int              `json:"refresh_interval"`
+	TotalBuffersSize     int64            `json:"total_buffers_size"`
+	TotalPeerConnections int              `json:"total_peer_connections"`
+	TotalQueuedSize      int64            `json:"total_queued_size"`
+	TotalWastedSession   int64            `json:"total_wasted_session"`
+	UpInfoData           int64            `json:"up_info_data"`
+	UpInfoSpeed          int              `json:"up_info_speed"`
+	UpRateLimit          int              `json:"up_rate_limit"`
+	UseAltSpeedLimits    bool             `json:"use_alt_speed_limits"`
+	UseSubcategories     bool             `json:"use_subcategories"`
+	WriteCacheOverload   string           `json:"write_cache_overload"`
+
+	Extras Extras `json:"-"` // fields unknown to the library, nil if there are none
+}
+
+// UnmarshalJSON decodes a server state, collecting unknown fields into Extras.
+// Fields missing from data are left untouched, so partial sync updates can be applied onto a previous value.
+func (s *ServerState) UnmarshalJSON(data []byte) error {
+	type Alias ServerState
+	if err := json.Unmarshal(data, (*Alias)(s)); err != nil {
+		return err
+	}
+	extras, err := collectExtras(data, serverStateFields(), s.Extras)
+	if err != nil {
+		return err
+	}
+	s.Extras = extras
+	return nil
 }
 
 type TorrentPeer struct {
@@ -178,26 +268,36 @@ type TorrentPeers struct {
 }
 
 // NewClient initializes a new qBittorrent client.
-// If httpClient is nil, http.DefaultClient is used.
+// If httpClient is nil, a client owning a transport from NewTransport is used.
 func NewClient(username, password, addr, port string, httpClient ...*http.Client) (*Client, error) {
-	// Use the provided http.Client if given, otherwise use http.DefaultClient
-	client := http.DefaultClient
-	if len(httpClient) > 0 && httpClient[0] != nil {
-		client = httpClient[0]
+	var opts []ClientOption
+	if len(httpClient) > 0 {
+		opts = append(opts, WithHTTPClient(httpClient[0]))
 	}
+	return NewClientWithOptions(username, password, addr, port, opts...)
+}
 
-	// Create and return the Client instance
+// NewClientWithOptions initializes a new qBittorrent client configured with the given options.
+func NewClientWithOptions(username, password, addr, port string, opts ...ClientOption) (*Client, error) {
+	// Create the Client instance with its own transport, so connections are not shared with http.DefaultClient
 	qbClient := &Client{
 		username: username,
 		password: password,
-		client:   client,
+		client:   &http.Client{Transport: NewTransport(TransportSettings{})},
 		baseURL:  fmt.Sprintf("http://%s:%s", addr, port),
 	}
 
-	// Authenticate if username and password are provided
-	if username != "" && password != "" {
+	for _, opt := range opts {
+		opt(qbClient)
+	}
+	if _, err := parseBaseURL(qbClient.baseURL); err != nil {
+		return nil, err
+	}
+
+	// Authenticate if username and password are provided, unless the login is deferred to the first request
+	if !qbClient.lazyLogin && !qbClient.noAuth && username != "" && password != "" {
 		if err := qbClient.AuthLogin(); err != nil {
-			return nil, fmt.Errorf("AuthLogin error: %v", err)
+			return nil, fmt.Errorf("AuthLogin error: %w", err)
 		}
 	}
 
@@ -206,39 +306,141 @@ func NewClient(username, password, addr, port string, httpClient ...*http.Client
 
 // AuthLogin logs in to the qBittorrent Web API
 func (c *Client) AuthLogin() error {
+	return c.authLogin(context.Background())
+}
+
+// authLogin logs in, bounded by ctx
+func (c *Client) authLogin(ctx context.Context) error {
 	data := url.Values{}
 	data.Set("username", c.username)
 	data.Set("password", c.password)
 
-	resp, err := c.doPostResponse("/api/v2/auth/login", strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
+	resp, err := c.doRequestContext(ctx, "POST", "/api/v2/auth/login", strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
 	if err != nil {
-		return fmt.Errorf("AuthLogin error: %v", err)
-	} else if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("AuthLogin error (%d): %s", resp.StatusCode, string(respBody))
+		return fmt.Errorf("AuthLogin error: %w", err)
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("AuthLogin error (%d): %s", resp.StatusCode, readErrorBody(resp))
+	}
 
-	// Extract the SID cookie from the response
+	// Extract the SID cookie from the response. Servers that bypass authentication for the client's
+	// address may not set one, in which case requests are sent without a cookie.
+	c.mu.Lock()
 	for _, cookie := range resp.Cookies() {
-		if cookie.Name == "SID" {
-			c.mu.Lock()
+		if isSessionCookie(cookie.Name) {
 			c.sid = cookie.Value
-			c.mu.Unlock()
+			c.sidName = cookie.Name
 			break
 		}
 	}
+	c.loggedIn = true
+	c.lastActivity = time.Now()
+	refresh := c.sessionRefresh && c.sessionTimeout == 0
+	c.mu.Unlock()
 
+	if refresh {
+		c.loadSessionTimeout(ctx)
+	}
 	return nil
 }
 
-// TorrentsExport retrieves the .torrent file for a given torrent hash
+// canLogin reports whether the client has credentials and is allowed to log in
+func (c *Client) canLogin() bool {
+	return !c.noAuth && c.username != ""
+}
+
+// Connect logs in, if the client has credentials, and checks that the server answers by fetching the Web
+// API version, bounded by ctx. It is meant for clients created with WithLazyLogin, e.g. by a daemon that
+// starts before qBittorrent and calls Connect until it succeeds; it may be called again to reconnect.
+func (c *Client) Connect(ctx context.Context) error {
+	if c.canLogin() {
+		if err := c.authLogin(ctx); err != nil {
+			return fmt.Errorf("Connect error: %w", err)
+		}
+	}
+
+	resp, err := c.doRequestContext(ctx, "GET", "/api/v2/app/webapiVersion", nil, "")
+	if err != nil {
+		return fmt.Errorf("Connect error: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Connect error: unexpected response code: %d, response: %s", resp.StatusCode, readErrorBody(resp))
+	}
+	respBody, err := c.readBody(resp, "/api/v2/app/webapiVersion")
+	if err != nil {
+		return fmt.Errorf("Connect error: %w", err)
+	}
+	v, err := ParseAPIVersion(strings.TrimSpace(string(respBody)))
+	if err != nil {
+		return fmt.Errorf("Connect error: %w", err)
+	}
+
+	c.mu.Lock()
+	c.apiVersion = &v
+	c.mu.Unlock()
+	return nil
+}
+
+// TorrentsExport retrieves the .torrent file for a given torrent hash.
+// See TorrentsExportTo to write it out without holding it in memory.
 func (c *Client) TorrentsExport(hash string) ([]byte, error) {
+	return c.exportBytes(context.Background(), hash)
+}
+
+// TorrentsExportMany fetches the .torrent files of hashes, four at a time. Hashes whose file could not be
+// fetched are missing from the result and reported in the *BatchError. Use Batch for other concurrency
+// settings or progress reporting.
+func (c *Client) TorrentsExportMany(ctx context.Context, hashes []InfoHash) (map[InfoHash][]byte, error) {
+	return c.Batch(ctx, hashes).ExportData()
+}
+
+// exportBytes reads the .torrent file of hash into memory
+func (c *Client) exportBytes(ctx context.Context, hash string) ([]byte, error) {
+	resp, err := c.exportResponse(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return c.readBody(resp, "/api/v2/torrents/export")
+}
+
+// TorrentsExportTo streams the .torrent file for a given torrent hash to w and returns the number of
+// bytes written. The response size limit does not apply.
+func (c *Client) TorrentsExportTo(ctx context.Context, hash string, w io.Writer) (int64, error) {
+	resp, err := c.exportResponse(ctx, hash)
+	if err != nil {
+		return 0, fmt.Errorf("TorrentsExportTo error: %w", err)
+	}
+	defer resp.Body.Close()
+	n, err := io.Copy(w, resp.Body)
+	if err != nil {
+		return n, fmt.Errorf("TorrentsExportTo error: %w", err)
+	}
+	return n, nil
+}
+
+// exportResponse requests the .torrent file of hash and returns the successful response. The endpoint is
+// documented as GET, which every version accepts; servers that only allow POST for it, as some reverse
+// proxy setups do, are retried with POST.
+func (c *Client) exportResponse(ctx context.Context, hash string) (*http.Response, error) {
 	params := url.Values{}
 	params.Set("hash", hash)
 
-	// Use the GET request helper
-	return c.doPostValues("/api/v2/torrents/export", params)
+	resp, err := c.doRequestContext(ctx, "GET", "/api/v2/torrents/export", nil, "", withQuery(params))
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		discard(resp)
+		resp, err = c.doRequestContext(ctx, "POST", "/api/v2/torrents/export", strings.NewReader(params.Encode()), "application/x-www-form-urlencoded")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.newStatusError(resp, "/api/v2/torrents/export", params)
+	}
+	return resp, nil
 }
 
 // TorrentsAdd adds a torrent to qBittorrent via Web API using multipart/form-data
@@ -248,7 +450,7 @@ func (c *Client) TorrentsAdd(torrentFile string, fileData []byte) error {
 
 	part, err := writer.CreateFormFile("torrents", torrentFile)
 	if err != nil {
-		return fmt.Errorf("CreateFormFile error: %v", err)
+		return fmt.Errorf("CreateFormFile error: %w", err)
 	}
 	if _, err := io.Copy(part, bytes.NewReader(fileData)); err != nil {
 		return fmt.Errorf("io.Copy error: %v", err)
@@ -256,12 +458,16 @@ func (c *Client) TorrentsAdd(torrentFile string, fileData []byte) error {
 
 	_ = writer.WriteField("skip_checking", "true") // Avoid recheck
 	_ = writer.WriteField("paused", "false")
+	_ = writer.WriteField("stopped", "false") // qBittorrent 5.x name of paused
 	_ = writer.WriteField("autoTMM", "false")
 	writer.Close()
 
-	_, err = c.doPost("/api/v2/torrents/add", &body, writer.FormDataContentType())
+	respData, err := c.doPost("/api/v2/torrents/add", &body, writer.FormDataContentType())
 	if err != nil {
-		return fmt.Errorf("TorrentsAdd error: %v", err)
+		return fmt.Errorf("TorrentsAdd error: %w", err)
+	}
+	if err := checkAddResponse(respData); err != nil {
+		return fmt.Errorf("TorrentsAdd error: %w", err)
 	}
 	return nil
 }
@@ -269,12 +475,22 @@ func (c *Client) TorrentsAdd(torrentFile string, fileData []byte) error {
 // These are not all the options, just the ones i need
 // documentation at: https://github.com/qbittorrent/qBittorrent/wiki/WebUI-API-(qBittorrent-4.1)#add-new-torrent
 type TorrentsAddOptions struct {
-	SkipChecking *bool
-	SavePath     *string
-	Category     *string
-	Tags         *[]string
-	StartPaused  *bool
-	AutoTMM      *bool
+	SkipChecking  *bool
+	SavePath      *string
+	Category      *string
+	Tags          *[]string
+	StartPaused   *bool
+	AutoTMM       *bool
+	SpaceMargin   *int64       // refuse to add when the torrent size plus this margin exceeds free space
+	SpaceWatcher  *SyncWatcher // source of the free space for SpaceMargin, see WithSpaceGuardWatcher
+	ContentLayout *ContentLayout
+	StopCondition *StopCondition
+
+	RatioLimit       *float64 // ShareLimitGlobal, ShareLimitUnlimited or the ratio, see WithShareLimits
+	SeedingTimeLimit *int64   // ShareLimitGlobal, ShareLimitUnlimited or minutes, see WithShareLimits
+
+	CheckDuplicates  *bool // refuse to add torrents already on the server, see WithDuplicateCheck
+	CheckContentPath *bool // refuse to add torrents colliding with the content of others, see WithContentPathCheck
 }
 
 type TorrentAddOption func(*TorrentsAddOptions)
@@ -315,53 +531,169 @@ func WithAutoTMM(autoTMM bool) TorrentAddOption {
 	}
 }
 
+// WithShareLimits sets the share limits of the added torrent as part of the add request, like
+// TorrentsSetShareLimits does for torrents already on the server
+func WithShareLimits(ratioLimit float64, seedingTimeLimit int64) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.RatioLimit = &ratioLimit
+		o.SeedingTimeLimit = &seedingTimeLimit
+	}
+}
+
+// WithSpaceGuard refuses the add with ErrInsufficientSpace when the torrent size plus margin bytes
+// exceeds the free space reported by the server. Each guarded add fetches the full sync/maindata, see
+// Client.FreeSpace; pass WithSpaceGuardWatcher to reuse the state of a SyncWatcher instead. Only
+// TorrentsAddWithOptions is guarded: the size of magnet links and URLs is unknown until the server fetched
+// their metadata, so TorrentsAddURLs adds them unchecked.
+func WithSpaceGuard(margin int64) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.SpaceMargin = &margin
+	}
+}
+
 func (c *Client) TorrentsAddWithOptions(torrentFile string, fileData []byte, opts ...TorrentAddOption) error {
+	options := &TorrentsAddOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.SpaceMargin != nil || options.checkDuplicates() || options.checkContentPath() {
+		meta, err := ParseMetainfo(fileData)
+		if err != nil {
+			return fmt.Errorf("TorrentsAdd error: %w", err)
+		}
+		if options.checkDuplicates() {
+			if err := c.checkNotExists([]InfoHash{meta.InfoHash}); err != nil {
+				return fmt.Errorf("TorrentsAdd error: %w", err)
+			}
+		}
+		if options.SpaceMargin != nil {
+			if err := c.checkFreeSpace(meta.Size, *options.SpaceMargin, options.SpaceWatcher); err != nil {
+				return fmt.Errorf("TorrentsAdd error: %w", err)
+			}
+		}
+		if options.checkContentPath() {
+			if err := c.checkContentPath(meta, options); err != nil {
+				return fmt.Errorf("TorrentsAdd error: %w", err)
+			}
+		}
+	}
+
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 
 	part, err := writer.CreateFormFile("torrents", torrentFile)
 	if err != nil {
-		return fmt.Errorf("CreateFormFile error: %v", err)
+		return fmt.Errorf("CreateFormFile error: %w", err)
 	}
 	if _, err := io.Copy(part, bytes.NewReader(fileData)); err != nil {
 		return fmt.Errorf("io.Copy error: %v", err)
 	}
 
-	options := &TorrentsAddOptions{}
+	options.writeFields(writer)
+	writer.Close()
 
-	for _, opt := range opts {
-		opt(options)
+	respData, err := c.doPost("/api/v2/torrents/add", &body, writer.FormDataContentType())
+	if err != nil {
+		return fmt.Errorf("TorrentsAdd error: %w", err)
+	}
+	if err := checkAddResponse(respData); err != nil {
+		return fmt.Errorf("TorrentsAdd error: %w", err)
+	}
+	return nil
+}
+
+// checkDuplicates reports whether WithDuplicateCheck is enabled
+func (o *TorrentsAddOptions) checkDuplicates() bool {
+	return o.CheckDuplicates != nil && *o.CheckDuplicates
+}
+
+// writeFields writes the options that are set as multipart form fields of an add request
+func (o *TorrentsAddOptions) writeFields(writer *multipart.Writer) {
+	if o.SkipChecking != nil {
+		_ = writer.WriteField("skip_checking", strconv.FormatBool(*o.SkipChecking))
+	}
+
+	if o.SavePath != nil {
+		_ = writer.WriteField("savepath", *o.SavePath)
 	}
 
-	if options.SkipChecking != nil {
-		_ = writer.WriteField("skip_checking", strconv.FormatBool(*options.SkipChecking))
+	if o.Category != nil {
+		_ = writer.WriteField("category", *o.Category)
 	}
 
-	if options.SavePath != nil {
-		_ = writer.WriteField("savepath", *options.SavePath)
+	if o.Tags != nil {
+		_ = writer.WriteField("tags", strings.Join(*o.Tags, ","))
 	}
 
-	if options.Category != nil {
-		_ = writer.WriteField("category", *options.Category)
+	if o.StartPaused != nil {
+		_ = writer.WriteField("paused", strconv.FormatBool(*o.StartPaused))
+		_ = writer.WriteField("stopped", strconv.FormatBool(*o.StartPaused)) // qBittorrent 5.x name of paused
 	}
 
-	if options.Tags != nil {
-		_ = writer.WriteField("tags", strings.Join(*options.Tags, ","))
+	if o.AutoTMM != nil {
+		_ = writer.WriteField("autoTMM", strconv.FormatBool(*o.AutoTMM))
 	}
 
-	if options.StartPaused != nil {
-		_ = writer.WriteField("paused", strconv.FormatBool(*options.StartPaused))
+	if o.ContentLayout != nil {
+		_ = writer.WriteField("contentLayout", string(*o.ContentLayout))
+		// Servers before Web API 2.7.0 only know whether to create the root folder
+		switch *o.ContentLayout {
+		case ContentLayoutSubfolder:
+			_ = writer.WriteField("root_folder", "true")
+		case ContentLayoutNoSubfolder:
+			_ = writer.WriteField("root_folder", "false")
+		}
+	}
+
+	if o.StopCondition != nil {
+		_ = writer.WriteField("stopCondition", string(*o.StopCondition))
+	}
+
+	if o.RatioLimit != nil {
+		_ = writer.WriteField("ratioLimit", strconv.FormatFloat(*o.RatioLimit, 'f', -1, 64))
 	}
 
-	if options.AutoTMM != nil {
-		_ = writer.WriteField("autoTMM", strconv.FormatBool(*options.AutoTMM))
+	if o.SeedingTimeLimit != nil {
+		_ = writer.WriteField("seedingTimeLimit", strconv.FormatInt(*o.SeedingTimeLimit, 10))
 	}
+}
+
+// TorrentsAddURLs adds torrents from URLs or magnet links
+func (c *Client) TorrentsAddURLs(urls []string, opts ...TorrentAddOption) error {
+	options := &TorrentsAddOptions{}
 
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.checkDuplicates() {
+		var hashes []InfoHash
+		for _, link := range urls {
+			if hash, ok := magnetInfoHash(link); ok {
+				hashes = append(hashes, hash)
+			}
+		}
+		if err := c.checkNotExists(hashes); err != nil {
+			return fmt.Errorf("TorrentsAddURLs error: %w", err)
+		}
+	}
+	// WithSpaceGuard and WithContentPathCheck are skipped, they need the metainfo a link does not carry
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	_ = writer.WriteField("urls", strings.Join(urls, "\n"))
+	options.writeFields(writer)
 	writer.Close()
 
-	_, err = c.doPost("/api/v2/torrents/add", &body, writer.FormDataContentType())
+	respData, err := c.doPost("/api/v2/torrents/add", &body, writer.FormDataContentType())
 	if err != nil {
-		return fmt.Errorf("TorrentsAdd error: %v", err)
+		return fmt.Errorf("TorrentsAddURLs error: %w", err)
+	}
+	if err := checkAddResponse(respData); err != nil {
+		return fmt.Errorf("TorrentsAddURLs error: %w", err)
 	}
 	return nil
 }
@@ -374,7 +706,7 @@ func (c *Client) TorrentsDelete(infohash string) error {
 
 	_, err := c.doPostValues("/api/v2/torrents/delete", data)
 	if err != nil {
-		return fmt.Errorf("TorrentsDelete error: %v", err)
+		return fmt.Errorf("TorrentsDelete error: %w", err)
 	}
 	return nil
 }
@@ -387,14 +719,17 @@ func (c *Client) SetForceStart(hash string, value bool) error {
 
 	_, err := c.doPostValues("/api/v2/torrents/setForceStart", data)
 	if err != nil {
-		return fmt.Errorf("SetForceStart error: %v", err)
+		return fmt.Errorf("SetForceStart error: %w", err)
 	}
 	return nil
 }
 
-// TorrentsDownload retrieves the torrent file by its hash from the qBittorrent server
+// TorrentsDownload retrieves the torrent file by its hash from the qBittorrent server.
+//
+// Deprecated: the Web API has no torrents/file endpoint, TorrentsDownload now calls TorrentsExport. Use
+// TorrentsExport, or TorrentsExportMany for several torrents.
 func (c *Client) TorrentsDownload(infohash string) ([]byte, error) {
-	return c.doGet("/api/v2/torrents/file", url.Values{"hashes": {infohash}})
+	return c.TorrentsExport(infohash)
 }
 
 // TorrentsInfoParams holds the optional parameters for the TorrentsInfo method
@@ -411,11 +746,42 @@ type TorrentsInfoParams struct {
 
 // TorrentsInfo retrieves a list of all torrents from the qBittorrent server
 func (c *Client) TorrentsInfo(params ...*TorrentsInfoParams) ([]TorrentInfo, error) {
+	query, err := c.torrentsInfoQuery(params...)
+	if err != nil {
+		return nil, err
+	}
+
+	respData, err := c.doGet("/api/v2/torrents/info", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var torrents []TorrentInfo
+	if err := c.decodeJSON(respData, &torrents); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	for i := range torrents {
+		torrents[i].State = c.stateNormalization.normalize(torrents[i].State)
+	}
+
+	return torrents, nil
+}
+
+// torrentsInfoQuery builds the query of a torrents/info request
+func (c *Client) torrentsInfoQuery(params ...*TorrentsInfoParams) (url.Values, error) {
 	var query url.Values
 	if len(params) > 0 && params[0] != nil {
 		query = url.Values{}
-		if params[0].Filter != "" {
-			query.Set("filter", params[0].Filter)
+		if filter := params[0].Filter; filter != "" {
+			// "paused" and "resumed" became "stopped" and "running" in qBittorrent 5.x
+			if needsFilterTranslation(filter) {
+				startStop, err := c.usesStartStop()
+				if err != nil {
+					return nil, err
+				}
+				filter = translateFilter(filter, startStop)
+			}
+			query.Set("filter", filter)
 		}
 		if params[0].Category != "" {
 			query.Set("category", params[0].Category)
@@ -439,18 +805,7 @@ func (c *Client) TorrentsInfo(params ...*TorrentsInfoParams) ([]TorrentInfo, err
 			query.Set("hashes", strings.Join(params[0].Hashes, "|"))
 		}
 	}
-
-	respData, err := c.doGet("/api/v2/torrents/info", query)
-	if err != nil {
-		return nil, err
-	}
-
-	var torrents []TorrentInfo
-	if err := json.Unmarshal(respData, &torrents); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %v", err)
-	}
-
-	return torrents, nil
+	return query, nil
 }
 
 // TorrentsTrackers retrieves the tracker info for a given torrent hash
@@ -460,181 +815,321 @@ func (c *Client) TorrentsTrackers(hash string) ([]TrackerInfo, error) {
 
 	respData, err := c.doGet("/api/v2/torrents/trackers", params)
 	if err != nil {
-		return nil, fmt.Errorf("TorrentsTrackers error: %v", err)
+		return nil, fmt.Errorf("TorrentsTrackers error: %w", err)
 	}
 
 	var trackers []TrackerInfo
-	if err := json.Unmarshal(respData, &trackers); err != nil {
+	if err := c.decodeJSON(respData, &trackers); err != nil {
 		return nil, fmt.Errorf("failed to decode trackers response: %v", err)
 	}
 
 	return trackers, nil
 }
 
-// TorrentsAddTags adds tags to the specified torrents
-func (c *Client) TorrentsAddTags(hashes, tags string) error {
+// TorrentsAddTags adds tags to the specified torrents, creating tags that do not exist yet.
+// Multiple hashes are separated by "|". Tags must not be empty or contain commas.
+func (c *Client) TorrentsAddTags(hashes string, tags []string) error {
+	joined, err := joinTags(tags)
+	if err != nil {
+		return fmt.Errorf("AddTags error: %w", err)
+	}
 	data := url.Values{}
 	data.Set("hashes", hashes)
-	data.Set("tags", tags)
+	data.Set("tags", joined)
 
-	_, err := c.doPostValues("/api/v2/torrents/addTags", data)
+	_, err = c.doPostValues("/api/v2/torrents/addTags", data)
 	if err != nil {
-		return fmt.Errorf("AddTags error: %v", err)
+		return fmt.Errorf("AddTags error: %w", err)
 	}
 	return nil
 }
 
-// TorrentsRemoveTags removes tags from the specified torrents
-func (c *Client) TorrentsRemoveTags(hashes, tags string) error {
+// TorrentsRemoveTags removes tags from the specified torrents.
+// Multiple hashes are separated by "|". Tags must not be empty or contain commas.
+func (c *Client) TorrentsRemoveTags(hashes string, tags []string) error {
+	joined, err := joinTags(tags)
+	if err != nil {
+		return fmt.Errorf("RemoveTags error: %w", err)
+	}
 	data := url.Values{}
 	data.Set("hashes", hashes)
-	data.Set("tags", tags)
+	data.Set("tags", joined)
 
-	_, err := c.doPostValues("/api/v2/torrents/removeTags", data)
+	_, err = c.doPostValues("/api/v2/torrents/removeTags", data)
 	if err != nil {
-		return fmt.Errorf("RemoveTags error: %v", err)
+		return fmt.Errorf("RemoveTags error: %w", err)
 	}
 	return nil
 }
 
-// TorrentsGetTags retrieves the tags for the given torrent hashes
-func (c *Client) TorrentsGetTags(hashes string) ([]string, error) {
+// TorrentsGetTags retrieves the tags of each of the given torrents. Multiple hashes are separated by "|".
+// Torrents without tags map to an empty slice; use UnionTags for the tags used by any of them.
+func (c *Client) TorrentsGetTags(hashes string) (map[InfoHash][]string, error) {
 	params := &TorrentsInfoParams{
 		Hashes: []string{hashes},
 	}
 
 	torrents, err := c.TorrentsInfo(params)
 	if err != nil {
-		return nil, fmt.Errorf("TorrentsGetTags error: %v", err)
+		return nil, fmt.Errorf("TorrentsGetTags error: %w", err)
 	}
 
-	tagSet := make(map[string]struct{})
+	tags := make(map[InfoHash][]string, len(torrents))
 	for _, torrent := range torrents {
-		for _, tag := range torrent.Tags {
+		tags[torrent.Hash] = torrent.Tags
+	}
+	return tags, nil
+}
+
+// UnionTags returns the sorted set of tags appearing in any of the per-torrent tag lists
+func UnionTags(tags map[InfoHash][]string) []string {
+	tagSet := make(map[string]struct{})
+	for _, list := range tags {
+		for _, tag := range list {
 			tagSet[tag] = struct{}{}
 		}
 	}
 
-	var tags []string
+	union := make([]string, 0, len(tagSet))
 	for tag := range tagSet {
-		tags = append(tags, tag)
+		union = append(union, tag)
 	}
-
-	return tags, nil
+	sort.Strings(union)
+	return union
 }
 
 // TorrentsGetAllTags retrieves all tags from qBittorrent
 func (c *Client) TorrentsGetAllTags() ([]string, error) {
 	respData, err := c.doGet("/api/v2/torrents/tags", nil)
 	if err != nil {
-		return nil, fmt.Errorf("GetAllTags error: %v", err)
+		return nil, fmt.Errorf("GetAllTags error: %w", err)
 	}
 
 	var tags []string
-	if err := json.Unmarshal(respData, &tags); err != nil {
+	if err := c.decodeJSON(respData, &tags); err != nil {
 		return nil, fmt.Errorf("failed to decode tags response: %v", err)
 	}
 
 	return tags, nil
 }
 
-// TorrentsCreateTags creates new tags in qBittorrent
-func (c *Client) TorrentsCreateTags(tags string) error {
+// TorrentsCreateTags creates new tags in qBittorrent. Tags must not be empty or contain commas.
+func (c *Client) TorrentsCreateTags(tags []string) error {
+	joined, err := joinTags(tags)
+	if err != nil {
+		return fmt.Errorf("CreateTags error: %w", err)
+	}
 	data := url.Values{}
-	data.Set("tags", tags)
+	data.Set("tags", joined)
 
-	_, err := c.doPostValues("/api/v2/torrents/createTags", data)
+	_, err = c.doPostValues("/api/v2/torrents/createTags", data)
 	if err != nil {
-		return fmt.Errorf("CreateTags error: %v", err)
+		return fmt.Errorf("CreateTags error: %w", err)
 	}
 	return nil
 }
 
-// TorrentsDeleteTags deletes tags from qBittorrent
-func (c *Client) TorrentsDeleteTags(tags string) error {
+// TorrentsDeleteTags deletes tags from qBittorrent, removing them from all torrents.
+// Tags must not be empty or contain commas.
+func (c *Client) TorrentsDeleteTags(tags []string) error {
+	joined, err := joinTags(tags)
+	if err != nil {
+		return fmt.Errorf("DeleteTags error: %w", err)
+	}
 	data := url.Values{}
-	data.Set("tags", tags)
+	data.Set("tags", joined)
 
-	_, err := c.doPostValues("/api/v2/torrents/deleteTags", data)
+	_, err = c.doPostValues("/api/v2/torrents/deleteTags", data)
 	if err != nil {
-		return fmt.Errorf("DeleteTags error: %v", err)
+		return fmt.Errorf("DeleteTags error: %w", err)
 	}
 	return nil
 }
 
-// doPostResponse POSTs to qBittorrent and returns the HTTP response
+// joinTags validates tags and joins them into the comma separated form the API expects.
+// The API has no way to escape commas, so tags containing one are rejected rather than split.
+func joinTags(tags []string) (string, error) {
+	if len(tags) == 0 {
+		return "", fmt.Errorf("%w: no tags given", ErrInvalidTag)
+	}
+	trimmed := make([]string, len(tags))
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			return "", fmt.Errorf("%w: empty tag", ErrInvalidTag)
+		}
+		if strings.Contains(tag, ",") {
+			return "", fmt.Errorf("%w: %q contains a comma", ErrInvalidTag, tag)
+		}
+		trimmed[i] = tag
+	}
+	return strings.Join(trimmed, ","), nil
+}
+
+// splitTags splits a comma separated tag list, trimming the spaces the API puts after commas
+func splitTags(tags string) []string {
+	if strings.TrimSpace(tags) == "" {
+		return []string{}
+	}
+	out := make([]string, 0, strings.Count(tags, ",")+1)
+	for tag, rest, more := strings.Cut(tags, ","); ; tag, rest, more = strings.Cut(rest, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			out = append(out, tag)
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// doPostResponse POSTs to qBittorrent and returns the HTTP response. It empties the response cache and
+// forgets the GET requests in flight, as any POST may change what they return.
 func (c *Client) doPostResponse(endpoint string, body io.Reader, contentType string) (*http.Response, error) {
+	if c.cache != nil {
+		defer c.cache.invalidate()
+	}
+	if c.flights != nil {
+		defer c.flights.forget()
+	}
 	return c.doRequest("POST", endpoint, body, contentType)
 }
 
 // doPost makes POSTs to qBittorrent and returns the response body
 func (c *Client) doPost(endpoint string, body io.Reader, contentType string) ([]byte, error) {
+	return c.doPostParams(endpoint, body, contentType, nil)
+}
+
+// doPostValues POSTs to qBittorrent with url.Values and returns the response body
+func (c *Client) doPostValues(endpoint string, data url.Values) ([]byte, error) {
+	return c.doPostParams(endpoint, strings.NewReader(data.Encode()), "application/x-www-form-urlencoded", data)
+}
+
+// doPostParams is doPost with the parameters encoded in body, if known, for the StatusError of a failure
+func (c *Client) doPostParams(endpoint string, body io.Reader, contentType string, params url.Values) ([]byte, error) {
 	resp, err := c.doPostResponse(endpoint, body, contentType)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	} else if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("POST error (%d): %s", resp.StatusCode, string(respBody))
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newStatusError(resp, endpoint, params)
 	}
-	return respBody, nil
+	return c.readBody(resp, endpoint)
 }
 
-// doPostValues POSTs to qBittorrent with url.Values and returns the response body
-func (c *Client) doPostValues(endpoint string, data url.Values) ([]byte, error) {
-	return c.doPost(endpoint, strings.NewReader(data.Encode()), "application/x-www-form-urlencoded")
+// doGet is a helper method for making GET requests to the qBittorrent API with query parameters. Responses
+// of cacheable endpoints are served from the cache when WithCache is used, and concurrent identical
+// requests are coalesced with WithRequestCoalescing.
+func (c *Client) doGet(endpoint string, query url.Values) ([]byte, error) {
+	return c.doGetContext(context.Background(), endpoint, query)
 }
 
-// doGet is a helper method for making GET requests to the qBittorrent API with query parameters
-func (c *Client) doGet(endpoint string, query url.Values) ([]byte, error) {
-	resp, err := c.doRequest("GET", endpoint, nil, "", withQuery(query))
+// doGetContext is doGet bounded by ctx. Requests with a cancellable ctx are not coalesced, so that one
+// caller giving up does not fail the others.
+func (c *Client) doGetContext(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	var key string
+	var gen uint64
+	if c.cache != nil {
+		if key = cacheKey(endpoint, query); key != "" {
+			data, g, ok := c.cache.get(key)
+			if ok {
+				return data, nil
+			}
+			gen = g
+		}
+	}
+
+	var responseData []byte
+	var err error
+	if c.flights != nil && ctx.Done() == nil {
+		responseData, err = c.flights.do(flightKey(endpoint, query), func() ([]byte, error) {
+			return c.fetch(ctx, endpoint, query)
+		})
+	} else {
+		responseData, err = c.fetch(ctx, endpoint, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		c.cache.put(key, gen, responseData)
+	}
+	return responseData, nil
+}
+
+// fetch sends a GET request and returns the response body
+func (c *Client) fetch(ctx context.Context, endpoint string, query url.Values) ([]byte, error) {
+	resp, err := c.doRequestContext(ctx, "GET", endpoint, nil, "", withQuery(query))
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected response code: %d, response: %s", resp.StatusCode, string(respBody))
+		return nil, c.newStatusError(resp, endpoint, query)
 	}
 
-	responseData, err := io.ReadAll(resp.Body)
+	responseData, err := c.readBody(resp, endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("ReadAll error: %v", err)
+		return nil, fmt.Errorf("ReadAll error: %w", err)
 	}
 	return responseData, nil
 }
 
 // doRequest is a helper function to handle HTTP requests with optional query parameters
 func (c *Client) doRequest(method, endpoint string, body io.Reader, contentType string, opts ...func(*http.Request) error) (*http.Response, error) {
-	apiURL, err := url.Parse(c.baseURL)
+	return c.doRequestContext(context.Background(), method, endpoint, body, contentType, opts...)
+}
+
+// doRequestContext is doRequest bounded by ctx
+func (c *Client) doRequestContext(ctx context.Context, method, endpoint string, body io.Reader, contentType string, opts ...func(*http.Request) error) (*http.Response, error) {
+	apiURL, err := c.endpointURL(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse base URL: %v", err)
+		return nil, err
 	}
 
-	apiURL.Path = strings.TrimSuffix(apiURL.Path, "/") + endpoint
+	if endpoint != "/api/v2/auth/login" {
+		if err := c.ensureSession(ctx); err != nil {
+			return nil, fmt.Errorf("login failed: %w", err)
+		}
+	}
 
-	// Store body in buffer if it's not nil so we can retry the request
-	var bodyBuffer []byte
-	if body != nil {
-		bodyBuffer, err = io.ReadAll(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %v", err)
+	// Bound the whole exchange, including a re-authenticated retry, by the configured timeout
+	cancel := context.CancelFunc(func() {})
+	if timeout := c.requestTimeout(endpoint); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	// A 403 is retried once after logging in again, which needs the body a second time. Bodies in memory
+	// are rewound through Request.GetBody; others are buffered only if the retry can happen.
+	switch body.(type) {
+	case nil, *bytes.Buffer, *bytes.Reader, *strings.Reader:
+	default:
+		if !c.noAuth && c.username != "" {
+			data, err := io.ReadAll(body)
+			if err != nil {
+				cancel()
+				return nil, fmt.Errorf("failed to read request body: %v", err)
+			}
+			body = bytes.NewReader(data)
 		}
 	}
 
+	var getBody func() (io.ReadCloser, error)
 	makeRequest := func() (*http.Request, error) {
-		var bodyReader io.Reader
-		if bodyBuffer != nil {
-			bodyReader = bytes.NewReader(bodyBuffer)
+		bodyReader := body
+		if getBody != nil {
+			rc, err := getBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %v", err)
+			}
+			bodyReader = rc
 		}
-		req, err := http.NewRequest(method, apiURL.String(), bodyReader)
+		req, err := http.NewRequestWithContext(ctx, method, apiURL.String(), bodyReader)
 		if err != nil {
-			return nil, fmt.Errorf("NewRequest error: %v", err)
+			return nil, fmt.Errorf("NewRequest error: %w", err)
 		}
+		getBody = req.GetBody
 
 		if contentType != "" {
 			req.Header.Set("Content-Type", contentType)
@@ -642,7 +1137,11 @@ func (c *Client) doRequest(method, endpoint string, body io.Reader, contentType
 
 		c.mu.RLock()
 		if c.sid != "" {
-			req.AddCookie(&http.Cookie{Name: "SID", Value: c.sid})
+			name := c.sidName
+			if name == "" {
+				name = "SID"
+			}
+			req.AddCookie(&http.Cookie{Name: name, Value: c.sid})
 		}
 		c.mu.RUnlock()
 
@@ -658,11 +1157,13 @@ func (c *Client) doRequest(method, endpoint string, body io.Reader, contentType
 	// Make initial request
 	req, err := makeRequest()
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
-	resp, err := c.client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
@@ -670,22 +1171,156 @@ func (c *Client) doRequest(method, endpoint string, body io.Reader, contentType
 	if resp.StatusCode == http.StatusForbidden {
 		resp.Body.Close() // Close the first response
 
-		if err := c.AuthLogin(); err != nil {
-			return nil, fmt.Errorf("re-authentication failed: %v", err)
+		// Without credentials a login cannot succeed, so report the 403 instead of attempting one
+		if c.noAuth || c.username == "" {
+			cancel()
+			return nil, fmt.Errorf("%w: %s", ErrForbidden, endpoint)
+		}
+
+		if err := c.authLogin(ctx); err != nil {
+			cancel()
+			return nil, fmt.Errorf("re-authentication failed: %w", err)
 		}
 
 		// Retry the original request with the new SID
 		req, err := makeRequest()
 		if err != nil {
+			cancel()
+			return nil, err
+		}
+
+		resp, err = c.do(req)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusForbidden {
+		c.touchSession()
+	}
+
+	// The timeout must outlive doRequest since callers read the body afterwards
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// endpointURL resolves an API endpoint such as "/api/v2/app/version" against the base URL, keeping any
+// subpath the WebUI is served under, e.g. "https://host/seedbox/qbt/" with or without the trailing slash
+func (c *Client) endpointURL(endpoint string) (*url.URL, error) {
+	base, err := parseBaseURL(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	base.Path = strings.TrimRight(base.Path, "/") + "/" + strings.TrimLeft(endpoint, "/")
+	base.RawPath = ""
+	return base, nil
+}
+
+// parseBaseURL parses and validates the base URL of the WebUI. Query and fragment are dropped since
+// they would otherwise be sent with every request.
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base URL: %v", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme must be http or https", Redact(rawURL))
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: missing host", Redact(rawURL))
+	}
+	u.RawQuery, u.Fragment, u.RawFragment = "", "", ""
+	return u, nil
+}
+
+// do sends req, waiting for the rate limit, failing fast while the circuit breaker is open and retrying
+// according to the retry policy
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+		if c.breaker != nil {
+			if err := c.breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.send(req)
+		if c.breaker != nil {
+			c.breaker.record(req, resp, err)
+		}
+		if !c.retry.retryable(req, attempt, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			discard(resp)
+		}
+		if err := sleepContext(req.Context(), c.retry.delay(attempt)); err != nil {
 			return nil, err
 		}
+	}
+}
 
-		return c.client.Do(req)
+// send sends req once, logging it to the debug logger if one is set. URLs are redacted both in the log
+// and in the returned error, as query parameters may carry tracker URLs with announce keys.
+// The correlation ID, if any, is set on req here so that every attempt carries it.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	id := c.requestCorrelationID(req.Context())
+	logID := ""
+	if id != "" {
+		req.Header.Set(c.correlationIDHeader(), id)
+		logID = " id=" + id
 	}
 
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = Redact(urlErr.URL)
+		}
+		if c.debugLog != nil {
+			c.debugLog.Printf("qbittorrent: %s %s%s: %v", req.Method, Redact(req.URL.String()), logID, err)
+		}
+		if id != "" {
+			err = fmt.Errorf("%w (correlation ID %s)", err, id)
+		}
+		return nil, err
+	}
+	if c.debugLog != nil {
+		c.debugLog.Printf("qbittorrent: %s %s%s: %d (%s)", req.Method, Redact(req.URL.String()), logID, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+	}
 	return resp, nil
 }
 
+// isSessionCookie reports whether name is the WebUI session cookie, "SID" or the "QBT_SID_<port>"
+// name used by newer qBittorrent versions
+func isSessionCookie(name string) bool {
+	return name == "SID" || strings.HasPrefix(name, "QBT_SID")
+}
+
+// requestTimeout returns the timeout that applies to the given endpoint
+func (c *Client) requestTimeout(endpoint string) time.Duration {
+	if timeout, ok := c.endpointTimeouts[endpoint]; ok {
+		return timeout
+	}
+	return c.timeout
+}
+
+// cancelOnClose releases a request context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
 // withQuery returns a request modifier that adds query parameters
 func withQuery(query url.Values) func(*http.Request) error {
 	return func(req *http.Request) error {
@@ -695,23 +1330,39 @@ func withQuery(query url.Values) func(*http.Request) error {
 }
 
 func (c *Client) SyncMainData(rid int) (*MainData, error) {
-	params := url.Values{}
-	params.Set("rid", strconv.Itoa(rid))
+	return c.syncMainData(context.Background(), rid)
+}
 
-	resp, err := c.doGet("/api/v2/sync/maindata", params)
+// syncMainData is SyncMainData bounded by ctx
+func (c *Client) syncMainData(ctx context.Context, rid int) (*MainData, error) {
+	resp, err := c.syncMainDataRaw(ctx, rid)
 	if err != nil {
 		return nil, err
 	}
 
 	var result MainData
-	err = json.Unmarshal(resp, &result)
+	err = c.decodeJSON(resp, &result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
+	for hash, torrent := range result.Torrents {
+		if torrent.State != "" {
+			torrent.State = c.stateNormalization.normalize(torrent.State)
+			result.Torrents[hash] = torrent
+		}
+	}
 
 	return &result, nil
 }
 
+// syncMainDataRaw returns the undecoded /api/v2/sync/maindata response
+func (c *Client) syncMainDataRaw(ctx context.Context, rid int) ([]byte, error) {
+	params := url.Values{}
+	params.Set("rid", strconv.Itoa(rid))
+
+	return c.doGetContext(ctx, "/api/v2/sync/maindata", params)
+}
+
 func (c *Client) SyncTorrentPeers(hash string, rid int) (*TorrentPeers, error) {
 	params := url.Values{}
 	params.Set("rid", strconv.Itoa(rid))
@@ -723,7 +1374,7 @@ func (c *Client) SyncTorrentPeers(hash string, rid int) (*TorrentPeers, error) {
 	}
 
 	var result TorrentPeers
-	err = json.Unmarshal(resp, &result)
+	err = c.decodeJSON(resp, &result)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -0,0 +1,175 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Preferences holds qBittorrent application preferences keyed by their API names, e.g. "save_path".
+// Only the keys present are sent by AppSetPreferences, so a Preferences value can describe a partial update.
+type Preferences map[string]interface{}
+
+// AppPreferences retrieves the application preferences
+func (c *Client) AppPreferences() (Preferences, error) {
+	respData, err := c.doGet("/api/v2/app/preferences", nil)
+	if err != nil {
+		return nil, fmt.Errorf("AppPreferences error: %w", err)
+	}
+
+	var prefs Preferences
+	if err := c.decodeJSON(respData, &prefs); err != nil {
+		return nil, fmt.Errorf("failed to decode preferences response: %v", err)
+	}
+
+	return prefs, nil
+}
+
+// AppSetPreferences changes the given preferences, leaving all others untouched
+func (c *Client) AppSetPreferences(prefs Preferences) error {
+	encoded, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("AppSetPreferences error: %w", err)
+	}
+
+	data := url.Values{}
+	data.Set("json", string(encoded))
+
+	_, err = c.doPostValues("/api/v2/app/setPreferences", data)
+	if err != nil {
+		return fmt.Errorf("AppSetPreferences error: %w", err)
+	}
+	return nil
+}
+
+// AppVersion retrieves the qBittorrent version, e.g. "v5.0.3"
+func (c *Client) AppVersion() (string, error) {
+	respData, err := c.doGet("/api/v2/app/version", nil)
+	if err != nil {
+		return "", fmt.Errorf("AppVersion error: %w", err)
+	}
+	return strings.TrimSpace(string(respData)), nil
+}
+
+// AppWebAPIVersion retrieves the Web API version, e.g. "2.11.2"
+func (c *Client) AppWebAPIVersion() (string, error) {
+	respData, err := c.doGet("/api/v2/app/webapiVersion", nil)
+	if err != nil {
+		return "", fmt.Errorf("AppWebAPIVersion error: %w", err)
+	}
+	return strings.TrimSpace(string(respData)), nil
+}
+
+// NetworkInterface is a network interface qBittorrent can bind to, as returned by AppNetworkInterfaces
+type NetworkInterface struct {
+	Name  string `json:"name"`  // display name
+	Value string `json:"value"` // identifier used by the current_network_interface preference
+}
+
+// AppNetworkInterfaces retrieves the network interfaces of the host running qBittorrent
+func (c *Client) AppNetworkInterfaces() ([]NetworkInterface, error) {
+	respData, err := c.doGet("/api/v2/app/networkInterfaceList", nil)
+	if err != nil {
+		return nil, fmt.Errorf("AppNetworkInterfaces error: %w", err)
+	}
+
+	var interfaces []NetworkInterface
+	if err := c.decodeJSON(respData, &interfaces); err != nil {
+		return nil, fmt.Errorf("failed to decode network interfaces response: %v", err)
+	}
+
+	return interfaces, nil
+}
+
+// AppNetworkInterfaceAddresses retrieves the addresses of the network interface iface, identified by its
+// NetworkInterface.Value. An empty iface returns the addresses of every interface.
+func (c *Client) AppNetworkInterfaceAddresses(iface string) ([]string, error) {
+	params := url.Values{}
+	params.Set("iface", iface)
+
+	respData, err := c.doGet("/api/v2/app/networkInterfaceAddress", params)
+	if err != nil {
+		return nil, fmt.Errorf("AppNetworkInterfaceAddresses error: %w", err)
+	}
+
+	var addresses []string
+	if err := c.decodeJSON(respData, &addresses); err != nil {
+		return nil, fmt.Errorf("failed to decode network interface addresses response: %v", err)
+	}
+
+	return addresses, nil
+}
+
+// Cookie is a cookie qBittorrent sends when downloading .torrent files from URLs, e.g. the session cookie of
+// a private tracker. The cookie endpoints require qBittorrent 5.0 or newer.
+type Cookie struct {
+	Name           string `json:"name"`
+	Domain         string `json:"domain"`
+	Path           string `json:"path"`
+	Value          string `json:"value"`
+	ExpirationDate int64  `json:"expirationDate"` // Unix time in seconds
+}
+
+// AppCookies retrieves the cookies used for downloads
+func (c *Client) AppCookies() ([]Cookie, error) {
+	respData, err := c.doGet("/api/v2/app/cookies", nil)
+	if err != nil {
+		return nil, fmt.Errorf("AppCookies error: %w", err)
+	}
+
+	var cookies []Cookie
+	if err := c.decodeJSON(respData, &cookies); err != nil {
+		return nil, fmt.Errorf("failed to decode cookies response: %v", err)
+	}
+
+	return cookies, nil
+}
+
+// AppSetCookies replaces the cookies used for downloads with cookies
+func (c *Client) AppSetCookies(cookies []Cookie) error {
+	if cookies == nil {
+		cookies = []Cookie{}
+	}
+	encoded, err := json.Marshal(cookies)
+	if err != nil {
+		return fmt.Errorf("AppSetCookies error: %w", err)
+	}
+
+	data := url.Values{}
+	data.Set("cookies", string(encoded))
+	_, err = c.doPostValues("/api/v2/app/setCookies", data)
+	if err != nil {
+		return fmt.Errorf("AppSetCookies error: %w", err)
+	}
+	return nil
+}
+
+// AppAddCookies adds cookies to the ones used for downloads, replacing existing cookies with the same
+// name, domain and path
+func (c *Client) AppAddCookies(cookies ...Cookie) error {
+	existing, err := c.AppCookies()
+	if err != nil {
+		return fmt.Errorf("AppAddCookies error: %w", err)
+	}
+
+	type cookieKey struct{ name, domain, path string }
+	index := make(map[cookieKey]int, len(existing))
+	for i, cookie := range existing {
+		index[cookieKey{cookie.Name, cookie.Domain, cookie.Path}] = i
+	}
+	for _, cookie := range cookies {
+		key := cookieKey{cookie.Name, cookie.Domain, cookie.Path}
+		if i, ok := index[key]; ok {
+			existing[i] = cookie
+			continue
+		}
+		index[key] = len(existing)
+		existing = append(existing, cookie)
+	}
+
+	if err := c.AppSetCookies(existing); err != nil {
+		return fmt.Errorf("AppAddCookies error: %w", err)
+	}
+	return nil
+}
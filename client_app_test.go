@@ -0,0 +1,93 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_AppPreferences(t *testing.T) {
+	var posted string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"save_path":"/downloads","dht":true,"max_ratio":1.5}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	prefs, err := client.AppPreferences()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if prefs["save_path"] != "/downloads" || prefs["dht"] != true || prefs["max_ratio"] != 1.5 {
+		t.Errorf("unexpected preferences %v", prefs)
+	}
+
+	if err := client.AppSetPreferences(Preferences{"dht": false}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if posted != `{"dht":false}` {
+		t.Errorf("expected json={\"dht\":false}, got %s", posted)
+	}
+}
+
+func TestClient_AppCookies(t *testing.T) {
+	var posted []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/cookies":
+			w.Write([]byte(`[
+				{"name": "uid", "domain": "tracker.example.org", "path": "/", "value": "1", "expirationDate": 1767225600},
+				{"name": "pass", "domain": "tracker.example.org", "path": "/", "value": "old", "expirationDate": 1767225600}
+			]`))
+		case "/api/v2/app/setCookies":
+			r.ParseForm()
+			posted = append(posted, r.PostForm.Get("cookies"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	cookies, err := client.AppCookies()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cookies) != 2 || cookies[0].Name != "uid" || cookies[0].ExpirationDate != 1767225600 {
+		t.Errorf("unexpected cookies %+v", cookies)
+	}
+
+	err = client.AppAddCookies(
+		Cookie{Name: "pass", Domain: "tracker.example.org", Path: "/", Value: "new"},
+		Cookie{Name: "sid", Domain: "other.net", Path: "/", Value: "x"},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.AppSetCookies(nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		`[{"name":"uid","domain":"tracker.example.org","path":"/","value":"1","expirationDate":1767225600},` +
+			`{"name":"pass","domain":"tracker.example.org","path":"/","value":"new","expirationDate":0},` +
+			`{"name":"sid","domain":"other.net","path":"/","value":"x","expirationDate":0}]`,
+		`[]`,
+	}
+	if len(posted) != len(want) || posted[0] != want[0] || posted[1] != want[1] {
+		t.Errorf("expected posted %v, got %v", want, posted)
+	}
+}
@@ -1,7 +1,13 @@
 package qbittorrent
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -129,3 +135,127 @@ func TestAuthLogin_Failure(t *testing.T) {
 		t.Errorf("Not all expected requests were made")
 	}
 }
+
+func TestWithNoAuth(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/test": {statusCode: http.StatusForbidden, responseBody: "Forbidden"},
+	}
+	// No login, neither on creation nor after the 403
+	expectedRequests := []expectedRequest{{method: "GET", url: "/api/test"}}
+
+	mockTransport := &mockRoundTripper{
+		responses:        endpointResponses,
+		expectedRequests: expectedRequests,
+		t:                t,
+	}
+
+	client, err := NewClientWithOptions("user", "pass", "localhost", "8080",
+		WithHTTPClient(&http.Client{Transport: mockTransport}), WithNoAuth())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	_, err = client.doRequest("GET", "/api/test", nil, "")
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestAuthLogin_SessionCookie(t *testing.T) {
+	var cookies []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			http.SetCookie(w, &http.Cookie{Name: "QBT_SID_8080", Value: "abc"})
+			w.Write([]byte("Ok."))
+			return
+		}
+		cookies = append(cookies, r.Header.Get("Cookie"))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{username: "user", password: "pass", baseURL: mockServer.URL, client: mockServer.Client()}
+	if err := client.AuthLogin(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.doGet("/api/test", nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(cookies) != 1 || cookies[0] != "QBT_SID_8080=abc" {
+		t.Errorf("Expected the session cookie to be sent back, got %v", cookies)
+	}
+}
+
+func TestWithLazyLogin(t *testing.T) {
+	up := false
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+			w.Write([]byte("Ok."))
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.9.3"))
+		case "/api/v2/app/version":
+			if c, err := r.Cookie("SID"); err != nil || c.Value != "sid" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte("v4.6.7"))
+		}
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClientWithOptions("admin", "adminadmin", "", "", WithBaseURL(mockServer.URL), WithHTTPClient(mockServer.Client()), WithLazyLogin())
+	if err != nil {
+		t.Fatalf("expected the constructor to succeed while the server is down, got %v", err)
+	}
+	if err := client.Connect(context.Background()); err == nil {
+		t.Fatal("expected Connect to fail while the server is down")
+	}
+
+	up = true
+	if version, err := client.AppVersion(); err != nil || version != "v4.6.7" {
+		t.Fatalf("expected v4.6.7, got %q, %v", version, err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{"/api/v2/auth/login", "/api/v2/app/version", "/api/v2/auth/login", "/api/v2/app/webapiVersion"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+	if v, err := client.APIVersion(); err != nil || v != (APIVersion{2, 9, 3}) {
+		t.Errorf("expected the version cached by Connect, got %v, %v", v, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := client.Connect(ctx); err == nil || !strings.Contains(err.Error(), "context canceled") {
+		t.Errorf("expected a cancelled Connect, got %v", err)
+	}
+}
+
+func TestWithLazyLogin_ErrorWrapping(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClientWithOptions("admin", "adminadmin", "", "", WithBaseURL(mockServer.URL), WithHTTPClient(mockServer.Client()), WithLazyLogin())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.TorrentsExportTo(ctx, "abc", io.Discard); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the lazy login to fail with context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package qbittorrent
+
+import "strings"
+
+// The functions below keep the comma separated tag signatures of earlier versions.
+
+// TorrentsAddTagsCSV adds comma separated tags to the specified torrents.
+//
+// Deprecated: use TorrentsAddTags, which cannot split a tag containing a comma by accident.
+func (c *Client) TorrentsAddTagsCSV(hashes, tags string) error {
+	return c.TorrentsAddTags(hashes, strings.Split(tags, ","))
+}
+
+// TorrentsRemoveTagsCSV removes comma separated tags from the specified torrents.
+//
+// Deprecated: use TorrentsRemoveTags.
+func (c *Client) TorrentsRemoveTagsCSV(hashes, tags string) error {
+	return c.TorrentsRemoveTags(hashes, strings.Split(tags, ","))
+}
+
+// TorrentsCreateTagsCSV creates comma separated tags.
+//
+// Deprecated: use TorrentsCreateTags.
+func (c *Client) TorrentsCreateTagsCSV(tags string) error {
+	return c.TorrentsCreateTags(strings.Split(tags, ","))
+}
+
+// TorrentsDeleteTagsCSV deletes comma separated tags.
+//
+// Deprecated: use TorrentsDeleteTags.
+func (c *Client) TorrentsDeleteTagsCSV(tags string) error {
+	return c.TorrentsDeleteTags(strings.Split(tags, ","))
+}
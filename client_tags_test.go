@@ -2,8 +2,11 @@ package qbittorrent
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -28,6 +31,11 @@ func TestTorrentInfo_UnmarshalJSON(t *testing.T) {
 			jsonData: `{"tags": "tag1,tag2,tag3"}`,
 			expected: []string{"tag1", "tag2", "tag3"},
 		},
+		{
+			name:     "Tags separated by comma and space",
+			jsonData: `{"tags": "tag1, tag2, tag 3"}`,
+			expected: []string{"tag1", "tag2", "tag 3"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -54,8 +62,11 @@ func TestTorrentInfo_UnmarshalJSON(t *testing.T) {
 func TestClient_TorrentsGetTags(t *testing.T) {
 	// Mock server response
 	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("hashes"); got != "somehash1|somehash2|somehash3" {
+			t.Errorf("expected hashes filter, got %q", got)
+		}
 		w.WriteHeader(http.StatusOK)
-		w.Write([]byte(`[{"tags": "tag1,tag2"},{"tags": "tag2,tag3"}]`))
+		w.Write([]byte(`[{"hash": "somehash1", "tags": "tag1, tag2"},{"hash": "somehash2", "tags": "tag2,tag3"},{"hash": "somehash3", "tags": ""}]`))
 	}))
 	defer mockServer.Close()
 
@@ -64,24 +75,55 @@ func TestClient_TorrentsGetTags(t *testing.T) {
 		client:  mockServer.Client(),
 	}
 
-	tags, err := client.TorrentsGetTags("somehash1|somehash2")
+	tags, err := client.TorrentsGetTags("somehash1|somehash2|somehash3")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	expectedTags := []string{"tag1", "tag2", "tag3"}
-	if len(tags) != len(expectedTags) {
-		t.Fatalf("expected %d tags, got %d", len(expectedTags), len(tags))
+	expected := map[InfoHash][]string{
+		"somehash1": {"tag1", "tag2"},
+		"somehash2": {"tag2", "tag3"},
+		"somehash3": {},
 	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("expected %v, got %v", expected, tags)
+	}
+
+	if union := UnionTags(tags); !reflect.DeepEqual(union, []string{"tag1", "tag2", "tag3"}) {
+		t.Errorf("expected union [tag1 tag2 tag3], got %v", union)
+	}
+}
+
+func TestClient_TorrentsAddTags(t *testing.T) {
+	var forms []url.Values
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		forms = append(forms, r.PostForm)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
 
-	tagSet := make(map[string]struct{})
-	for _, tag := range tags {
-		tagSet[tag] = struct{}{}
+	if err := client.TorrentsAddTags("hash1|hash2", []string{"hd", " new "}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.TorrentsRemoveTagsCSV("hash1", "hd,new"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []url.Values{
+		{"hashes": {"hash1|hash2"}, "tags": {"hd,new"}},
+		{"hashes": {"hash1"}, "tags": {"hd,new"}},
+	}
+	if !reflect.DeepEqual(forms, want) {
+		t.Errorf("expected %v, got %v", want, forms)
 	}
 
-	for _, expectedTag := range expectedTags {
-		if _, exists := tagSet[expectedTag]; !exists {
-			t.Errorf("expected tag %v to be present", expectedTag)
+	for _, tags := range [][]string{nil, {""}, {"a,b"}} {
+		if err := client.TorrentsCreateTags(tags); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("expected ErrInvalidTag for %q, got %v", tags, err)
 		}
 	}
+	if len(forms) != 2 {
+		t.Errorf("expected invalid tags not to be sent, got %d requests", len(forms))
+	}
 }
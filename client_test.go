@@ -1,7 +1,12 @@
 package qbittorrent
 
 import (
+	"bytes"
+	"context"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -14,7 +19,7 @@ func TestTorrentsExport(t *testing.T) {
 	}
 	expectedRequests := []expectedRequest{
 		{method: "POST", url: "/api/v2/auth/login"},
-		{method: "POST", url: "/api/v2/torrents/export"},
+		{method: "GET", url: "/api/v2/torrents/export"},
 	}
 
 	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
@@ -118,6 +123,50 @@ func TestSetForceStart(t *testing.T) {
 	}
 }
 
+func TestTorrentsPauseResume(t *testing.T) {
+	tests := []struct {
+		name          string
+		webAPIVersion string
+		pause, resume string
+	}{
+		{name: "4.x", webAPIVersion: "2.9.3", pause: "/api/v2/torrents/pause", resume: "/api/v2/torrents/resume"},
+		{name: "5.x", webAPIVersion: "2.11.2", pause: "/api/v2/torrents/stop", resume: "/api/v2/torrents/start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpointResponses := map[string]mockResponse{
+				"/api/v2/auth/login":        {statusCode: http.StatusOK, responseBody: "Ok."},
+				"/api/v2/app/webapiVersion": {statusCode: http.StatusOK, responseBody: tt.webAPIVersion},
+				tt.pause:                    {statusCode: http.StatusOK},
+				tt.resume:                   {statusCode: http.StatusOK},
+			}
+			expectedRequests := []expectedRequest{
+				{method: "POST", url: "/api/v2/auth/login"},
+				{method: "GET", url: "/api/v2/app/webapiVersion"},
+				{method: "POST", url: tt.pause, params: url.Values{"hashes": {"hash1|hash2"}}},
+				{method: "POST", url: tt.resume, params: url.Values{"hashes": {"hash1"}}},
+			}
+
+			client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if err := client.TorrentsPause("hash1|hash2"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			// The version is cached, so this does not fetch it again
+			if err := client.TorrentsResume("hash1"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+				t.Errorf("Not all expected requests were made")
+			}
+		})
+	}
+}
+
 func TestTorrentsTrackers(t *testing.T) {
 	responseBody := `[{"url":"tracker1","status":1},{"url":"tracker2","status":0}]`
 	// Mock successful AuthLogin and TorrentsTrackers responses
@@ -153,3 +202,93 @@ func TestTorrentsTrackers(t *testing.T) {
 		t.Errorf("Not all expected requests were made")
 	}
 }
+
+func TestTorrentsAddURLs(t *testing.T) {
+	var urls, category string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/torrents/add" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		r.ParseMultipartForm(1 << 20)
+		urls = r.FormValue("urls")
+		category = r.FormValue("category")
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	err := client.TorrentsAddURLs([]string{"magnet:?xt=urn:btih:abc", "http://example.com/a.torrent"}, WithCategory("movies"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if urls != "magnet:?xt=urn:btih:abc\nhttp://example.com/a.torrent" {
+		t.Errorf("Unexpected urls %q", urls)
+	}
+	if category != "movies" {
+		t.Errorf("Expected category 'movies', got '%s'", category)
+	}
+}
+
+func TestTorrentsDownload(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/torrents/export" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("torrent " + r.URL.Query().Get("hash")))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	data, err := client.TorrentsDownload("abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if string(data) != "torrent abc" {
+		t.Errorf("unexpected torrent %q", data)
+	}
+}
+
+func TestTorrentsExportTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		postOnly bool
+		want     []string
+	}{
+		{"get", false, []string{"GET hash=abc"}},
+		{"post fallback", true, []string{"GET hash=abc", "POST hash=abc"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				requests = append(requests, r.Method+" hash="+r.Form.Get("hash"))
+				if tt.postOnly && r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				w.Write([]byte("torrent " + r.Form.Get("hash")))
+			}))
+			defer mockServer.Close()
+
+			client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+			var buf bytes.Buffer
+			n, err := client.TorrentsExportTo(context.Background(), "abc", &buf)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if n != 11 || buf.String() != "torrent abc" {
+				t.Errorf("unexpected export %d %q", n, buf.String())
+			}
+			if !reflect.DeepEqual(requests, tt.want) {
+				t.Errorf("expected requests %v, got %v", tt.want, requests)
+			}
+		})
+	}
+}
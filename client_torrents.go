@@ -0,0 +1,479 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TorrentsReannounce re-announces the specified torrents to their trackers.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsReannounce(hashes string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+
+	_, err := c.doPostValues("/api/v2/torrents/reannounce", data)
+	if err != nil {
+		return fmt.Errorf("Reannounce error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsPause pauses the specified torrents. Multiple hashes are separated by "|".
+// On qBittorrent 5.x, which renamed pausing to stopping, the stop endpoint is used.
+func (c *Client) TorrentsPause(hashes string) error {
+	if err := c.pauseOrResume(hashes, "/api/v2/torrents/pause", "/api/v2/torrents/stop"); err != nil {
+		return fmt.Errorf("TorrentsPause error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsResume resumes the specified torrents. Multiple hashes are separated by "|".
+// On qBittorrent 5.x, which renamed resuming to starting, the start endpoint is used.
+func (c *Client) TorrentsResume(hashes string) error {
+	if err := c.pauseOrResume(hashes, "/api/v2/torrents/resume", "/api/v2/torrents/start"); err != nil {
+		return fmt.Errorf("TorrentsResume error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsStop is the qBittorrent 5.x name of TorrentsPause, it works with both versions
+func (c *Client) TorrentsStop(hashes string) error {
+	return c.TorrentsPause(hashes)
+}
+
+// TorrentsStart is the qBittorrent 5.x name of TorrentsResume, it works with both versions
+func (c *Client) TorrentsStart(hashes string) error {
+	return c.TorrentsResume(hashes)
+}
+
+// pauseOrResume posts hashes to legacy on 4.x servers and to startStop on 5.x servers
+func (c *Client) pauseOrResume(hashes, legacy, startStop string) error {
+	useStartStop, err := c.usesStartStop()
+	if err != nil {
+		return err
+	}
+	endpoint := legacy
+	if useStartStop {
+		endpoint = startStop
+	}
+
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	_, err = c.doPostValues(endpoint, data)
+	return err
+}
+
+// TorrentsRemove removes the specified torrents, deleting their downloaded data if deleteFiles is set.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsRemove(hashes string, deleteFiles bool) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("deleteFiles", strconv.FormatBool(deleteFiles))
+
+	_, err := c.doPostValues("/api/v2/torrents/delete", data)
+	if err != nil {
+		return fmt.Errorf("TorrentsRemove error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsSetLocation moves the data of the specified torrents to location.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetLocation(hashes, location string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("location", location)
+
+	_, err := c.doPostValues("/api/v2/torrents/setLocation", data)
+	if err != nil {
+		return fmt.Errorf("SetLocation error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsSetCategory assigns category to the specified torrents, an empty category removes it.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetCategory(hashes, category string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("category", category)
+
+	_, err := c.doPostValues("/api/v2/torrents/setCategory", data)
+	if err != nil {
+		return fmt.Errorf("SetCategory error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsSetAutoManagement enables or disables automatic torrent management of the specified torrents.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetAutoManagement(hashes string, enable bool) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("enable", strconv.FormatBool(enable))
+
+	_, err := c.doPostValues("/api/v2/torrents/setAutoManagement", data)
+	if err != nil {
+		return fmt.Errorf("SetAutoManagement error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsToggleSequentialDownload flips sequential downloading of the specified torrents.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsToggleSequentialDownload(hashes string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+
+	_, err := c.doPostValues("/api/v2/torrents/toggleSequentialDownload", data)
+	if err != nil {
+		return fmt.Errorf("ToggleSequentialDownload error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsToggleFirstLastPiecePrio flips the priority of the first and last piece of every file of the
+// specified torrents. Multiple hashes are separated by "|".
+func (c *Client) TorrentsToggleFirstLastPiecePrio(hashes string) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+
+	_, err := c.doPostValues("/api/v2/torrents/toggleFirstLastPiecePrio", data)
+	if err != nil {
+		return fmt.Errorf("ToggleFirstLastPiecePrio error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsEditTracker replaces the tracker origURL of the torrent with newURL. The server answers 409
+// Conflict, reported as ErrConflict, when newURL already exists or origURL was not found.
+func (c *Client) TorrentsEditTracker(hash, origURL, newURL string) error {
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("origUrl", origURL)
+	data.Set("newUrl", newURL)
+
+	_, err := c.doPostValues("/api/v2/torrents/editTracker", data)
+	if err != nil {
+		return fmt.Errorf("EditTracker error: %w", err)
+	}
+	return nil
+}
+
+// TorrentFile is a file of a torrent as returned by TorrentsFiles
+type TorrentFile struct {
+	Index        int     `json:"index"`
+	Name         string  `json:"name"` // path relative to the save path, including the torrent's root folder
+	Size         int64   `json:"size"`
+	Progress     float64 `json:"progress"`
+	Priority     int     `json:"priority"`
+	IsSeed       bool    `json:"is_seed"`
+	PieceRange   []int   `json:"piece_range"`
+	Availability float64 `json:"availability"`
+}
+
+// TorrentsFiles retrieves the files of a torrent
+func (c *Client) TorrentsFiles(hash string) ([]TorrentFile, error) {
+	params := url.Values{}
+	params.Set("hash", hash)
+
+	respData, err := c.doGet("/api/v2/torrents/files", params)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsFiles error: %w", err)
+	}
+
+	var files []TorrentFile
+	if err := c.decodeJSON(respData, &files); err != nil {
+		return nil, fmt.Errorf("failed to decode files response: %v", err)
+	}
+
+	return files, nil
+}
+
+// TorrentProperties are the generic properties of a torrent as returned by TorrentsProperties
+type TorrentProperties struct {
+	SavePath               string  `json:"save_path"`
+	CreationDate           int64   `json:"creation_date"` // unix seconds
+	PieceSize              int64   `json:"piece_size"`
+	Comment                string  `json:"comment"`
+	CreatedBy              string  `json:"created_by"`
+	TotalWasted            int64   `json:"total_wasted"`
+	TotalUploaded          int64   `json:"total_uploaded"`
+	TotalUploadedSession   int64   `json:"total_uploaded_session"`
+	TotalDownloaded        int64   `json:"total_downloaded"`
+	TotalDownloadedSession int64   `json:"total_downloaded_session"`
+	TotalSize              int64   `json:"total_size"`
+	UpLimit                int64   `json:"up_limit"`
+	DLLimit                int64   `json:"dl_limit"`
+	UpSpeed                int64   `json:"up_speed"`
+	UpSpeedAvg             int64   `json:"up_speed_avg"`
+	DLSpeed                int64   `json:"dl_speed"`
+	DLSpeedAvg             int64   `json:"dl_speed_avg"`
+	TimeElapsed            int64   `json:"time_elapsed"` // seconds
+	SeedingTime            int64   `json:"seeding_time"` // seconds
+	ETA                    int64   `json:"eta"`          // seconds
+	NbConnections          int64   `json:"nb_connections"`
+	NbConnectionsLimit     int64   `json:"nb_connections_limit"`
+	ShareRatio             float64 `json:"share_ratio"`
+	AdditionDate           int64   `json:"addition_date"`   // unix seconds
+	CompletionDate         int64   `json:"completion_date"` // unix seconds, -1 if not completed
+	LastSeen               int64   `json:"last_seen"`       // unix seconds, -1 if never seen complete
+	Peers                  int64   `json:"peers"`
+	PeersTotal             int64   `json:"peers_total"`
+	Seeds                  int64   `json:"seeds"`
+	SeedsTotal             int64   `json:"seeds_total"`
+	PiecesNum              int64   `json:"pieces_num"`
+	PiecesHave             int64   `json:"pieces_have"`
+	Reannounce             int64   `json:"reannounce"` // seconds until the next announce
+}
+
+// TorrentsProperties retrieves the generic properties of a torrent
+func (c *Client) TorrentsProperties(hash string) (TorrentProperties, error) {
+	params := url.Values{}
+	params.Set("hash", hash)
+
+	respData, err := c.doGet("/api/v2/torrents/properties", params)
+	if err != nil {
+		return TorrentProperties{}, fmt.Errorf("TorrentsProperties error: %w", err)
+	}
+
+	var props TorrentProperties
+	if err := c.decodeJSON(respData, &props); err != nil {
+		return TorrentProperties{}, fmt.Errorf("failed to decode properties response: %v", err)
+	}
+
+	return props, nil
+}
+
+// Piece states, as returned by TorrentsPieceStates
+const (
+	PieceNotDownloaded = 0
+	PieceDownloading   = 1
+	PieceDownloaded    = 2
+)
+
+// TorrentsPieceStates retrieves the state of every piece of a torrent, see PieceDownloaded
+func (c *Client) TorrentsPieceStates(hash string) ([]int, error) {
+	params := url.Values{}
+	params.Set("hash", hash)
+
+	respData, err := c.doGet("/api/v2/torrents/pieceStates", params)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsPieceStates error: %w", err)
+	}
+
+	var states []int
+	if err := c.decodeJSON(respData, &states); err != nil {
+		return nil, fmt.Errorf("failed to decode piece states response: %v", err)
+	}
+
+	return states, nil
+}
+
+// TorrentsPieceHashes retrieves the hex encoded hashes of the pieces of a torrent
+func (c *Client) TorrentsPieceHashes(hash string) ([]string, error) {
+	params := url.Values{}
+	params.Set("hash", hash)
+
+	respData, err := c.doGet("/api/v2/torrents/pieceHashes", params)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsPieceHashes error: %w", err)
+	}
+
+	var hashes []string
+	if err := c.decodeJSON(respData, &hashes); err != nil {
+		return nil, fmt.Errorf("failed to decode piece hashes response: %v", err)
+	}
+
+	return hashes, nil
+}
+
+// File priorities, as in TorrentFile.Priority and TorrentsSetFilePriority
+const (
+	FilePriorityDontDownload = 0
+	FilePriorityNormal       = 1
+	FilePriorityHigh         = 6
+	FilePriorityMaximal      = 7
+)
+
+// TorrentsSetFilePriority sets the priority of the files of a torrent with the given indexes, see
+// TorrentFile.Index. FilePriorityDontDownload skips the files.
+func (c *Client) TorrentsSetFilePriority(hash string, indexes []int, priority int) error {
+	ids := make([]string, len(indexes))
+	for i, index := range indexes {
+		ids[i] = strconv.Itoa(index)
+	}
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("id", strings.Join(ids, "|"))
+	data.Set("priority", strconv.Itoa(priority))
+
+	_, err := c.doPostValues("/api/v2/torrents/filePrio", data)
+	if err != nil {
+		return fmt.Errorf("SetFilePriority error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsRenameFile renames or moves a file of a torrent. Paths are relative to the save path and include
+// the torrent's root folder, as in TorrentFile.Name. Requires Web API 2.8.0 (qBittorrent 4.3.3).
+func (c *Client) TorrentsRenameFile(hash, oldPath, newPath string) error {
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("oldPath", oldPath)
+	data.Set("newPath", newPath)
+
+	_, err := c.doPostValues("/api/v2/torrents/renameFile", data)
+	if err != nil {
+		return fmt.Errorf("RenameFile error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsRenameFolder renames or moves a folder of a torrent, with the paths of TorrentsRenameFile.
+// Requires Web API 2.8.0 (qBittorrent 4.3.3).
+func (c *Client) TorrentsRenameFolder(hash, oldPath, newPath string) error {
+	data := url.Values{}
+	data.Set("hash", hash)
+	data.Set("oldPath", oldPath)
+	data.Set("newPath", newPath)
+
+	_, err := c.doPostValues("/api/v2/torrents/renameFolder", data)
+	if err != nil {
+		return fmt.Errorf("RenameFolder error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsCategories retrieves all categories, keyed by name
+func (c *Client) TorrentsCategories() (map[string]Category, error) {
+	respData, err := c.doGet("/api/v2/torrents/categories", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Categories error: %w", err)
+	}
+
+	var categories map[string]Category
+	if err := c.decodeJSON(respData, &categories); err != nil {
+		return nil, fmt.Errorf("failed to decode categories response: %v", err)
+	}
+
+	return categories, nil
+}
+
+// TorrentsCreateCategory creates a category, savePath may be empty to use the default save path
+func (c *Client) TorrentsCreateCategory(category, savePath string) error {
+	data := url.Values{}
+	data.Set("category", category)
+	data.Set("savePath", savePath)
+
+	_, err := c.doPostValues("/api/v2/torrents/createCategory", data)
+	if err != nil {
+		return fmt.Errorf("CreateCategory error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsEditCategory changes the save path of a category, an empty savePath selects the default save path
+func (c *Client) TorrentsEditCategory(category, savePath string) error {
+	data := url.Values{}
+	data.Set("category", category)
+	data.Set("savePath", savePath)
+
+	_, err := c.doPostValues("/api/v2/torrents/editCategory", data)
+	if err != nil {
+		return fmt.Errorf("EditCategory error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsRemoveCategories removes categories, leaving their torrents uncategorized
+func (c *Client) TorrentsRemoveCategories(categories []string) error {
+	data := url.Values{}
+	data.Set("categories", strings.Join(categories, "\n"))
+
+	_, err := c.doPostValues("/api/v2/torrents/removeCategories", data)
+	if err != nil {
+		return fmt.Errorf("RemoveCategories error: %w", err)
+	}
+	return nil
+}
+
+// Share limit values with a special meaning for TorrentsSetShareLimits
+const (
+	ShareLimitGlobal    = -2 // use the global share limit
+	ShareLimitUnlimited = -1 // no share limit
+)
+
+// TorrentsSetShareLimits sets the ratio and seeding time (in minutes) limits of the specified torrents.
+// The inactive seeding time limit of newer servers is set to ShareLimitGlobal, see
+// TorrentsSetShareLimitsInactive. Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetShareLimits(hashes string, ratioLimit float64, seedingTimeLimit int64) error {
+	return c.TorrentsSetShareLimitsInactive(hashes, ratioLimit, seedingTimeLimit, ShareLimitGlobal)
+}
+
+// TorrentsSetShareLimitsInactive sets the ratio, seeding time and inactive seeding time (both in minutes)
+// limits of the specified torrents. Servers before qBittorrent 4.6 ignore the inactive seeding time limit.
+// Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetShareLimitsInactive(hashes string, ratioLimit float64, seedingTimeLimit, inactiveSeedingTimeLimit int64) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("ratioLimit", strconv.FormatFloat(ratioLimit, 'f', -1, 64))
+	data.Set("seedingTimeLimit", strconv.FormatInt(seedingTimeLimit, 10))
+	data.Set("inactiveSeedingTimeLimit", strconv.FormatInt(inactiveSeedingTimeLimit, 10))
+
+	_, err := c.doPostValues("/api/v2/torrents/setShareLimits", data)
+	if err != nil {
+		return fmt.Errorf("SetShareLimits error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsDownloadLimit retrieves the download limits, in bytes per second, of the specified torrents.
+// A limit of 0 means unlimited. Multiple hashes are separated by "|", "all" selects every torrent.
+func (c *Client) TorrentsDownloadLimit(hashes string) (map[InfoHash]int64, error) {
+	limits, err := getHashMap[int64](c, "/api/v2/torrents/downloadLimit", hashes)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsDownloadLimit error: %w", err)
+	}
+	return limits, nil
+}
+
+// TorrentsUploadLimit retrieves the upload limits, in bytes per second, of the specified torrents.
+// A limit of 0 means unlimited. Multiple hashes are separated by "|", "all" selects every torrent.
+func (c *Client) TorrentsUploadLimit(hashes string) (map[InfoHash]int64, error) {
+	limits, err := getHashMap[int64](c, "/api/v2/torrents/uploadLimit", hashes)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsUploadLimit error: %w", err)
+	}
+	return limits, nil
+}
+
+// TorrentsSetDownloadLimit sets the download limit, in bytes per second, of the specified torrents.
+// A limit of 0 removes it. Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetDownloadLimit(hashes string, limit int64) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("limit", strconv.FormatInt(limit, 10))
+
+	_, err := c.doPostValues("/api/v2/torrents/setDownloadLimit", data)
+	if err != nil {
+		return fmt.Errorf("SetDownloadLimit error: %w", err)
+	}
+	return nil
+}
+
+// TorrentsSetUploadLimit sets the upload limit, in bytes per second, of the specified torrents.
+// A limit of 0 removes it. Multiple hashes are separated by "|".
+func (c *Client) TorrentsSetUploadLimit(hashes string, limit int64) error {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+	data.Set("limit", strconv.FormatInt(limit, 10))
+
+	_, err := c.doPostValues("/api/v2/torrents/setUploadLimit", data)
+	if err != nil {
+		return fmt.Errorf("SetUploadLimit error: %w", err)
+	}
+	return nil
+}
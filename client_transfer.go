@@ -0,0 +1,70 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// TransferSpeedLimitsMode reports whether the alternative speed limits are enabled
+func (c *Client) TransferSpeedLimitsMode() (bool, error) {
+	respData, err := c.doGet("/api/v2/transfer/speedLimitsMode", nil)
+	if err != nil {
+		return false, fmt.Errorf("SpeedLimitsMode error: %w", err)
+	}
+	return strings.TrimSpace(string(respData)) == "1", nil
+}
+
+// TransferToggleSpeedLimitsMode switches between the regular and the alternative speed limits
+func (c *Client) TransferToggleSpeedLimitsMode() error {
+	_, err := c.doPost("/api/v2/transfer/toggleSpeedLimitsMode", nil, "")
+	if err != nil {
+		return fmt.Errorf("ToggleSpeedLimitsMode error: %w", err)
+	}
+	return nil
+}
+
+// TransferBanPeers bans peers, given as "host:port" with IPv6 hosts in brackets, from all torrents.
+// Only the host matters, the port merely has to be present.
+func (c *Client) TransferBanPeers(peers []string) error {
+	data := url.Values{}
+	data.Set("peers", strings.Join(peers, "|"))
+
+	_, err := c.doPostValues("/api/v2/transfer/banPeers", data)
+	if err != nil {
+		return fmt.Errorf("BanPeers error: %w", err)
+	}
+	return nil
+}
+
+// TransferInfo is the global transfer state as returned by TransferInfo
+type TransferInfo struct {
+	DLInfoSpeed      int64            `json:"dl_info_speed"` // bytes per second
+	DLInfoData       int64            `json:"dl_info_data"`  // bytes downloaded since qBittorrent started
+	UpInfoSpeed      int64            `json:"up_info_speed"`
+	UpInfoData       int64            `json:"up_info_data"`
+	DLRateLimit      int64            `json:"dl_rate_limit"` // 0 for unlimited
+	UpRateLimit      int64            `json:"up_rate_limit"`
+	DHTNodes         int              `json:"dht_nodes"`
+	ConnectionStatus ConnectionStatus `json:"connection_status"`
+}
+
+// TransferInfo retrieves the global transfer state
+func (c *Client) TransferInfo() (*TransferInfo, error) {
+	return c.transferInfo(context.Background())
+}
+
+// transferInfo is TransferInfo bounded by ctx
+func (c *Client) transferInfo(ctx context.Context) (*TransferInfo, error) {
+	respData, err := c.doGetContext(ctx, "/api/v2/transfer/info", nil)
+	if err != nil {
+		return nil, fmt.Errorf("TransferInfo error: %w", err)
+	}
+
+	var info TransferInfo
+	if err := c.decodeJSON(respData, &info); err != nil {
+		return nil, fmt.Errorf("failed to decode transfer info response: %v", err)
+	}
+	return &info, nil
+}
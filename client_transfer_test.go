@@ -0,0 +1,40 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestTransferSpeedLimitsMode(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":                     {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/transfer/speedLimitsMode":       {statusCode: http.StatusOK, responseBody: "1"},
+		"/api/v2/transfer/toggleSpeedLimitsMode": {statusCode: http.StatusOK},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "GET", url: "/api/v2/transfer/speedLimitsMode"},
+		{method: "POST", url: "/api/v2/transfer/toggleSpeedLimitsMode"},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	enabled, err := client.TransferSpeedLimitsMode()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !enabled {
+		t.Errorf("Expected alternative speed limits to be enabled")
+	}
+
+	if err := client.TransferToggleSpeedLimitsMode(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
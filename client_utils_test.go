@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 )
 
@@ -254,3 +256,36 @@ func TestDoRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestDoRequest_RetryBody(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		body func() io.Reader
+	}{
+		{"in memory", func() io.Reader { return strings.NewReader("a=1&b=2") }},
+		{"stream", func() io.Reader { return io.MultiReader(strings.NewReader("a=1"), strings.NewReader("&b=2")) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var bodies []string
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/api/v2/auth/login" {
+					return
+				}
+				data, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(data))
+				if len(bodies) == 1 {
+					w.WriteHeader(http.StatusForbidden)
+				}
+			}))
+			defer mockServer.Close()
+			client := &Client{baseURL: mockServer.URL, client: mockServer.Client(), username: "user", password: "pass"}
+
+			if _, err := client.doPost("/api/test", tt.body(), "application/x-www-form-urlencoded"); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(bodies) != 2 || bodies[0] != "a=1&b=2" || bodies[1] != bodies[0] {
+				t.Errorf("expected the body sent twice, got %q", bodies)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// exporter keeps the state polled from qBittorrent and renders it as metrics
+type exporter struct {
+	watcher    *qbittorrent.SyncWatcher
+	perTorrent bool
+
+	mu         sync.Mutex
+	up         bool
+	lastScrape time.Time
+}
+
+func newExporter(client *qbittorrent.Client, perTorrent bool) *exporter {
+	return &exporter{
+		watcher:    qbittorrent.NewSyncWatcher(client, qbittorrent.SyncWatcherConfig{}),
+		perTorrent: perTorrent,
+	}
+}
+
+// run polls every interval until ctx is cancelled
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *exporter) poll(ctx context.Context) {
+	_, err := e.watcher.Poll(ctx)
+	if err != nil && ctx.Err() == nil {
+		log.Printf("qbt-exporter: %v", err)
+	}
+
+	e.mu.Lock()
+	e.up = err == nil
+	if e.up {
+		e.lastScrape = time.Now()
+	}
+	e.mu.Unlock()
+}
+
+// ServeHTTP serves the metrics
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	up, lastScrape := e.up, e.lastScrape
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	writeMetrics(bw, e.watcher.State(), up, lastScrape, e.perTorrent)
+	bw.Flush()
+}
+
+// metricWriter writes metric families in the Prometheus text exposition format
+type metricWriter struct {
+	w io.Writer
+}
+
+// family writes the HELP and TYPE lines of a gauge
+func (m metricWriter) family(name, help string) {
+	fmt.Fprintf(m.w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// sample writes one sample, labels are given as name, value pairs
+func (m metricWriter) sample(name string, value float64, labels ...string) {
+	io.WriteString(m.w, name)
+	if len(labels) > 0 {
+		io.WriteString(m.w, "{")
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				io.WriteString(m.w, ",")
+			}
+			fmt.Fprintf(m.w, "%s=\"%s\"", labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		io.WriteString(m.w, "}")
+	}
+	fmt.Fprintf(m.w, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// writeMetrics renders state. The server and aggregate series are omitted until the first successful poll.
+func writeMetrics(w io.Writer, state qbittorrent.SyncState, up bool, lastScrape time.Time, perTorrent bool) {
+	m := metricWriter{w: w}
+
+	m.family("qbittorrent_up", "Whether the last poll of qBittorrent succeeded.")
+	m.sample("qbittorrent_up", boolValue(up))
+	if lastScrape.IsZero() {
+		return
+	}
+	m.family("qbittorrent_last_poll_timestamp_seconds", "Time of the last successful poll.")
+	m.sample("qbittorrent_last_poll_timestamp_seconds", float64(lastScrape.Unix()))
+
+	s := state.ServerState
+	m.family("qbittorrent_download_speed_bytes", "Global download speed in bytes per second.")
+	m.sample("qbittorrent_download_speed_bytes", float64(s.DLInfoSpeed))
+	m.family("qbittorrent_upload_speed_bytes", "Global upload speed in bytes per second.")
+	m.sample("qbittorrent_upload_speed_bytes", float64(s.UpInfoSpeed))
+	m.family("qbittorrent_download_limit_bytes", "Global download speed limit in bytes per second, 0 if unlimited.")
+	m.sample("qbittorrent_download_limit_bytes", float64(s.DLRateLimit))
+	m.family("qbittorrent_upload_limit_bytes", "Global upload speed limit in bytes per second, 0 if unlimited.")
+	m.sample("qbittorrent_upload_limit_bytes", float64(s.UpRateLimit))
+	m.family("qbittorrent_alltime_downloaded_bytes", "Data downloaded over all sessions.")
+	m.sample("qbittorrent_alltime_downloaded_bytes", float64(s.AllTimeDL))
+	m.family("qbittorrent_alltime_uploaded_bytes", "Data uploaded over all sessions.")
+	m.sample("qbittorrent_alltime_uploaded_bytes", float64(s.AllTimeUL))
+	if ratio, err := strconv.ParseFloat(s.GlobalRatio, 64); err == nil {
+		m.family("qbittorrent_global_ratio", "Global share ratio.")
+		m.sample("qbittorrent_global_ratio", ratio)
+	}
+	m.family("qbittorrent_free_space_bytes", "Free space on the default save path's disk.")
+	m.sample("qbittorrent_free_space_bytes", float64(s.FreeSpaceOnDisk))
+	m.family("qbittorrent_dht_nodes", "Number of DHT nodes.")
+	m.sample("qbittorrent_dht_nodes", float64(s.DHTNodes))
+	m.family("qbittorrent_peer_connections", "Number of peer connections.")
+	m.sample("qbittorrent_peer_connections", float64(s.TotalPeerConnections))
+	m.family("qbittorrent_alt_speed_limits_enabled", "Whether the alternative speed limits are active.")
+	m.sample("qbittorrent_alt_speed_limits_enabled", boolValue(s.UseAltSpeedLimits))
+	m.family("qbittorrent_connected", "Whether qBittorrent reports its connection status as connected.")
+	m.sample("qbittorrent_connected", boolValue(s.ConnectionStatus.IsConnected()))
+
+	hashes := make([]qbittorrent.InfoHash, 0, len(state.Torrents))
+	byCategory := make(map[string]int)
+	byState := make(map[string]int)
+	for name := range state.Categories {
+		byCategory[name] = 0
+	}
+	for hash, t := range state.Torrents {
+		hashes = append(hashes, hash)
+		byCategory[t.Category]++
+		byState[t.State]++
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+
+	m.family("qbittorrent_torrents", "Number of torrents.")
+	m.sample("qbittorrent_torrents", float64(len(state.Torrents)))
+	m.family("qbittorrent_category_torrents", "Number of torrents per category, the empty category is uncategorized.")
+	for _, name := range sortedKeys(byCategory) {
+		m.sample("qbittorrent_category_torrents", float64(byCategory[name]), "category", name)
+	}
+	m.family("qbittorrent_state_torrents", "Number of torrents per state.")
+	for _, name := range sortedKeys(byState) {
+		m.sample("qbittorrent_state_torrents", float64(byState[name]), "state", name)
+	}
+
+	if !perTorrent {
+		return
+	}
+	gauges := []struct {
+		name, help string
+		value      func(qbittorrent.TorrentInfo) float64
+	}{
+		{"qbittorrent_torrent_download_speed_bytes", "Torrent download speed in bytes per second.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.DLSpeed) }},
+		{"qbittorrent_torrent_upload_speed_bytes", "Torrent upload speed in bytes per second.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.UpSpeed) }},
+		{"qbittorrent_torrent_ratio", "Torrent share ratio.", func(t qbittorrent.TorrentInfo) float64 { return t.Ratio }},
+		{"qbittorrent_torrent_progress", "Torrent progress between 0 and 1.", func(t qbittorrent.TorrentInfo) float64 { return t.Progress }},
+		{"qbittorrent_torrent_size_bytes", "Size of the selected files of the torrent.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.Size) }},
+		{"qbittorrent_torrent_seeds", "Connected seeds.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.NumSeeds) }},
+		{"qbittorrent_torrent_leechers", "Connected leechers.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.NumLeechs) }},
+		{"qbittorrent_torrent_downloaded_bytes", "Data downloaded for the torrent.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.Downloaded) }},
+		{"qbittorrent_torrent_uploaded_bytes", "Data uploaded for the torrent.", func(t qbittorrent.TorrentInfo) float64 { return float64(t.Uploaded) }},
+	}
+	for _, g := range gauges {
+		m.family(g.name, g.help)
+		for _, hash := range hashes {
+			t := state.Torrents[hash]
+			m.sample(g.name, g.value(t), "hash", string(hash), "name", t.Name, "category", t.Category)
+		}
+	}
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
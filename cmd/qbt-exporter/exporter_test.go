@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+func TestExporter(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "aaa", Name: `Some "quoted" name`, Category: "tv", State: "uploading", Ratio: 1.5, UpSpeed: 1024, NumLeechs: 3})
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "bbb", Name: "Other", State: "downloading", DLSpeed: 2048, Progress: 0.25})
+	server.UpdateServerState(func(s *qbittorrent.ServerState) {
+		s.GlobalRatio = "1.25"
+	})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	e := newExporter(client, true)
+
+	get := func() string {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		body, _ := io.ReadAll(rec.Body)
+		return string(body)
+	}
+
+	if out := get(); !strings.Contains(out, "qbittorrent_up 0\n") || strings.Contains(out, "qbittorrent_torrents ") {
+		t.Errorf("expected only qbittorrent_up before the first poll, got:\n%s", out)
+	}
+
+	e.poll(context.Background())
+	out := get()
+	for _, want := range []string{
+		"# TYPE qbittorrent_up gauge\nqbittorrent_up 1\n",
+		"qbittorrent_download_speed_bytes 2048\n",
+		"qbittorrent_upload_speed_bytes 1024\n",
+		"qbittorrent_global_ratio 1.25\n",
+		"qbittorrent_free_space_bytes 1.099511627776e+12\n",
+		"qbittorrent_torrents 2\n",
+		`qbittorrent_category_torrents{category=""} 1` + "\n",
+		`qbittorrent_category_torrents{category="tv"} 1` + "\n",
+		`qbittorrent_state_torrents{state="downloading"} 1` + "\n",
+		`qbittorrent_torrent_ratio{hash="aaa",name="Some \"quoted\" name",category="tv"} 1.5` + "\n",
+		`qbittorrent_torrent_leechers{hash="aaa",name="Some \"quoted\" name",category="tv"} 3` + "\n",
+		`qbittorrent_torrent_progress{hash="bbb",name="Other",category=""} 0.25` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	e.perTorrent = false
+	if out := get(); strings.Contains(out, "qbittorrent_torrent_ratio") {
+		t.Errorf("expected no per-torrent series, got:\n%s", out)
+	}
+}
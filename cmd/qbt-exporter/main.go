@@ -0,0 +1,78 @@
+// Command qbt-exporter serves qBittorrent statistics in the Prometheus text exposition format.
+//
+// Usage:
+//
+//	qbt-exporter [flags]
+//
+// It polls sync/maindata incrementally and serves the latest values on /metrics. The connection is configured
+// by the QBITTORRENT_* environment variables and the -url, -username and -password flags, like qbt.
+// Per-torrent series can be disabled with -per-torrent=false on instances with many torrents.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+func main() {
+	url := flag.String("url", "", "WebUI URL, e.g. http://localhost:8080 (default $"+qbittorrent.EnvURL+")")
+	username := flag.String("username", "", "WebUI username (default $"+qbittorrent.EnvUsername+")")
+	password := flag.String("password", "", "WebUI password (default $"+qbittorrent.EnvPassword+")")
+	listen := flag.String("listen", ":9846", "address to serve /metrics on")
+	interval := flag.Duration("interval", 15*time.Second, "how often qBittorrent is polled")
+	perTorrent := flag.Bool("per-torrent", true, "export per-torrent series")
+	flag.Parse()
+
+	client, err := newClient(*url, *username, *password)
+	if err != nil {
+		log.Fatalf("qbt-exporter: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := newExporter(client, *perTorrent)
+	go e.run(ctx, *interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	server := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+	log.Printf("qbt-exporter: serving metrics on %s/metrics", *listen)
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatalf("qbt-exporter: %v", err)
+	}
+}
+
+// newClient connects to qBittorrent as configured by the QBITTORRENT_* environment variables, see
+// qbittorrent.NewClientFromEnv, the connection flags that are set taking precedence
+func newClient(url, username, password string) (*qbittorrent.Client, error) {
+	cfg, err := qbittorrent.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		cfg.URL = url
+	}
+	if username != "" {
+		cfg.Username = username
+	}
+	if password != "" {
+		cfg.Password = password
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no WebUI URL, set -url or %s", qbittorrent.EnvURL)
+	}
+	return qbittorrent.NewClientFromConfig(cfg)
+}
@@ -0,0 +1,96 @@
+// Command qbt-top shows a live, htop-style view of a qBittorrent instance.
+//
+// Usage:
+//
+//	qbt-top [flags]
+//
+// It follows the incremental sync/maindata updates and redraws the global transfer speeds and the most active
+// torrents every -interval. The connection is configured by the QBITTORRENT_* environment variables and the
+// -url, -username and -password flags, like qbt. The terminal width is taken from $COLUMNS. Press Ctrl-C to quit.
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+func main() {
+	url := flag.String("url", "", "WebUI URL, e.g. http://localhost:8080 (default $"+qbittorrent.EnvURL+")")
+	username := flag.String("username", "", "WebUI username (default $"+qbittorrent.EnvUsername+")")
+	password := flag.String("password", "", "WebUI password (default $"+qbittorrent.EnvPassword+")")
+	interval := flag.Duration("interval", 2*time.Second, "refresh interval")
+	limit := flag.Int("n", 20, "number of torrents shown")
+	all := flag.Bool("all", false, "show idle torrents too")
+	flag.Parse()
+
+	client, err := newClient(*url, *username, *password)
+	if err != nil {
+		log.Fatalf("qbt-top: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	width := 100
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 40 {
+		width = columns
+	}
+	opts := viewOptions{width: width, limit: *limit, all: *all}
+
+	out := bufio.NewWriter(os.Stdout)
+	fmt.Fprint(out, hideCursor)
+	defer func() {
+		fmt.Fprint(out, showCursor)
+		out.Flush()
+	}()
+
+	watcher := qbittorrent.NewSyncWatcher(client, qbittorrent.SyncWatcherConfig{})
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	for {
+		var pollErr error
+		if _, err := watcher.Poll(ctx); err != nil {
+			pollErr = err
+		}
+		fmt.Fprint(out, clearScreen)
+		render(out, watcher.State(), opts, pollErr)
+		out.Flush()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// newClient connects to qBittorrent as configured by the QBITTORRENT_* environment variables, see
+// qbittorrent.NewClientFromEnv, the connection flags that are set taking precedence
+func newClient(url, username, password string) (*qbittorrent.Client, error) {
+	cfg, err := qbittorrent.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		cfg.URL = url
+	}
+	if username != "" {
+		cfg.Username = username
+	}
+	if password != "" {
+		cfg.Password = password
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no WebUI URL, set -url or %s", qbittorrent.EnvURL)
+	}
+	return qbittorrent.NewClientFromConfig(cfg)
+}
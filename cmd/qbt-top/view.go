@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// ANSI escape sequences
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	bold        = "\x1b[1m"
+	reset       = "\x1b[0m"
+)
+
+// viewOptions controls what render draws
+type viewOptions struct {
+	width int  // terminal columns
+	limit int  // maximum number of torrent rows
+	all   bool // include torrents that are not transferring
+}
+
+// render draws one frame of the dashboard for state. pollErr, if set, is shown below the header.
+func render(w io.Writer, state qbittorrent.SyncState, opts viewOptions, pollErr error) {
+	s := state.ServerState
+	var downloading, seeding int
+	for _, t := range state.Torrents {
+		switch {
+		case t.DLSpeed > 0:
+			downloading++
+		case t.UpSpeed > 0:
+			seeding++
+		}
+	}
+
+	fmt.Fprintf(w, "%sqbt-top%s  %s  DHT nodes: %d  peers: %d  free: %s\n", bold, reset,
+		s.ConnectionStatus, s.DHTNodes, s.TotalPeerConnections, qbittorrent.FormatBytes(s.FreeSpaceOnDisk, qbittorrent.IEC))
+	fmt.Fprintf(w, "DL %12s  UL %12s  ratio %s  torrents: %d (%d downloading, %d uploading)",
+		qbittorrent.FormatSpeed(int64(s.DLInfoSpeed), qbittorrent.IEC), qbittorrent.FormatSpeed(int64(s.UpInfoSpeed), qbittorrent.IEC), s.GlobalRatio,
+		len(state.Torrents), downloading, seeding)
+	if s.UseAltSpeedLimits {
+		fmt.Fprint(w, "  [alt speed]")
+	}
+	fmt.Fprintln(w)
+	if pollErr != nil {
+		fmt.Fprintf(w, "error: %v\n", pollErr)
+	}
+	fmt.Fprintln(w)
+
+	// Fixed columns take 56 characters plus the progress bar, the name gets the rest
+	barWidth := 20
+	nameWidth := opts.width - 56 - barWidth
+	if nameWidth < 10 {
+		nameWidth = 10
+	}
+	fmt.Fprintf(w, "%s%-*s %-*s %6s %11s %11s %8s %12s%s\n", bold, nameWidth, "NAME", barWidth+2, "PROGRESS", "", "DOWN", "UP", "ETA", "STATE", reset)
+	for _, t := range activeTorrents(state.Torrents, opts) {
+		fmt.Fprintf(w, "%-*s [%s] %5.1f%% %11s %11s %8s %12s\n",
+			nameWidth, truncate(t.Name, nameWidth), progressBar(t.Progress, barWidth), t.ProgressPercent(),
+			qbittorrent.FormatSpeed(t.DLSpeed, qbittorrent.IEC), qbittorrent.FormatSpeed(t.UpSpeed, qbittorrent.IEC),
+			qbittorrent.FormatETA(t.ETA), truncate(t.State, 12))
+	}
+}
+
+// activeTorrents returns the torrents to show, busiest first
+func activeTorrents(torrents map[qbittorrent.InfoHash]qbittorrent.TorrentInfo, opts viewOptions) []qbittorrent.TorrentInfo {
+	var out []qbittorrent.TorrentInfo
+	for _, t := range torrents {
+		if opts.all || t.DLSpeed > 0 || t.UpSpeed > 0 {
+			out = append(out, t)
+		}
+	}
+	qbittorrent.SortTorrents(out, qbittorrent.Descending(qbittorrent.BySpeed), qbittorrent.ByName)
+	if opts.limit > 0 && len(out) > opts.limit {
+		out = out[:opts.limit]
+	}
+	return out
+}
+
+func progressBar(progress float64, width int) string {
+	filled := int(progress * float64(width))
+	if filled > width {
+		filled = width
+	}
+	if filled < 0 {
+		filled = 0
+	}
+	return strings.Repeat("#", filled) + strings.Repeat(".", width-filled)
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+	return string(r[:width-1]) + "…"
+}
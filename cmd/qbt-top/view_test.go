@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+func TestRender(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "aaa", Name: "Busy", State: "downloading", Progress: 0.5, DLSpeed: 4096, ETA: 3725})
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "bbb", Name: "Seeding", State: "uploading", Progress: 1, UpSpeed: 1024})
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "ccc", Name: "Idle", State: "stalledUP", Progress: 1, ETA: 8640000})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	watcher := qbittorrent.NewSyncWatcher(client, qbittorrent.SyncWatcherConfig{})
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	render(&buf, watcher.State(), viewOptions{width: 100, limit: 10}, nil)
+	out := buf.String()
+	for _, want := range []string{
+		"DL    4.0 KiB/s",
+		"UL    1.0 KiB/s",
+		"torrents: 3 (1 downloading, 1 uploading)",
+		"[##########..........]  50.0%",
+		"1h02m",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "Busy") > strings.Index(out, "Seeding") {
+		t.Errorf("expected the busiest torrent first, got:\n%s", out)
+	}
+	if strings.Contains(out, "Idle") {
+		t.Errorf("expected idle torrents to be hidden, got:\n%s", out)
+	}
+
+	buf.Reset()
+	render(&buf, watcher.State(), viewOptions{width: 100, limit: 10, all: true}, nil)
+	if !strings.Contains(buf.String(), "Idle") {
+		t.Errorf("expected idle torrents with all, got:\n%s", buf.String())
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("abcdef", 4); got != "abc…" {
+		t.Errorf("expected abc…, got %q", got)
+	}
+	if got := truncate("abc", 4); got != "abc" {
+		t.Errorf("expected abc, got %q", got)
+	}
+}
@@ -0,0 +1,426 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+func commands() map[string]command {
+	return map[string]command{
+		"list":     {name: "list", args: "[flags]", summary: "List torrents", run: cmdList},
+		"add":      {name: "add", args: "[flags] <file|url>...", summary: "Add torrents from .torrent files, magnet links or URLs", run: cmdAdd},
+		"delete":   {name: "delete", args: "[flags] <hash>...", summary: "Delete torrents", run: cmdDelete},
+		"pause":    {name: "pause", args: "<hash|all>...", summary: "Pause torrents", run: cmdPause},
+		"resume":   {name: "resume", args: "<hash|all>...", summary: "Resume torrents", run: cmdResume},
+		"tag":      {name: "tag", args: "list | create <tag>... | delete <tag>... | add <tag,...> <hash>... | remove <tag,...> <hash>...", summary: "Manage tags", run: cmdTag},
+		"category": {name: "category", args: "list | create <name> [save path] | set <name> <hash>...", summary: "Manage categories", run: cmdCategory},
+		"info":     {name: "info", args: "<hash>", summary: "Show the details and files of a torrent", run: cmdInfo},
+		"trackers": {name: "trackers", args: "<hash>", summary: "Show the trackers of a torrent", run: cmdTrackers},
+		"export":   {name: "export", args: "[flags] <hash>", summary: "Export the .torrent file of a torrent", run: cmdExport},
+		"prefs":    {name: "prefs", args: "[get <key>... | set <key>=<value>... | backup [file] | restore <file>]", summary: "Show, change, back up or restore application preferences", run: cmdPrefs},
+	}
+}
+
+func cmdList(e *env, args []string) error {
+	fs := e.flags("list")
+	params := &qbittorrent.TorrentsInfoParams{}
+	fs.StringVar(&params.Filter, "filter", "", "state filter, e.g. downloading, completed, paused, errored")
+	fs.StringVar(&params.Category, "category", "", "only torrents in this category")
+	fs.StringVar(&params.Tag, "tag", "", "only torrents with this tag")
+	fs.StringVar(&params.Sort, "sort", "", "field to sort by, e.g. name, added_on, ratio")
+	fs.BoolVar(&params.Reverse, "reverse", false, "reverse the sort order")
+	asJSON := fs.Bool("json", false, "print the torrents as JSON")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	torrents, err := e.client.TorrentsInfo(params)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		listed := make([]listedTorrent, len(torrents))
+		for i, t := range torrents {
+			listed[i] = listedTorrent{TorrentInfo: t, Tags: t.Tags}
+		}
+		return printJSON(e, listed)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HASH\tNAME\tSTATE\tPROGRESS\tSIZE\tRATIO\tCATEGORY\tTAGS")
+	for _, t := range torrents {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.1f%%\t%s\t%.2f\t%s\t%s\n",
+			t.Hash, t.Name, t.State, t.ProgressPercent(), qbittorrent.FormatBytes(t.Size, qbittorrent.IEC), t.Ratio, t.Category, strings.Join(t.Tags, ","))
+	}
+	return tw.Flush()
+}
+
+// listedTorrent is a torrent as printed by list -json, with the tags TorrentInfo leaves out of its JSON
+type listedTorrent struct {
+	qbittorrent.TorrentInfo
+	Tags []string `json:"tags"`
+}
+
+func cmdAdd(e *env, args []string) error {
+	fs := e.flags("add")
+	category := fs.String("category", "", "category to assign")
+	tags := fs.String("tags", "", "comma separated tags to assign")
+	savePath := fs.String("savepath", "", "download directory")
+	paused := fs.Bool("paused", false, "add the torrents paused")
+	skipChecking := fs.Bool("skip-checking", false, "skip hash checking of existing data")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	var opts []qbittorrent.TorrentAddOption
+	if *category != "" {
+		opts = append(opts, qbittorrent.WithCategory(*category))
+	}
+	if *tags != "" {
+		opts = append(opts, qbittorrent.WithTags(strings.Split(*tags, ",")))
+	}
+	if *savePath != "" {
+		opts = append(opts, qbittorrent.WithSavePath(*savePath))
+	}
+	if *paused {
+		opts = append(opts, qbittorrent.WithStartPaused(true))
+	}
+	if *skipChecking {
+		opts = append(opts, qbittorrent.WithSkipChecking(true))
+	}
+
+	var urls []string
+	for _, arg := range fs.Args() {
+		if isURL(arg) {
+			urls = append(urls, arg)
+			continue
+		}
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			return err
+		}
+		if err := e.client.TorrentsAddWithOptions(filepath.Base(arg), data, opts...); err != nil {
+			return fmt.Errorf("%s: %v", arg, err)
+		}
+	}
+	if len(urls) > 0 {
+		return e.client.TorrentsAddURLs(urls, opts...)
+	}
+	return nil
+}
+
+func isURL(arg string) bool {
+	for _, prefix := range []string{"magnet:", "http://", "https://"} {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func cmdDelete(e *env, args []string) error {
+	fs := e.flags("delete")
+	deleteFiles := fs.Bool("files", false, "also delete the downloaded data")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	return e.client.TorrentsRemove(strings.Join(fs.Args(), "|"), *deleteFiles)
+}
+
+func cmdPause(e *env, args []string) error {
+	fs := e.flags("pause")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	all, err := selectsAll(fs.Args())
+	if err != nil {
+		return err
+	}
+	if all {
+		return e.client.PauseAll()
+	}
+	return e.client.TorrentsPause(strings.Join(fs.Args(), "|"))
+}
+
+func cmdResume(e *env, args []string) error {
+	fs := e.flags("resume")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	all, err := selectsAll(fs.Args())
+	if err != nil {
+		return err
+	}
+	if all {
+		return e.client.ResumeAll()
+	}
+	return e.client.TorrentsResume(strings.Join(fs.Args(), "|"))
+}
+
+// selectsAll reports whether args is the single argument "all", which must not be mixed with hashes
+func selectsAll(args []string) (bool, error) {
+	for _, arg := range args {
+		if arg == string(qbittorrent.AllTorrents) {
+			if len(args) > 1 {
+				return false, fmt.Errorf("%q cannot be combined with hashes", arg)
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func cmdTag(e *env, args []string) error {
+	fs := e.flags("tag")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	sub, rest := fs.Arg(0), fs.Args()[1:]
+
+	switch {
+	case sub == "list":
+		tags, err := e.client.TorrentsGetAllTags()
+		if err != nil {
+			return err
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			fmt.Fprintln(e.stdout, tag)
+		}
+		return nil
+	case sub == "create" && len(rest) > 0:
+		return e.client.TorrentsCreateTags(rest)
+	case sub == "delete" && len(rest) > 0:
+		return e.client.TorrentsDeleteTags(rest)
+	case sub == "add" && len(rest) > 1:
+		return e.client.TorrentsAddTags(strings.Join(rest[1:], "|"), strings.Split(rest[0], ","))
+	case sub == "remove" && len(rest) > 1:
+		return e.client.TorrentsRemoveTags(strings.Join(rest[1:], "|"), strings.Split(rest[0], ","))
+	}
+	fs.Usage()
+	return errUsage
+}
+
+func cmdCategory(e *env, args []string) error {
+	fs := e.flags("category")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	sub, rest := fs.Arg(0), fs.Args()[1:]
+
+	switch {
+	case sub == "list":
+		categories, err := e.client.TorrentsCategories()
+		if err != nil {
+			return err
+		}
+		names := make([]string, 0, len(categories))
+		for name := range categories {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tSAVE PATH")
+		for _, name := range names {
+			fmt.Fprintf(tw, "%s\t%v\n", name, categories[name]["savePath"])
+		}
+		return tw.Flush()
+	case sub == "create" && (len(rest) == 1 || len(rest) == 2):
+		savePath := ""
+		if len(rest) == 2 {
+			savePath = rest[1]
+		}
+		return e.client.TorrentsCreateCategory(rest[0], savePath)
+	case sub == "set" && len(rest) > 1:
+		return e.client.TorrentsSetCategory(strings.Join(rest[1:], "|"), rest[0])
+	}
+	fs.Usage()
+	return errUsage
+}
+
+func cmdInfo(e *env, args []string) error {
+	fs := e.flags("info")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+	hash := fs.Arg(0)
+
+	torrents, err := e.client.TorrentsInfo(&qbittorrent.TorrentsInfoParams{Hashes: []string{hash}})
+	if err != nil {
+		return err
+	}
+	if len(torrents) == 0 {
+		return fmt.Errorf("torrent %s not found", hash)
+	}
+	t := torrents[0]
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Name:\t%s\n", t.Name)
+	fmt.Fprintf(tw, "Hash:\t%s\n", t.Hash)
+	fmt.Fprintf(tw, "State:\t%s\n", t.State)
+	fmt.Fprintf(tw, "Progress:\t%.1f%%\n", t.ProgressPercent())
+	fmt.Fprintf(tw, "Size:\t%s\n", qbittorrent.FormatBytes(t.Size, qbittorrent.IEC))
+	fmt.Fprintf(tw, "Downloaded:\t%s\n", qbittorrent.FormatBytes(t.Downloaded, qbittorrent.IEC))
+	fmt.Fprintf(tw, "Uploaded:\t%s\n", qbittorrent.FormatBytes(t.Uploaded, qbittorrent.IEC))
+	fmt.Fprintf(tw, "Ratio:\t%.2f\n", t.Ratio)
+	fmt.Fprintf(tw, "Save path:\t%s\n", t.SavePath)
+	fmt.Fprintf(tw, "Category:\t%s\n", t.Category)
+	fmt.Fprintf(tw, "Tags:\t%s\n", strings.Join(t.Tags, ","))
+	fmt.Fprintf(tw, "Tracker:\t%s\n", t.Tracker)
+	fmt.Fprintf(tw, "Added:\t%s\n", formatTime(t.AddedAt()))
+	fmt.Fprintf(tw, "Completed:\t%s\n", formatTime(t.CompletedAt()))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	files, err := e.client.TorrentsFiles(hash)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	fmt.Fprintln(e.stdout, "\nFiles:")
+	tw = tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	for _, f := range files {
+		fmt.Fprintf(tw, "  %s\t%s\t%.1f%%\n", f.Name, qbittorrent.FormatBytes(f.Size, qbittorrent.IEC), f.Progress*100)
+	}
+	return tw.Flush()
+}
+
+func cmdTrackers(e *env, args []string) error {
+	fs := e.flags("trackers")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	trackers, err := e.client.TorrentsTrackers(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(e.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "URL\tSTATUS\tTIER\tPEERS\tMESSAGE")
+	for _, t := range trackers {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", t.URL, t.Status, t.Tier, t.NumPeers, t.Msg)
+	}
+	return tw.Flush()
+}
+
+func cmdExport(e *env, args []string) error {
+	fs := e.flags("export")
+	output := fs.String("o", "", "file to write to instead of standard output")
+	if err := parse(fs, args, 1); err != nil {
+		return err
+	}
+
+	data, err := e.client.TorrentsExport(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = e.stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
+
+func cmdPrefs(e *env, args []string) error {
+	fs := e.flags("prefs")
+	if err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	if fs.Arg(0) == "set" && fs.NArg() > 1 {
+		prefs := qbittorrent.Preferences{}
+		for _, arg := range fs.Args()[1:] {
+			key, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return fmt.Errorf("expected <key>=<value>, got %q", arg)
+			}
+			prefs[key] = parseValue(value)
+		}
+		return e.client.AppSetPreferences(prefs)
+	}
+	if fs.Arg(0) == "backup" && fs.NArg() <= 2 {
+		if fs.NArg() == 1 {
+			return e.client.AppPreferencesBackup(e.stdout)
+		}
+		f, err := os.Create(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		if err := e.client.AppPreferencesBackup(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	if fs.Arg(0) == "restore" && fs.NArg() == 2 {
+		f, err := os.Open(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		restored, err := e.client.AppPreferencesRestore(f)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(e.stdout, "restored %d preferences\n", len(restored))
+		return nil
+	}
+
+	prefs, err := e.client.AppPreferences()
+	if err != nil {
+		return err
+	}
+	switch {
+	case fs.NArg() == 0:
+		return printJSON(e, prefs)
+	case fs.Arg(0) == "get" && fs.NArg() > 1:
+		for _, key := range fs.Args()[1:] {
+			value, ok := prefs[key]
+			if !ok {
+				return fmt.Errorf("unknown preference %q", key)
+			}
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(e.stdout, "%s=%s\n", key, data)
+		}
+		return nil
+	}
+	fs.Usage()
+	return errUsage
+}
+
+// parseValue interprets a preference value as JSON, so numbers and booleans keep their type,
+// and falls back to a plain string
+func parseValue(value string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return value
+	}
+	return v
+}
+
+func printJSON(e *env, v interface{}) error {
+	enc := json.NewEncoder(e.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
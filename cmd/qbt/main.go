@@ -0,0 +1,155 @@
+// Command qbt manages a qBittorrent instance from the command line.
+//
+// Usage:
+//
+//	qbt [global flags] <command> [flags] [arguments]
+//
+// The connection is configured by the QBITTORRENT_* environment variables read by
+// qbittorrent.NewClientFromEnv, e.g. QBITTORRENT_URL, QBITTORRENT_USERNAME and QBITTORRENT_PASSWORD, which the
+// global flags -url, -username and -password override. Run "qbt help" for the commands.
+//
+// Every command is implemented with the public API of the qbittorrent package only, so the source doubles as
+// usage examples and the tool as a smoke test against a real server.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// command is a qbt subcommand
+type command struct {
+	name    string
+	args    string // argument synopsis shown in the usage
+	summary string
+	run     func(e *env, args []string) error
+}
+
+// env is what a command runs with
+type env struct {
+	client *qbittorrent.Client
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// errUsage reports invalid arguments, the command's usage has already been printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("qbt", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	url := global.String("url", "", "WebUI URL, e.g. http://localhost:8080 (default $"+qbittorrent.EnvURL+")")
+	username := global.String("username", "", "WebUI username (default $"+qbittorrent.EnvUsername+")")
+	password := global.String("password", "", "WebUI password (default $"+qbittorrent.EnvPassword+")")
+	global.Usage = func() { usage(global, stderr) }
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if global.NArg() == 0 {
+		usage(global, stderr)
+		return 2
+	}
+
+	name := global.Arg(0)
+	if name == "help" {
+		usage(global, stdout)
+		return 0
+	}
+	cmd, ok := commands()[name]
+	if !ok {
+		fmt.Fprintf(stderr, "qbt: unknown command %q, run \"qbt help\" for usage\n", name)
+		return 2
+	}
+
+	client, err := newClient(*url, *username, *password)
+	if err != nil {
+		fmt.Fprintf(stderr, "qbt: %v\n", err)
+		return 1
+	}
+	if err := cmd.run(&env{client: client, stdout: stdout, stderr: stderr}, global.Args()[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "qbt %s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+func usage(global *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "Usage: qbt [global flags] <command> [flags] [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	cmds := commands()
+	names := make([]string, 0, len(cmds))
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(tw, "  %s\t%s\n", name, cmds[name].summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w, "\nGlobal flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	fmt.Fprintln(w, "\nRun \"qbt <command> -h\" for the flags of a command.")
+}
+
+// flags creates the flag set of a command, printing the command's usage on errors
+func (e *env) flags(name string) *flag.FlagSet {
+	cmd := commands()[name]
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: qbt %s %s\n\n%s\n", name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the command's flags and checks it has at least min positional arguments
+func parse(fs *flag.FlagSet, args []string, min int) error {
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+	if fs.NArg() < min {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// newClient connects to qBittorrent as configured by the QBITTORRENT_* environment variables, see
+// qbittorrent.NewClientFromEnv, the connection flags that are set taking precedence
+func newClient(url, username, password string) (*qbittorrent.Client, error) {
+	cfg, err := qbittorrent.ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if url != "" {
+		cfg.URL = url
+	}
+	if username != "" {
+		cfg.Username = username
+	}
+	if password != "" {
+		cfg.Password = password
+	}
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("no WebUI URL, set -url or %s", qbittorrent.EnvURL)
+	}
+	return qbittorrent.NewClientFromConfig(cfg)
+}
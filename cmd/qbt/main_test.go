@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+// qbt runs the command line against server and returns the exit code and output
+func qbt(server *qbittorrenttest.Server, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"-url", server.URL, "-username", "admin", "-password", "secret"}, args...), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "abc", Name: "Example", State: "uploading", Progress: 1, Size: 2048})
+	backup := filepath.Join(t.TempDir(), "prefs.json")
+
+	steps := []struct {
+		args   []string
+		stdout []string // substrings expected in the output
+	}{
+		{args: []string{"list"}, stdout: []string{"abc", "Example", "uploading", "100.0%", "2.0 KiB"}},
+		{args: []string{"category", "create", "tv", "/tv"}},
+		{args: []string{"category", "set", "tv", "abc"}},
+		{args: []string{"category", "list"}, stdout: []string{"tv", "/tv"}},
+		{args: []string{"tag", "add", "hd,new", "abc"}},
+		{args: []string{"tag", "list"}, stdout: []string{"hd\nnew\n"}},
+		{args: []string{"list", "-tag", "hd", "-json"}, stdout: []string{`"hash": "abc"`, `"category": "tv"`, `"tags": [`, `"hd"`}},
+		{args: []string{"pause", "abc"}},
+		{args: []string{"info", "abc"}, stdout: []string{"Example", "pausedUP", "tv"}},
+		{args: []string{"resume", "all"}},
+		{args: []string{"trackers", "abc"}, stdout: []string{"** [DHT] **", "working"}},
+		{args: []string{"add", "-category", "tv", "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=Other"}},
+		{args: []string{"prefs", "set", "save_path=/data/", "dht=false"}},
+		{args: []string{"prefs", "get", "save_path", "dht"}, stdout: []string{`save_path="/data/"`, "dht=false"}},
+		{args: []string{"prefs", "backup", backup}},
+		{args: []string{"prefs", "set", "dht=true"}},
+		{args: []string{"prefs", "restore", backup}, stdout: []string{"restored 1 preferences"}},
+		{args: []string{"prefs", "get", "dht"}, stdout: []string{"dht=false"}},
+		{args: []string{"delete", "-files", "abc"}},
+		{args: []string{"list"}, stdout: []string{"Other"}},
+	}
+	for _, step := range steps {
+		code, stdout, stderr := qbt(server, step.args...)
+		if code != 0 {
+			t.Fatalf("%v: expected exit code 0, got %d: %s", step.args, code, stderr)
+		}
+		for _, want := range step.stdout {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("%v: expected output to contain %q, got:\n%s", step.args, want, stdout)
+			}
+		}
+	}
+
+	torrents := server.Torrents()
+	if len(torrents) != 1 || torrents[0].Name != "Other" || torrents[0].Category != "tv" {
+		t.Errorf("unexpected torrents %+v", torrents)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+
+	if code, _, stderr := qbt(server, "unknown"); code != 2 || !strings.Contains(stderr, "unknown command") {
+		t.Errorf("expected exit code 2 for an unknown command, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := qbt(server, "tag", "add", "hd"); code != 2 || !strings.Contains(stderr, "Usage: qbt tag") {
+		t.Errorf("expected exit code 2 with usage, got %d: %s", code, stderr)
+	}
+	if code, _, stderr := qbt(server, "info", "missing"); code != 1 || !strings.Contains(stderr, "not found") {
+		t.Errorf("expected exit code 1 for a missing torrent, got %d: %s", code, stderr)
+	}
+}
+
+func TestRun_UsageHidesPassword(t *testing.T) {
+	t.Setenv(qbittorrent.EnvPassword, "hunter2")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-h"}, &stdout, &stderr); code != 2 {
+		t.Errorf("expected exit code 2, got %d", code)
+	}
+	if strings.Contains(stderr.String(), "hunter2") || !strings.Contains(stderr.String(), "-password") {
+		t.Errorf("expected the usage without the password, got %s", stderr.String())
+	}
+}
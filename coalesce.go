@@ -0,0 +1,66 @@
+package qbittorrent
+
+import (
+	"net/url"
+	"sync"
+)
+
+// WithRequestCoalescing makes concurrent identical GET requests, same endpoint and query, share a single
+// request to qBittorrent: callers arriving while one is in flight wait for it and receive its response or
+// error. Web frontends rendering the same data from several handlers then send it once. A GET issued after
+// a POST made through the client has returned never joins a request started before it.
+func WithRequestCoalescing() ClientOption {
+	return func(c *Client) {
+		c.flights = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+}
+
+// flightCall is a GET request in flight
+type flightCall struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+// flightGroup coalesces concurrent calls with the same key, like golang.org/x/sync/singleflight
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightKey returns the key of a GET request
+func flightKey(endpoint string, query url.Values) string {
+	return endpoint + "?" + query.Encode()
+}
+
+// do calls fn unless a call with key is in flight, in which case it waits for that call and returns its
+// result instead
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+	call := &flightCall{done: make(chan struct{})}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		if g.calls[key] == call {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		close(call.done)
+	}()
+	call.data, call.err = fn()
+	return call.data, call.err
+}
+
+// forget makes the calls in flight unavailable to later callers, which then start new ones
+func (g *flightGroup) forget() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	clear(g.calls)
+}
@@ -0,0 +1,93 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithRequestCoalescing(t *testing.T) {
+	var requests atomic.Int32
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte("Ok."))
+			return
+		}
+		requests.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Write([]byte(`[{"hash":"abc","name":"a"}]`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithRequestCoalescing()(client)
+
+	var wg sync.WaitGroup
+	results := make([][]TorrentInfo, 5)
+	get := func(i int) {
+		defer wg.Done()
+		torrents, err := client.TorrentsInfo()
+		if err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+		results[i] = torrents
+	}
+	wg.Add(1)
+	go get(0)
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go get(i)
+	}
+	time.Sleep(50 * time.Millisecond) // let the other callers join the request in flight
+	close(release)
+	wg.Wait()
+
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+	for i, torrents := range results {
+		if len(torrents) != 1 || torrents[0].Name != "a" {
+			t.Errorf("caller %d: unexpected torrents %v", i, torrents)
+		}
+	}
+}
+
+func TestFlightGroup_Forget(t *testing.T) {
+	g := &flightGroup{calls: make(map[string]*flightCall)}
+	release := make(chan struct{})
+	first := make(chan []byte)
+	go func() {
+		data, _ := g.do("key", func() ([]byte, error) {
+			<-release
+			return []byte("before"), nil
+		})
+		first <- data
+	}()
+	for {
+		g.mu.Lock()
+		n := len(g.calls)
+		g.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// A POST made meanwhile: later callers do not join the call started before it
+	g.forget()
+	data, _ := g.do("key", func() ([]byte, error) { return []byte("after"), nil })
+	if string(data) != "after" {
+		t.Errorf("expected a new call, got %s", data)
+	}
+	close(release)
+	if data := <-first; string(data) != "before" {
+		t.Errorf("unexpected first result %s", data)
+	}
+}
@@ -0,0 +1,156 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CohortTaggerConfig configures a CohortTagger. Zero values select the defaults.
+type CohortTaggerConfig struct {
+	Prefix   string         // prefix of the tags, followed by the year and month, default "completed:"
+	Location *time.Location // time zone the months are in, default time.Local
+	Interval time.Duration  // how often Run tags the torrents, default 1h
+
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// CohortTagChange describes the torrents a CohortTagger added a tag to or removed it from
+type CohortTagChange struct {
+	Tag     string
+	Added   []InfoHash
+	Removed []InfoHash
+}
+
+// CohortTagger tags completed torrents with the month they completed in, as "completed:2024-06", so
+// retention policies can select whole cohorts by tag. Tags with the prefix that do not match the completion
+// month, e.g. of torrents that were rechecked and are incomplete again, are removed.
+type CohortTagger struct {
+	client *Client
+	cfg    CohortTaggerConfig
+}
+
+// NewCohortTagger creates a CohortTagger for the given client
+func NewCohortTagger(client *Client, cfg CohortTaggerConfig) *CohortTagger {
+	if cfg.Prefix == "" {
+		cfg.Prefix = "completed:"
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Hour
+	}
+	return &CohortTagger{client: client, cfg: cfg}
+}
+
+// Run tags the torrents every Interval until ctx is cancelled
+func (t *CohortTagger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := t.RunOnce(ctx); err != nil && ctx.Err() == nil && t.cfg.OnError != nil {
+			t.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tag returns the tag of torrent, "" if it did not complete
+func (t *CohortTagger) Tag(torrent TorrentInfo) string {
+	completed := torrent.CompletedAt()
+	if completed.IsZero() {
+		return ""
+	}
+	return t.cfg.Prefix + completed.In(t.cfg.Location).Format("2006-01")
+}
+
+// RunOnce tags all torrents and returns the changes made, one entry per changed tag
+func (t *CohortTagger) RunOnce(ctx context.Context) ([]CohortTagChange, error) {
+	data, err := t.client.SyncMainData(0)
+	if err != nil {
+		return nil, fmt.Errorf("CohortTagger error: %w", err)
+	}
+
+	changed := make(map[string]*CohortTagChange)
+	change := func(tag string) *CohortTagChange {
+		if changed[tag] == nil {
+			changed[tag] = &CohortTagChange{Tag: tag}
+		}
+		return changed[tag]
+	}
+	for hash, torrent := range data.Torrents {
+		torrent.Hash = InfoHash(hash)
+		want := t.Tag(torrent)
+		if want != "" && !hasTag(torrent, want) {
+			change(want).Added = append(change(want).Added, torrent.Hash)
+		}
+		for _, tag := range torrent.Tags {
+			if tag != want && strings.HasPrefix(tag, t.cfg.Prefix) {
+				change(tag).Removed = append(change(tag).Removed, torrent.Hash)
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(changed))
+	for tag := range changed {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var changes []CohortTagChange
+	for _, tag := range tags {
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+		c := changed[tag]
+		sortHashes(c.Added)
+		sortHashes(c.Removed)
+		if len(c.Added) > 0 {
+			if err := t.client.TorrentsAddTags(joinHashes(c.Added), []string{tag}); err != nil {
+				return changes, fmt.Errorf("CohortTagger error: %w", err)
+			}
+		}
+		if len(c.Removed) > 0 {
+			if err := t.client.TorrentsRemoveTags(joinHashes(c.Removed), []string{tag}); err != nil {
+				return changes, fmt.Errorf("CohortTagger error: %w", err)
+			}
+		}
+		changes = append(changes, *c)
+	}
+	return changes, nil
+}
+
+// Cleanup deletes all tags with the prefix, removing them from the torrents, for when the retention policy
+// relying on them is dropped. It returns the deleted tags.
+func (t *CohortTagger) Cleanup(ctx context.Context) ([]string, error) {
+	data, err := t.client.SyncMainData(0)
+	if err != nil {
+		return nil, fmt.Errorf("CohortTagger error: %w", err)
+	}
+	var tags []string
+	for _, tag := range data.Tags {
+		if strings.HasPrefix(tag, t.cfg.Prefix) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(tags)
+	if err := t.client.TorrentsDeleteTags(tags); err != nil {
+		return nil, fmt.Errorf("CohortTagger error: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,71 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCohortTagger_RunOnce(t *testing.T) {
+	var calls []url.Values
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/sync/maindata":
+			w.Write([]byte(`{
+				"torrents": {
+					"hash1": {"tags": "", "completion_on": 1717243200},
+					"hash2": {"tags": "completed:2024-06, keep", "completion_on": 1717243200},
+					"hash3": {"tags": "completed:2024-06", "completion_on": 1719835200},
+					"hash4": {"tags": "completed:2024-07", "completion_on": -1}
+				},
+				"tags": ["completed:2024-06", "completed:2024-07", "keep"]
+			}`))
+		case "/api/v2/torrents/addTags", "/api/v2/torrents/removeTags", "/api/v2/torrents/deleteTags":
+			r.ParseForm()
+			r.PostForm.Set("path", r.URL.Path)
+			calls = append(calls, r.PostForm)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	tagger := NewCohortTagger(client, CohortTaggerConfig{Location: time.UTC})
+
+	changes, err := tagger.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantChanges := []CohortTagChange{
+		{Tag: "completed:2024-06", Added: []InfoHash{"hash1"}, Removed: []InfoHash{"hash3"}},
+		{Tag: "completed:2024-07", Added: []InfoHash{"hash3"}, Removed: []InfoHash{"hash4"}},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("expected changes %+v, got %+v", wantChanges, changes)
+	}
+
+	deleted, err := tagger.Cleanup(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := []string{"completed:2024-06", "completed:2024-07"}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("expected deleted tags %v, got %v", want, deleted)
+	}
+
+	wantCalls := []url.Values{
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"hash1"}, "tags": {"completed:2024-06"}},
+		{"path": {"/api/v2/torrents/removeTags"}, "hashes": {"hash3"}, "tags": {"completed:2024-06"}},
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"hash3"}, "tags": {"completed:2024-07"}},
+		{"path": {"/api/v2/torrents/removeTags"}, "hashes": {"hash4"}, "tags": {"completed:2024-07"}},
+		{"path": {"/api/v2/torrents/deleteTags"}, "tags": {"completed:2024-06,completed:2024-07"}},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("expected calls %v, got %v", wantCalls, calls)
+	}
+}
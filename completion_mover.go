@@ -0,0 +1,87 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CompletionMoverConfig configures a CompletionMover. The built-in actions run before Action, in field order.
+type CompletionMoverConfig struct {
+	Location string   // move the data of completed torrents here, if set
+	Category string   // assign this category to completed torrents, if set
+	AddTags  []string // add these tags to completed torrents, if set
+	// LocationTemplate, if set, replaces Location by a location computed per torrent, see TorrentSavePathVars
+	LocationTemplate *SavePathTemplate
+
+	// Action, if set, is called for every completed torrent after the built-in actions
+	Action func(ctx context.Context, client *Client, torrent TorrentInfo) error
+	// Filter, if set, restricts the mover to torrents for which it returns true
+	Filter func(torrent TorrentInfo) bool
+
+	Interval time.Duration // how often Run polls for completions, by default the server's refresh interval
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// CompletionMover applies actions such as moving, re-categorising or tagging to torrents when they finish downloading
+type CompletionMover struct {
+	client *Client
+	cfg    CompletionMoverConfig
+}
+
+// NewCompletionMover creates a CompletionMover for the given client
+func NewCompletionMover(client *Client, cfg CompletionMoverConfig) *CompletionMover {
+	return &CompletionMover{client: client, cfg: cfg}
+}
+
+// Run watches for completions until ctx is cancelled
+func (m *CompletionMover) Run(ctx context.Context) error {
+	watcher := NewSyncWatcher(m.client, SyncWatcherConfig{
+		Interval: m.cfg.Interval,
+		OnEvent: func(event SyncEvent) {
+			if err := m.HandleEvent(ctx, event); err != nil && m.cfg.OnError != nil {
+				m.cfg.OnError(err)
+			}
+		},
+		OnError: m.cfg.OnError,
+	})
+	return watcher.Run(ctx)
+}
+
+// HandleEvent applies the configured actions if event is a completion, so the mover can share a SyncWatcher
+func (m *CompletionMover) HandleEvent(ctx context.Context, event SyncEvent) error {
+	if event.Type != EventTorrentCompleted {
+		return nil
+	}
+	if m.cfg.Filter != nil && !m.cfg.Filter(event.Torrent) {
+		return nil
+	}
+
+	hash := string(event.Hash)
+	location := m.cfg.Location
+	if m.cfg.LocationTemplate != nil {
+		location = m.cfg.LocationTemplate.Expand(TorrentSavePathVars(event.Torrent))
+	}
+	if location != "" {
+		if err := m.client.TorrentsSetLocation(hash, location); err != nil {
+			return fmt.Errorf("CompletionMover error: %w", err)
+		}
+	}
+	if m.cfg.Category != "" {
+		if err := m.client.TorrentsSetCategory(hash, m.cfg.Category); err != nil {
+			return fmt.Errorf("CompletionMover error: %w", err)
+		}
+	}
+	if len(m.cfg.AddTags) > 0 {
+		if err := m.client.TorrentsAddTags(hash, m.cfg.AddTags); err != nil {
+			return fmt.Errorf("CompletionMover error: %w", err)
+		}
+	}
+	if m.cfg.Action != nil {
+		if err := m.cfg.Action(ctx, m.client, event.Torrent); err != nil {
+			return fmt.Errorf("CompletionMover error: %w", err)
+		}
+	}
+	return nil
+}
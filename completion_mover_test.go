@@ -0,0 +1,82 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestCompletionMover_HandleEvent(t *testing.T) {
+	var calls []url.Values
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		r.PostForm.Set("path", r.URL.Path)
+		calls = append(calls, r.PostForm)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	var actioned []InfoHash
+	mover := NewCompletionMover(client, CompletionMoverConfig{
+		Location: "/archive",
+		Category: "done",
+		AddTags:  []string{"archived", "seeding"},
+		Filter:   func(torrent TorrentInfo) bool { return torrent.Category != "skip" },
+		Action: func(ctx context.Context, client *Client, torrent TorrentInfo) error {
+			actioned = append(actioned, torrent.Hash)
+			return nil
+		},
+	})
+
+	events := []SyncEvent{
+		{Type: EventTorrentAdded, Hash: "added", Torrent: TorrentInfo{Hash: "added"}},
+		{Type: EventTorrentCompleted, Hash: "skipped", Torrent: TorrentInfo{Hash: "skipped", Category: "skip"}},
+		{Type: EventTorrentCompleted, Hash: "done", Torrent: TorrentInfo{Hash: "done"}},
+	}
+	for _, event := range events {
+		if err := mover.HandleEvent(context.Background(), event); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	wantCalls := []url.Values{
+		{"path": {"/api/v2/torrents/setLocation"}, "hashes": {"done"}, "location": {"/archive"}},
+		{"path": {"/api/v2/torrents/setCategory"}, "hashes": {"done"}, "category": {"done"}},
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"done"}, "tags": {"archived,seeding"}},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("expected calls %v, got %v", wantCalls, calls)
+	}
+	if !reflect.DeepEqual(actioned, []InfoHash{"done"}) {
+		t.Errorf("expected action for [done], got %v", actioned)
+	}
+}
+
+func TestCompletionMover_LocationTemplate(t *testing.T) {
+	var locations []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		locations = append(locations, r.PostForm.Get("location"))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	mover := NewCompletionMover(client, CompletionMoverConfig{
+		LocationTemplate: MustParseSavePathTemplate("/archive/{category:misc}/{year}-{month}"),
+	})
+	torrent := TorrentInfo{Hash: "done", Category: "tv", CompletionOn: 1710000000}
+	if err := mover.HandleEvent(context.Background(), SyncEvent{Type: EventTorrentCompleted, Hash: "done", Torrent: torrent}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := []string{"/archive/tv/2024-03"}; !reflect.DeepEqual(locations, want) {
+		t.Errorf("expected %v, got %v", want, locations)
+	}
+}
@@ -0,0 +1,98 @@
+package qbittorrent
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Config describes a client for NewClientFromConfig. Only URL is required.
+type Config struct {
+	URL      string // WebUI URL, e.g. "http://localhost:8080" or "https://example.org/seedbox/qbt/"
+	Username string
+	Password string
+	NoAuth   bool // never log in, see WithNoAuth
+	// LazyLogin defers the login to the first request, see WithLazyLogin
+	LazyLogin bool
+
+	Timeout time.Duration // per-request timeout, 0 for none
+	// TLS, if set, configures https connections, e.g. to trust a private CA. It is ignored when
+	// HTTPClient is set.
+	TLS *tls.Config
+	// HTTPClient, if set, sends the requests, by default a client with a transport from NewTransport
+	HTTPClient *http.Client
+
+	Retry     RetryPolicy // retries of failed GET requests, none by default
+	RateLimit RateLimit   // bound on the request rate, none by default
+}
+
+// Validate checks the configuration without contacting the server
+func (cfg Config) Validate() error {
+	if cfg.URL == "" {
+		return errors.New("URL is required")
+	}
+	if !strings.Contains(cfg.URL, "://") {
+		return fmt.Errorf("URL %q has no scheme, use e.g. http://%s", Redact(cfg.URL), Redact(cfg.URL))
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %v", err)
+	}
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return fmt.Errorf("URL %q has scheme %q, must be http or https", Redact(cfg.URL), u.Scheme)
+	case u.Host == "":
+		return fmt.Errorf("URL %q has no host", Redact(cfg.URL))
+	case u.RawQuery != "" || u.Fragment != "":
+		return fmt.Errorf("URL %q must not have a query or fragment", Redact(cfg.URL))
+	}
+	if cfg.Password != "" && cfg.Username == "" {
+		return errors.New("Password is set without Username")
+	}
+	if cfg.NoAuth && (cfg.Username != "" || cfg.Password != "") {
+		return errors.New("NoAuth is set along with credentials")
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("negative Timeout %s", cfg.Timeout)
+	}
+	if cfg.TLS != nil && u.Scheme != "https" {
+		return errors.New("TLS is set but the URL does not use https")
+	}
+	if cfg.Retry.MaxAttempts < 0 || cfg.Retry.Backoff < 0 || cfg.Retry.MaxBackoff < 0 {
+		return errors.New("negative Retry setting")
+	}
+	if cfg.RateLimit.RequestsPerSecond < 0 || cfg.RateLimit.Burst < 0 {
+		return errors.New("negative RateLimit setting")
+	}
+	return nil
+}
+
+// NewClientFromConfig validates cfg and initializes a client from it, logging in unless NoAuth is set or no
+// credentials are given. opts are applied after cfg, so they take precedence.
+func NewClientFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("NewClientFromConfig error: %w", err)
+	}
+
+	cfgOpts := []ClientOption{WithBaseURL(cfg.URL), WithRequestTimeout(cfg.Timeout), WithRetry(cfg.Retry), WithRateLimit(cfg.RateLimit)}
+	if cfg.NoAuth {
+		cfgOpts = append(cfgOpts, WithNoAuth())
+	}
+	if cfg.LazyLogin {
+		cfgOpts = append(cfgOpts, WithLazyLogin())
+	}
+	switch {
+	case cfg.HTTPClient != nil:
+		cfgOpts = append(cfgOpts, WithHTTPClient(cfg.HTTPClient))
+	case cfg.TLS != nil:
+		transport := NewTransport(TransportSettings{})
+		transport.TLSClientConfig = cfg.TLS
+		cfgOpts = append(cfgOpts, WithHTTPClient(&http.Client{Transport: transport}))
+	}
+
+	return NewClientWithOptions(cfg.Username, cfg.Password, "", "", append(cfgOpts, opts...)...)
+}
@@ -0,0 +1,76 @@
+package qbittorrent
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string // substring of the error, "" for none
+	}{
+		{"valid", Config{URL: "http://localhost:8080", Username: "admin", Password: "x"}, ""},
+		{"subpath", Config{URL: "https://example.org/qbt/", TLS: &tls.Config{}}, ""},
+		{"missing url", Config{}, "URL is required"},
+		{"no scheme", Config{URL: "localhost:8080"}, `URL "localhost:8080" has no scheme, use e.g. http://localhost:8080`},
+		{"bad scheme", Config{URL: "ftp://localhost"}, `has scheme "ftp"`},
+		{"no host", Config{URL: "http:///api"}, "has no host"},
+		{"query", Config{URL: "http://localhost/?a=b"}, "must not have a query"},
+		{"password without username", Config{URL: "http://localhost", Password: "x"}, "Password is set without Username"},
+		{"no auth with credentials", Config{URL: "http://localhost", NoAuth: true, Username: "admin"}, "NoAuth"},
+		{"negative timeout", Config{URL: "http://localhost", Timeout: -time.Second}, "negative Timeout"},
+		{"tls over http", Config{URL: "http://localhost", TLS: &tls.Config{}}, "does not use https"},
+		{"negative retry", Config{URL: "http://localhost", Retry: RetryPolicy{MaxAttempts: -1}}, "Retry"},
+		{"negative rate", Config{URL: "http://localhost", RateLimit: RateLimit{RequestsPerSecond: -1}}, "RateLimit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("expected no error, got %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/app/version" {
+			w.Write([]byte("v5.0.3"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClientFromConfig(Config{
+		URL:        mockServer.URL,
+		NoAuth:     true,
+		Timeout:    time.Second,
+		HTTPClient: mockServer.Client(),
+		Retry:      RetryPolicy{MaxAttempts: 3},
+		RateLimit:  RateLimit{RequestsPerSecond: 100, Burst: 5},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !client.noAuth || client.timeout != time.Second || client.retry.MaxAttempts != 3 || client.limiter == nil {
+		t.Errorf("configuration not applied: %+v", client)
+	}
+	if version, err := client.AppVersion(); err != nil || version != "v5.0.3" {
+		t.Errorf("expected v5.0.3, got %q, %v", version, err)
+	}
+
+	if _, err := NewClientFromConfig(Config{URL: "localhost:8080"}); err == nil || !strings.HasPrefix(err.Error(), "NewClientFromConfig error: ") {
+		t.Errorf("expected a validation error, got %v", err)
+	}
+}
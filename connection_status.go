@@ -0,0 +1,40 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+)
+
+// ConnectionStatus is qBittorrent's connectivity to the BitTorrent network, as reported in the transfer info
+// and the server state of sync updates
+type ConnectionStatus string
+
+const (
+	ConnectionConnected    ConnectionStatus = "connected"    // incoming connections reach qBittorrent
+	ConnectionFirewalled   ConnectionStatus = "firewalled"   // only outgoing connections work, e.g. as the port is not forwarded
+	ConnectionDisconnected ConnectionStatus = "disconnected" // no network connection
+)
+
+// IsConnected reports whether qBittorrent is connectable
+func (s ConnectionStatus) IsConnected() bool {
+	return s == ConnectionConnected
+}
+
+// IsFirewalled reports whether incoming connections do not reach qBittorrent
+func (s ConnectionStatus) IsFirewalled() bool {
+	return s == ConnectionFirewalled
+}
+
+// ConnectionStatus retrieves the connection status, bounded by ctx
+func (c *Client) ConnectionStatus(ctx context.Context) (ConnectionStatus, error) {
+	respData, err := c.doGetContext(ctx, "/api/v2/transfer/info", nil)
+	if err != nil {
+		return "", fmt.Errorf("ConnectionStatus error: %w", err)
+	}
+
+	var info TransferInfo
+	if err := c.decodeJSON(respData, &info); err != nil {
+		return "", fmt.Errorf("failed to decode transfer info response: %w", err)
+	}
+	return info.ConnectionStatus, nil
+}
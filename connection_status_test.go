@@ -0,0 +1,36 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConnectionStatus(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/transfer/info" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"connection_status":"firewalled","dht_nodes":12}`))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	status, err := client.ConnectionStatus(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status != ConnectionFirewalled || !status.IsFirewalled() || status.IsConnected() {
+		t.Errorf("unexpected status %q", status)
+	}
+	if !ConnectionConnected.IsConnected() || ConnectionDisconnected.IsConnected() || ConnectionDisconnected.IsFirewalled() {
+		t.Error("unexpected helpers")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ConnectionStatus(ctx); err == nil {
+		t.Error("expected an error for a cancelled context")
+	}
+}
@@ -0,0 +1,99 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ContentLayout selects whether the files of a torrent are placed in a root folder, as the contentLayout
+// add option and the torrent_content_layout preference
+type ContentLayout string
+
+const (
+	ContentLayoutOriginal    ContentLayout = "Original"    // as in the .torrent file
+	ContentLayoutSubfolder   ContentLayout = "Subfolder"   // always in a root folder, named after the torrent
+	ContentLayoutNoSubfolder ContentLayout = "NoSubfolder" // never in a root folder
+)
+
+// APIVersionRenamePaths is the first Web API version, shipped with qBittorrent 4.3.3, that renames files by
+// path and folders, which SetContentLayout relies on
+var APIVersionRenamePaths = APIVersion{Major: 2, Minor: 8, Patch: 0}
+
+// WithContentLayout places the files of the added torrent according to layout instead of the server's
+// default layout
+func WithContentLayout(layout ContentLayout) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.ContentLayout = &layout
+	}
+}
+
+// SetContentLayout changes the layout of a torrent that was already added, by moving its files into a root
+// folder named after the torrent for ContentLayoutSubfolder or out of their root folder for
+// ContentLayoutNoSubfolder; like qBittorrent, the folder of a single file torrent is named after the file
+// without its extension. Nothing is renamed if the torrent already has that layout. ContentLayoutOriginal
+// cannot be restored, the server does not keep the original paths. Servers older than
+// APIVersionRenamePaths fail with errors.ErrUnsupported.
+func (c *Client) SetContentLayout(hash string, layout ContentLayout) error {
+	if layout != ContentLayoutSubfolder && layout != ContentLayoutNoSubfolder {
+		return fmt.Errorf("SetContentLayout error: unsupported layout %q", layout)
+	}
+	v, err := c.APIVersion()
+	if err != nil {
+		return fmt.Errorf("SetContentLayout error: %w", err)
+	}
+	if !v.AtLeast(APIVersionRenamePaths) {
+		return fmt.Errorf("SetContentLayout error: %w: Web API %s cannot rename files by path", errors.ErrUnsupported, v)
+	}
+
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return fmt.Errorf("SetContentLayout error: %w", err)
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("SetContentLayout error: torrent %s has no files yet", hash)
+	}
+	root := rootFolder(files)
+
+	var rename func(name string) string
+	switch {
+	case layout == ContentLayoutNoSubfolder && root != "":
+		rename = func(name string) string { return strings.TrimPrefix(name, root+"/") }
+	case layout == ContentLayoutSubfolder && root == "":
+		torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{hash}})
+		if err != nil {
+			return fmt.Errorf("SetContentLayout error: %w", err)
+		}
+		if len(torrents) == 0 {
+			return fmt.Errorf("SetContentLayout error: %w: torrent %s", ErrNotFound, hash)
+		}
+		folder := torrents[0].Name
+		if len(files) == 1 {
+			folder = strings.TrimSuffix(files[0].Name, path.Ext(files[0].Name))
+		}
+		rename = func(name string) string { return folder + "/" + name }
+	default:
+		return nil
+	}
+
+	for _, file := range files {
+		if err := c.TorrentsRenameFile(hash, file.Name, rename(file.Name)); err != nil {
+			return fmt.Errorf("SetContentLayout error: %w", err)
+		}
+	}
+	return nil
+}
+
+// rootFolder returns the folder all files are in, "" if they are not in a common folder
+func rootFolder(files []TorrentFile) string {
+	var root string
+	for i, file := range files {
+		dir, _, ok := strings.Cut(file.Name, "/")
+		if !ok || (i > 0 && dir != root) {
+			return ""
+		}
+		root = dir
+	}
+	return root
+}
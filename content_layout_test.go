@@ -0,0 +1,95 @@
+package qbittorrent
+
+import (
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWithContentLayout(t *testing.T) {
+	var fields map[string][]string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		form, err := multipart.NewReader(r.Body, params["boundary"]).ReadForm(1 << 20)
+		if err != nil {
+			t.Fatalf("invalid form: %v", err)
+		}
+		fields = form.Value
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if err := client.TorrentsAddURLs([]string{"magnet:?xt=urn:btih:abc"}, WithContentLayout(ContentLayoutNoSubfolder)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got := fields["contentLayout"]; !reflect.DeepEqual(got, []string{"NoSubfolder"}) {
+		t.Errorf("unexpected contentLayout %v", got)
+	}
+	if got := fields["root_folder"]; !reflect.DeepEqual(got, []string{"false"}) {
+		t.Errorf("unexpected root_folder %v", got)
+	}
+}
+
+func TestClient_SetContentLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   string
+		layout  ContentLayout
+		renames []string
+	}{
+		{"remove root", `[{"name":"Show/a.mkv"},{"name":"Show/sub/b.srt"}]`, ContentLayoutNoSubfolder, []string{"Show/a.mkv a.mkv", "Show/sub/b.srt sub/b.srt"}},
+		{"add root", `[{"name":"a.mkv"},{"name":"sub/b.srt"}]`, ContentLayoutSubfolder, []string{"a.mkv Show/a.mkv", "sub/b.srt Show/sub/b.srt"}},
+		{"single file", `[{"name":"movie.mkv"}]`, ContentLayoutSubfolder, []string{"movie.mkv movie/movie.mkv"}},
+		{"already rooted", `[{"name":"Show/a.mkv"}]`, ContentLayoutSubfolder, nil},
+		{"already flat", `[{"name":"a.mkv"},{"name":"b.mkv"}]`, ContentLayoutNoSubfolder, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var renames []string
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.ParseForm()
+				switch r.URL.Path {
+				case "/api/v2/app/webapiVersion":
+					w.Write([]byte("2.9.3"))
+				case "/api/v2/torrents/files":
+					w.Write([]byte(tt.files))
+				case "/api/v2/torrents/info":
+					w.Write([]byte(`[{"hash":"abc","name":"Show"}]`))
+				case "/api/v2/torrents/renameFile":
+					renames = append(renames, r.PostForm.Get("oldPath")+" "+r.PostForm.Get("newPath"))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockServer.Close()
+			client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+			if err := client.SetContentLayout("abc", tt.layout); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(renames, tt.renames) {
+				t.Errorf("expected renames %v, got %v", tt.renames, renames)
+			}
+		})
+	}
+}
+
+func TestClient_SetContentLayout_Unsupported(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("2.7.0"))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if err := client.SetContentLayout("abc", ContentLayoutSubfolder); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("expected errors.ErrUnsupported, got %v", err)
+	}
+	if err := client.SetContentLayout("abc", ContentLayoutOriginal); err == nil {
+		t.Error("expected an error for the original layout")
+	}
+}
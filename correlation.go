@@ -0,0 +1,55 @@
+package qbittorrent
+
+import (
+	"context"
+)
+
+// DefaultCorrelationIDHeader is the header correlation IDs are sent in unless WithCorrelationIDHeader
+// selects another one
+const DefaultCorrelationIDHeader = "X-Request-ID"
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id. Requests made with the context, including
+// retries and re-authentication, send id instead of the one set with WithCorrelationID.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID set with ContextWithCorrelationID, "" if none
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// WithCorrelationID sends id with every request, so the requests of a client, including retries and
+// re-authentication, can be traced in reverse proxy logs. The ID also appears in the debug log and in the
+// errors of failed requests. ContextWithCorrelationID overrides it for single operations.
+func WithCorrelationID(id string) ClientOption {
+	return func(c *Client) {
+		c.correlationID = id
+	}
+}
+
+// WithCorrelationIDHeader sets the header correlation IDs are sent in, DefaultCorrelationIDHeader by default
+func WithCorrelationIDHeader(name string) ClientOption {
+	return func(c *Client) {
+		c.correlationHeader = name
+	}
+}
+
+// requestCorrelationID returns the correlation ID for a request made with ctx, "" if none
+func (c *Client) requestCorrelationID(ctx context.Context) string {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return id
+	}
+	return c.correlationID
+}
+
+// correlationIDHeader returns the header correlation IDs are sent in
+func (c *Client) correlationIDHeader() string {
+	if c.correlationHeader == "" {
+		return DefaultCorrelationIDHeader
+	}
+	return c.correlationHeader
+}
@@ -0,0 +1,54 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCorrelationID(t *testing.T) {
+	var ids []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get("X-Trace"))
+		if r.URL.Path == "/api/v2/torrents/export" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	var logBuf bytes.Buffer
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	for _, opt := range []ClientOption{
+		WithCorrelationID("client-id"),
+		WithCorrelationIDHeader("X-Trace"),
+		WithDebugLogger(log.New(&logBuf, "", 0)),
+	} {
+		opt(client)
+	}
+
+	if _, err := client.AppVersion(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	ctx := ContextWithCorrelationID(context.Background(), "op-42")
+	_, err := client.TorrentsExportTo(ctx, "abc", io.Discard)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.CorrelationID != "op-42" {
+		t.Fatalf("expected a status error with the correlation ID, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "correlation ID op-42") {
+		t.Errorf("expected the correlation ID in %q", err)
+	}
+
+	if want := []string{"client-id", "op-42"}; strings.Join(ids, ",") != strings.Join(want, ",") {
+		t.Errorf("expected IDs %v, got %v", want, ids)
+	}
+	if !strings.Contains(logBuf.String(), "id=client-id") || !strings.Contains(logBuf.String(), "id=op-42") {
+		t.Errorf("expected the IDs in the debug log, got %q", logBuf.String())
+	}
+}
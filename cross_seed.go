@@ -0,0 +1,123 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"sort"
+)
+
+// contentFile is a file path and size used to compare torrent contents
+type contentFile struct {
+	path string
+	size int64
+}
+
+// FindCrossSeedMatches returns the torrents whose name, total size and file layout are identical to meta.
+// The torrent with meta's own infohash is not included.
+func (c *Client) FindCrossSeedMatches(meta *Metainfo) ([]TorrentInfo, error) {
+	want := make([]contentFile, len(meta.Files))
+	for i, f := range meta.Files {
+		want[i] = contentFile{path: f.Path, size: f.Length}
+	}
+	return c.findContentMatches(meta.InfoHash, meta.Name, meta.Size, want)
+}
+
+// FindCrossSeedMatchesByHash returns the torrents whose content is identical to the torrent with the given hash
+func (c *Client) FindCrossSeedMatchesByHash(hash string) ([]TorrentInfo, error) {
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{hash}})
+	if err != nil {
+		return nil, fmt.Errorf("FindCrossSeedMatches error: %w", err)
+	}
+	if len(torrents) == 0 {
+		return nil, fmt.Errorf("FindCrossSeedMatches error: torrent %s not found", hash)
+	}
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return nil, fmt.Errorf("FindCrossSeedMatches error: %w", err)
+	}
+
+	want := make([]contentFile, len(files))
+	for i, f := range files {
+		want[i] = contentFile{path: f.Name, size: f.Size}
+	}
+	return c.findContentMatches(InfoHash(hash), torrents[0].Name, torrents[0].TotalSize, want)
+}
+
+func (c *Client) findContentMatches(self InfoHash, name string, size int64, want []contentFile) ([]TorrentInfo, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("FindCrossSeedMatches error: %w", err)
+	}
+	sortContent(want)
+
+	var matches []TorrentInfo
+	for _, torrent := range torrents {
+		// Cheap checks first, the file list costs a request per candidate
+		if torrent.Hash == self || torrent.Name != name || torrent.TotalSize != size {
+			continue
+		}
+		files, err := c.TorrentsFiles(string(torrent.Hash))
+		if err != nil {
+			return nil, fmt.Errorf("FindCrossSeedMatches error: %w", err)
+		}
+		have := make([]contentFile, len(files))
+		for i, f := range files {
+			have[i] = contentFile{path: f.Name, size: f.Size}
+		}
+		sortContent(have)
+		if sameContent(have, want) {
+			matches = append(matches, torrent)
+		}
+	}
+	return matches, nil
+}
+
+// CrossSeed adds the .torrent in fileData on top of the data of an existing torrent with identical content,
+// skipping the hash check. It returns the torrent whose data is reused, or ErrNoCrossSeedMatch.
+// Options are applied after the save path and skip checking options, so they can add a category or tags.
+func (c *Client) CrossSeed(torrentFile string, fileData []byte, opts ...TorrentAddOption) (*TorrentInfo, error) {
+	meta, err := ParseMetainfo(fileData)
+	if err != nil {
+		return nil, fmt.Errorf("CrossSeed error: %w", err)
+	}
+	matches, err := c.FindCrossSeedMatches(meta)
+	if err != nil {
+		return nil, fmt.Errorf("CrossSeed error: %w", err)
+	}
+
+	// Prefer a complete torrent as the source of the data
+	var match *TorrentInfo
+	for i := range matches {
+		if match == nil || matches[i].Progress > match.Progress {
+			match = &matches[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("CrossSeed error: %w", ErrNoCrossSeedMatch)
+	}
+
+	addOpts := append([]TorrentAddOption{
+		WithSavePath(match.SavePath),
+		WithSkipChecking(true),
+		WithAutoTMM(false),
+	}, opts...)
+	if err := c.TorrentsAddWithOptions(torrentFile, fileData, addOpts...); err != nil {
+		return nil, fmt.Errorf("CrossSeed error: %w", err)
+	}
+	return match, nil
+}
+
+func sortContent(files []contentFile) {
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+}
+
+func sameContent(a, b []contentFile) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
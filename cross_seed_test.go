@@ -0,0 +1,97 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newCrossSeedServer(t *testing.T, added map[string]string) *httptest.Server {
+	files := map[string]string{
+		"match":    `[{"name":"album/cd1/01.flac","size":100},{"name":"album/cover.jpg","size":20}]`,
+		"partial":  `[{"name":"album/cd1/01.flac","size":100},{"name":"album/cover.jpg","size":20}]`,
+		"renamed":  `[{"name":"album/cd1/01.flac","size":110},{"name":"album/cover.png","size":10}]`,
+		"original": `[{"name":"album/cd1/01.flac","size":100},{"name":"album/cover.jpg","size":20}]`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			if r.URL.Query().Get("hashes") == "original" {
+				w.Write([]byte(`[{"hash":"original","name":"album","total_size":120}]`))
+				return
+			}
+			w.Write([]byte(`[
+				{"hash":"original","name":"album","total_size":120,"progress":1,"save_path":"/data/original"},
+				{"hash":"partial","name":"album","total_size":120,"progress":0.5,"save_path":"/data/partial"},
+				{"hash":"match","name":"album","total_size":120,"progress":1,"save_path":"/data/music"},
+				{"hash":"renamed","name":"album","total_size":120,"progress":1},
+				{"hash":"other","name":"other","total_size":120}
+			]`))
+		case "/api/v2/torrents/files":
+			hash := r.URL.Query().Get("hash")
+			if hash == "other" {
+				t.Errorf("files of a torrent with a different name should not be fetched")
+			}
+			w.Write([]byte(files[hash]))
+		case "/api/v2/torrents/add":
+			r.ParseMultipartForm(1 << 20)
+			for _, field := range []string{"savepath", "skip_checking", "autoTMM", "category"} {
+				added[field] = r.FormValue(field)
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+}
+
+func TestClient_FindCrossSeedMatchesByHash(t *testing.T) {
+	mockServer := newCrossSeedServer(t, nil)
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	matches, err := client.FindCrossSeedMatchesByHash("original")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(matches) != 2 || matches[0].Hash != "partial" || matches[1].Hash != "match" {
+		t.Errorf("expected [partial match], got %v", matches)
+	}
+}
+
+func TestClient_CrossSeed(t *testing.T) {
+	added := map[string]string{}
+	mockServer := newCrossSeedServer(t, added)
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	data := makeTorrent("album", map[string]int64{"cd1/01.flac": 100, "cover.jpg": 20})
+	match, err := client.CrossSeed("album.torrent", data, WithCategory("cross-seed"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if match.Hash != "original" {
+		t.Errorf("expected the first complete match to be used, got %s", match.Hash)
+	}
+	want := map[string]string{"savepath": "/data/original", "skip_checking": "true", "autoTMM": "false", "category": "cross-seed"}
+	for field, value := range want {
+		if added[field] != value {
+			t.Errorf("expected %s=%s, got %s", field, value, added[field])
+		}
+	}
+
+	other := makeTorrent("album", map[string]int64{"cd1/01.flac": 60, "cover.jpg": 60})
+	if _, err := client.CrossSeed("other.torrent", other); !errors.Is(err, ErrNoCrossSeedMatch) {
+		t.Errorf("expected ErrNoCrossSeedMatch, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// WithStrictDecoding makes responses fail to decode when a field does not have the type the library
+// expects, instead of converting it. Use it in tests against new qBittorrent releases to detect schema
+// drift early.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strictDecoding = true
+	}
+}
+
+// decodeJSON unmarshals a response into v, tolerating schema drift unless strict decoding is enabled
+func (c *Client) decodeJSON(data []byte, v any) error {
+	return decodeJSON(data, v, c.strictDecoding)
+}
+
+// decodeJSON unmarshals data into v. Unless strict is set, fields whose JSON type differs from the Go type,
+// such as numbers sent as strings by some qBittorrent versions, are converted when the conversion is
+// lossless enough to be meaningful; fields that are missing keep their previous or zero value either way.
+func decodeJSON(data []byte, v any, strict bool) error {
+	err := json.Unmarshal(data, v)
+	var typeErr *json.UnmarshalTypeError
+	if err == nil || strict || !errors.As(err, &typeErr) {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw any
+	if dec.Decode(&raw) != nil {
+		return err
+	}
+	converted, marshalErr := json.Marshal(convertJSON(raw, reflect.TypeOf(v)))
+	if marshalErr != nil {
+		return err
+	}
+	return json.Unmarshal(converted, v)
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// convertJSON converts a value decoded with UseNumber towards what encoding/json expects for typ. Values
+// that cannot be converted are returned unchanged, and the final unmarshal reports them.
+func convertJSON(raw any, typ reflect.Type) any {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if raw == nil {
+		return nil
+	}
+	// Types decoding themselves define their own format, except structs that use it to post-process fields
+	if typ.Kind() != reflect.Struct && reflect.PointerTo(typ).Implements(unmarshalerType) {
+		return raw
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return raw
+		}
+		fields := jsonFields(typ)
+		for key, value := range obj {
+			field, ok := fields[key]
+			if !ok {
+				field, ok = fields[strings.ToLower(key)]
+			}
+			if !ok {
+				continue
+			}
+			if converted := convertJSON(value, field); converted != nil || value == nil {
+				obj[key] = converted
+			} else {
+				delete(obj, key)
+			}
+		}
+		return obj
+	case reflect.Map:
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return raw
+		}
+		for key, value := range obj {
+			obj[key] = convertJSON(value, typ.Elem())
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		list, ok := raw.([]any)
+		if !ok {
+			return raw
+		}
+		for i, value := range list {
+			list[i] = convertJSON(value, typ.Elem())
+		}
+		return list
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return convertInt(raw)
+	case reflect.Float32, reflect.Float64:
+		return convertFloat(raw)
+	case reflect.String:
+		switch value := raw.(type) {
+		case json.Number:
+			return value.String()
+		case bool:
+			return strconv.FormatBool(value)
+		}
+	case reflect.Bool:
+		switch value := raw.(type) {
+		case string:
+			if b, err := strconv.ParseBool(strings.TrimSpace(value)); err == nil {
+				return b
+			}
+		case json.Number:
+			if f, err := value.Float64(); err == nil {
+				return f != 0
+			}
+		}
+	}
+	return raw
+}
+
+// convertInt converts numeric strings, fractional numbers and booleans to an integer. Empty strings
+// convert to nil, which drops the field.
+func convertInt(raw any) any {
+	var s string
+	switch value := raw.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = strings.TrimSpace(value)
+		if s == "" {
+			return nil
+		}
+	case bool:
+		if value {
+			return json.Number("1")
+		}
+		return json.Number("0")
+	default:
+		return raw
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return json.Number(s)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return json.Number(strconv.FormatInt(int64(f), 10))
+	}
+	return raw
+}
+
+// convertFloat converts numeric strings to a number. Empty strings convert to nil, which drops the field.
+func convertFloat(raw any) any {
+	value, ok := raw.(string)
+	if !ok {
+		return raw
+	}
+	s := strings.TrimSpace(value)
+	if s == "" {
+		return nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return raw
+}
+
+// jsonFields maps the JSON names of the fields of a struct type, and their lower case forms for the case
+// insensitive matching of encoding/json, to the field types
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			for embedded, t := range jsonFields(field.Type) {
+				fields[embedded] = t
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+		fields[strings.ToLower(name)] = field.Type
+	}
+	return fields
+}
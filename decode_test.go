@@ -0,0 +1,51 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDecodeJSON_SchemaDrift(t *testing.T) {
+	body := `[{"hash":"a","name":"A","size":"1024","progress":"0.5","ratio":1,"dlspeed":12.0,` +
+		`"auto_tmm":1,"seq_dl":"true","tags":"x, y","category":42,"priority":""}]`
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	torrents, err := client.TorrentsInfo()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got := torrents[0]
+	if got.Size != 1024 || got.Progress != 0.5 || got.Ratio != 1 || got.DLSpeed != 12 || !got.AutoTMM ||
+		!got.SequentialDownload || got.Category != "42" || got.Priority != 0 {
+		t.Errorf("unexpected torrent %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[1] != "y" {
+		t.Errorf("unexpected tags %v", got.Tags)
+	}
+
+	client.strictDecoding = true
+	if _, err := client.TorrentsInfo(); err == nil {
+		t.Errorf("expected strict decoding to fail")
+	}
+}
+
+func TestDecodeJSON_Unconvertible(t *testing.T) {
+	var state ServerState
+	err := decodeJSON([]byte(`{"alltime_dl":"lots","dht_nodes":"12"}`), &state, false)
+	if err == nil {
+		t.Errorf("expected an error for a non-numeric string")
+	}
+
+	var files []TorrentFile
+	if err := decodeJSON([]byte(`[{"index":"3","name":"a","piece_range":["0","4"]}]`), &files, false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if files[0].Index != 3 || len(files[0].PieceRange) != 2 || files[0].PieceRange[1] != 4 {
+		t.Errorf("unexpected files %+v", files)
+	}
+}
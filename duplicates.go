@@ -0,0 +1,118 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DuplicateGroup is a set of torrents with the same content, as found by FindDuplicates
+type DuplicateGroup struct {
+	Torrents []TorrentInfo
+	Size     int64 // size of one copy of the content
+	// Wasted is the disk space taken by the extra copies. Torrents sharing a content path, such as
+	// cross-seeds, use the same copy.
+	Wasted int64
+}
+
+// FindDuplicates groups the torrents of the library that have the same content: a matching v1 or v2
+// infohash, or the same total size and the same file names and sizes, ignoring the folders they are in so
+// renamed re-downloads are found too. The file lists are only requested for torrents of equal size. Groups
+// are sorted by wasted space, largest first.
+func (c *Client) FindDuplicates() ([]DuplicateGroup, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("FindDuplicates error: %w", err)
+	}
+
+	parent := make([]int, len(torrents))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) { parent[find(i)] = find(j) }
+
+	// Torrents with a common infohash, then torrents with the same size and file signature
+	hashes := make(map[InfoHash]int)
+	sizes := make(map[int64][]int)
+	for i, torrent := range torrents {
+		for _, h := range []InfoHash{torrent.InfohashV1, torrent.InfohashV2} {
+			if h == "" {
+				continue
+			}
+			if j, ok := hashes[h]; ok {
+				union(i, j)
+			} else {
+				hashes[h] = i
+			}
+		}
+		sizes[torrent.TotalSize] = append(sizes[torrent.TotalSize], i)
+	}
+	for _, candidates := range sizes {
+		if len(candidates) < 2 {
+			continue
+		}
+		signatures := make(map[string]int)
+		for _, i := range candidates {
+			files, err := c.TorrentsFiles(string(torrents[i].Hash))
+			if err != nil {
+				return nil, fmt.Errorf("FindDuplicates error: %w", err)
+			}
+			if len(files) == 0 {
+				continue // no metadata yet
+			}
+			signature := contentSignature(files)
+			if j, ok := signatures[signature]; ok {
+				union(i, j)
+			} else {
+				signatures[signature] = i
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range torrents {
+		members[find(i)] = append(members[find(i)], i)
+	}
+	var groups []DuplicateGroup
+	for _, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+		group := DuplicateGroup{}
+		paths := make(map[string]bool)
+		for _, i := range indexes {
+			group.Torrents = append(group.Torrents, torrents[i])
+			group.Size = max(group.Size, torrents[i].TotalSize)
+			paths[torrents[i].ContentPath] = true
+		}
+		group.Wasted = group.Size * int64(len(paths)-1)
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		if a.Wasted != b.Wasted {
+			return cmp.Compare(b.Wasted, a.Wasted)
+		}
+		return cmp.Compare(a.Torrents[0].Hash, b.Torrents[0].Hash)
+	})
+	return groups, nil
+}
+
+// contentSignature returns the sorted multiset of file names, without their folders, and sizes
+func contentSignature(files []TorrentFile) string {
+	entries := make([]string, len(files))
+	for i, file := range files {
+		entries[i] = path.Base(file.Name) + "\x00" + strconv.FormatInt(file.Size, 10)
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, "\x00")
+}
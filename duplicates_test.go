@@ -0,0 +1,69 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_FindDuplicates(t *testing.T) {
+	var fileRequests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"a","total_size":100,"content_path":"/data/movie"},
+				{"hash":"b","total_size":100,"content_path":"/data/Movie (2020)"},
+				{"hash":"c","total_size":100,"content_path":"/data/other"},
+				{"hash":"d","total_size":100,"content_path":"/data/movie"},
+				{"hash":"e","total_size":5,"content_path":"/x","infohash_v1":"e1","infohash_v2":"f2"},
+				{"hash":"f","total_size":7,"content_path":"/y","infohash_v2":"f2"},
+				{"hash":"g","total_size":9,"content_path":"/z"}
+			]`))
+		case "/api/v2/torrents/files":
+			hash := r.URL.Query().Get("hash")
+			fileRequests = append(fileRequests, hash)
+			switch hash {
+			case "a", "d":
+				w.Write([]byte(`[{"name":"movie/film.mkv","size":90},{"name":"movie/film.nfo","size":10}]`))
+			case "b":
+				w.Write([]byte(`[{"name":"Movie (2020)/film.nfo","size":10},{"name":"Movie (2020)/film.mkv","size":90}]`))
+			case "c":
+				w.Write([]byte(`[{"name":"other/film.mkv","size":100}]`))
+			}
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	groups, err := client.FindDuplicates()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %+v", groups)
+	}
+
+	var got [][]InfoHash
+	for _, group := range groups {
+		var hashes []InfoHash
+		for _, torrent := range group.Torrents {
+			hashes = append(hashes, torrent.Hash)
+		}
+		got = append(got, hashes)
+	}
+	want := [][]InfoHash{{"a", "b", "d"}, {"e", "f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected groups %v, got %v", want, got)
+	}
+	if groups[0].Size != 100 || groups[0].Wasted != 100 {
+		t.Errorf("expected 100 bytes wasted by the copy, got %+v", groups[0])
+	}
+	if groups[1].Wasted != 7 {
+		t.Errorf("expected 7 bytes wasted, got %d", groups[1].Wasted)
+	}
+	if len(fileRequests) != 4 {
+		t.Errorf("expected files of the equally sized torrents only, got %v", fileRequests)
+	}
+}
@@ -0,0 +1,129 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ReconcileReport lists the actions taken by EnsureCategories or EnsureTags, each sorted by name
+type ReconcileReport struct {
+	Created []string
+	Updated []string // categories whose save path was changed
+	Deleted []string
+}
+
+// EnsureCategories creates the categories of desired that are missing and corrects the save path of those
+// that differ, the save path being the "savePath" entry of a Category. With deleteExtra, categories not in
+// desired are removed as well, except the parents of desired subcategories. The report lists the actions
+// taken, also when an error stops the reconciliation.
+func (c *Client) EnsureCategories(desired map[string]Category, deleteExtra bool) (*ReconcileReport, error) {
+	current, err := c.TorrentsCategories()
+	if err != nil {
+		return nil, fmt.Errorf("EnsureCategories error: %w", err)
+	}
+
+	report := &ReconcileReport{}
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	// Sorted, parents come before their subcategories
+	sort.Strings(names)
+	for _, name := range names {
+		savePath := categorySavePath(desired[name])
+		existing, ok := current[name]
+		switch {
+		case !ok:
+			if err := c.TorrentsCreateCategory(name, savePath); err != nil {
+				return report, fmt.Errorf("EnsureCategories error: %w", err)
+			}
+			report.Created = append(report.Created, name)
+		case !samePath(categorySavePath(existing), savePath):
+			if err := c.TorrentsEditCategory(name, savePath); err != nil {
+				return report, fmt.Errorf("EnsureCategories error: %w", err)
+			}
+			report.Updated = append(report.Updated, name)
+		}
+	}
+
+	if deleteExtra {
+		var extra []string
+		for name := range current {
+			if _, ok := desired[name]; !ok && !isCategoryParent(name, desired) {
+				extra = append(extra, name)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			if err := c.TorrentsRemoveCategories(extra); err != nil {
+				return report, fmt.Errorf("EnsureCategories error: %w", err)
+			}
+			report.Deleted = extra
+		}
+	}
+	return report, nil
+}
+
+// categorySavePath returns the save path of a category as returned by TorrentsCategories
+func categorySavePath(category Category) string {
+	savePath, _ := category["savePath"].(string)
+	return savePath
+}
+
+// isCategoryParent reports whether name is the parent of one of categories, e.g. "tv" of "tv/anime"
+func isCategoryParent(name string, categories map[string]Category) bool {
+	for category := range categories {
+		if strings.HasPrefix(category, name+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// EnsureTags creates the tags of desired that are missing and, with deleteExtra, deletes the tags that are
+// not in desired, removing them from their torrents. The report lists the actions taken.
+func (c *Client) EnsureTags(desired []string, deleteExtra bool) (*ReconcileReport, error) {
+	current, err := c.TorrentsGetAllTags()
+	if err != nil {
+		return nil, fmt.Errorf("EnsureTags error: %w", err)
+	}
+	have := make(map[string]bool, len(current))
+	for _, tag := range current {
+		have[tag] = true
+	}
+	want := make(map[string]bool, len(desired))
+	for _, tag := range desired {
+		want[strings.TrimSpace(tag)] = true
+	}
+
+	report := &ReconcileReport{}
+	for tag := range want {
+		if !have[tag] {
+			report.Created = append(report.Created, tag)
+		}
+	}
+	if len(report.Created) > 0 {
+		sort.Strings(report.Created)
+		if err := c.TorrentsCreateTags(report.Created); err != nil {
+			return &ReconcileReport{}, fmt.Errorf("EnsureTags error: %w", err)
+		}
+	}
+
+	if deleteExtra {
+		var extra []string
+		for _, tag := range current {
+			if !want[tag] {
+				extra = append(extra, tag)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			if err := c.TorrentsDeleteTags(extra); err != nil {
+				return report, fmt.Errorf("EnsureTags error: %w", err)
+			}
+			report.Deleted = extra
+		}
+	}
+	return report, nil
+}
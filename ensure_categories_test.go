@@ -0,0 +1,62 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEnsureCategoriesAndTags(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/tv/"},"movies":{"name":"movies","savePath":"/old"},"old":{"name":"old","savePath":""}}`))
+		case "/api/v2/torrents/tags":
+			w.Write([]byte(`["keep","stale"]`))
+		default:
+			requests = append(requests, r.URL.Path+" "+r.Form.Encode())
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	report, err := client.EnsureCategories(map[string]Category{
+		"tv/anime": {"savePath": "/anime"},
+		"movies":   {"savePath": "/movies"},
+	}, true)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := &ReconcileReport{Created: []string{"tv/anime"}, Updated: []string{"movies"}, Deleted: []string{"old"}}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("expected %+v, got %+v", want, report)
+	}
+	wantRequests := []string{
+		"/api/v2/torrents/editCategory category=movies&savePath=%2Fmovies",
+		"/api/v2/torrents/createCategory category=tv%2Fanime&savePath=%2Fanime",
+		"/api/v2/torrents/removeCategories categories=old",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+
+	requests = nil
+	report, err = client.EnsureTags([]string{"keep", "new"}, false)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(report, &ReconcileReport{Created: []string{"new"}}) {
+		t.Errorf("unexpected report %+v", report)
+	}
+	report, err = client.EnsureTags([]string{"keep"}, true)
+	if err != nil || !reflect.DeepEqual(report, &ReconcileReport{Deleted: []string{"stale"}}) {
+		t.Errorf("unexpected report %+v, %v", report, err)
+	}
+	wantRequests = []string{"/api/v2/torrents/createTags tags=new", "/api/v2/torrents/deleteTags tags=stale"}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+}
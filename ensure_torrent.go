@@ -0,0 +1,165 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TorrentSource is a torrent for EnsureTorrent: the contents of a .torrent file or a link to add it from
+type TorrentSource struct {
+	File     []byte // contents of a .torrent file
+	FileName string // name the file is uploaded as, by default "<hash>.torrent"
+	URL      string // magnet link or URL of a .torrent file, used if File is empty
+	// Hash identifies the torrent on the server. It is computed from File or taken from a magnet link when
+	// empty, so it is only needed for URLs of .torrent files.
+	Hash InfoHash
+}
+
+// infoHash returns the infohash of the source
+func (s TorrentSource) infoHash() (InfoHash, error) {
+	switch {
+	case s.Hash != "":
+		return InfoHash(strings.ToLower(string(s.Hash))), nil
+	case len(s.File) > 0:
+		meta, err := ParseMetainfo(s.File)
+		if err != nil {
+			return "", err
+		}
+		return meta.InfoHash, nil
+	case s.URL != "":
+		if hash, ok := magnetInfoHash(s.URL); ok {
+			return hash, nil
+		}
+		return "", errors.New("the infohash of a .torrent URL must be given in Hash")
+	}
+	return "", errors.New("no torrent file or URL given")
+}
+
+// TorrentSpec is the desired configuration of a torrent for EnsureTorrent. Nil fields are left as they are.
+type TorrentSpec struct {
+	Category *string  // must exist on the server, "" for none
+	Tags     []string // exact tag set, empty for none
+	SavePath *string  // moving the data of an existing torrent if it differs
+	Limits   *ShareLimits
+}
+
+// ShareLimits are the share limits of a torrent, see TorrentsSetShareLimits
+type ShareLimits struct {
+	Ratio       float64 // ShareLimitGlobal, ShareLimitUnlimited or the ratio
+	SeedingTime int64   // ShareLimitGlobal, ShareLimitUnlimited or minutes
+}
+
+// EnsureResult reports what EnsureTorrent did
+type EnsureResult struct {
+	Hash    InfoHash
+	Added   bool     // the torrent was missing and has been added
+	Updated []string // the properties changed on an existing torrent: "category", "tags", "save_path", "limits"
+}
+
+// EnsureTorrent adds the torrent if it is not on the server, configured as desired, and otherwise changes
+// the properties of the existing torrent that differ from desired. Running it again with the same arguments
+// changes nothing, which makes it a building block for declarative torrent management.
+func (c *Client) EnsureTorrent(ctx context.Context, source TorrentSource, desired TorrentSpec) (*EnsureResult, error) {
+	hash, err := source.infoHash()
+	if err != nil {
+		return nil, fmt.Errorf("EnsureTorrent error: %w", err)
+	}
+	result := &EnsureResult{Hash: hash}
+
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{string(hash)}})
+	if err != nil {
+		return nil, fmt.Errorf("EnsureTorrent error: %w", err)
+	}
+	if len(torrents) == 0 {
+		if err := c.addSpec(source, hash, desired); err != nil {
+			return nil, fmt.Errorf("EnsureTorrent error: %w", err)
+		}
+		result.Added = true
+		return result, nil
+	}
+
+	current := torrents[0]
+	for _, step := range c.specChanges(current, desired) {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		if err := step.apply(); err != nil {
+			return result, fmt.Errorf("EnsureTorrent error: %w", err)
+		}
+		result.Updated = append(result.Updated, step.property)
+	}
+	return result, nil
+}
+
+// addSpec adds source with the properties of spec. They are all sent with the add request, as qBittorrent
+// adds torrents asynchronously and ignores changes to torrents it has not added yet.
+func (c *Client) addSpec(source TorrentSource, hash InfoHash, spec TorrentSpec) error {
+	var opts []TorrentAddOption
+	if spec.Category != nil {
+		opts = append(opts, WithCategory(*spec.Category))
+	}
+	if len(spec.Tags) > 0 {
+		opts = append(opts, WithTags(spec.Tags))
+	}
+	if spec.SavePath != nil {
+		opts = append(opts, WithSavePath(*spec.SavePath), WithAutoTMM(false))
+	}
+	if spec.Limits != nil {
+		opts = append(opts, WithShareLimits(spec.Limits.Ratio, spec.Limits.SeedingTime))
+	}
+
+	if len(source.File) > 0 {
+		name := source.FileName
+		if name == "" {
+			name = string(hash) + ".torrent"
+		}
+		return c.TorrentsAddWithOptions(name, source.File, opts...)
+	}
+	return c.TorrentsAddURLs([]string{source.URL}, opts...)
+}
+
+// specStep is a change of one property of a torrent
+type specStep struct {
+	property string
+	apply    func() error
+}
+
+// specChanges returns the changes that bring torrent to spec
+func (c *Client) specChanges(torrent TorrentInfo, spec TorrentSpec) []specStep {
+	hash := string(torrent.Hash)
+	var steps []specStep
+	if spec.Category != nil && *spec.Category != torrent.Category {
+		steps = append(steps, specStep{"category", func() error { return c.TorrentsSetCategory(hash, *spec.Category) }})
+	}
+	if spec.Tags != nil && !sameTags(torrent.Tags, spec.Tags) {
+		steps = append(steps, specStep{"tags", func() error { return c.TorrentsSetTags(hash, spec.Tags) }})
+	}
+	if spec.SavePath != nil && !samePath(torrent.SavePath, *spec.SavePath) {
+		steps = append(steps, specStep{"save_path", func() error { return c.TorrentsSetLocation(hash, *spec.SavePath) }})
+	}
+	if limits := spec.Limits; limits != nil && (limits.Ratio != torrent.RatioLimit || limits.SeedingTime != torrent.SeedingTimeLimit) {
+		steps = append(steps, specStep{"limits", func() error {
+			return c.TorrentsSetShareLimits(hash, limits.Ratio, limits.SeedingTime)
+		}})
+	}
+	return steps
+}
+
+// sameTags reports whether a and b hold the same tags in any order
+func sameTags(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	for i := range b {
+		b[i] = strings.TrimSpace(b[i])
+	}
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// samePath reports whether two save paths are the same, ignoring trailing separators
+func samePath(a, b string) bool {
+	return strings.TrimRight(a, `/\`) == strings.TrimRight(b, `/\`)
+}
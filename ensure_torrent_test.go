@@ -0,0 +1,64 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestEnsureTorrent(t *testing.T) {
+	const hash = "0123456789abcdef0123456789abcdef01234567"
+	existing := `[{"hash":"` + hash + `","category":"old","tags":"a, b","save_path":"/data/","ratio_limit":-2,"seeding_time_limit":-2}]`
+
+	var requests []string
+	info := existing
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseMultipartForm(1 << 20)
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(info))
+			return
+		case "/api/v2/torrents/add":
+			requests = append(requests, r.URL.Path+" "+r.FormValue("category")+" "+r.FormValue("tags")+" "+
+				r.FormValue("ratioLimit")+" "+r.FormValue("seedingTimeLimit"))
+		default:
+			requests = append(requests, r.URL.Path)
+		}
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	category, savePath := "tv", "/data"
+	source := TorrentSource{URL: "magnet:?xt=urn:btih:" + hash}
+	spec := TorrentSpec{Category: &category, Tags: []string{"b", "a"}, SavePath: &savePath, Limits: &ShareLimits{Ratio: 2, SeedingTime: 60}}
+
+	result, err := client.EnsureTorrent(context.Background(), source, spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Added || !reflect.DeepEqual(result.Updated, []string{"category", "limits"}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if want := []string{"/api/v2/torrents/setCategory", "/api/v2/torrents/setShareLimits"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+
+	requests, info = nil, `[]`
+	result, err = client.EnsureTorrent(context.Background(), source, spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !result.Added || result.Hash != hash {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if want := []string{"/api/v2/torrents/add tv b,a 2 60"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+
+	if _, err := client.EnsureTorrent(context.Background(), TorrentSource{URL: "https://example.org/a.torrent"}, spec); err == nil {
+		t.Errorf("expected an error for a .torrent URL without a hash")
+	}
+}
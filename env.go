@@ -0,0 +1,128 @@
+package qbittorrent
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	EnvURL                = "QBITTORRENT_URL"                  // WebUI URL, e.g. "http://localhost:8080", required
+	EnvUsername           = "QBITTORRENT_USERNAME"             // WebUI username
+	EnvPassword           = "QBITTORRENT_PASSWORD"             // WebUI password
+	EnvPasswordFile       = "QBITTORRENT_PASSWORD_FILE"        // file holding the password, e.g. a Docker secret
+	EnvNoAuth             = "QBITTORRENT_NO_AUTH"              // "true" to never log in, see WithNoAuth
+	EnvTimeout            = "QBITTORRENT_TIMEOUT"              // request timeout as a Go duration, e.g. "30s"
+	EnvCAFile             = "QBITTORRENT_CA_FILE"              // PEM file of CAs to trust for https, on top of the system ones
+	EnvInsecureSkipVerify = "QBITTORRENT_INSECURE_SKIP_VERIFY" // "true" to skip verifying the server certificate
+)
+
+// NewClientFromEnv initializes a client configured by the QBITTORRENT_* environment variables, see EnvURL
+// and the other Env constants. opts are applied after the environment, so they take precedence.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	cfg, err := configFromEnv(os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("NewClientFromEnv error: %w", err)
+	}
+	return NewClientFromConfig(cfg, opts...)
+}
+
+// ConfigFromEnv returns the Config NewClientFromEnv would use, without requiring EnvURL to be set, so that
+// programs can override it, e.g. with command line flags, before calling NewClientFromConfig
+func ConfigFromEnv() (Config, error) {
+	cfg, err := readEnvConfig(os.Getenv)
+	if err != nil {
+		return cfg, fmt.Errorf("ConfigFromEnv error: %w", err)
+	}
+	return cfg, nil
+}
+
+// configFromEnv builds a Config from the environment read through getenv, which must set EnvURL
+func configFromEnv(getenv func(string) string) (Config, error) {
+	if getenv(EnvURL) == "" {
+		return Config{}, fmt.Errorf("%s is not set", EnvURL)
+	}
+	return readEnvConfig(getenv)
+}
+
+// readEnvConfig builds a Config from the environment read through getenv
+func readEnvConfig(getenv func(string) string) (Config, error) {
+	cfg := Config{
+		URL:      getenv(EnvURL),
+		Username: getenv(EnvUsername),
+		Password: getenv(EnvPassword),
+	}
+
+	if path := getenv(EnvPasswordFile); path != "" {
+		if cfg.Password != "" {
+			return cfg, fmt.Errorf("only one of %s and %s may be set", EnvPassword, EnvPasswordFile)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvPasswordFile, err)
+		}
+		cfg.Password = strings.TrimRight(string(data), "\r\n")
+	}
+
+	var err error
+	if cfg.NoAuth, err = envBool(getenv, EnvNoAuth); err != nil {
+		return cfg, err
+	}
+
+	if value := getenv(EnvTimeout); value != "" {
+		if cfg.Timeout, err = time.ParseDuration(value); err != nil {
+			return cfg, fmt.Errorf("%s: %v", EnvTimeout, err)
+		}
+	}
+
+	insecure, err := envBool(getenv, EnvInsecureSkipVerify)
+	if err != nil {
+		return cfg, err
+	}
+	if caFile := getenv(EnvCAFile); caFile != "" || insecure {
+		if cfg.TLS, err = newTLSConfig(caFile, insecure); err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// envBool parses a boolean environment variable, unset meaning false
+func envBool(getenv func(string) string, name string) (bool, error) {
+	value := getenv(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid boolean %q", name, value)
+	}
+	return b, nil
+}
+
+// newTLSConfig returns a TLS configuration trusting the CAs in caFile, if set, in addition to the system
+// ones, or skipping certificate verification altogether if insecure is set
+func newTLSConfig(caFile string, insecure bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: insecure}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %v", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
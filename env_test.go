@@ -0,0 +1,75 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClientFromEnv(t *testing.T) {
+	var login string
+	mockServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/qbt/api/v2/auth/login":
+			r.ParseForm()
+			login = r.PostForm.Get("username") + ":" + r.PostForm.Get("password")
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+			w.Write([]byte("Ok."))
+		case "/qbt/api/v2/app/version":
+			w.Write([]byte("v4.6.7"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvURL, mockServer.URL+"/qbt/")
+	t.Setenv(EnvUsername, "admin")
+	t.Setenv(EnvPasswordFile, passwordFile)
+	t.Setenv(EnvTimeout, "5s")
+	t.Setenv(EnvInsecureSkipVerify, "true")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if login != "admin:s3cret" {
+		t.Errorf("expected login admin:s3cret, got %q", login)
+	}
+	if client.timeout != 5*time.Second {
+		t.Errorf("expected a 5s timeout, got %v", client.timeout)
+	}
+	if version, err := client.AppVersion(); err != nil || version != "v4.6.7" {
+		t.Errorf("expected v4.6.7, got %q, %v", version, err)
+	}
+}
+
+func TestClientOptionsFromEnv_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"missing url", map[string]string{}, "QBITTORRENT_URL is not set"},
+		{"bad timeout", map[string]string{EnvURL: "http://localhost", EnvTimeout: "soon"}, "QBITTORRENT_TIMEOUT"},
+		{"bad bool", map[string]string{EnvURL: "http://localhost", EnvNoAuth: "maybe"}, `QBITTORRENT_NO_AUTH: invalid boolean "maybe"`},
+		{"both passwords", map[string]string{EnvURL: "http://localhost", EnvPassword: "a", EnvPasswordFile: "b"}, "only one of"},
+		{"missing ca", map[string]string{EnvURL: "http://localhost", EnvCAFile: "/nonexistent/ca.pem"}, "failed to read CA file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := configFromEnv(func(name string) string { return tt.env[name] })
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
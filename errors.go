@@ -0,0 +1,54 @@
+package qbittorrent
+
+import "errors"
+
+// ErrInsufficientSpace is returned when adding a torrent would exceed the free space on the server
+var ErrInsufficientSpace = errors.New("insufficient free space on disk")
+
+// ErrNoCrossSeedMatch is returned when no torrent with the same content is found for cross-seeding
+var ErrNoCrossSeedMatch = errors.New("no torrent with matching content")
+
+// ErrForbidden is returned when the server rejects a request with 403 Forbidden and the client cannot log in,
+// either because it has no credentials or because it was created with WithNoAuth
+var ErrForbidden = errors.New("forbidden: the server requires authentication")
+
+// ErrInvalidTag is returned for tags the API cannot represent, such as empty tags or tags containing commas
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ErrNoInterfaceBound is returned by VPNWatchdog when qBittorrent is not bound to a network interface and
+// none was configured, so there is no link to watch
+var ErrNoInterfaceBound = errors.New("no network interface bound")
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set with WithMaxResponseSize
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrAddFailed is returned when qBittorrent rejects a torrent being added, e.g. because the .torrent file is
+// invalid. The server reports this with a successful status and a "Fails." body.
+var ErrAddFailed = errors.New("qBittorrent failed to add the torrent")
+
+// ErrAlreadyExists is returned by adds with WithDuplicateCheck when the torrent is already on the server
+var ErrAlreadyExists = errors.New("torrent already exists")
+
+// ErrNotConnected is returned when qBittorrent does not report the "connected" status after its listen port
+// was changed, e.g. because incoming connections are firewalled
+var ErrNotConnected = errors.New("qBittorrent is not connectable")
+
+// ErrNotFound is returned, wrapped in a StatusError, when the server answers 404 Not Found, which the Web API
+// does for unknown torrent hashes
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is returned, wrapped in a StatusError, when the server answers 409 Conflict, e.g. when the new
+// URL given to editTracker already exists or the category given to setCategory does not
+var ErrConflict = errors.New("conflict")
+
+// ErrInvalidTorrentFile is returned, wrapped in a StatusError, when the server answers 415 Unsupported Media
+// Type, which it does for torrent files it cannot parse
+var ErrInvalidTorrentFile = errors.New("invalid torrent file")
+
+// ErrCircuitOpen is returned without contacting the server while the circuit breaker set with
+// WithCircuitBreaker is open after repeated connection failures
+var ErrCircuitOpen = errors.New("circuit breaker open: server unreachable")
+
+// ErrContentPathCollision is returned by adds with WithContentPathCheck when the files of the torrent would
+// be written into the content path of a torrent already on the server
+var ErrContentPathCollision = errors.New("content path used by another torrent")
@@ -0,0 +1,126 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ETABalancerConfig configures an ETABalancer. Zero values select the defaults.
+type ETABalancerConfig struct {
+	Budget   int64         // upload bytes per second shared by the downloading torrents, required
+	MinLimit int64         // lowest limit of a downloading torrent, even beyond Budget, default 10 KiB/s
+	Interval time.Duration // how often Run recomputes the limits, default 30s
+
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// ETABalancer shares an upload budget among the downloading torrents so that the ones closest to completion
+// get the most: peers reciprocate with the torrents uploading to them, which speeds up their downloads. Each
+// torrent's share is inversely proportional to its remaining size. The limits are recomputed as torrents
+// progress, and removed again from torrents that stopped downloading. The balancer owns the upload limits of
+// downloading torrents, limits set by other means are overwritten.
+type ETABalancer struct {
+	client  *Client
+	cfg     ETABalancerConfig
+	managed map[InfoHash]bool // torrents the balancer set a limit on
+}
+
+// NewETABalancer creates an ETABalancer for the given client
+func NewETABalancer(client *Client, cfg ETABalancerConfig) *ETABalancer {
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = 10 << 10
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &ETABalancer{client: client, cfg: cfg, managed: make(map[InfoHash]bool)}
+}
+
+// Run recomputes the limits every Interval until ctx is cancelled
+func (b *ETABalancer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := b.RunOnce(ctx); err != nil && ctx.Err() == nil && b.cfg.OnError != nil {
+			b.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Allocate returns the upload limits of the downloading torrents among torrents, the torrents with bytes
+// left that are neither paused nor stopped
+func (b *ETABalancer) Allocate(torrents []TorrentInfo) map[InfoHash]int64 {
+	var total float64
+	for _, torrent := range torrents {
+		if isBalancedTorrent(torrent) {
+			total += 1 / float64(torrent.AmountLeft)
+		}
+	}
+	limits := make(map[InfoHash]int64)
+	for _, torrent := range torrents {
+		if isBalancedTorrent(torrent) {
+			share := float64(b.cfg.Budget) / float64(torrent.AmountLeft) / total
+			limits[torrent.Hash] = max(int64(share), b.cfg.MinLimit)
+		}
+	}
+	return limits
+}
+
+// isBalancedTorrent reports whether an ETABalancer limits torrent
+func isBalancedTorrent(torrent TorrentInfo) bool {
+	return torrent.AmountLeft > 0 && !IsPausedState(torrent.State)
+}
+
+// RunOnce recomputes the limits, sets those that changed and removes the limits of torrents that stopped
+// downloading. It returns the limits set, 0 for removed ones.
+func (b *ETABalancer) RunOnce(ctx context.Context) (map[InfoHash]int64, error) {
+	if b.cfg.Budget <= 0 {
+		return nil, fmt.Errorf("ETABalancer error: no upload budget configured")
+	}
+	torrents, err := b.client.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("ETABalancer error: %w", err)
+	}
+
+	limits := b.Allocate(torrents)
+	changed := make(map[InfoHash]int64)
+	for _, torrent := range torrents {
+		limit, ok := limits[torrent.Hash]
+		switch {
+		case ok && torrent.UpLimit != limit:
+			changed[torrent.Hash] = limit
+		case !ok && b.managed[torrent.Hash]:
+			changed[torrent.Hash] = 0
+		}
+	}
+
+	hashes := make([]InfoHash, 0, len(changed))
+	for hash := range changed {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+	applied := make(map[InfoHash]int64)
+	for _, hash := range hashes {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		if err := b.client.TorrentsSetUploadLimit(string(hash), changed[hash]); err != nil {
+			return applied, fmt.Errorf("ETABalancer error: %w", err)
+		}
+		applied[hash] = changed[hash]
+	}
+	clear(b.managed)
+	for hash := range limits {
+		b.managed[hash] = true
+	}
+	return applied, nil
+}
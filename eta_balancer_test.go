@@ -0,0 +1,73 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestETABalancer_Allocate(t *testing.T) {
+	b := NewETABalancer(nil, ETABalancerConfig{Budget: 1000, MinLimit: 50})
+	limits := b.Allocate([]TorrentInfo{
+		{Hash: "a", AmountLeft: 100, State: "downloading"},
+		{Hash: "b", AmountLeft: 400, State: "stalledDL"},
+		{Hash: "c", AmountLeft: 10000, State: "downloading"},
+		{Hash: "d", AmountLeft: 100, State: "pausedDL"},
+		{Hash: "e", State: "uploading"},
+	})
+	want := map[InfoHash]int64{"a": 793, "b": 198, "c": 50}
+	if !reflect.DeepEqual(limits, want) {
+		t.Errorf("expected limits %v, got %v", want, limits)
+	}
+}
+
+func TestETABalancer_RunOnce(t *testing.T) {
+	var calls []url.Values
+	info := `[{"hash":"a","amount_left":100,"state":"downloading","up_limit":-1},
+		{"hash":"b","amount_left":100,"state":"downloading","up_limit":500}]`
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(info))
+		case "/api/v2/torrents/setUploadLimit":
+			r.ParseForm()
+			calls = append(calls, r.PostForm)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	b := NewETABalancer(client, ETABalancerConfig{Budget: 1000, MinLimit: 1})
+
+	applied, err := b.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := map[InfoHash]int64{"a": 500}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("expected applied %v, got %v", want, applied)
+	}
+
+	// b completes, its limit is removed and a gets the whole budget
+	info = `[{"hash":"a","amount_left":100,"state":"downloading","up_limit":500},
+		{"hash":"b","state":"uploading","up_limit":500}]`
+	applied, err = b.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := map[InfoHash]int64{"a": 1000, "b": 0}; !reflect.DeepEqual(applied, want) {
+		t.Errorf("expected applied %v, got %v", want, applied)
+	}
+
+	wantCalls := []url.Values{
+		{"hashes": {"a"}, "limit": {"500"}},
+		{"hashes": {"a"}, "limit": {"1000"}},
+		{"hashes": {"b"}, "limit": {"0"}},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("expected calls %v, got %v", wantCalls, calls)
+	}
+}
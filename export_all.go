@@ -0,0 +1,181 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ExportAllOptions holds the optional parameters for the ExportAll method
+type ExportAllOptions struct {
+	Concurrency int // number of concurrent exports, default 4
+	PageSize    int // number of torrents listed per request, default 500
+
+	// FileName returns the file name for a torrent, default "<hash>.torrent"
+	FileName func(torrent TorrentInfo) string
+	// OnProgress, if set, is called after each torrent was exported or failed to export.
+	// It may be called concurrently.
+	OnProgress func(done, total int, torrent TorrentInfo, err error)
+}
+
+// ExportFileNameByName names exported files "<name> [<hash>].torrent", replacing characters that are
+// not valid in file names
+func ExportFileNameByName(torrent TorrentInfo) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, torrent.Name)
+	return fmt.Sprintf("%s [%s].torrent", name, torrent.Hash)
+}
+
+// ExportAll writes the .torrent file of every torrent in the session into dir, producing a backup that can be
+// re-added later. It returns the number of files written; failures of individual torrents are joined into
+// the returned error and do not stop the export.
+func (c *Client) ExportAll(ctx context.Context, dir string, opts *ExportAllOptions) (int, error) {
+	options := ExportAllOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.Concurrency <= 0 {
+		options.Concurrency = 4
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = 500
+	}
+	if options.FileName == nil {
+		options.FileName = func(torrent TorrentInfo) string { return string(torrent.Hash) + ".torrent" }
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("ExportAll error: %w", err)
+	}
+
+	torrents, err := c.listPaged(ctx, options.PageSize)
+	if err != nil {
+		return 0, fmt.Errorf("ExportAll error: %w", err)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		done    int
+		written int
+		errs    []error
+	)
+	sem := make(chan struct{}, options.Concurrency)
+	for _, torrent := range torrents {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(torrent TorrentInfo) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := c.exportTo(ctx, torrent, filepath.Join(dir, options.FileName(torrent)))
+
+			mu.Lock()
+			done++
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", torrent.Hash, err))
+			} else {
+				written++
+			}
+			progress := done
+			mu.Unlock()
+
+			if options.OnProgress != nil {
+				options.OnProgress(progress, len(torrents), torrent, err)
+			}
+		}(torrent)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return written, err
+	}
+	if len(errs) > 0 {
+		return written, fmt.Errorf("ExportAll error: %w", errors.Join(errs...))
+	}
+	return written, nil
+}
+
+// pagedListAttempts bounds how often listPaged starts over because the torrent list changed
+const pagedListAttempts = 5
+
+// listPaged lists every torrent in pages of pageSize, sorted by addition time so that torrents added meanwhile
+// land on the last page. A torrent removed meanwhile shifts the later ones back by one, which would skip a
+// torrent at the page boundary: consecutive pages overlap by one torrent to detect that, and the listing
+// starts over.
+func (c *Client) listPaged(ctx context.Context, pageSize int) ([]TorrentInfo, error) {
+	for attempt := 0; attempt < pagedListAttempts; attempt++ {
+		torrents, complete, err := c.listPages(ctx, pageSize)
+		if err != nil || complete {
+			return torrents, err
+		}
+	}
+	return nil, errors.New("the torrent list kept changing while it was listed")
+}
+
+// listPages lists the torrents once, reporting false if a removal shifted them between two pages
+func (c *Client) listPages(ctx context.Context, pageSize int) ([]TorrentInfo, bool, error) {
+	var torrents []TorrentInfo
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		params := &TorrentsInfoParams{Sort: "added_on", Limit: pageSize}
+		last := len(torrents) - 1
+		if last >= 0 {
+			params.Offset, params.Limit = last, pageSize+1
+		}
+		page, err := c.TorrentsInfo(params)
+		if err != nil {
+			return nil, false, err
+		}
+		if last >= 0 {
+			if len(page) == 0 || page[0].Hash != torrents[last].Hash {
+				return nil, false, nil
+			}
+			page = page[1:]
+		}
+		torrents = append(torrents, page...)
+		if len(page) < pageSize {
+			return torrents, true, nil
+		}
+	}
+}
+
+// exportTo streams the .torrent file of torrent to path. The file is written under a temporary name and
+// renamed once complete, so a failed export leaves nothing behind.
+func (c *Client) exportTo(ctx context.Context, torrent TorrentInfo, path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".export-*")
+	if err != nil {
+		return err
+	}
+	_, err = c.TorrentsExportTo(ctx, string(torrent.Hash), f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
@@ -0,0 +1,135 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newExportServer serves the torrents, in order of addition, and their exports. Exports of missing hashes
+// fail with 404 Not Found. onPage, if set, is called after each page was served.
+func newExportServer(t *testing.T, torrents *[]string, missing string, onPage func(offset int)) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			mu.Lock()
+			defer mu.Unlock()
+			query := r.URL.Query()
+			if query.Get("sort") != "added_on" {
+				t.Errorf("expected sort=added_on, got %s", r.URL.RawQuery)
+			}
+			offset, _ := strconv.Atoi(query.Get("offset"))
+			limit, _ := strconv.Atoi(query.Get("limit"))
+			var page []string
+			for i := offset; i < len(*torrents) && i < offset+limit; i++ {
+				hash := (*torrents)[i]
+				page = append(page, fmt.Sprintf(`{"hash":%q,"name":%q}`, hash, strings.ToUpper(hash[:1])+"/B"))
+			}
+			w.Write([]byte("[" + strings.Join(page, ",") + "]"))
+			if onPage != nil {
+				onPage(offset)
+			}
+		case "/api/v2/torrents/export":
+			r.ParseForm()
+			hash := r.Form.Get("hash")
+			if hash == missing {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("torrent " + hash))
+		}
+	}))
+}
+
+func TestClient_ExportAll(t *testing.T) {
+	var mu sync.Mutex
+	var offsets []int
+	torrents := []string{"aaa", "bbb", "ccc"}
+	mockServer := newExportServer(t, &torrents, "bbb", func(offset int) { offsets = append(offsets, offset) })
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	var progress []int
+	written, err := client.ExportAll(context.Background(), dir, &ExportAllOptions{
+		Concurrency: 2,
+		PageSize:    2,
+		FileName:    ExportFileNameByName,
+		OnProgress: func(done, total int, torrent TorrentInfo, err error) {
+			mu.Lock()
+			progress = append(progress, done)
+			mu.Unlock()
+			if total != 3 {
+				t.Errorf("expected total 3, got %d", total)
+			}
+		},
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "bbb") || !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound mentioning bbb, got %v", err)
+	}
+	if written != 2 {
+		t.Errorf("expected 2 files written, got %d", written)
+	}
+	// The second page starts at the last torrent of the first one
+	if !reflect.DeepEqual(offsets, []int{0, 1}) {
+		t.Errorf("expected pages at offsets [0 1], got %v", offsets)
+	}
+	if len(progress) != 3 {
+		t.Errorf("expected 3 progress callbacks, got %v", progress)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "A_B [aaa].torrent"))
+	if err != nil || string(data) != "torrent aaa" {
+		t.Errorf("unexpected export of aaa: %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "C_B [ccc].torrent")); err != nil {
+		t.Errorf("expected export of ccc, got %v", err)
+	}
+	// The failed export of bbb leaves no partial file behind
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("expected 2 files in %s, got %v", dir, entries)
+	}
+}
+
+func TestClient_ExportAllRemovedWhileListing(t *testing.T) {
+	torrents := []string{"aaa", "bbb", "ccc", "ddd"}
+	var offsets []int
+	// aaa is removed after the first page, shifting ccc onto it
+	mockServer := newExportServer(t, &torrents, "", func(offset int) {
+		offsets = append(offsets, offset)
+		if len(offsets) == 1 {
+			torrents = torrents[1:]
+		}
+	})
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	dir := t.TempDir()
+	written, err := client.ExportAll(context.Background(), dir, &ExportAllOptions{PageSize: 2})
+	if err != nil || written != 3 {
+		t.Fatalf("expected 3 files written, got %d, %v", written, err)
+	}
+	if !reflect.DeepEqual(offsets, []int{0, 1, 0, 1}) {
+		t.Errorf("expected the listing to start over, got pages at offsets %v", offsets)
+	}
+	for _, hash := range []string{"bbb", "ccc", "ddd"} {
+		if _, err := os.Stat(filepath.Join(dir, hash+".torrent")); err != nil {
+			t.Errorf("expected export of %s, got %v", hash, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Extras holds the fields of a response object that the library does not know about, such as those added by
+// qBittorrent releases newer than the library, keyed by their JSON name. TorrentInfo and ServerState collect
+// them; Preferences, being a map, holds every field already.
+type Extras map[string]json.RawMessage
+
+// Get decodes the extra field name into v and reports whether it is present
+func (e Extras) Get(name string, v any) (bool, error) {
+	raw, ok := e[name]
+	if !ok {
+		return false, nil
+	}
+	return true, json.Unmarshal(raw, v)
+}
+
+var (
+	torrentInfoFields = sync.OnceValue(func() map[string]reflect.Type { return jsonFields(reflect.TypeOf(TorrentInfo{})) })
+	serverStateFields = sync.OnceValue(func() map[string]reflect.Type { return jsonFields(reflect.TypeOf(ServerState{})) })
+)
+
+// collectExtras adds the fields of the JSON object data whose names are neither in known nor in handled to
+// extras, which is allocated when needed and returned. Fields already in extras are replaced, so partial
+// sync updates can be applied onto a previous value.
+//
+// It runs for every torrent of every sync update, so the object is scanned in place rather than decoded
+// again, and only the unknown fields are copied.
+func collectExtras(data []byte, known map[string]reflect.Type, extras Extras, handled ...string) (Extras, error) {
+	err := scanObject(data, func(key, raw []byte) error {
+		if _, ok := known[string(key)]; ok {
+			return nil
+		}
+		name := string(key)
+		if bytes.IndexByte(key, '\\') >= 0 {
+			if err := json.Unmarshal(append(append([]byte{'"'}, key...), '"'), &name); err != nil {
+				return err
+			}
+		}
+		if _, ok := known[strings.ToLower(name)]; ok || slices.Contains(handled, name) {
+			return nil
+		}
+		if extras == nil {
+			extras = make(Extras)
+		}
+		extras[name] = bytes.Clone(raw)
+		return nil
+	})
+	return extras, err
+}
+
+// scanObject calls fn with the still escaped name and the raw value of each field of the JSON object data.
+// Values are only skipped over, not validated.
+func scanObject(data []byte, fn func(key, raw []byte) error) error {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return errNotObject
+	}
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return nil
+	}
+	for {
+		if i >= len(data) || data[i] != '"' {
+			return errNotObject
+		}
+		end, ok := skipString(data, i)
+		if !ok {
+			return errNotObject
+		}
+		key := data[i+1 : end-1]
+		i = skipSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return errNotObject
+		}
+		start := skipSpace(data, i+1)
+		end, ok = skipValue(data, start)
+		if !ok {
+			return errNotObject
+		}
+		if err := fn(key, data[start:end]); err != nil {
+			return err
+		}
+		i = skipSpace(data, end)
+		if i >= len(data) {
+			return errNotObject
+		}
+		switch data[i] {
+		case ',':
+			i = skipSpace(data, i+1)
+		case '}':
+			return nil
+		default:
+			return errNotObject
+		}
+	}
+}
+
+var errNotObject = errors.New("invalid JSON object")
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipString returns the index after the string starting with the quote at data[i]
+func skipString(data []byte, i int) (int, bool) {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// skipValue returns the index after the value starting at data[i]
+func skipValue(data []byte, i int) (int, bool) {
+	if i >= len(data) {
+		return 0, false
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(data); i++ {
+			switch data[i] {
+			case '"':
+				end, ok := skipString(data, i)
+				if !ok {
+					return 0, false
+				}
+				i = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1, true
+				}
+			}
+		}
+		return 0, false
+	}
+	// Numbers and literals end at the next delimiter
+	start := i
+	for i < len(data) && !strings.ContainsRune(",}] \t\n\r", rune(data[i])) {
+		i++
+	}
+	return i, i > start
+}
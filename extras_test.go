@@ -0,0 +1,67 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestExtras(t *testing.T) {
+	var torrent TorrentInfo
+	if err := json.Unmarshal([]byte(`{"name":"a","tags":"x","popularity":1.5,"reannounce":30}`), &torrent); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(torrent.Extras) != 2 {
+		t.Fatalf("expected 2 extras, got %v", torrent.Extras)
+	}
+	var popularity float64
+	if ok, err := torrent.Extras.Get("popularity", &popularity); !ok || err != nil || popularity != 1.5 {
+		t.Errorf("unexpected popularity %v, %v, %v", popularity, ok, err)
+	}
+	if ok, _ := torrent.Extras.Get("missing", &popularity); ok {
+		t.Errorf("expected missing to be absent")
+	}
+
+	// Partial updates replace the extras they carry and keep the others
+	if err := json.Unmarshal([]byte(`{"reannounce":10}`), &torrent); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if string(torrent.Extras["reannounce"]) != "10" || string(torrent.Extras["popularity"]) != "1.5" {
+		t.Errorf("unexpected extras %v", torrent.Extras)
+	}
+
+	var known TorrentInfo
+	if err := json.Unmarshal([]byte(`{"name":"a","tags":"","Size":1}`), &known); err != nil || known.Extras != nil {
+		t.Errorf("expected no extras, got %v, %v", known.Extras, err)
+	}
+
+	var state ServerState
+	if err := json.Unmarshal([]byte(`{"dht_nodes":5,"last_external_address_v4":"1.2.3.4"}`), &state); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if state.DHTNodes != 5 || string(state.Extras["last_external_address_v4"]) != `"1.2.3.4"` {
+		t.Errorf("unexpected state %+v", state)
+	}
+}
+
+func TestCollectExtras_Scan(t *testing.T) {
+	data := []byte(` { "name" : "a,}\"b" , "nested":{"x":[1,{"y":"]"}]}, "list":[ ], "name2":null, "num":-1.5e3, "pop\u0075":1 } `)
+	extras, err := collectExtras(data, torrentInfoFields(), nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := map[string]string{"nested": `{"x":[1,{"y":"]"}]}`, "list": "[ ]", "name2": "null", "num": "-1.5e3", "popu": "1"}
+	if len(extras) != len(want) {
+		t.Fatalf("unexpected extras %v", extras)
+	}
+	for name, raw := range want {
+		if string(extras[name]) != raw {
+			t.Errorf("extra %s: expected %s, got %s", name, raw, extras[name])
+		}
+	}
+
+	for _, invalid := range []string{``, `[]`, `{"a"}`, `{"a":1`, `{"a":"1}`, `{"a":}`} {
+		if _, err := collectExtras([]byte(invalid), torrentInfoFields(), nil); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// SetFilePrioritiesByPattern sets priority on the files of a torrent matching the path.Match pattern glob
+// in a single request and returns their indexes. A pattern without a slash is matched against every
+// segment of the file names, so "*.nfo" matches the .nfo files in any folder and "Sample" all files in
+// folders named Sample; a pattern with a slash is matched against the whole name or one of its folders,
+// as in "*/Sample". Nothing is sent if no file matches.
+func (c *Client) SetFilePrioritiesByPattern(hash, glob string, priority int) ([]int, error) {
+	if _, err := path.Match(glob, ""); err != nil {
+		return nil, fmt.Errorf("SetFilePrioritiesByPattern error: %w: %q", err, glob)
+	}
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return nil, fmt.Errorf("SetFilePrioritiesByPattern error: %w", err)
+	}
+
+	var indexes []int
+	for _, file := range files {
+		if matchFilePattern(glob, file.Name) {
+			indexes = append(indexes, file.Index)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	if err := c.TorrentsSetFilePriority(hash, indexes, priority); err != nil {
+		return nil, fmt.Errorf("SetFilePrioritiesByPattern error: %w", err)
+	}
+	return indexes, nil
+}
+
+// matchFilePattern reports whether the slash separated name matches glob, see SetFilePrioritiesByPattern
+func matchFilePattern(glob, name string) bool {
+	segments := strings.Split(name, "/")
+	for i := range segments {
+		candidate := segments[i]
+		if strings.Contains(glob, "/") {
+			candidate = strings.Join(segments[:i+1], "/")
+		}
+		if ok, _ := path.Match(glob, candidate); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// VideoExtensions are the extensions of common video files, for SelectByExtension
+var VideoExtensions = []string{".mkv", ".mp4", ".m4v", ".avi", ".mov", ".wmv", ".mpg", ".mpeg", ".ts", ".webm"}
+
+// SelectLargestFile downloads only the largest file of a torrent, skipping the others, and returns it. The
+// torrent's metadata must be available.
+func (c *Client) SelectLargestFile(hash string) (TorrentFile, error) {
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return TorrentFile{}, fmt.Errorf("SelectLargestFile error: %w", err)
+	}
+	if len(files) == 0 {
+		return TorrentFile{}, fmt.Errorf("SelectLargestFile error: torrent %s has no files yet", hash)
+	}
+	largest := files[0]
+	for _, file := range files[1:] {
+		if file.Size > largest.Size {
+			largest = file
+		}
+	}
+	if err := c.selectFiles(hash, files, []int{largest.Index}); err != nil {
+		return TorrentFile{}, fmt.Errorf("SelectLargestFile error: %w", err)
+	}
+	return largest, nil
+}
+
+// SelectByExtension downloads only the files of a torrent with one of the extensions, such as
+// VideoExtensions, compared case insensitively, and returns them. The other files are skipped. If no file
+// matches, nothing is changed and an error is returned.
+func (c *Client) SelectByExtension(hash string, exts []string) ([]TorrentFile, error) {
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return nil, fmt.Errorf("SelectByExtension error: %w", err)
+	}
+	var selected []TorrentFile
+	var indexes []int
+	for _, file := range files {
+		ext := path.Ext(file.Name)
+		for _, e := range exts {
+			if strings.EqualFold(ext, e) {
+				selected = append(selected, file)
+				indexes = append(indexes, file.Index)
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("SelectByExtension error: no file of torrent %s has extension %s", hash, strings.Join(exts, ", "))
+	}
+	if err := c.selectFiles(hash, files, indexes); err != nil {
+		return nil, fmt.Errorf("SelectByExtension error: %w", err)
+	}
+	return selected, nil
+}
+
+// selectFiles skips the files not in indexes and downloads the skipped ones in indexes with normal
+// priority, leaving the priority of the other selected files alone
+func (c *Client) selectFiles(hash string, files []TorrentFile, indexes []int) error {
+	var skip, resume []int
+	for _, file := range files {
+		selected := slices.Contains(indexes, file.Index)
+		switch {
+		case !selected && file.Priority != FilePriorityDontDownload:
+			skip = append(skip, file.Index)
+		case selected && file.Priority == FilePriorityDontDownload:
+			resume = append(resume, file.Index)
+		}
+	}
+	if len(skip) > 0 {
+		if err := c.TorrentsSetFilePriority(hash, skip, FilePriorityDontDownload); err != nil {
+			return err
+		}
+	}
+	if len(resume) > 0 {
+		if err := c.TorrentsSetFilePriority(hash, resume, FilePriorityNormal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestMatchFilePattern(t *testing.T) {
+	tests := []struct {
+		glob, name string
+		want       bool
+	}{
+		{"*.nfo", "show/info.nfo", true},
+		{"*.nfo", "info.nfo", true},
+		{"*.nfo", "show/info.nfo.mkv", false},
+		{"Sample", "movie/Sample/clip.mkv", true},
+		{"sample*", "movie/Sample/clip.mkv", false},
+		{"*/Sample", "movie/Sample/clip.mkv", true},
+		{"*/Sample", "movie/extras/Sample/clip.mkv", false},
+		{"movie/*.mkv", "movie/film.mkv", true},
+		{"movie/*.mkv", "movie/Sample/clip.mkv", false},
+	}
+	for _, tt := range tests {
+		if got := matchFilePattern(tt.glob, tt.name); got != tt.want {
+			t.Errorf("matchFilePattern(%q, %q) = %v, want %v", tt.glob, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClient_SetFilePrioritiesByPattern(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.URL.Path+" "+r.PostForm.Encode())
+		if r.URL.Path == "/api/v2/torrents/files" {
+			w.Write([]byte(`[{"index":0,"name":"movie/film.mkv"},{"index":1,"name":"movie/film.nfo"},` +
+				`{"index":2,"name":"movie/Sample/clip.mkv"}]`))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	indexes, err := client.SetFilePrioritiesByPattern("abc", "*.nfo", FilePriorityDontDownload)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(indexes, []int{1}) {
+		t.Errorf("expected indexes [1], got %v", indexes)
+	}
+	indexes, err = client.SetFilePrioritiesByPattern("abc", "*.iso", FilePriorityDontDownload)
+	if err != nil || indexes != nil {
+		t.Errorf("expected no match, got %v, %v", indexes, err)
+	}
+
+	want := []string{
+		"/api/v2/torrents/files ",
+		"/api/v2/torrents/filePrio hash=abc&id=1&priority=0",
+		"/api/v2/torrents/files ",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests\n%v\ngot\n%v", want, requests)
+	}
+
+	if _, err := client.SetFilePrioritiesByPattern("abc", "[", 0); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("expected ErrBadPattern, got %v", err)
+	}
+}
+
+// selectionServer fakes a server whose torrent has a video, its sample and a skipped subtitle
+func selectionServer(requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.URL.Path == "/api/v2/torrents/files" {
+			w.Write([]byte(`[{"index":0,"name":"movie/film.MKV","size":1000,"priority":1},` +
+				`{"index":1,"name":"movie/sample.mkv","size":10,"priority":1},` +
+				`{"index":2,"name":"movie/film.srt","size":1,"priority":0},` +
+				`{"index":3,"name":"movie/film.nfo","size":1,"priority":1}]`))
+			return
+		}
+		*requests = append(*requests, r.URL.Path+" "+r.PostForm.Encode())
+	}))
+}
+
+func TestClient_SelectLargestFile(t *testing.T) {
+	var requests []string
+	mockServer := selectionServer(&requests)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	file, err := client.SelectLargestFile("abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if file.Index != 0 {
+		t.Errorf("expected file 0, got %+v", file)
+	}
+	want := []string{"/api/v2/torrents/filePrio hash=abc&id=1%7C3&priority=0"}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests\n%v\ngot\n%v", want, requests)
+	}
+}
+
+func TestClient_SelectByExtension(t *testing.T) {
+	var requests []string
+	mockServer := selectionServer(&requests)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	files, err := client.SelectByExtension("abc", []string{".mkv", ".srt"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(files) != 3 {
+		t.Errorf("expected 3 files, got %+v", files)
+	}
+	want := []string{
+		"/api/v2/torrents/filePrio hash=abc&id=3&priority=0",
+		"/api/v2/torrents/filePrio hash=abc&id=2&priority=1",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests\n%v\ngot\n%v", want, requests)
+	}
+
+	requests = nil
+	if _, err := client.SelectByExtension("abc", []string{".iso"}); err == nil {
+		t.Error("expected an error without matching files")
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected no changes, got %v", requests)
+	}
+}
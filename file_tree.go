@@ -0,0 +1,226 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// FilePriorityMixed is reported by FileNode.Priority for folders whose files have different priorities
+const FilePriorityMixed = -1
+
+// FileNode is a file or folder of a torrent's file tree, as built by BuildFileTree. Name and the Priority of
+// File may be edited; Files and ApplyFileTree pick the edits up.
+type FileNode struct {
+	Name     string       // last path segment, "" for the root
+	File     *TorrentFile // the file, nil for folders
+	Children []*FileNode  // folders first, then files, each sorted by name
+
+	parent *FileNode
+}
+
+// BuildFileTree builds the tree of the slash separated file names returned by TorrentsFiles. The root is a
+// folder without name, usually holding the torrent's root folder. files is not modified.
+func BuildFileTree(files []TorrentFile) *FileNode {
+	root := &FileNode{}
+	for _, file := range files {
+		file := file
+		node := root
+		segments := strings.Split(file.Name, "/")
+		for _, segment := range segments[:len(segments)-1] {
+			node = node.child(segment)
+		}
+		node.Children = append(node.Children, &FileNode{Name: segments[len(segments)-1], File: &file, parent: node})
+	}
+	root.Walk(func(n *FileNode) bool {
+		slices.SortStableFunc(n.Children, func(a, b *FileNode) int {
+			if a.IsDir() != b.IsDir() {
+				if a.IsDir() {
+					return -1
+				}
+				return 1
+			}
+			return cmp.Compare(a.Name, b.Name)
+		})
+		return true
+	})
+	return root
+}
+
+// child returns the sub-folder name, creating it if needed
+func (n *FileNode) child(name string) *FileNode {
+	for _, c := range n.Children {
+		if c.Name == name && c.IsDir() {
+			return c
+		}
+	}
+	c := &FileNode{Name: name, parent: n}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// IsDir reports whether n is a folder
+func (n *FileNode) IsDir() bool {
+	return n.File == nil
+}
+
+// Path returns the slash separated path of n relative to the save path, reflecting renamed nodes
+func (n *FileNode) Path() string {
+	if n.parent == nil {
+		return n.Name
+	}
+	if parent := n.parent.Path(); parent != "" {
+		return parent + "/" + n.Name
+	}
+	return n.Name
+}
+
+// Walk calls fn for n and the nodes below it, depth first. Returning false skips the children of a node.
+func (n *FileNode) Walk(fn func(node *FileNode) bool) {
+	if !fn(n) {
+		return
+	}
+	for _, c := range n.Children {
+		c.Walk(fn)
+	}
+}
+
+// Find returns the node at the slash separated path relative to n, or nil
+func (n *FileNode) Find(path string) *FileNode {
+	node := n
+	for _, segment := range strings.Split(path, "/") {
+		var next *FileNode
+		for _, c := range node.Children {
+			if c.Name == segment {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+// Size returns the size of the file or the total size of the files in the folder
+func (n *FileNode) Size() int64 {
+	var size int64
+	n.Walk(func(node *FileNode) bool {
+		if node.File != nil {
+			size += node.File.Size
+		}
+		return true
+	})
+	return size
+}
+
+// Progress returns the progress of the file or the size weighted progress of the files in the folder
+func (n *FileNode) Progress() float64 {
+	var size, done float64
+	n.Walk(func(node *FileNode) bool {
+		if node.File != nil {
+			size += float64(node.File.Size)
+			done += float64(node.File.Size) * node.File.Progress
+		}
+		return true
+	})
+	if size == 0 {
+		return 0
+	}
+	return done / size
+}
+
+// Priority returns the priority of the file or the common priority of the files in the folder,
+// FilePriorityMixed if they differ
+func (n *FileNode) Priority() int {
+	priority, seen, mixed := FilePriorityMixed, false, false
+	n.Walk(func(node *FileNode) bool {
+		switch {
+		case mixed || node.File == nil:
+		case !seen:
+			priority, seen = node.File.Priority, true
+		case node.File.Priority != priority:
+			mixed = true
+		}
+		return !mixed
+	})
+	if mixed {
+		return FilePriorityMixed
+	}
+	return priority
+}
+
+// SetPriority sets the priority of the file or of all files in the folder
+func (n *FileNode) SetPriority(priority int) {
+	n.Walk(func(node *FileNode) bool {
+		if node.File != nil {
+			node.File.Priority = priority
+		}
+		return true
+	})
+}
+
+// Files flattens the tree below n back to files sorted by index, named after their current path and with
+// their current priority
+func (n *FileNode) Files() []TorrentFile {
+	var files []TorrentFile
+	n.Walk(func(node *FileNode) bool {
+		if node.File != nil {
+			file := *node.File
+			file.Name = node.Path()
+			files = append(files, file)
+		}
+		return true
+	})
+	slices.SortFunc(files, func(a, b TorrentFile) int { return cmp.Compare(a.Index, b.Index) })
+	return files
+}
+
+// Indexes returns the indexes of the files below n, sorted, e.g. for TorrentsSetFilePriority
+func (n *FileNode) Indexes() []int {
+	var indexes []int
+	for _, file := range n.Files() {
+		indexes = append(indexes, file.Index)
+	}
+	return indexes
+}
+
+// ApplyFileTree applies the edits made to tree, built from original, to the torrent hash: files whose path
+// changed are renamed and files whose priority changed get the new one, one request per priority.
+func (c *Client) ApplyFileTree(hash string, original []TorrentFile, tree *FileNode) error {
+	before := make(map[int]TorrentFile, len(original))
+	for _, file := range original {
+		before[file.Index] = file
+	}
+
+	priorities := make(map[int][]int)
+	for _, file := range tree.Files() {
+		old, ok := before[file.Index]
+		if !ok {
+			return fmt.Errorf("ApplyFileTree error: file %d is not in the original files", file.Index)
+		}
+		if file.Name != old.Name {
+			if err := c.TorrentsRenameFile(hash, old.Name, file.Name); err != nil {
+				return fmt.Errorf("ApplyFileTree error: %w", err)
+			}
+		}
+		if file.Priority != old.Priority {
+			priorities[file.Priority] = append(priorities[file.Priority], file.Index)
+		}
+	}
+
+	keys := make([]int, 0, len(priorities))
+	for priority := range priorities {
+		keys = append(keys, priority)
+	}
+	slices.Sort(keys)
+	for _, priority := range keys {
+		if err := c.TorrentsSetFilePriority(hash, priorities[priority], priority); err != nil {
+			return fmt.Errorf("ApplyFileTree error: %w", err)
+		}
+	}
+	return nil
+}
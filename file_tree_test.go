@@ -0,0 +1,94 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+var fileTreeFiles = []TorrentFile{
+	{Index: 0, Name: "show/s01/e01.mkv", Size: 100, Progress: 1, Priority: 1},
+	{Index: 1, Name: "show/s01/e02.mkv", Size: 300, Progress: 0, Priority: 1},
+	{Index: 2, Name: "show/info.nfo", Size: 4, Progress: 1, Priority: 0},
+	{Index: 3, Name: "show/s02/e01.mkv", Size: 100, Progress: 0.5, Priority: 1},
+}
+
+func TestBuildFileTree(t *testing.T) {
+	root := BuildFileTree(fileTreeFiles)
+
+	var paths []string
+	root.Walk(func(n *FileNode) bool {
+		paths = append(paths, n.Path())
+		return true
+	})
+	want := []string{"", "show", "show/s01", "show/s01/e01.mkv", "show/s01/e02.mkv", "show/s02", "show/s02/e01.mkv", "show/info.nfo"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("expected paths %v, got %v", want, paths)
+	}
+
+	season := root.Find("show/s01")
+	if season == nil || !season.IsDir() {
+		t.Fatalf("expected folder show/s01, got %+v", season)
+	}
+	if season.Size() != 400 || season.Progress() != 0.25 || season.Priority() != 1 {
+		t.Errorf("unexpected aggregates size=%d progress=%v priority=%d", season.Size(), season.Progress(), season.Priority())
+	}
+	if p := root.Priority(); p != FilePriorityMixed {
+		t.Errorf("expected mixed priority, got %d", p)
+	}
+	if root.Find("show/s03") != nil {
+		t.Error("expected no node for a missing path")
+	}
+	if fileTreeFiles[0].Priority != 1 {
+		t.Error("expected the files not to be modified")
+	}
+}
+
+func TestFileNode_Files(t *testing.T) {
+	root := BuildFileTree(fileTreeFiles)
+	root.Find("show/s01").Name = "Season 1"
+	root.Find("show/s02").SetPriority(0)
+
+	got := root.Files()
+	want := []TorrentFile{
+		{Index: 0, Name: "show/Season 1/e01.mkv", Size: 100, Progress: 1, Priority: 1},
+		{Index: 1, Name: "show/Season 1/e02.mkv", Size: 300, Progress: 0, Priority: 1},
+		{Index: 2, Name: "show/info.nfo", Size: 4, Progress: 1, Priority: 0},
+		{Index: 3, Name: "show/s02/e01.mkv", Size: 100, Progress: 0.5, Priority: 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if indexes := root.Find("show/Season 1").Indexes(); !reflect.DeepEqual(indexes, []int{0, 1}) {
+		t.Errorf("expected indexes [0 1], got %v", indexes)
+	}
+}
+
+func TestClient_ApplyFileTree(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		requests = append(requests, r.URL.Path+" "+r.PostForm.Encode())
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	root := BuildFileTree(fileTreeFiles)
+	root.Find("show/s01").Name = "Season 1"
+	root.Find("show/Season 1/e02.mkv").SetPriority(7)
+	root.Find("show/info.nfo").SetPriority(1)
+	if err := client.ApplyFileTree("abc", fileTreeFiles, root); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"/api/v2/torrents/renameFile hash=abc&newPath=show%2FSeason+1%2Fe01.mkv&oldPath=show%2Fs01%2Fe01.mkv",
+		"/api/v2/torrents/renameFile hash=abc&newPath=show%2FSeason+1%2Fe02.mkv&oldPath=show%2Fs01%2Fe02.mkv",
+		"/api/v2/torrents/filePrio hash=abc&id=2&priority=1",
+		"/api/v2/torrents/filePrio hash=abc&id=1&priority=7",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests\n%v\ngot\n%v", want, requests)
+	}
+}
@@ -0,0 +1,164 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ForceStartPolicyConfig configures a ForceStartPolicy. A torrent matches if it carries one of Tags, is in
+// one of Categories or is among the first MaxQueuePosition of the queue, and Filter, if set, accepts it.
+type ForceStartPolicyConfig struct {
+	Tags             []string
+	Categories       []string
+	MaxQueuePosition int64 // 0 disables matching by queue position
+	// Filter, if set, restricts the policy to torrents for which it returns true
+	Filter func(torrent TorrentInfo) bool
+
+	// Max caps the number of force-started torrents, including those force-started by other means, default 3
+	Max      int
+	Interval time.Duration // how often Run applies the policy, default 30s
+
+	// OnChange, if set, is called when the policy enables or disables force start of a torrent
+	OnChange func(hash InfoHash, forced bool)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// ForceStartPolicy force-starts the torrents matching its criteria, bypassing the queue, as long as fewer
+// than Max torrents are force-started. It disables force start again on the torrents it force-started once
+// they no longer match, and leaves other force-started torrents alone. Paused torrents are not started.
+type ForceStartPolicy struct {
+	client *Client
+	cfg    ForceStartPolicyConfig
+
+	mu     sync.Mutex
+	forced map[InfoHash]bool // torrents force-started by the policy
+}
+
+// NewForceStartPolicy creates a ForceStartPolicy for the given client
+func NewForceStartPolicy(client *Client, cfg ForceStartPolicyConfig) *ForceStartPolicy {
+	if cfg.Max <= 0 {
+		cfg.Max = 3
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &ForceStartPolicy{client: client, cfg: cfg, forced: make(map[InfoHash]bool)}
+}
+
+// Run applies the policy every Interval until ctx is cancelled
+func (p *ForceStartPolicy) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.RunOnce(ctx); err != nil && ctx.Err() == nil && p.cfg.OnError != nil {
+			p.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the policy once
+func (p *ForceStartPolicy) RunOnce(ctx context.Context) error {
+	torrents, err := p.client.TorrentsInfo()
+	if err != nil {
+		return fmt.Errorf("ForceStartPolicy error: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	active := 0
+	present := make(map[InfoHash]bool, len(torrents))
+	var candidates []TorrentInfo
+	for _, torrent := range torrents {
+		present[torrent.Hash] = true
+		matches := p.matches(torrent)
+		switch {
+		case !torrent.ForceStart:
+			// Force start was disabled by someone else, the policy may enable it again
+			delete(p.forced, torrent.Hash)
+			if matches && !IsPausedState(torrent.State) {
+				candidates = append(candidates, torrent)
+			}
+		case p.forced[torrent.Hash] && !matches:
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := p.client.SetForceStart(string(torrent.Hash), false); err != nil {
+				return fmt.Errorf("ForceStartPolicy error: %w", err)
+			}
+			delete(p.forced, torrent.Hash)
+			p.changed(torrent.Hash, false)
+		default:
+			active++
+		}
+	}
+	for hash := range p.forced {
+		if !present[hash] {
+			delete(p.forced, hash)
+		}
+	}
+
+	// The front of the queue first, then the oldest torrents
+	slices.SortStableFunc(candidates, func(a, b TorrentInfo) int {
+		if c := cmp.Compare(queueKey(a.Priority), queueKey(b.Priority)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.AddedOn, b.AddedOn)
+	})
+	for _, torrent := range candidates {
+		if active >= p.cfg.Max {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.client.SetForceStart(string(torrent.Hash), true); err != nil {
+			return fmt.Errorf("ForceStartPolicy error: %w", err)
+		}
+		p.forced[torrent.Hash] = true
+		active++
+		p.changed(torrent.Hash, true)
+	}
+	return nil
+}
+
+// matches reports whether torrent meets the criteria of the policy
+func (p *ForceStartPolicy) matches(torrent TorrentInfo) bool {
+	if p.cfg.Filter != nil && !p.cfg.Filter(torrent) {
+		return false
+	}
+	for _, tag := range p.cfg.Tags {
+		if hasTag(torrent, tag) {
+			return true
+		}
+	}
+	if slices.Contains(p.cfg.Categories, torrent.Category) {
+		return true
+	}
+	return p.cfg.MaxQueuePosition > 0 && torrent.Priority > 0 && torrent.Priority <= p.cfg.MaxQueuePosition
+}
+
+func (p *ForceStartPolicy) changed(hash InfoHash, forced bool) {
+	if p.cfg.OnChange != nil {
+		p.cfg.OnChange(hash, forced)
+	}
+}
+
+// queueKey maps the queue position 0, used for torrents outside the queue, past every real position
+func queueKey(position int64) int64 {
+	if position <= 0 {
+		return 1<<63 - 1
+	}
+	return position
+}
@@ -0,0 +1,73 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestForceStartPolicy_RunOnce(t *testing.T) {
+	info := `[
+		{"hash":"tagged","tags":"vip","priority":5,"state":"queuedDL"},
+		{"hash":"front","priority":1,"state":"queuedDL"},
+		{"hash":"paused","category":"urgent","priority":2,"state":"pausedDL"},
+		{"hash":"manual","force_start":true,"state":"forcedDL"},
+		{"hash":"other","priority":9,"state":"queuedDL"}
+	]`
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(info))
+		case "/api/v2/torrents/setForceStart":
+			requests = append(requests, r.PostForm.Get("hashes")+"="+r.PostForm.Get("value"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	var changes []string
+	p := NewForceStartPolicy(client, ForceStartPolicyConfig{
+		Tags:             []string{"vip"},
+		Categories:       []string{"urgent"},
+		MaxQueuePosition: 2,
+		Max:              2,
+		OnChange: func(hash InfoHash, forced bool) {
+			if forced {
+				changes = append(changes, "+"+string(hash))
+			} else {
+				changes = append(changes, "-"+string(hash))
+			}
+		},
+	})
+
+	// manual counts towards the cap, so only the front of the queue is force-started
+	if err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := []string{"front=true"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+
+	// front moved down the queue, manual was stopped: front is released and tagged takes its place
+	info = `[
+		{"hash":"tagged","tags":"vip","priority":4,"state":"queuedDL"},
+		{"hash":"front","force_start":true,"priority":3,"state":"forcedDL"},
+		{"hash":"manual","state":"pausedDL"}
+	]`
+	requests = nil
+	if err := p.RunOnce(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := []string{"front=false", "tagged=true"}; !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+	if want := []string{"+front", "-front", "+tagged"}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+}
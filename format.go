@@ -0,0 +1,57 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"time"
+)
+
+// ByteUnits selects the units of FormatBytes and FormatSpeed
+type ByteUnits int
+
+const (
+	IEC ByteUnits = iota // powers of 1024: KiB, MiB, GiB, as shown by qBittorrent
+	SI                   // powers of 1000: kB, MB, GB
+)
+
+// FormatBytes formats a size in bytes with one decimal in the largest unit it reaches, e.g. "1.5 GiB"
+func FormatBytes(n int64, units ByteUnits) string {
+	unit, prefixes, suffix := int64(1024), "KMGTPE", "iB"
+	if units == SI {
+		unit, prefixes, suffix = 1000, "kMGTPE", "B"
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	if n < unit {
+		return fmt.Sprintf("%s%d B", sign, n)
+	}
+	div, exp := unit, 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%s%.1f %c%s", sign, float64(n)/float64(div), prefixes[exp], suffix)
+}
+
+// FormatSpeed formats a speed in bytes per second, e.g. "2.0 MiB/s"
+func FormatSpeed(bytesPerSecond int64, units ByteUnits) string {
+	return FormatBytes(bytesPerSecond, units) + "/s"
+}
+
+// FormatETA formats an ETA in seconds as reported by qBittorrent, e.g. "3h05m", or "-" if the torrent is not
+// expected to complete. ETAs of 100 hours or more are shown in days.
+func FormatETA(seconds int64) string {
+	eta, ok := TorrentInfo{ETA: seconds}.ETADuration()
+	if !ok || eta == 0 {
+		return "-"
+	}
+	h, m, s := int64(eta/time.Hour), int64(eta/time.Minute)%60, int64(eta/time.Second)%60
+	if h > 99 {
+		return fmt.Sprintf("%dd", h/24)
+	}
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+	return fmt.Sprintf("%dm%02ds", m, s)
+}
@@ -0,0 +1,43 @@
+package qbittorrent
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n     int64
+		units ByteUnits
+		want  string
+	}{
+		{0, IEC, "0 B"},
+		{1023, IEC, "1023 B"},
+		{1536, IEC, "1.5 KiB"},
+		{5 << 30, IEC, "5.0 GiB"},
+		{1500, SI, "1.5 kB"},
+		{2500000000, SI, "2.5 GB"},
+		{-2048, IEC, "-2.0 KiB"},
+	}
+	for _, tt := range tests {
+		if got := FormatBytes(tt.n, tt.units); got != tt.want {
+			t.Errorf("FormatBytes(%d, %d) = %q, want %q", tt.n, tt.units, got, tt.want)
+		}
+	}
+	if got := FormatSpeed(2<<20, IEC); got != "2.0 MiB/s" {
+		t.Errorf("unexpected speed %q", got)
+	}
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := map[int64]string{
+		0:            "-",
+		-1:           "-",
+		ETAInfinite:  "-",
+		75:           "1m15s",
+		3*3600 + 300: "3h05m",
+		120 * 3600:   "5d",
+	}
+	for seconds, want := range tests {
+		if got := FormatETA(seconds); got != want {
+			t.Errorf("FormatETA(%d) = %q, want %q", seconds, got, want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Endpoints that return one value per torrent, such as torrents/downloadLimit, are exposed as
+// map[InfoHash]T. ZipByHash pairs such a map with a torrent list.
+
+// TorrentValue pairs a torrent with its entry in a per-hash result map
+type TorrentValue[T any] struct {
+	Torrent TorrentInfo
+	Value   T
+	OK      bool // whether the map had an entry for the torrent
+}
+
+// ZipByHash pairs every torrent with its value in values, keeping the order of torrents
+func ZipByHash[T any](torrents []TorrentInfo, values map[InfoHash]T) []TorrentValue[T] {
+	out := make([]TorrentValue[T], len(torrents))
+	for i, torrent := range torrents {
+		value, ok := values[torrent.Hash]
+		out[i] = TorrentValue[T]{Torrent: torrent, Value: value, OK: ok}
+	}
+	return out
+}
+
+// getHashMap POSTs hashes to a multi-hash getter and decodes its hash keyed response
+func getHashMap[T any](c *Client, endpoint, hashes string) (map[InfoHash]T, error) {
+	data := url.Values{}
+	data.Set("hashes", hashes)
+
+	respData, err := c.doPostValues(endpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result map[InfoHash]T
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %v", err)
+	}
+	if result == nil {
+		result = make(map[InfoHash]T)
+	}
+	return result, nil
+}
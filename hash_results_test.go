@@ -0,0 +1,60 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTorrentsDownloadLimit(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":              {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/torrents/downloadLimit":  {statusCode: http.StatusOK, responseBody: `{"hash1":1048576,"hash2":0}`},
+		"/api/v2/torrents/uploadLimit":    {statusCode: http.StatusOK, responseBody: `{"hash1":512}`},
+		"/api/v2/torrents/setUploadLimit": {statusCode: http.StatusOK},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "POST", url: "/api/v2/torrents/downloadLimit", params: url.Values{"hashes": {"hash1|hash2"}}},
+		{method: "POST", url: "/api/v2/torrents/uploadLimit", params: url.Values{"hashes": {"hash1"}}},
+		{method: "POST", url: "/api/v2/torrents/setUploadLimit", params: url.Values{"hashes": {"hash1"}, "limit": {"2048"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	limits, err := client.TorrentsDownloadLimit("hash1|hash2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := map[InfoHash]int64{"hash1": 1048576, "hash2": 0}; !reflect.DeepEqual(limits, want) {
+		t.Errorf("Expected %v, got %v", want, limits)
+	}
+	limits, err = client.TorrentsUploadLimit("hash1")
+	if err != nil || limits["hash1"] != 512 {
+		t.Errorf("Expected hash1 limit 512, got %v, %v", limits, err)
+	}
+	if err := client.TorrentsSetUploadLimit("hash1", 2048); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestZipByHash(t *testing.T) {
+	torrents := []TorrentInfo{{Hash: "b"}, {Hash: "a"}}
+	zipped := ZipByHash(torrents, map[InfoHash]int64{"a": 10})
+
+	want := []TorrentValue[int64]{
+		{Torrent: TorrentInfo{Hash: "b"}},
+		{Torrent: TorrentInfo{Hash: "a"}, Value: 10, OK: true},
+	}
+	if !reflect.DeepEqual(zipped, want) {
+		t.Errorf("Expected %+v, got %+v", want, zipped)
+	}
+}
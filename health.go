@@ -0,0 +1,113 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"time"
+)
+
+// TorrentHealth rates how likely a torrent is to complete or keep being seeded
+type TorrentHealth struct {
+	Hash             InfoHash
+	Score            int      // 0 for a dead torrent to 100 for a healthy one
+	Reasons          []string // why points were deducted from the score
+	Availability     float64  // distributed copies, negative when unknown
+	Seeds            int64    // seeds in the swarm
+	Peers            int64    // leechers in the swarm
+	WorkingTrackers  int
+	Trackers         int       // DHT, PeX and LSD not included
+	AddedAt          time.Time // zero if unknown
+	LastSeenComplete time.Time // zero if never seen complete
+}
+
+// Health retrieves the torrent with the given hash and its trackers and rates its health, see ComputeHealth
+func (c *Client) Health(hash string) (*TorrentHealth, error) {
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{hash}})
+	if err != nil {
+		return nil, fmt.Errorf("Health error: %w", err)
+	}
+	if len(torrents) == 0 {
+		return nil, fmt.Errorf("Health error: torrent %s not found", hash)
+	}
+	trackers, err := c.TorrentsTrackers(hash)
+	if err != nil {
+		return nil, fmt.Errorf("Health error: %w", err)
+	}
+	return ComputeHealth(torrents[0], trackers, time.Now()), nil
+}
+
+// Score deductions of ComputeHealth
+const (
+	healthNoSeeds          = 40
+	healthFewSeeds         = 10
+	healthUnavailable      = 30
+	healthNoWorkingTracker = 20
+	healthNeverComplete    = 10
+	healthStaleComplete    = 10
+)
+
+// healthStaleAfter is how long ago a complete copy must have been seen for it to lower the score
+const healthStaleAfter = 30 * 24 * time.Hour
+
+// ComputeHealth rates torrent from its swarm counts, its availability, its trackers and the time a complete
+// copy was last seen, as of now. Each problem found deducts from a score of 100 and adds a reason.
+func ComputeHealth(torrent TorrentInfo, trackers []TrackerInfo, now time.Time) *TorrentHealth {
+	h := &TorrentHealth{
+		Hash:             torrent.Hash,
+		Score:            100,
+		Availability:     torrent.Availability,
+		Seeds:            torrent.NumComplete,
+		Peers:            torrent.NumIncomplete,
+		AddedAt:          torrent.AddedAt(),
+		LastSeenComplete: torrent.SeenCompleteAt(),
+	}
+	deduct := func(points int, reason string) {
+		h.Score -= points
+		h.Reasons = append(h.Reasons, reason)
+	}
+
+	switch {
+	case h.Seeds == 0:
+		deduct(healthNoSeeds, "no seeds")
+	case h.Seeds < 3:
+		deduct(healthFewSeeds, fmt.Sprintf("few seeds (%d)", h.Seeds))
+	}
+	if torrent.Progress < 1 && h.Availability >= 0 && h.Availability < 1 {
+		deduct(healthUnavailable, fmt.Sprintf("availability %.2f, part of the content is missing from the swarm", h.Availability))
+	}
+
+	for _, tracker := range trackers {
+		if isPseudoTracker(tracker.URL) {
+			continue
+		}
+		h.Trackers++
+		if tracker.Status == TrackerWorking {
+			h.WorkingTrackers++
+		}
+	}
+	if h.Trackers > 0 && h.WorkingTrackers == 0 {
+		deduct(healthNoWorkingTracker, "no working tracker")
+	}
+
+	switch {
+	case h.LastSeenComplete.IsZero():
+		deduct(healthNeverComplete, "never seen complete")
+	case now.Sub(h.LastSeenComplete) >= healthStaleAfter:
+		deduct(healthStaleComplete, fmt.Sprintf("last seen complete %s", h.LastSeenComplete.Format(time.DateOnly)))
+	}
+
+	h.Score = max(h.Score, 0)
+	return h
+}
+
+// Dead reports whether the torrent has no seeds and no complete copy was seen for at least d as of now.
+// Torrents never seen complete count from the time they were added.
+func (h *TorrentHealth) Dead(d time.Duration, now time.Time) bool {
+	if h.Seeds > 0 {
+		return false
+	}
+	since := h.LastSeenComplete
+	if since.IsZero() {
+		since = h.AddedAt
+	}
+	return !since.IsZero() && now.Sub(since) >= d
+}
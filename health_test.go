@@ -0,0 +1,82 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputeHealth(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	healthy := TorrentInfo{Hash: "aaa", NumComplete: 12, Progress: 1, Availability: -1, SeenComplete: now.Unix()}
+	h := ComputeHealth(healthy, []TrackerInfo{
+		{URL: "** [DHT] **", Status: TrackerDisabled},
+		{URL: "https://tracker.example/announce", Status: TrackerWorking},
+	}, now)
+	if h.Score != 100 || h.Reasons != nil || h.Trackers != 1 || h.WorkingTrackers != 1 {
+		t.Errorf("expected a healthy torrent, got %+v", h)
+	}
+
+	old := now.Add(-60 * 24 * time.Hour)
+	dead := TorrentInfo{Hash: "bbb", Progress: 0.5, Availability: 0.4, AddedOn: old.Unix(), SeenComplete: unixNever}
+	h = ComputeHealth(dead, []TrackerInfo{{URL: "https://tracker.example/announce", Status: TrackerNotWorking}}, now)
+	if h.Score != 0 {
+		t.Errorf("expected score 0, got %d", h.Score)
+	}
+	want := []string{
+		"no seeds",
+		"availability 0.40, part of the content is missing from the swarm",
+		"no working tracker",
+		"never seen complete",
+	}
+	if !reflect.DeepEqual(h.Reasons, want) {
+		t.Errorf("expected reasons %q, got %q", want, h.Reasons)
+	}
+	if !h.Dead(30*24*time.Hour, now) {
+		t.Error("expected the torrent dead for 30 days")
+	}
+	if h.Dead(90*24*time.Hour, now) {
+		t.Error("expected the torrent not dead for 90 days")
+	}
+
+	stale := TorrentInfo{NumComplete: 1, Progress: 1, SeenComplete: old.Unix()}
+	h = ComputeHealth(stale, nil, now)
+	if want := []string{"few seeds (1)", "last seen complete 2024-04-02"}; h.Score != 80 || !reflect.DeepEqual(h.Reasons, want) {
+		t.Errorf("unexpected health %+v", h)
+	}
+	if h.Dead(time.Hour, now) {
+		t.Error("expected a seeded torrent not dead")
+	}
+}
+
+func TestClient_Health(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			if r.URL.Query().Get("hashes") == "aaa" {
+				w.Write([]byte(`[{"hash":"aaa","num_complete":5,"progress":1,"seen_complete":1700000000}]`))
+			} else {
+				w.Write([]byte(`[]`))
+			}
+		case "/api/v2/torrents/trackers":
+			w.Write([]byte(`[{"url":"https://tracker.example/announce","status":2}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	h, err := client.Health("aaa")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if h.Hash != "aaa" || h.Seeds != 5 || h.WorkingTrackers != 1 {
+		t.Errorf("unexpected health %+v", h)
+	}
+	if _, err := client.Health("bbb"); err == nil {
+		t.Error("expected an error for an unknown torrent")
+	}
+}
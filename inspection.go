@@ -0,0 +1,161 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// StopCondition stops a torrent once it reached a stage after being added, as the stopCondition add option.
+// Requires qBittorrent 4.5 or newer, older servers ignore it.
+type StopCondition string
+
+const (
+	StopConditionNone             StopCondition = "None"
+	StopConditionMetadataReceived StopCondition = "MetadataReceived" // stop once a magnet's metadata arrived
+	StopConditionFilesChecked     StopCondition = "FilesChecked"     // stop once existing files were checked
+)
+
+// WithStopCondition stops the added torrent once it reached condition
+func WithStopCondition(condition StopCondition) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.StopCondition = &condition
+	}
+}
+
+// inspectionPollInterval is how often AddForInspection checks whether the metadata arrived
+const inspectionPollInterval = time.Second
+
+// Inspection is a torrent added by AddForInspection, stopped with its metadata but nothing downloaded
+type Inspection struct {
+	Hash  InfoHash
+	Name  string
+	Files []TorrentFile
+
+	client *Client
+}
+
+// AddForInspection adds a magnet link that stops once its metadata arrived, waits for it and returns the
+// torrent's files, so the caller can choose which to download before calling Start, or Discard. Options
+// such as WithSavePath or WithCategory apply as for TorrentsAddURLs. The magnet must carry a v1 infohash,
+// and a torrent already on the server fails with ErrAlreadyExists. If ctx ends before the metadata
+// arrived, the torrent is removed again, without deleting files from its save path.
+//
+// Servers without stop conditions, before qBittorrent 4.5, start downloading once the metadata arrived
+// until AddForInspection stops the torrent.
+func (c *Client) AddForInspection(ctx context.Context, magnet string, opts ...TorrentAddOption) (*Inspection, error) {
+	hash, ok := magnetInfoHash(magnet)
+	if !ok {
+		return nil, fmt.Errorf("AddForInspection error: no infohash in %s", Redact(magnet))
+	}
+	if err := c.checkNotExists([]InfoHash{hash}); err != nil {
+		return nil, fmt.Errorf("AddForInspection error: %w", err)
+	}
+	opts = append(slices.Clip(opts), WithStartPaused(false), WithStopCondition(StopConditionMetadataReceived))
+	if err := c.TorrentsAddURLs([]string{magnet}, opts...); err != nil {
+		return nil, fmt.Errorf("AddForInspection error: %w", err)
+	}
+
+	inspection, err := c.waitForMetadata(ctx, hash)
+	if err != nil {
+		if rerr := c.TorrentsRemove(string(hash), false); rerr != nil {
+			return nil, fmt.Errorf("AddForInspection error: %w (removing the torrent failed: %v)", err, rerr)
+		}
+		return nil, fmt.Errorf("AddForInspection error: %w", err)
+	}
+	return inspection, nil
+}
+
+// waitForMetadata polls the torrent hash until its metadata arrived and stops it if the server did not
+func (c *Client) waitForMetadata(ctx context.Context, hash InfoHash) (*Inspection, error) {
+	for {
+		torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{string(hash)}})
+		if err != nil {
+			return nil, err
+		}
+		if len(torrents) > 0 && !isMetadataState(torrents[0].State) {
+			torrent := torrents[0]
+			files, err := c.TorrentsFiles(string(hash))
+			if err != nil {
+				return nil, err
+			}
+			if len(files) > 0 {
+				if !IsPausedState(torrent.State) {
+					if err := c.TorrentsPause(string(hash)); err != nil {
+						return nil, err
+					}
+				}
+				return &Inspection{Hash: hash, Name: torrent.Name, Files: files, client: c}, nil
+			}
+		}
+		if err := sleepContext(ctx, inspectionPollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// isMetadataState reports whether a torrent in state is still fetching its metadata
+func isMetadataState(state string) bool {
+	return state == "" || state == "metaDL" || state == "forcedMetaDL" || state == "checkingResumeData"
+}
+
+// Start downloads the files with the given indexes, see TorrentFile.Index, and skips the others. With no
+// indexes all files are downloaded.
+func (i *Inspection) Start(indexes ...int) error {
+	if len(indexes) > 0 {
+		var skipped []int
+		for _, file := range i.Files {
+			if !slices.Contains(indexes, file.Index) {
+				skipped = append(skipped, file.Index)
+			}
+		}
+		if len(skipped) > 0 {
+			if err := i.client.TorrentsSetFilePriority(string(i.Hash), skipped, FilePriorityDontDownload); err != nil {
+				return fmt.Errorf("Inspection.Start error: %w", err)
+			}
+		}
+	}
+	if err := i.client.TorrentsResume(string(i.Hash)); err != nil {
+		return fmt.Errorf("Inspection.Start error: %w", err)
+	}
+	return nil
+}
+
+// Discard removes the torrent, keeping any files in its save path
+func (i *Inspection) Discard() error {
+	if err := i.client.TorrentsRemove(string(i.Hash), false); err != nil {
+		return fmt.Errorf("Inspection.Discard error: %w", err)
+	}
+	return nil
+}
+
+// MagnetMetadata is the metadata of a magnet link, as resolved by FetchMetadata
+type MagnetMetadata struct {
+	Metainfo
+	Torrent []byte // the .torrent file
+}
+
+// FetchMetadata resolves a magnet link to its .torrent file using qBittorrent: the magnet is added with
+// AddForInspection, exported once the metadata arrived and removed again, so nothing is downloaded but the
+// metadata. The server's default save path is used, opts may choose another, e.g. with WithSavePath. Like
+// AddForInspection it fails with ErrAlreadyExists for a torrent that is on the server already; export that
+// one with TorrentsExport instead.
+func (c *Client) FetchMetadata(ctx context.Context, magnet string, opts ...TorrentAddOption) (*MagnetMetadata, error) {
+	inspection, err := c.AddForInspection(ctx, magnet, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("FetchMetadata error: %w", err)
+	}
+	data, err := c.exportBytes(ctx, string(inspection.Hash))
+	if derr := inspection.Discard(); derr != nil && err == nil {
+		err = derr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("FetchMetadata error: %w", err)
+	}
+	meta, err := ParseMetainfo(data)
+	if err != nil {
+		return nil, fmt.Errorf("FetchMetadata error: %w", err)
+	}
+	return &MagnetMetadata{Metainfo: *meta, Torrent: data}, nil
+}
@@ -0,0 +1,118 @@
+package qbittorrent
+
+import (
+	"context"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const inspectionMagnet = "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=test"
+
+// inspectionServer fakes a server whose torrent reports state once added
+func inspectionServer(t *testing.T, state string, requests *[]string) *httptest.Server {
+	added := false
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/add":
+			_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			form, err := multipart.NewReader(r.Body, params["boundary"]).ReadForm(1 << 20)
+			if err != nil {
+				t.Fatalf("invalid form: %v", err)
+			}
+			*requests = append(*requests, r.URL.Path+" stopCondition="+form.Value["stopCondition"][0])
+			added = true
+			w.Write([]byte("Ok."))
+			return
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.9.3"))
+			return
+		}
+		r.ParseForm()
+		*requests = append(*requests, r.URL.Path+" "+r.PostForm.Encode())
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			if !added {
+				w.Write([]byte(`[]`))
+				return
+			}
+			w.Write([]byte(`[{"hash":"0123456789abcdef0123456789abcdef01234567","name":"test","state":"` + state + `"}]`))
+		case "/api/v2/torrents/export":
+			w.Write(makeTorrent("test", map[string]int64{"a.mkv": 100, "b.nfo": 1}))
+		case "/api/v2/torrents/files":
+			w.Write([]byte(`[{"index":0,"name":"test/a.mkv"},{"index":1,"name":"test/b.nfo"},{"index":2,"name":"test/c.mkv"}]`))
+		}
+	}))
+}
+
+func TestClient_AddForInspection(t *testing.T) {
+	var requests []string
+	mockServer := inspectionServer(t, "pausedDL", &requests)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	inspection, err := client.AddForInspection(context.Background(), inspectionMagnet)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if inspection.Name != "test" || len(inspection.Files) != 3 {
+		t.Fatalf("unexpected inspection %+v", inspection)
+	}
+	if err := inspection.Start(0, 2); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"/api/v2/torrents/info ",
+		"/api/v2/torrents/add stopCondition=MetadataReceived",
+		"/api/v2/torrents/info ",
+		"/api/v2/torrents/files ",
+		"/api/v2/torrents/filePrio hash=0123456789abcdef0123456789abcdef01234567&id=1&priority=0",
+		"/api/v2/torrents/resume hashes=0123456789abcdef0123456789abcdef01234567",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests\n%v\ngot\n%v", want, requests)
+	}
+}
+
+func TestClient_AddForInspection_Cancelled(t *testing.T) {
+	var requests []string
+	mockServer := inspectionServer(t, "metaDL", &requests)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := client.AddForInspection(ctx, inspectionMagnet); err == nil {
+		t.Fatal("expected an error")
+	}
+	if last := requests[len(requests)-1]; last != "/api/v2/torrents/delete deleteFiles=false&hashes=0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("expected the torrent to be removed, got %s", last)
+	}
+
+	if _, err := client.AddForInspection(context.Background(), "https://example.org/a.torrent"); err == nil {
+		t.Error("expected an error for a link without infohash")
+	}
+}
+
+func TestClient_FetchMetadata(t *testing.T) {
+	var requests []string
+	mockServer := inspectionServer(t, "stoppedDL", &requests)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	meta, err := client.FetchMetadata(context.Background(), inspectionMagnet)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if meta.Name != "test" || meta.Size != 101 || len(meta.Files) != 2 || len(meta.Torrent) == 0 {
+		t.Errorf("unexpected metadata %+v", meta.Metainfo)
+	}
+	if last := requests[len(requests)-1]; last != "/api/v2/torrents/delete deleteFiles=false&hashes=0123456789abcdef0123456789abcdef01234567" {
+		t.Errorf("expected the torrent to be removed, got %s", last)
+	}
+}
@@ -0,0 +1,275 @@
+// Command apigen generates Web API bindings for the qbittorrent package from the machine-readable
+// description in apispec/webapi.json. Run it through go generate in the repository root:
+//
+//	go generate ./...
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Spec describes Web API endpoints and the types of their results
+type Spec struct {
+	Groups    []Group    `json:"groups"`
+	Types     []Type     `json:"types"`
+	Endpoints []Endpoint `json:"endpoints"`
+}
+
+// Group is an API area, generated as an interface named after it, e.g. "RSS" as RSSAPI
+type Group struct {
+	Name string `json:"name"`
+	Doc  string `json:"doc"` // how the area is referred to in the interface's comment
+}
+
+// Type is a struct type
+type Type struct {
+	Name   string  `json:"name"`
+	Doc    string  `json:"doc"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is a field of a struct type
+type Field struct {
+	Name string `json:"name"`
+	JSON string `json:"json"`
+	Type string `json:"type"`
+	Doc  string `json:"doc"`
+}
+
+// Endpoint is a Web API endpoint, generated as a Client method
+type Endpoint struct {
+	Name   string  `json:"name"`
+	Group  string  `json:"group"`
+	Method string  `json:"method"` // GET sends the parameters in the query, POST as a form
+	Path   string  `json:"path"`
+	Doc    string  `json:"doc"`
+	Params []Param `json:"params"`
+	Result string  `json:"result"` // Go type of the decoded JSON response, none if empty
+}
+
+// Param is a parameter of an endpoint
+type Param struct {
+	Name     string `json:"name"` // name in the API
+	Go       string `json:"go"`   // name of the Go argument, Name if empty
+	Type     string `json:"type"` // string, bool, int, int64 or []string, joined with "|"
+	Optional bool   `json:"optional"`
+}
+
+// GoName returns the name of the Go argument
+func (p Param) GoName() string {
+	if p.Go != "" {
+		return p.Go
+	}
+	return p.Name
+}
+
+// Format returns the expression formatting the argument as a parameter value
+func (p Param) Format() (string, error) {
+	switch p.Type {
+	case "string":
+		return p.GoName(), nil
+	case "bool":
+		return "strconv.FormatBool(" + p.GoName() + ")", nil
+	case "int":
+		return "strconv.Itoa(" + p.GoName() + ")", nil
+	case "int64":
+		return "strconv.FormatInt(" + p.GoName() + ", 10)", nil
+	case "[]string":
+		return `strings.Join(` + p.GoName() + `, "|")`, nil
+	}
+	return "", fmt.Errorf("parameter %s: unsupported type %q", p.Name, p.Type)
+}
+
+// IsSet returns the condition under which an optional argument is sent
+func (p Param) IsSet() string {
+	switch p.Type {
+	case "string":
+		return p.GoName() + ` != ""`
+	case "bool":
+		return p.GoName()
+	case "[]string":
+		return "len(" + p.GoName() + ") > 0"
+	}
+	return p.GoName() + " != 0"
+}
+
+// Signature returns the Go parameter list of the endpoint
+func (e Endpoint) Signature() string {
+	args := make([]string, len(e.Params))
+	for i, p := range e.Params {
+		args[i] = p.GoName() + " " + p.Type
+	}
+	return strings.Join(args, ", ")
+}
+
+// Returns returns the Go result list of the endpoint
+func (e Endpoint) Returns() string {
+	if e.Result == "" {
+		return "error"
+	}
+	return "(" + e.Result + ", error)"
+}
+
+// ResultElem returns the type decoded into, the result without its pointer
+func (e Endpoint) ResultElem() string {
+	return strings.TrimPrefix(e.Result, "*")
+}
+
+// check validates the parts of the spec the template relies on
+func (s *Spec) check() error {
+	groups := make(map[string]bool)
+	for _, g := range s.Groups {
+		groups[g.Name] = true
+	}
+	for _, e := range s.Endpoints {
+		if !groups[e.Group] {
+			return fmt.Errorf("endpoint %s: unknown group %q", e.Name, e.Group)
+		}
+		if e.Method != "GET" && e.Method != "POST" {
+			return fmt.Errorf("endpoint %s: unsupported method %q", e.Name, e.Method)
+		}
+		if r := e.Result; r != "" && !strings.HasPrefix(r, "[]") && !strings.HasPrefix(r, "map[") && !strings.HasPrefix(r, "*") {
+			return fmt.Errorf("endpoint %s: result %q must be a slice, map or pointer", e.Name, r)
+		}
+		for _, p := range e.Params {
+			if _, err := p.Format(); err != nil {
+				return fmt.Errorf("endpoint %s: %v", e.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+var tmpl = template.Must(template.New("api").Parse(`// Code generated by apigen from apispec/webapi.json. DO NOT EDIT.
+
+package qbittorrent
+
+import (
+{{- range .Imports}}
+	"{{.}}"
+{{- end}}
+)
+{{range $g := .Spec.Groups}}
+// {{$g.Name}}API is the {{$g.Doc}} part of the Web API
+type {{$g.Name}}API interface {
+{{- range $.Spec.Endpoints}}{{if eq .Group $g.Name}}
+	{{.Name}}({{.Signature}}) {{.Returns}}
+{{- end}}{{end}}
+}
+{{end}}
+{{- range .Spec.Types}}
+// {{.Doc}}
+type {{.Name}} struct {
+{{- range .Fields}}
+	{{.Name}} {{.Type}} ` + "`" + `json:"{{.JSON}}"` + "`" + `{{if .Doc}} // {{.Doc}}{{end}}
+{{- end}}
+}
+{{end}}
+{{- range .Spec.Endpoints}}
+// {{.Doc}}
+func (c *Client) {{.Name}}({{.Signature}}) {{.Returns}} {
+{{- $values := "params"}}{{if eq .Method "POST"}}{{$values = "data"}}{{end}}
+{{- if .Params}}
+	{{$values}} := url.Values{}
+{{- range .Params}}
+{{- if .Optional}}
+	if {{.IsSet}} {
+		{{$values}}.Set("{{.Name}}", {{.Format}})
+	}
+{{- else}}
+	{{$values}}.Set("{{.Name}}", {{.Format}})
+{{- end}}
+{{- end}}
+{{end}}
+{{- $params := "nil"}}{{if .Params}}{{$params = $values}}{{end}}
+{{- if .Result}}
+{{- if eq .Method "GET"}}
+	respData, err := c.doGet("{{.Path}}", {{$params}})
+{{- else}}
+	respData, err := c.doPostValues("{{.Path}}", {{$params}})
+{{- end}}
+	if err != nil {
+		return nil, fmt.Errorf("{{.Name}} error: %w", err)
+	}
+
+	var result {{.ResultElem}}
+	if err := c.decodeJSON(respData, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode {{.Name}} response: %v", err)
+	}
+	return {{if ne .Result .ResultElem}}&{{end}}result, nil
+{{- else}}
+{{- if eq .Method "GET"}}
+	_, err := c.doGet("{{.Path}}", {{$params}})
+{{- else}}
+	_, err := c.doPostValues("{{.Path}}", {{$params}})
+{{- end}}
+	if err != nil {
+		return fmt.Errorf("{{.Name}} error: %w", err)
+	}
+	return nil
+{{- end}}
+}
+{{end}}`))
+
+// generate renders the bindings described by spec as formatted Go source
+func generate(specData []byte) ([]byte, error) {
+	var spec Spec
+	if err := json.Unmarshal(specData, &spec); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %v", err)
+	}
+	if err := spec.check(); err != nil {
+		return nil, err
+	}
+
+	// Render once to find the packages the code uses
+	data := struct {
+		Spec    *Spec
+		Imports []string
+	}{Spec: &spec}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return nil, err
+	}
+	for _, pkg := range []string{"encoding/json", "fmt", "net/url", "strconv", "strings"} {
+		name := pkg[strings.LastIndex(pkg, "/")+1:]
+		if strings.Contains(body.String(), name+".") {
+			data.Imports = append(data.Imports, pkg)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code: %v\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+func main() {
+	specPath := flag.String("spec", "apispec/webapi.json", "path of the API description")
+	outPath := flag.String("out", "api_generated.go", "path of the generated file")
+	flag.Parse()
+
+	specData, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(specData)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*outPath, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
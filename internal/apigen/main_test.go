@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../apispec/webapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := generate(spec)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	got, err := os.ReadFile("../../api_generated.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("api_generated.go is out of date, run go generate ./...")
+	}
+}
+
+func TestGenerateInvalidSpec(t *testing.T) {
+	tests := map[string]string{
+		"group":  `{"endpoints":[{"name":"X","group":"Y","method":"GET","path":"/x"}]}`,
+		"method": `{"groups":[{"name":"Y"}],"endpoints":[{"name":"X","group":"Y","method":"PUT","path":"/x"}]}`,
+		"result": `{"groups":[{"name":"Y"}],"endpoints":[{"name":"X","group":"Y","method":"GET","path":"/x","result":"int"}]}`,
+		"param":  `{"groups":[{"name":"Y"}],"endpoints":[{"name":"X","group":"Y","method":"GET","path":"/x","params":[{"name":"p","type":"float64"}]}]}`,
+	}
+	for name, spec := range tests {
+		if _, err := generate([]byte(spec)); err == nil || !strings.Contains(err.Error(), "X") {
+			t.Errorf("%s: expected an error naming the endpoint, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// IPFilterEnabled reports whether the IP filter file is applied
+func (c *Client) IPFilterEnabled() (bool, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return false, fmt.Errorf("IPFilterEnabled error: %w", err)
+	}
+	enabled, _ := prefs["ip_filter_enabled"].(bool)
+	return enabled, nil
+}
+
+// SetIPFilterEnabled enables or disables the IP filter file
+func (c *Client) SetIPFilterEnabled(enabled bool) error {
+	if err := c.AppSetPreferences(Preferences{"ip_filter_enabled": enabled}); err != nil {
+		return fmt.Errorf("SetIPFilterEnabled error: %w", err)
+	}
+	return nil
+}
+
+// SetIPFilterPath sets the path, on the server, of the IP filter file (.dat, .p2p or .p2b) and enables
+// the filter. applyToTrackers also applies it to tracker connections.
+func (c *Client) SetIPFilterPath(path string, applyToTrackers bool) error {
+	err := c.AppSetPreferences(Preferences{
+		"ip_filter_path":     path,
+		"ip_filter_enabled":  true,
+		"ip_filter_trackers": applyToTrackers,
+	})
+	if err != nil {
+		return fmt.Errorf("SetIPFilterPath error: %w", err)
+	}
+	return nil
+}
+
+// BannedIPs retrieves the manually banned IP addresses. Unlike the IP filter file, they are always applied.
+func (c *Client) BannedIPs() ([]netip.Addr, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("BannedIPs error: %w", err)
+	}
+	list, _ := prefs["banned_IPs"].(string)
+	addrs, err := ParseBannedIPs(list)
+	if err != nil {
+		return nil, fmt.Errorf("BannedIPs error: %w", err)
+	}
+	return addrs, nil
+}
+
+// SetBannedIPs replaces the manually banned IP addresses with addrs
+func (c *Client) SetBannedIPs(addrs []netip.Addr) error {
+	if err := c.AppSetPreferences(Preferences{"banned_IPs": FormatBannedIPs(addrs)}); err != nil {
+		return fmt.Errorf("SetBannedIPs error: %w", err)
+	}
+	return nil
+}
+
+// BanIPs adds addrs to the manually banned IP addresses
+func (c *Client) BanIPs(addrs ...netip.Addr) error {
+	banned, err := c.BannedIPs()
+	if err != nil {
+		return fmt.Errorf("BanIPs error: %w", err)
+	}
+	if err := c.SetBannedIPs(append(banned, addrs...)); err != nil {
+		return fmt.Errorf("BanIPs error: %w", err)
+	}
+	return nil
+}
+
+// BanPeer disconnects and bans the peer with address ip from all torrents. With persist the address is
+// also added to the manually banned IP addresses, see BanIPs, so the ban survives a restart of qBittorrent.
+func (c *Client) BanPeer(ip string, persist bool) error {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return fmt.Errorf("BanPeer error: invalid IP %q: %v", ip, err)
+	}
+	addr = addr.Unmap()
+	if err := c.TransferBanPeers([]string{netip.AddrPortFrom(addr, 0).String()}); err != nil {
+		return fmt.Errorf("BanPeer error: %w", err)
+	}
+	if persist {
+		if err := c.BanIPs(addr); err != nil {
+			return fmt.Errorf("BanPeer error: %w", err)
+		}
+	}
+	return nil
+}
+
+// UnbanIPs removes addrs from the manually banned IP addresses
+func (c *Client) UnbanIPs(addrs ...netip.Addr) error {
+	banned, err := c.BannedIPs()
+	if err != nil {
+		return fmt.Errorf("UnbanIPs error: %w", err)
+	}
+	remove := make(map[netip.Addr]bool, len(addrs))
+	for _, addr := range addrs {
+		remove[addr.Unmap()] = true
+	}
+	var keep []netip.Addr
+	for _, addr := range banned {
+		if !remove[addr] {
+			keep = append(keep, addr)
+		}
+	}
+	if err := c.SetBannedIPs(keep); err != nil {
+		return fmt.Errorf("UnbanIPs error: %w", err)
+	}
+	return nil
+}
+
+// ParseBannedIPs parses the newline separated banned_IPs preference. Blank lines are skipped and
+// IPv4-mapped IPv6 addresses are converted to IPv4.
+func ParseBannedIPs(list string) ([]netip.Addr, error) {
+	var addrs []netip.Addr
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		addr, err := netip.ParseAddr(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid banned IP %q: %v", line, err)
+		}
+		addrs = append(addrs, addr.Unmap())
+	}
+	return addrs, nil
+}
+
+// FormatBannedIPs formats addrs as the banned_IPs preference, sorted and without duplicates.
+// Invalid (zero) addresses are dropped.
+func FormatBannedIPs(addrs []netip.Addr) string {
+	seen := make(map[netip.Addr]bool, len(addrs))
+	var unique []netip.Addr
+	for _, addr := range addrs {
+		addr = addr.Unmap()
+		if !addr.IsValid() || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		unique = append(unique, addr)
+	}
+	sort.Slice(unique, func(i, j int) bool { return unique[i].Less(unique[j]) })
+
+	lines := make([]string, len(unique))
+	for i, addr := range unique {
+		lines[i] = addr.String()
+	}
+	return strings.Join(lines, "\n")
+}
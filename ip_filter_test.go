@@ -0,0 +1,117 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseBannedIPs(t *testing.T) {
+	addrs, err := ParseBannedIPs("10.0.0.1\n\n ::ffff:192.0.2.7 \r\n2001:db8::1\n")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []netip.Addr{
+		netip.MustParseAddr("10.0.0.1"),
+		netip.MustParseAddr("192.0.2.7"),
+		netip.MustParseAddr("2001:db8::1"),
+	}
+	if !reflect.DeepEqual(addrs, want) {
+		t.Errorf("expected %v, got %v", want, addrs)
+	}
+
+	if _, err := ParseBannedIPs("10.0.0.1\nnot-an-ip"); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
+
+func TestFormatBannedIPs(t *testing.T) {
+	got := FormatBannedIPs([]netip.Addr{
+		netip.MustParseAddr("2001:db8::1"),
+		netip.MustParseAddr("10.0.0.2"),
+		netip.MustParseAddr("::ffff:10.0.0.2"),
+		{},
+		netip.MustParseAddr("10.0.0.1"),
+	})
+	if want := "10.0.0.1\n10.0.0.2\n2001:db8::1"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestClient_BanIPs(t *testing.T) {
+	var posted []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"banned_IPs": "10.0.0.1\n10.0.0.3", "ip_filter_enabled": true}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = append(posted, r.PostForm.Get("json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if enabled, err := client.IPFilterEnabled(); err != nil || !enabled {
+		t.Errorf("expected the filter enabled, got %v, %v", enabled, err)
+	}
+	if err := client.BanIPs(netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.1")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.UnbanIPs(netip.MustParseAddr("10.0.0.1")); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.SetIPFilterPath("/config/ipfilter.dat", false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		`{"banned_IPs":"10.0.0.1\n10.0.0.2\n10.0.0.3"}`,
+		`{"banned_IPs":"10.0.0.3"}`,
+		`{"ip_filter_enabled":true,"ip_filter_path":"/config/ipfilter.dat","ip_filter_trackers":false}`,
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("expected %q, got %q", want, posted)
+	}
+}
+
+func TestClient_BanPeer(t *testing.T) {
+	var banned, posted []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/transfer/banPeers":
+			banned = append(banned, r.PostForm.Get("peers"))
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"banned_IPs": "10.0.0.1"}`))
+		case "/api/v2/app/setPreferences":
+			posted = append(posted, r.PostForm.Get("json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	if err := client.BanPeer("2001:db8::1", false); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.BanPeer(" ::ffff:10.0.0.2", true); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.BanPeer("10.0.0", true); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+
+	if want := []string{"[2001:db8::1]:0", "10.0.0.2:0"}; !reflect.DeepEqual(banned, want) {
+		t.Errorf("expected bans %q, got %q", want, banned)
+	}
+	if want := []string{`{"banned_IPs":"10.0.0.1\n10.0.0.2"}`}; !reflect.DeepEqual(posted, want) {
+		t.Errorf("expected %q, got %q", want, posted)
+	}
+}
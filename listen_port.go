@@ -0,0 +1,74 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// connectionPollInterval is how often WaitForConnection checks the connection status
+const connectionPollInterval = time.Second
+
+// ListenPort retrieves the port qBittorrent listens on for incoming peer connections
+func (c *Client) ListenPort() (int, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return 0, fmt.Errorf("ListenPort error: %w", err)
+	}
+	port, _ := prefs["listen_port"].(float64)
+	return int(port), nil
+}
+
+// SetListenPort changes the port qBittorrent listens on for incoming peer connections and disables the
+// random port chosen on each start, so the port is kept across restarts
+func (c *Client) SetListenPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("SetListenPort error: invalid port %d", port)
+	}
+	if err := c.AppSetPreferences(Preferences{"listen_port": port, "random_port": false}); err != nil {
+		return fmt.Errorf("SetListenPort error: %w", err)
+	}
+	return nil
+}
+
+// WaitForConnection polls the connection status until it is ConnectionConnected or timeout elapsed and
+// returns the last status seen. A firewalled status means incoming connections do not reach qBittorrent,
+// e.g. because the port is not forwarded.
+func (c *Client) WaitForConnection(ctx context.Context, timeout time.Duration) (ConnectionStatus, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := c.ConnectionStatus(ctx)
+		if err != nil {
+			return "", fmt.Errorf("WaitForConnection error: %w", err)
+		}
+		remaining := time.Until(deadline)
+		if status.IsConnected() || remaining <= 0 {
+			return status, nil
+		}
+		if err := sleepContext(ctx, min(connectionPollInterval, remaining)); err != nil {
+			return status, err
+		}
+	}
+}
+
+// ApplyRandomListenPort sets the listen port to a random port between minPort and maxPort, inclusive, and
+// waits up to timeout for qBittorrent to become connectable. The port is returned also when it was applied
+// but the connection status did not become ConnectionConnected, in which case the error wraps ErrNotConnected.
+func (c *Client) ApplyRandomListenPort(ctx context.Context, minPort, maxPort int, timeout time.Duration) (int, error) {
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return 0, fmt.Errorf("ApplyRandomListenPort error: invalid port range %d-%d", minPort, maxPort)
+	}
+	port := minPort + rand.IntN(maxPort-minPort+1)
+	if err := c.SetListenPort(port); err != nil {
+		return 0, fmt.Errorf("ApplyRandomListenPort error: %w", err)
+	}
+	status, err := c.WaitForConnection(ctx, timeout)
+	if err != nil {
+		return port, fmt.Errorf("ApplyRandomListenPort error: %w", err)
+	}
+	if !status.IsConnected() {
+		return port, fmt.Errorf("ApplyRandomListenPort error: %w: status %s on port %d", ErrNotConnected, status, port)
+	}
+	return port, nil
+}
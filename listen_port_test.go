@@ -0,0 +1,54 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_ListenPort(t *testing.T) {
+	var posted string
+	status := "connected"
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"listen_port":6881,"random_port":true}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		case "/api/v2/transfer/info":
+			w.Write([]byte(`{"connection_status":"` + status + `"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	if port, err := client.ListenPort(); err != nil || port != 6881 {
+		t.Errorf("expected port 6881, got %d, %v", port, err)
+	}
+	if err := client.SetListenPort(70000); err == nil {
+		t.Error("expected an error for an invalid port")
+	}
+
+	port, err := client.ApplyRandomListenPort(context.Background(), 50000, 50000, time.Second)
+	if err != nil || port != 50000 {
+		t.Fatalf("expected port 50000, got %d, %v", port, err)
+	}
+	if want := `{"listen_port":50000,"random_port":false}`; posted != want {
+		t.Errorf("expected %s, got %s", want, posted)
+	}
+
+	status = "firewalled"
+	port, err = client.ApplyRandomListenPort(context.Background(), 40000, 40000, 10*time.Millisecond)
+	if !errors.Is(err, ErrNotConnected) || port != 40000 {
+		t.Errorf("expected ErrNotConnected for port 40000, got %d, %v", port, err)
+	}
+	if _, err := client.ApplyRandomListenPort(context.Background(), 2, 1, time.Second); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+}
@@ -0,0 +1,79 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// benchMainData builds a sync/maindata response with n torrents. A full update carries every field of every
+// torrent, a partial one only the fields that change between polls.
+func benchMainData(b *testing.B, n int, full bool) []byte {
+	b.Helper()
+	torrents := make(map[string]map[string]any, n)
+	for i := 0; i < n; i++ {
+		hash := fmt.Sprintf("%040x", i)
+		if !full {
+			torrents[hash] = map[string]any{"dlspeed": i * 10, "upspeed": i, "eta": 3600 + i, "last_activity": 1700000000 + i}
+			continue
+		}
+		torrents[hash] = map[string]any{
+			"added_on": 1700000000, "amount_left": 0, "auto_tmm": true, "availability": -1,
+			"category": "movies", "completed": 1 << 30, "completion_on": 1700003600,
+			"content_path": "/data/movies/Some.Movie." + hash, "dl_limit": -1, "dlspeed": 0,
+			"downloaded": 1 << 30, "downloaded_session": 0, "eta": 8640000, "f_l_piece_prio": false,
+			"force_start": false, "last_activity": 1700007200, "magnet_uri": "magnet:?xt=urn:btih:" + hash,
+			"max_ratio": -1, "max_seeding_time": -1, "name": "Some.Movie." + hash, "num_complete": 12,
+			"num_incomplete": 1, "num_leechs": 0, "num_seeds": 0, "priority": 0, "progress": 1,
+			"ratio": 1.5, "ratio_limit": -2, "save_path": "/data/movies", "seeding_time": 86400,
+			"seeding_time_limit": -2, "seen_complete": 1700007200, "seq_dl": false, "size": 1 << 30,
+			"state": "stalledUP", "super_seeding": false, "tags": "hd, x265", "time_active": 90000,
+			"total_size": 1 << 30, "tracker": "https://tracker.example/announce", "up_limit": -1,
+			"uploaded": 3 << 29, "uploaded_session": 0, "upspeed": 0,
+			"infohash_v1": hash, "infohash_v2": "", "download_path": "", "reannounce": 1200,
+		}
+	}
+	data, err := json.Marshal(map[string]any{"rid": 2, "full_update": full, "torrents": torrents,
+		"server_state": map[string]any{"dl_info_speed": 1024, "up_info_speed": 2048, "connection_status": "connected"}})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkSyncMainDataDecode(b *testing.B) {
+	data := benchMainData(b, 5000, true)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var result MainData
+		if err := decodeJSON(data, &result, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSyncStateApply(b *testing.B) {
+	for _, full := range []bool{true, false} {
+		name := "partial"
+		if full {
+			name = "full"
+		}
+		b.Run(name, func(b *testing.B) {
+			base := benchMainData(b, 5000, true)
+			data := benchMainData(b, 5000, full)
+			var state SyncState
+			if _, err := state.apply(base, StatesAsReported, false); err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := state.apply(data, StatesAsReported, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
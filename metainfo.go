@@ -0,0 +1,239 @@
+package qbittorrent
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+)
+
+// Metainfo is the subset of a .torrent file the client needs to reason about a torrent before adding it
+type Metainfo struct {
+	InfoHash    InfoHash       // hash qBittorrent identifies the torrent by, hex encoded
+	InfoHashV2  string         // v2 infohash of v2 and hybrid torrents, hex encoded, empty for v1 torrents
+	Name        string         // suggested name of the file or root directory
+	PieceLength int64          // bytes per piece
+	Files       []MetainfoFile // files in the torrent, in metainfo order
+	Size        int64          // total size of all files
+	Private     bool
+}
+
+// MetainfoFile is a single file entry of a Metainfo
+type MetainfoFile struct {
+	Path   string // slash separated, relative to the torrent root directory
+	Length int64
+}
+
+// ParseMetainfo decodes the bencoded contents of a .torrent file
+func ParseMetainfo(data []byte) (*Metainfo, error) {
+	d := &bdecoder{data: data}
+	root, err := d.decode()
+	if err != nil {
+		return nil, fmt.Errorf("ParseMetainfo error: %w", err)
+	}
+	top, ok := root.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("ParseMetainfo error: metainfo is not a dictionary")
+	}
+	info, ok := top["info"].(map[string]interface{})
+	if !ok || d.infoEnd == 0 {
+		return nil, errors.New("ParseMetainfo error: missing info dictionary")
+	}
+
+	// qBittorrent identifies v1 and hybrid torrents by their v1 infohash and v2 only torrents by their v2
+	// infohash truncated to the length of a v1 one
+	m := &Metainfo{}
+	raw := data[d.infoStart:d.infoEnd]
+	switch version, _ := info["meta version"].(int64); version {
+	case 0, 1:
+		sum := sha1.Sum(raw)
+		m.InfoHash = InfoHash(hex.EncodeToString(sum[:]))
+	case 2:
+		sum := sha256.Sum256(raw)
+		m.InfoHashV2 = hex.EncodeToString(sum[:])
+		if _, hybrid := info["pieces"]; hybrid {
+			v1 := sha1.Sum(raw)
+			m.InfoHash = InfoHash(hex.EncodeToString(v1[:]))
+		} else {
+			m.InfoHash = InfoHash(m.InfoHashV2[:2*sha1.Size])
+		}
+	default:
+		return nil, fmt.Errorf("ParseMetainfo error: unsupported meta version %d", version)
+	}
+	m.Name, _ = info["name"].(string)
+	m.PieceLength, _ = info["piece length"].(int64)
+	if private, ok := info["private"].(int64); ok && private == 1 {
+		m.Private = true
+	}
+
+	switch {
+	case info["length"] != nil:
+		length, _ := info["length"].(int64)
+		m.Files = []MetainfoFile{{Path: m.Name, Length: length}}
+	case info["files"] != nil:
+		files, _ := info["files"].([]interface{})
+		for _, f := range files {
+			entry, ok := f.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("ParseMetainfo error: malformed files list")
+			}
+			length, _ := entry["length"].(int64)
+			segments, _ := entry["path"].([]interface{})
+			parts := []string{m.Name}
+			for _, s := range segments {
+				segment, _ := s.(string)
+				parts = append(parts, segment)
+			}
+			m.Files = append(m.Files, MetainfoFile{Path: path.Join(parts...), Length: length})
+		}
+	case info["file tree"] != nil:
+		tree, _ := info["file tree"].(map[string]interface{})
+		m.Files = walkFileTree(m.Name, tree)
+	default:
+		return nil, errors.New("ParseMetainfo error: info dictionary has no files")
+	}
+
+	for _, f := range m.Files {
+		m.Size += f.Length
+	}
+	return m, nil
+}
+
+// walkFileTree flattens a BitTorrent v2 "file tree" dictionary
+func walkFileTree(prefix string, tree map[string]interface{}) []MetainfoFile {
+	names := make([]string, 0, len(tree))
+	for name := range tree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var files []MetainfoFile
+	for _, name := range names {
+		node, ok := tree[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		// A file is represented by an entry with an empty key holding its properties
+		if name == "" {
+			length, _ := node["length"].(int64)
+			files = append(files, MetainfoFile{Path: prefix, Length: length})
+			continue
+		}
+		files = append(files, walkFileTree(path.Join(prefix, name), node)...)
+	}
+	return files
+}
+
+// maxBencodeDepth bounds the nesting of lists and dictionaries, so crafted input cannot exhaust the stack
+const maxBencodeDepth = 64
+
+// bdecoder is a minimal bencode decoder that also records the byte range of the top-level info dictionary
+type bdecoder struct {
+	data      []byte
+	pos       int
+	depth     int
+	infoStart int
+	infoEnd   int
+}
+
+func (d *bdecoder) decode() (interface{}, error) {
+	if d.pos >= len(d.data) {
+		return nil, errors.New("unexpected end of data")
+	}
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		d.pos++
+		end := d.indexFrom('e')
+		if end < 0 {
+			return nil, errors.New("unterminated integer")
+		}
+		n, err := strconv.ParseInt(string(d.data[d.pos:end]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer: %v", err)
+		}
+		d.pos = end + 1
+		return n, nil
+	case c == 'l':
+		if d.depth >= maxBencodeDepth {
+			return nil, errors.New("nesting too deep")
+		}
+		d.pos++
+		d.depth++
+		list := []interface{}{}
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		if d.pos >= len(d.data) {
+			return nil, errors.New("unterminated list")
+		}
+		d.pos++
+		d.depth--
+		return list, nil
+	case c == 'd':
+		if d.depth >= maxBencodeDepth {
+			return nil, errors.New("nesting too deep")
+		}
+		d.pos++
+		d.depth++
+		dict := map[string]interface{}{}
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			key, err := d.decodeString()
+			if err != nil {
+				return nil, err
+			}
+			start := d.pos
+			v, err := d.decode()
+			if err != nil {
+				return nil, err
+			}
+			if d.depth == 1 && key == "info" {
+				d.infoStart, d.infoEnd = start, d.pos
+			}
+			dict[key] = v
+		}
+		if d.pos >= len(d.data) {
+			return nil, errors.New("unterminated dictionary")
+		}
+		d.pos++
+		d.depth--
+		return dict, nil
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+	default:
+		return nil, fmt.Errorf("invalid bencode type %q at offset %d", c, d.pos)
+	}
+}
+
+func (d *bdecoder) decodeString() (string, error) {
+	colon := d.indexFrom(':')
+	if colon < 0 {
+		return "", errors.New("malformed string length")
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil || n < 0 {
+		return "", fmt.Errorf("invalid string length at offset %d", d.pos)
+	}
+	start := colon + 1
+	if n > len(d.data)-start {
+		return "", errors.New("string exceeds data")
+	}
+	d.pos = start + n
+	return string(d.data[start:d.pos]), nil
+}
+
+func (d *bdecoder) indexFrom(b byte) int {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == b {
+			return i
+		}
+	}
+	return -1
+}
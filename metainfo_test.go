@@ -0,0 +1,116 @@
+package qbittorrent
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParseMetainfo(t *testing.T) {
+	data := makeTorrent("album", map[string]int64{
+		"cd1/01.flac": 100,
+		"cover.jpg":   20,
+	})
+
+	meta, err := ParseMetainfo(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if meta.Name != "album" {
+		t.Errorf("expected name 'album', got '%s'", meta.Name)
+	}
+	if meta.Size != 120 {
+		t.Errorf("expected size 120, got %d", meta.Size)
+	}
+	if meta.PieceLength != 16384 {
+		t.Errorf("expected piece length 16384, got %d", meta.PieceLength)
+	}
+	if len(meta.Files) != 2 || meta.Files[0].Path != "album/cd1/01.flac" || meta.Files[1].Path != "album/cover.jpg" {
+		t.Errorf("unexpected files %v", meta.Files)
+	}
+
+	// The infohash is the SHA-1 of the raw info dictionary
+	info := bencode(map[string]interface{}{
+		"name":         "album",
+		"piece length": 16384,
+		"pieces":       "",
+		"files": []interface{}{
+			map[string]interface{}{"length": 100, "path": []interface{}{"cd1", "01.flac"}},
+			map[string]interface{}{"length": 20, "path": []interface{}{"cover.jpg"}},
+		},
+	})
+	sum := sha1.Sum(info)
+	if want := InfoHash(hex.EncodeToString(sum[:])); meta.InfoHash != want {
+		t.Errorf("expected infohash %s, got %s", want, meta.InfoHash)
+	}
+}
+
+func TestParseMetainfo_SingleFile(t *testing.T) {
+	data := bencode(map[string]interface{}{
+		"info": map[string]interface{}{
+			"name":    "movie.mkv",
+			"length":  4096,
+			"private": 1,
+		},
+	})
+
+	meta, err := ParseMetainfo(data)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if meta.Size != 4096 || len(meta.Files) != 1 || meta.Files[0].Path != "movie.mkv" {
+		t.Errorf("unexpected metainfo %+v", meta)
+	}
+	if !meta.Private {
+		t.Errorf("expected private torrent")
+	}
+}
+
+func TestParseMetainfo_Invalid(t *testing.T) {
+	invalid := []string{
+		"", "garbage", "d4:infoi1ee", "d3:foo3:bare", "l1:ae",
+		"d4:info9223372036854775807:abce",
+		"d4:infod4:name1:a6:lengthi1e12:meta versioni3eee",
+		strings.Repeat("l", 10000) + strings.Repeat("e", 10000),
+	}
+	for _, data := range invalid {
+		if _, err := ParseMetainfo([]byte(data)); err == nil {
+			t.Errorf("expected error for %q, got none", data)
+		}
+	}
+}
+
+func TestParseMetainfo_V2(t *testing.T) {
+	tree := map[string]interface{}{
+		"movie.mkv": map[string]interface{}{"": map[string]interface{}{"length": 4096}},
+	}
+	v2 := map[string]interface{}{"name": "movie", "meta version": 2, "piece length": 16384, "file tree": tree}
+	hybrid := map[string]interface{}{"name": "movie", "meta version": 2, "piece length": 16384, "file tree": tree,
+		"length": 4096, "pieces": ""}
+
+	// v2 only torrents are identified by their truncated v2 infohash
+	meta, err := ParseMetainfo(bencode(map[string]interface{}{"info": v2}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	sum := sha256.Sum256(bencode(v2))
+	if want := hex.EncodeToString(sum[:]); meta.InfoHashV2 != want || string(meta.InfoHash) != want[:40] {
+		t.Errorf("expected infohashes %s and %s, got %s and %s", want[:40], want, meta.InfoHash, meta.InfoHashV2)
+	}
+	if meta.Size != 4096 || len(meta.Files) != 1 || meta.Files[0].Path != "movie/movie.mkv" {
+		t.Errorf("unexpected metainfo %+v", meta)
+	}
+
+	// Hybrid torrents keep their v1 infohash
+	meta, err = ParseMetainfo(bencode(map[string]interface{}{"info": hybrid}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	v1 := sha1.Sum(bencode(hybrid))
+	if want := InfoHash(hex.EncodeToString(v1[:])); meta.InfoHash != want || meta.InfoHashV2 == "" {
+		t.Errorf("expected infohash %s and a v2 infohash, got %+v", want, meta)
+	}
+}
@@ -0,0 +1,142 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MigrateOptions holds the optional parameters for Migrate
+type MigrateOptions struct {
+	Hashes       []string // torrents to migrate, all if empty
+	StartPaused  bool     // add the torrents paused on the destination
+	SkipChecking bool     // skip the hash check, for data that is already in place on the destination
+
+	// MapSavePath, if set, translates a source save path to the destination, e.g. for different mount points
+	MapSavePath func(savePath string) string
+	// OnProgress, if set, is called after each torrent was migrated or failed
+	OnProgress func(result MigrateResult)
+}
+
+// MigrateResult is the outcome of migrating one torrent
+type MigrateResult struct {
+	Hash    InfoHash
+	Name    string
+	Skipped bool  // the torrent already existed on the destination
+	Err     error // nil on success
+}
+
+// Migrate copies torrents from src to dst: the .torrent files are exported from src and added to dst with the
+// same save path, category and tags. Torrents already on dst are skipped. Missing categories are created first.
+// Failures of individual torrents are reported in the results; the returned error is only set when the
+// migration could not run at all.
+func Migrate(ctx context.Context, src, dst *Client, opts *MigrateOptions) ([]MigrateResult, error) {
+	options := MigrateOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	mapPath := options.MapSavePath
+	if mapPath == nil {
+		mapPath = func(savePath string) string { return savePath }
+	}
+
+	var params *TorrentsInfoParams
+	if len(options.Hashes) > 0 {
+		params = &TorrentsInfoParams{Hashes: options.Hashes}
+	}
+	torrents, err := src.TorrentsInfo(params)
+	if err != nil {
+		return nil, fmt.Errorf("Migrate error: source: %w", err)
+	}
+	existing, err := dst.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("Migrate error: destination: %w", err)
+	}
+	present := make(map[InfoHash]struct{}, len(existing))
+	for _, torrent := range existing {
+		present[torrent.Hash] = struct{}{}
+	}
+
+	if err := migrateCategories(src, dst, mapPath); err != nil {
+		return nil, fmt.Errorf("Migrate error: %w", err)
+	}
+
+	sort.Slice(torrents, func(i, j int) bool { return torrents[i].Hash < torrents[j].Hash })
+	results := make([]MigrateResult, 0, len(torrents))
+	for _, torrent := range torrents {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		result := MigrateResult{Hash: torrent.Hash, Name: torrent.Name}
+		if _, ok := present[torrent.Hash]; ok {
+			result.Skipped = true
+		} else {
+			result.Err = migrateTorrent(src, dst, torrent, options, mapPath)
+		}
+
+		results = append(results, result)
+		if options.OnProgress != nil {
+			options.OnProgress(result)
+		}
+	}
+	return results, nil
+}
+
+// migrateCategories creates the categories of src that are missing on dst
+func migrateCategories(src, dst *Client, mapPath func(string) string) error {
+	want, err := src.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+	have, err := dst.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("destination: %w", err)
+	}
+
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := have[name]; ok {
+			continue
+		}
+		savePath, _ := want[name]["savePath"].(string)
+		if savePath != "" {
+			savePath = mapPath(savePath)
+		}
+		if err := dst.TorrentsCreateCategory(name, savePath); err != nil {
+			return fmt.Errorf("destination: %w", err)
+		}
+	}
+	return nil
+}
+
+// migrateTorrent exports a single torrent from src and adds it to dst
+func migrateTorrent(src, dst *Client, torrent TorrentInfo, options MigrateOptions, mapPath func(string) string) error {
+	data, err := src.TorrentsExport(string(torrent.Hash))
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+
+	tags := snapshotTorrent(torrent).Tags
+	addOpts := []TorrentAddOption{
+		WithSavePath(mapPath(torrent.SavePath)),
+		WithAutoTMM(false),
+		WithStartPaused(options.StartPaused),
+		WithSkipChecking(options.SkipChecking),
+	}
+	if torrent.Category != "" {
+		addOpts = append(addOpts, WithCategory(torrent.Category))
+	}
+	if len(tags) > 0 {
+		addOpts = append(addOpts, WithTags(tags))
+	}
+
+	if err := dst.TorrentsAddWithOptions(string(torrent.Hash)+".torrent", data, addOpts...); err != nil {
+		return fmt.Errorf("add: %w", err)
+	}
+	return nil
+}
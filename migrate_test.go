@@ -0,0 +1,93 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"ccc","name":"C","save_path":"/mnt/a/tv","category":"tv","tags":"hd, new"},
+				{"hash":"aaa","name":"A","save_path":"/mnt/a"},
+				{"hash":"bbb","name":"B","save_path":"/mnt/a"}
+			]`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/mnt/a/tv"},"movies":{"name":"movies","savePath":""}}`))
+		case "/api/v2/torrents/export":
+			r.ParseForm()
+			if r.Form.Get("hash") == "bbb" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("data " + r.Form.Get("hash")))
+		}
+	}))
+	defer source.Close()
+
+	var created []string
+	added := map[string]map[string]string{}
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"aaa"}]`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"movies":{"name":"movies","savePath":""}}`))
+		case "/api/v2/torrents/createCategory":
+			r.ParseForm()
+			created = append(created, r.PostForm.Get("category")+"="+r.PostForm.Get("savePath"))
+		case "/api/v2/torrents/add":
+			r.ParseMultipartForm(1 << 20)
+			fields := map[string]string{}
+			for _, field := range []string{"savepath", "category", "tags", "paused", "skip_checking"} {
+				fields[field] = r.FormValue(field)
+			}
+			added[r.MultipartForm.File["torrents"][0].Filename] = fields
+		}
+	}))
+	defer target.Close()
+
+	src := &Client{baseURL: source.URL, client: source.Client()}
+	dst := &Client{baseURL: target.URL, client: target.Client()}
+
+	var progress int
+	results, err := Migrate(context.Background(), src, dst, &MigrateOptions{
+		StartPaused:  true,
+		SkipChecking: true,
+		MapSavePath: func(savePath string) string {
+			return "/data" + savePath[len("/mnt/a"):]
+		},
+		OnProgress: func(result MigrateResult) { progress++ },
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(results) != 3 || progress != 3 {
+		t.Fatalf("expected 3 results, got %v", results)
+	}
+	if !results[0].Skipped || results[0].Hash != "aaa" {
+		t.Errorf("expected aaa to be skipped, got %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, ErrNotFound) {
+		t.Errorf("expected bbb to fail with ErrNotFound, got %+v", results[1])
+	}
+	if results[2].Err != nil {
+		t.Errorf("expected ccc to succeed, got %v", results[2].Err)
+	}
+
+	if len(created) != 1 || created[0] != "tv=/data/tv" {
+		t.Errorf("expected category tv=/data/tv to be created, got %v", created)
+	}
+	want := map[string]string{"savepath": "/data/tv", "category": "tv", "tags": "hd,new", "paused": "true", "skip_checking": "true"}
+	for field, value := range want {
+		if added["ccc.torrent"][field] != value {
+			t.Errorf("expected %s=%s, got %s", field, value, added["ccc.torrent"][field])
+		}
+	}
+}
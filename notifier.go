@@ -0,0 +1,298 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// NotificationKind identifies what a Notification is about
+type NotificationKind string
+
+const (
+	NotifyCompleted     NotificationKind = "completed"      // a torrent finished downloading
+	NotifyErrored       NotificationKind = "errored"        // a torrent entered the error or missingFiles state
+	NotifyTrackerFailed NotificationKind = "tracker_failed" // a tracker of a torrent stopped working
+)
+
+// Notification is sent to webhooks and the callback of a Notifier
+type Notification struct {
+	Kind    NotificationKind `json:"kind"`
+	Hash    InfoHash         `json:"hash"`
+	Name    string           `json:"name"`
+	Message string           `json:"message,omitempty"` // the error state or the tracker message
+	Tracker string           `json:"tracker,omitempty"` // the failing tracker for NotifyTrackerFailed
+	Time    time.Time        `json:"time"`
+	Torrent TorrentInfo      `json:"torrent"`
+}
+
+// Summary returns a one line human readable description, as used by the chat templates
+func (n Notification) Summary() string {
+	switch n.Kind {
+	case NotifyCompleted:
+		return fmt.Sprintf("Completed: %s", n.Name)
+	case NotifyErrored:
+		return fmt.Sprintf("Errored: %s (%s)", n.Name, n.Message)
+	case NotifyTrackerFailed:
+		return fmt.Sprintf("Tracker failed: %s (%s: %s)", n.Name, trackerHost(n.Tracker), n.Message)
+	}
+	return fmt.Sprintf("%s: %s", n.Kind, n.Name)
+}
+
+// NewWebhookTemplate parses a webhook body template. The template is executed with a Notification and
+// can use the "json" function to encode a value, e.g. {"text": {{json .Summary}}}.
+func NewWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(text)
+}
+
+// Webhook body templates for chat services
+var (
+	DiscordTemplate = template.Must(NewWebhookTemplate(`{"content": {{json .Summary}}}`))
+	SlackTemplate   = template.Must(NewWebhookTemplate(`{"text": {{json .Summary}}}`))
+)
+
+// Webhook is a URL notifications are POSTed to
+type Webhook struct {
+	URL string
+	// Template renders the request body, the Notification encoded as JSON if nil
+	Template *template.Template
+	// ContentType of the body, default "application/json"
+	ContentType string
+}
+
+// NotifierConfig configures a Notifier. Zero values select the defaults.
+type NotifierConfig struct {
+	Webhooks []Webhook
+	// Callback, if set, is called for every notification in addition to the webhooks
+	Callback func(ctx context.Context, n Notification) error
+	// Kinds restricts the notifications sent, all kinds if empty
+	Kinds []NotificationKind
+
+	HTTPClient *http.Client // used for webhooks, default http.DefaultClient
+	// Interval is how often Run polls for torrent changes. By default it follows the server's refresh
+	// interval and backs off while the server is busy, see SyncWatcherConfig.
+	Interval        time.Duration
+	TrackerInterval time.Duration // how often Run checks trackers, default 5m
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// Notifier sends notifications on completions, torrent errors and tracker failures to webhooks or a callback
+type Notifier struct {
+	client TorrentsAPI
+	cfg    NotifierConfig
+	now    func() time.Time
+
+	mu      sync.Mutex                       // guards failing, used by HandleEvent and CheckTrackers
+	failing map[InfoHash]map[string]struct{} // trackers already notified as failing, per torrent
+}
+
+// NewNotifier creates a Notifier for the given client. Run polls with a SyncWatcher, so it requires a *Client;
+// with another TorrentsAPI, feed HandleEvent from a SyncWatcher and call CheckTrackers instead.
+func NewNotifier(client TorrentsAPI, cfg NotifierConfig) *Notifier {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = http.DefaultClient
+	}
+	if cfg.TrackerInterval <= 0 {
+		cfg.TrackerInterval = 5 * time.Minute
+	}
+	return &Notifier{
+		client:  client,
+		cfg:     cfg,
+		now:     time.Now,
+		failing: make(map[InfoHash]map[string]struct{}),
+	}
+}
+
+// Run watches torrents and trackers until ctx is cancelled
+func (n *Notifier) Run(ctx context.Context) error {
+	client, ok := n.client.(*Client)
+	if !ok {
+		return errors.New("Notifier.Run error: polling sync/maindata requires a *Client")
+	}
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{
+		Interval: n.cfg.Interval,
+		OnEvent: func(event SyncEvent) {
+			n.report(n.HandleEvent(ctx, event))
+		},
+	})
+	events := time.NewTimer(0)
+	defer events.Stop()
+	trackers := time.NewTicker(n.cfg.TrackerInterval)
+	defer trackers.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-events.C:
+			if _, err := watcher.Poll(ctx); err != nil && ctx.Err() == nil {
+				n.report(err)
+			}
+			events.Reset(watcher.NextInterval())
+		case <-trackers.C:
+			n.report(n.CheckTrackers(ctx))
+		}
+	}
+}
+
+func (n *Notifier) report(err error) {
+	if err != nil && n.cfg.OnError != nil {
+		n.cfg.OnError(err)
+	}
+}
+
+// HandleEvent sends a notification if event is a completion or a torrent entering an error state,
+// so the notifier can share a SyncWatcher
+func (n *Notifier) HandleEvent(ctx context.Context, event SyncEvent) error {
+	switch {
+	case event.Type == EventTorrentCompleted:
+		return n.Notify(ctx, n.notification(NotifyCompleted, event.Torrent))
+	case event.Type == EventTorrentStateChanged && isErrorState(event.Torrent.State) && !isErrorState(event.Previous.State):
+		notification := n.notification(NotifyErrored, event.Torrent)
+		notification.Message = event.Torrent.State
+		return n.Notify(ctx, notification)
+	case event.Type == EventTorrentRemoved:
+		n.mu.Lock()
+		delete(n.failing, event.Hash)
+		n.mu.Unlock()
+	}
+	return nil
+}
+
+func isErrorState(state string) bool {
+	return state == "error" || state == "missingFiles"
+}
+
+func (n *Notifier) notification(kind NotificationKind, torrent TorrentInfo) Notification {
+	return Notification{Kind: kind, Hash: torrent.Hash, Name: torrent.Name, Time: n.now(), Torrent: torrent}
+}
+
+// CheckTrackers fetches the trackers of all torrents and notifies about trackers that stopped working
+// since the previous check. A tracker is notified again only after it recovered. Torrents whose trackers
+// cannot be fetched keep the trackers notified before.
+func (n *Notifier) CheckTrackers(ctx context.Context) error {
+	torrents, err := n.client.TorrentsInfo()
+	if err != nil {
+		return fmt.Errorf("Notifier error: %w", err)
+	}
+
+	n.mu.Lock()
+	notified := maps.Clone(n.failing) // HandleEvent may delete from n.failing meanwhile
+	n.mu.Unlock()
+
+	var errs []error
+	failing := make(map[InfoHash]map[string]struct{})
+	for _, torrent := range torrents {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		trackers, err := n.client.TorrentsTrackers(string(torrent.Hash))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Notifier error: %w", err))
+			if previous := notified[torrent.Hash]; previous != nil {
+				failing[torrent.Hash] = previous
+			}
+			continue
+		}
+		for _, tracker := range trackers {
+			if isPseudoTracker(tracker.URL) || tracker.Status != TrackerNotWorking {
+				continue
+			}
+			if failing[torrent.Hash] == nil {
+				failing[torrent.Hash] = make(map[string]struct{})
+			}
+			failing[torrent.Hash][tracker.URL] = struct{}{}
+			if _, ok := notified[torrent.Hash][tracker.URL]; ok {
+				continue
+			}
+
+			notification := n.notification(NotifyTrackerFailed, torrent)
+			notification.Tracker = tracker.URL
+			notification.Message = tracker.Msg
+			if err := n.Notify(ctx, notification); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	n.mu.Lock()
+	n.failing = failing
+	n.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// Notify sends a notification to the callback and every webhook, unless its kind is filtered out
+func (n *Notifier) Notify(ctx context.Context, notification Notification) error {
+	if !n.wants(notification.Kind) {
+		return nil
+	}
+
+	var errs []error
+	if n.cfg.Callback != nil {
+		if err := n.cfg.Callback(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("Notifier error: %w", err))
+		}
+	}
+	for _, hook := range n.cfg.Webhooks {
+		if err := n.post(ctx, hook, notification); err != nil {
+			errs = append(errs, fmt.Errorf("Notifier error: %s: %v", Redact(hook.URL), Redact(err.Error())))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) wants(kind NotificationKind) bool {
+	if len(n.cfg.Kinds) == 0 {
+		return true
+	}
+	for _, k := range n.cfg.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *Notifier) post(ctx context.Context, hook Webhook, notification Notification) error {
+	var body bytes.Buffer
+	if hook.Template != nil {
+		if err := hook.Template.Execute(&body, notification); err != nil {
+			return err
+		}
+	} else if err := json.NewEncoder(&body).Encode(notification); err != nil {
+		return err
+	}
+	contentType := hook.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := n.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNotifier(t *testing.T) {
+	var slack []string
+	var generic []Notification
+	hooks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected application/json, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Path == "/slack" {
+			slack = append(slack, string(body))
+			return
+		}
+		var n Notification
+		if err := json.Unmarshal(body, &n); err != nil {
+			t.Errorf("expected a JSON notification, got %s", body)
+		}
+		generic = append(generic, n)
+	}))
+	defer hooks.Close()
+
+	trackerStatus := TrackerNotWorking
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"abc","name":"Example"}]`))
+		case "/api/v2/torrents/trackers":
+			json.NewEncoder(w).Encode([]TrackerInfo{
+				{URL: "** [DHT] **", Status: TrackerNotWorking},
+				{URL: "https://tracker.example.org/announce", Status: trackerStatus, Msg: "Torrent not registered"},
+			})
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	var callbacks []NotificationKind
+	notifier := NewNotifier(client, NotifierConfig{
+		Webhooks: []Webhook{
+			{URL: hooks.URL + "/slack", Template: SlackTemplate},
+			{URL: hooks.URL + "/generic"},
+		},
+		Callback: func(ctx context.Context, n Notification) error {
+			callbacks = append(callbacks, n.Kind)
+			return nil
+		},
+	})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	notifier.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	events := []SyncEvent{
+		{Type: EventTorrentAdded, Hash: "abc", Torrent: TorrentInfo{Hash: "abc", Name: "Example"}},
+		{Type: EventTorrentCompleted, Hash: "abc", Torrent: TorrentInfo{Hash: "abc", Name: "Example", State: "uploading"}},
+		{Type: EventTorrentStateChanged, Hash: "abc", Torrent: TorrentInfo{Hash: "abc", Name: "Example", State: "missingFiles"}, Previous: TorrentInfo{State: "uploading"}},
+		// still errored, not notified again
+		{Type: EventTorrentStateChanged, Hash: "abc", Torrent: TorrentInfo{Hash: "abc", Name: "Example", State: "error"}, Previous: TorrentInfo{State: "missingFiles"}},
+	}
+	for _, event := range events {
+		if err := notifier.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	// A failing tracker is notified once, and again only after it recovered
+	for _, status := range []TrackerStatus{TrackerNotWorking, TrackerNotWorking, TrackerWorking, TrackerNotWorking} {
+		trackerStatus = status
+		if err := notifier.CheckTrackers(ctx); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	wantSlack := []string{
+		`{"text": "Completed: Example"}`,
+		`{"text": "Errored: Example (missingFiles)"}`,
+		`{"text": "Tracker failed: Example (tracker.example.org: Torrent not registered)"}`,
+		`{"text": "Tracker failed: Example (tracker.example.org: Torrent not registered)"}`,
+	}
+	if !reflect.DeepEqual(slack, wantSlack) {
+		t.Errorf("expected slack bodies %q, got %q", wantSlack, slack)
+	}
+	wantCallbacks := []NotificationKind{NotifyCompleted, NotifyErrored, NotifyTrackerFailed, NotifyTrackerFailed}
+	if !reflect.DeepEqual(callbacks, wantCallbacks) {
+		t.Errorf("expected callbacks %v, got %v", wantCallbacks, callbacks)
+	}
+	if len(generic) != 4 || generic[2].Tracker != "https://tracker.example.org/announce" || generic[2].Hash != "abc" || !generic[2].Time.Equal(now) {
+		t.Errorf("unexpected generic notifications %+v", generic)
+	}
+}
+
+func TestNotifier_Kinds(t *testing.T) {
+	var sent []NotificationKind
+	notifier := NewNotifier(nil, NotifierConfig{
+		Kinds: []NotificationKind{NotifyErrored},
+		Callback: func(ctx context.Context, n Notification) error {
+			sent = append(sent, n.Kind)
+			return nil
+		},
+	})
+
+	ctx := context.Background()
+	notifier.Notify(ctx, Notification{Kind: NotifyCompleted})
+	notifier.Notify(ctx, Notification{Kind: NotifyErrored})
+	if !reflect.DeepEqual(sent, []NotificationKind{NotifyErrored}) {
+		t.Errorf("expected only errored notifications, got %v", sent)
+	}
+}
+
+func TestNotifier_TrackerFetchErrors(t *testing.T) {
+	fail := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"abc","name":"Example"}]`))
+		case "/api/v2/torrents/trackers":
+			if fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			json.NewEncoder(w).Encode([]TrackerInfo{{URL: "https://tracker.example.org/announce", Status: TrackerNotWorking}})
+		}
+	}))
+	defer mockServer.Close()
+
+	var notified int
+	notifier := NewNotifier(&Client{baseURL: mockServer.URL, client: mockServer.Client()}, NotifierConfig{
+		Callback: func(ctx context.Context, n Notification) error {
+			notified++
+			return nil
+		},
+	})
+
+	// The failed fetch keeps the tracker notified before, so it is not notified again
+	ctx := context.Background()
+	for _, fail = range []bool{false, true, false} {
+		err := notifier.CheckTrackers(ctx)
+		if fail != (err != nil) {
+			t.Errorf("expected an error only for the failed fetch, got %v", err)
+		}
+	}
+	if notified != 1 {
+		t.Errorf("expected 1 notification, got %d", notified)
+	}
+
+	// HandleEvent may run on a SyncWatcher goroutine while trackers are checked
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			notifier.HandleEvent(ctx, SyncEvent{Type: EventTorrentRemoved, Hash: "abc"})
+		}
+	}()
+	for i := 0; i < 10; i++ {
+		notifier.CheckTrackers(ctx)
+	}
+	wg.Wait()
+}
+
+func TestNotifier_RunFollowsRefreshInterval(t *testing.T) {
+	var mu sync.Mutex
+	var polls int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		polls++
+		mu.Unlock()
+		w.Write([]byte(`{"rid":1,"full_update":true,"server_state":{"refresh_interval":500}}`))
+	}))
+	defer mockServer.Close()
+
+	// The server asks for 500ms, much shorter than the 2s used before it answered
+	notifier := NewNotifier(&Client{baseURL: mockServer.URL, client: mockServer.Client()}, NotifierConfig{})
+	ctx, cancel := context.WithTimeout(context.Background(), 1200*time.Millisecond)
+	defer cancel()
+	notifier.Run(ctx)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if polls < 2 {
+		t.Errorf("expected polls every 500ms, got %d polls in 1.2s", polls)
+	}
+}
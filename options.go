@@ -0,0 +1,82 @@
+package qbittorrent
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures optional behaviour of a Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the http.Client used for requests.
+// If httpClient is nil, the default client with a transport from NewTransport is kept.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		if httpClient != nil {
+			c.client = httpClient
+		}
+	}
+}
+
+// WithBaseURL sets the URL of the WebUI, replacing the one built from the address and port given to
+// NewClientWithOptions. Use it for https or for a WebUI served under a subpath behind a reverse proxy,
+// e.g. "https://example.org/seedbox/qbt/".
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithRequestTimeout bounds every request, including a re-authenticated retry, by d.
+// It applies on top of any timeout configured on the http.Client.
+func WithRequestTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithEndpointTimeout overrides the request timeout for a single endpoint,
+// e.g. a longer timeout for "/api/v2/torrents/export" or a shorter one for "/api/v2/sync/maindata".
+// A zero duration disables the timeout for that endpoint.
+func WithEndpointTimeout(endpoint string, d time.Duration) ClientOption {
+	return func(c *Client) {
+		if c.endpointTimeouts == nil {
+			c.endpointTimeouts = make(map[string]time.Duration)
+		}
+		c.endpointTimeouts[endpoint] = d
+	}
+}
+
+// WithStateNormalization selects how torrent states renamed in qBittorrent 5.x are reported by TorrentsInfo,
+// SyncMainData and SyncWatcher. By default states are passed through unchanged.
+func WithStateNormalization(n StateNormalization) ClientOption {
+	return func(c *Client) {
+		c.stateNormalization = n
+	}
+}
+
+// WithNoAuth is for servers configured to bypass authentication for the client's address, e.g. localhost
+// or a whitelisted subnet. The client never logs in, even if credentials are given, and a 403 response
+// fails with ErrForbidden instead of triggering a login.
+func WithNoAuth() ClientOption {
+	return func(c *Client) {
+		c.noAuth = true
+	}
+}
+
+// WithDebugLogger logs the method, URL, status and duration of every request to logger. Credentials in
+// the logged URLs are removed with Redact; request and response bodies are not logged.
+func WithDebugLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		c.debugLog = logger
+	}
+}
+
+// WithLazyLogin defers the login from the constructor to the first request, so a client can be created
+// while the server is down. Call Client.Connect to log in and check the connection explicitly.
+func WithLazyLogin() ClientOption {
+	return func(c *Client) {
+		c.lazyLogin = true
+	}
+}
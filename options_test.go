@@ -0,0 +1,158 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+	WithRequestTimeout(50 * time.Millisecond)(client)
+
+	if _, err := client.doGet("/api/v2/slow", nil); err == nil {
+		t.Fatalf("expected timeout error, got none")
+	}
+
+	if _, err := client.doGet("/api/v2/fast", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// A per-endpoint override takes precedence over the default
+	WithEndpointTimeout("/api/v2/slow", time.Second)(client)
+	resp, err := client.doGet("/api/v2/slow", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if string(resp) != "Ok." {
+		t.Errorf("expected 'Ok.', got '%s'", string(resp))
+	}
+}
+
+func TestNewClientWithOptions(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login": {statusCode: http.StatusOK, responseBody: "Ok."},
+	}
+	expectedRequests := []expectedRequest{{method: "POST", url: "/api/v2/auth/login"}}
+
+	mockTransport := &mockRoundTripper{
+		responses:        endpointResponses,
+		expectedRequests: expectedRequests,
+		t:                t,
+	}
+
+	client, err := NewClientWithOptions("testuser", "testpass", "localhost", "8080",
+		WithHTTPClient(&http.Client{Transport: mockTransport}),
+		WithRequestTimeout(time.Minute),
+		WithEndpointTimeout("/api/v2/torrents/export", 5*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if got := client.requestTimeout("/api/v2/torrents/info"); got != time.Minute {
+		t.Errorf("Expected default timeout %v, got %v", time.Minute, got)
+	}
+	if got := client.requestTimeout("/api/v2/torrents/export"); got != 5*time.Minute {
+		t.Errorf("Expected export timeout %v, got %v", 5*time.Minute, got)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestWithDebugLogger(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+
+	var buf bytes.Buffer
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithDebugLogger(log.New(&buf, "", 0))(client)
+
+	query := url.Values{"url": {"https://t.example.org/announce?passkey=secret"}}
+	if _, err := client.doGet("/api/v2/test", query); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "qbittorrent: GET "+mockServer.URL+"/api/v2/test?url=") || !strings.Contains(out, ": 200 (") {
+		t.Errorf("unexpected log %q", out)
+	}
+	if strings.Contains(out, "secret") {
+		t.Errorf("expected the passkey to be redacted, got %q", out)
+	}
+}
+
+func TestWithBaseURL_Subpath(t *testing.T) {
+	for _, base := range []string{"/seedbox/qbt", "/seedbox/qbt/", "/seedbox/qbt//"} {
+		t.Run(base, func(t *testing.T) {
+			var paths []string
+			loggedIn := false
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path+"?"+r.URL.RawQuery)
+				switch r.URL.Path {
+				case "/seedbox/qbt/api/v2/auth/login":
+					loggedIn = true
+					http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+					w.Write([]byte("Ok."))
+				case "/seedbox/qbt/api/v2/torrents/files":
+					if !loggedIn {
+						w.WriteHeader(http.StatusForbidden)
+						return
+					}
+					w.Write([]byte(`[]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer mockServer.Close()
+
+			client, err := NewClientWithOptions("user", "pass", "", "", WithBaseURL(mockServer.URL+base+"?ignored=1"), WithHTTPClient(mockServer.Client()))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			// Force a re-authentication on the next request
+			loggedIn = false
+			if _, err := client.TorrentsFiles("abc"); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			want := []string{
+				"/seedbox/qbt/api/v2/auth/login?",
+				"/seedbox/qbt/api/v2/torrents/files?hash=abc",
+				"/seedbox/qbt/api/v2/auth/login?",
+				"/seedbox/qbt/api/v2/torrents/files?hash=abc",
+			}
+			if strings.Join(paths, " ") != strings.Join(want, " ") {
+				t.Errorf("expected requests %v, got %v", want, paths)
+			}
+		})
+	}
+}
+
+func TestWithBaseURL_Invalid(t *testing.T) {
+	for _, base := range []string{"localhost:8080", "ftp://host/", "http://"} {
+		if _, err := NewClientWithOptions("", "", "", "", WithBaseURL(base)); err == nil {
+			t.Errorf("%s: expected an error", base)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package qbittorrent
+
+import (
+	"fmt"
+)
+
+// PeerDiscovery selects the decentralised ways qBittorrent finds peers besides trackers. Private torrents
+// never use them, whatever the settings.
+type PeerDiscovery struct {
+	DHT bool // distributed hash table, the dht preference
+	PeX bool // peer exchange, the pex preference; qBittorrent applies a change on its next start
+	LSD bool // local service discovery on the LAN, the lsd preference
+}
+
+// PeerDiscovery retrieves which peer discovery methods are enabled
+func (c *Client) PeerDiscovery() (PeerDiscovery, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return PeerDiscovery{}, fmt.Errorf("PeerDiscovery error: %w", err)
+	}
+	dht, _ := prefs["dht"].(bool)
+	pex, _ := prefs["pex"].(bool)
+	lsd, _ := prefs["lsd"].(bool)
+	return PeerDiscovery{DHT: dht, PeX: pex, LSD: lsd}, nil
+}
+
+// SetPeerDiscovery enables and disables the peer discovery methods at once
+func (c *Client) SetPeerDiscovery(d PeerDiscovery) error {
+	if err := c.AppSetPreferences(Preferences{"dht": d.DHT, "pex": d.PeX, "lsd": d.LSD}); err != nil {
+		return fmt.Errorf("SetPeerDiscovery error: %w", err)
+	}
+	return nil
+}
+
+// SetDHT enables or disables the DHT
+func (c *Client) SetDHT(enabled bool) error {
+	if err := c.AppSetPreferences(Preferences{"dht": enabled}); err != nil {
+		return fmt.Errorf("SetDHT error: %w", err)
+	}
+	return nil
+}
+
+// SetPeX enables or disables peer exchange. qBittorrent applies the change on its next start.
+func (c *Client) SetPeX(enabled bool) error {
+	if err := c.AppSetPreferences(Preferences{"pex": enabled}); err != nil {
+		return fmt.Errorf("SetPeX error: %w", err)
+	}
+	return nil
+}
+
+// SetLocalPeerDiscovery enables or disables local service discovery
+func (c *Client) SetLocalPeerDiscovery(enabled bool) error {
+	if err := c.AppSetPreferences(Preferences{"lsd": enabled}); err != nil {
+		return fmt.Errorf("SetLocalPeerDiscovery error: %w", err)
+	}
+	return nil
+}
+
+// DHTNodes retrieves the number of DHT nodes qBittorrent is connected to. It stays 0 while the DHT is
+// disabled and, when enabled, one that does not grow within minutes suggests UDP traffic is blocked.
+func (c *Client) DHTNodes() (int, error) {
+	info, err := c.TransferInfo()
+	if err != nil {
+		return 0, fmt.Errorf("DHTNodes error: %w", err)
+	}
+	return info.DHTNodes, nil
+}
@@ -0,0 +1,63 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_PeerDiscovery(t *testing.T) {
+	var posted []map[string]any
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"dht":true,"pex":false,"lsd":true,"listen_port":6881}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			var prefs map[string]any
+			if err := json.Unmarshal([]byte(r.PostForm.Get("json")), &prefs); err != nil {
+				t.Errorf("invalid preferences: %v", err)
+			}
+			posted = append(posted, prefs)
+		case "/api/v2/transfer/info":
+			w.Write([]byte(`{"dht_nodes":312,"connection_status":"connected"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	d, err := client.PeerDiscovery()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := (PeerDiscovery{DHT: true, LSD: true}); d != want {
+		t.Errorf("expected %+v, got %+v", want, d)
+	}
+
+	if err := client.SetPeerDiscovery(PeerDiscovery{PeX: true}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, set := range []func(bool) error{client.SetDHT, client.SetPeX, client.SetLocalPeerDiscovery} {
+		if err := set(false); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	want := []map[string]any{
+		{"dht": false, "pex": true, "lsd": false},
+		{"dht": false},
+		{"pex": false},
+		{"lsd": false},
+	}
+	if !reflect.DeepEqual(posted, want) {
+		t.Errorf("expected %v, got %v", want, posted)
+	}
+
+	nodes, err := client.DHTNodes()
+	if err != nil || nodes != 312 {
+		t.Errorf("expected 312 nodes, got %d, %v", nodes, err)
+	}
+}
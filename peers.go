@@ -0,0 +1,90 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+)
+
+// List returns the peers sorted by their "ip:port" key
+func (p *TorrentPeers) List() []TorrentPeer {
+	keys := make([]string, 0, len(p.Peers))
+	for key := range p.Peers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	peers := make([]TorrentPeer, len(keys))
+	for i, key := range keys {
+		peers[i] = p.Peers[key]
+	}
+	return peers
+}
+
+// TopUploaders returns at most n of peers sorted by the amount downloaded from them, the largest first.
+// A non-positive n returns all of them. peers is not modified.
+func TopUploaders(peers []TorrentPeer, n int) []TorrentPeer {
+	return topPeers(peers, n, func(p TorrentPeer) int64 { return p.Downloaded })
+}
+
+// TopDownloaders returns at most n of peers sorted by the amount uploaded to them, the largest first.
+// A non-positive n returns all of them. peers is not modified.
+func TopDownloaders(peers []TorrentPeer, n int) []TorrentPeer {
+	return topPeers(peers, n, func(p TorrentPeer) int64 { return p.Uploaded })
+}
+
+func topPeers(peers []TorrentPeer, n int, amount func(TorrentPeer) int64) []TorrentPeer {
+	sorted := slices.Clone(peers)
+	slices.SortStableFunc(sorted, func(a, b TorrentPeer) int { return cmp.Compare(amount(b), amount(a)) })
+	if n > 0 && n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// PeerGroup aggregates the peers sharing a country or client
+type PeerGroup struct {
+	Key        string // country code or client name, empty when unknown
+	Peers      int
+	Downloaded int64 // downloaded from the peers
+	Uploaded   int64 // uploaded to the peers
+	DLSpeed    int64
+	UPSpeed    int64
+}
+
+// GroupPeersByCountry aggregates peers by country code. Groups are sorted by number of peers, the largest
+// first, and then by code.
+func GroupPeersByCountry(peers []TorrentPeer) []PeerGroup {
+	return groupPeers(peers, func(p TorrentPeer) string { return p.CountryCode })
+}
+
+// GroupPeersByClient aggregates peers by client name, sorted like GroupPeersByCountry
+func GroupPeersByClient(peers []TorrentPeer) []PeerGroup {
+	return groupPeers(peers, func(p TorrentPeer) string { return p.Client })
+}
+
+func groupPeers(peers []TorrentPeer, key func(TorrentPeer) string) []PeerGroup {
+	index := make(map[string]int)
+	var groups []PeerGroup
+	for _, peer := range peers {
+		k := key(peer)
+		i, ok := index[k]
+		if !ok {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, PeerGroup{Key: k})
+		}
+		g := &groups[i]
+		g.Peers++
+		g.Downloaded += peer.Downloaded
+		g.Uploaded += peer.Uploaded
+		g.DLSpeed += peer.DLSpeed
+		g.UPSpeed += peer.UPSpeed
+	}
+	slices.SortFunc(groups, func(a, b PeerGroup) int {
+		if c := cmp.Compare(b.Peers, a.Peers); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	return groups
+}
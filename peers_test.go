@@ -0,0 +1,42 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPeerHelpers(t *testing.T) {
+	list := (&TorrentPeers{Peers: map[string]TorrentPeer{
+		"3.3.3.3:1": {IP: "3.3.3.3", CountryCode: "de", Client: "qBittorrent", Downloaded: 10, Uploaded: 300, UPSpeed: 5},
+		"1.1.1.1:1": {IP: "1.1.1.1", CountryCode: "us", Client: "Transmission", Downloaded: 500, Uploaded: 20, DLSpeed: 7},
+		"2.2.2.2:1": {IP: "2.2.2.2", CountryCode: "de", Client: "qBittorrent", Downloaded: 50, Uploaded: 100},
+	}}).List()
+
+	ips := func(peers []TorrentPeer) []string {
+		var out []string
+		for _, p := range peers {
+			out = append(out, p.IP)
+		}
+		return out
+	}
+	if got := ips(list); !reflect.DeepEqual(got, []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"}) {
+		t.Errorf("unexpected list order %v", got)
+	}
+	if got := ips(TopUploaders(list, 2)); !reflect.DeepEqual(got, []string{"1.1.1.1", "2.2.2.2"}) {
+		t.Errorf("unexpected top uploaders %v", got)
+	}
+	if got := ips(TopDownloaders(list, 0)); !reflect.DeepEqual(got, []string{"3.3.3.3", "2.2.2.2", "1.1.1.1"}) {
+		t.Errorf("unexpected top downloaders %v", got)
+	}
+
+	want := []PeerGroup{
+		{Key: "de", Peers: 2, Downloaded: 60, Uploaded: 400, UPSpeed: 5},
+		{Key: "us", Peers: 1, Downloaded: 500, Uploaded: 20, DLSpeed: 7},
+	}
+	if got := GroupPeersByCountry(list); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if got := GroupPeersByClient(list); len(got) != 2 || got[0].Key != "qBittorrent" || got[1].Key != "Transmission" {
+		t.Errorf("unexpected client groups %+v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PieceMap is the download state of the pieces of a torrent, as built by TorrentsPieceMap, for players
+// streaming a torrent while it downloads
+type PieceMap struct {
+	States    []int    // state of every piece, see PieceDownloaded
+	Hashes    []string // hex encoded piece hashes, as returned by TorrentsPieceHashes
+	PieceSize int64    // bytes per piece, the last piece may be shorter
+}
+
+// TorrentsPieceMap retrieves the piece states, piece hashes and piece size of a torrent
+func (c *Client) TorrentsPieceMap(hash string) (*PieceMap, error) {
+	props, err := c.TorrentsProperties(hash)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsPieceMap error: %w", err)
+	}
+	states, err := c.TorrentsPieceStates(hash)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsPieceMap error: %w", err)
+	}
+	hashes, err := c.TorrentsPieceHashes(hash)
+	if err != nil {
+		return nil, fmt.Errorf("TorrentsPieceMap error: %w", err)
+	}
+	if len(hashes) != len(states) {
+		return nil, fmt.Errorf("TorrentsPieceMap error: %d piece states for %d piece hashes", len(states), len(hashes))
+	}
+	return &PieceMap{States: states, Hashes: hashes, PieceSize: props.PieceSize}, nil
+}
+
+// Len returns the number of pieces
+func (m *PieceMap) Len() int {
+	return len(m.States)
+}
+
+// ContiguousDownloadedPrefix returns the number of downloaded pieces at the start of the torrent, before the
+// first piece that is missing
+func (m *PieceMap) ContiguousDownloadedPrefix() int {
+	return m.ContiguousDownloadedFrom(0)
+}
+
+// ContiguousDownloadedFrom returns the number of downloaded pieces starting at piece first, before the next
+// piece that is missing
+func (m *PieceMap) ContiguousDownloadedFrom(first int) int {
+	n := 0
+	for i := max(first, 0); i < len(m.States) && m.States[i] == PieceDownloaded; i++ {
+		n++
+	}
+	return n
+}
+
+// FileBuffered returns the bytes of file that are downloaded contiguously from its start, the buffer depth
+// a streaming player can rely on. It is measured in whole pieces from the file's first piece, which may
+// also hold the end of the previous file, and capped at the file's size.
+func (m *PieceMap) FileBuffered(file TorrentFile) int64 {
+	if len(file.PieceRange) != 2 {
+		return 0
+	}
+	first, last := file.PieceRange[0], file.PieceRange[1]
+	n := m.ContiguousDownloadedFrom(first)
+	if first+n > last {
+		return file.Size
+	}
+	return min(int64(n)*m.PieceSize, file.Size)
+}
+
+// PercentByRange returns the percentage, 0 to 100, of downloaded pieces from first to last, inclusive, as
+// in TorrentFile.PieceRange. Pieces outside the torrent are ignored.
+func (m *PieceMap) PercentByRange(first, last int) float64 {
+	first, last = max(first, 0), min(last, len(m.States)-1)
+	if first > last {
+		return 0
+	}
+	downloaded := 0
+	for _, state := range m.States[first : last+1] {
+		if state == PieceDownloaded {
+			downloaded++
+		}
+	}
+	return float64(downloaded) * 100 / float64(last-first+1)
+}
+
+// ProgressBar rasterizes the pieces into a bar width characters wide: a cell is █ if all its pieces are
+// downloaded, ▓ if some are downloaded or downloading and ░ otherwise
+func (m *PieceMap) ProgressBar(width int) string {
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	for cell := range width {
+		first := cell * len(m.States) / width
+		last := max((cell+1)*len(m.States)/width, first+1)
+		all, some := true, false
+		for i := first; i < last && i < len(m.States); i++ {
+			if m.States[i] != PieceDownloaded {
+				all = false
+			}
+			if m.States[i] != PieceNotDownloaded {
+				some = true
+			}
+		}
+		switch {
+		case len(m.States) == 0:
+			b.WriteRune('░')
+		case all:
+			b.WriteRune('█')
+		case some:
+			b.WriteRune('▓')
+		default:
+			b.WriteRune('░')
+		}
+	}
+	return b.String()
+}
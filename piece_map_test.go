@@ -0,0 +1,59 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPieceMap(t *testing.T) {
+	m := &PieceMap{States: []int{2, 2, 1, 2, 0, 0, 2, 2}, PieceSize: 100}
+
+	if n := m.ContiguousDownloadedPrefix(); n != 2 {
+		t.Errorf("expected prefix 2, got %d", n)
+	}
+	if n := m.ContiguousDownloadedFrom(6); n != 2 {
+		t.Errorf("expected 2 pieces from 6, got %d", n)
+	}
+	if p := m.PercentByRange(0, 3); p != 75 {
+		t.Errorf("expected 75%%, got %v", p)
+	}
+	if p := m.PercentByRange(6, 20); p != 100 {
+		t.Errorf("expected 100%% for a clamped range, got %v", p)
+	}
+	if got := m.FileBuffered(TorrentFile{Size: 350, PieceRange: []int{0, 3}}); got != 200 {
+		t.Errorf("expected 200 buffered bytes, got %d", got)
+	}
+	if got := m.FileBuffered(TorrentFile{Size: 150, PieceRange: []int{6, 7}}); got != 150 {
+		t.Errorf("expected the whole file buffered, got %d", got)
+	}
+	if bar := m.ProgressBar(4); bar != "█▓░█" {
+		t.Errorf("expected bar █▓░█, got %s", bar)
+	}
+	if bar := (&PieceMap{}).ProgressBar(2); bar != "░░" {
+		t.Errorf("expected an empty bar, got %s", bar)
+	}
+}
+
+func TestClient_TorrentsPieceMap(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/properties":
+			w.Write([]byte(`{"piece_size":16384,"pieces_num":3}`))
+		case "/api/v2/torrents/pieceStates":
+			w.Write([]byte(`[2,1,0]`))
+		case "/api/v2/torrents/pieceHashes":
+			w.Write([]byte(`["aa","bb","cc"]`))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	m, err := client.TorrentsPieceMap("abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if m.Len() != 3 || m.PieceSize != 16384 || m.Hashes[2] != "cc" {
+		t.Errorf("unexpected piece map %+v", m)
+	}
+}
@@ -0,0 +1,173 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PortSource provides the port forwarded to qBittorrent, typically by a VPN provider. Port returns 0 while
+// no port is forwarded.
+type PortSource interface {
+	Port(ctx context.Context) (int, error)
+}
+
+// PortSourceFunc adapts a function to a PortSource
+type PortSourceFunc func(ctx context.Context) (int, error)
+
+// Port calls f
+func (f PortSourceFunc) Port(ctx context.Context) (int, error) {
+	return f(ctx)
+}
+
+// FilePortSource reads the port from a file holding just the port number, such as the forwarded_port file
+// written by gluetun. A missing or empty file means no port is forwarded.
+type FilePortSource struct {
+	Path string
+}
+
+// Port reads the port from the file
+func (s FilePortSource) Port(ctx context.Context) (int, error) {
+	data, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return parsePort(data)
+}
+
+// HTTPPortSource fetches the port from an HTTP endpoint returning either the bare port number or a JSON
+// object with a "port" field, such as gluetun's control server at /v1/openvpn/portforwarded.
+type HTTPPortSource struct {
+	URL    string
+	Client *http.Client // http.DefaultClient if nil
+}
+
+// Port fetches the port from the endpoint
+func (s HTTPPortSource) Port(ctx context.Context) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("port source returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return 0, err
+	}
+	return parsePort(data)
+}
+
+// parsePort parses a bare port number or a JSON object with a "port" field, empty data being port 0
+func parsePort(data []byte) (int, error) {
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return 0, nil
+	}
+	var port int
+	if strings.HasPrefix(text, "{") {
+		var obj struct {
+			Port int `json:"port"`
+		}
+		if err := json.Unmarshal([]byte(text), &obj); err != nil {
+			return 0, fmt.Errorf("invalid port response: %v", err)
+		}
+		port = obj.Port
+	} else {
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return 0, fmt.Errorf("invalid port %q", text)
+		}
+		port = n
+	}
+	if port < 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %d", port)
+	}
+	return port, nil
+}
+
+// PortSyncConfig configures a PortSync. Zero values select the defaults.
+type PortSyncConfig struct {
+	Source   PortSource
+	Interval time.Duration // how often Run polls the source, default 30s
+
+	// OnChange, if set, is called after the listen port was changed from old to port
+	OnChange func(old, port int)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// PortSync keeps qBittorrent's listen port equal to the port provided by a PortSource, e.g. the port a VPN
+// provider forwards, which may change on every reconnect. Nothing is changed while the source reports no port.
+type PortSync struct {
+	client *Client
+	cfg    PortSyncConfig
+}
+
+// NewPortSync creates a PortSync for the given client
+func NewPortSync(client *Client, cfg PortSyncConfig) *PortSync {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &PortSync{client: client, cfg: cfg}
+}
+
+// Run synchronises the port every Interval until ctx is cancelled
+func (s *PortSync) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil && s.cfg.OnError != nil {
+			s.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce reads the source and updates the listen port if it differs, reporting whether it was changed
+func (s *PortSync) RunOnce(ctx context.Context) (bool, error) {
+	port, err := s.cfg.Source.Port(ctx)
+	if err != nil {
+		return false, fmt.Errorf("PortSync error: %w", err)
+	}
+	if port == 0 {
+		return false, nil
+	}
+	current, err := s.client.ListenPort()
+	if err != nil {
+		return false, fmt.Errorf("PortSync error: %w", err)
+	}
+	if current == port {
+		return false, nil
+	}
+	if err := s.client.SetListenPort(port); err != nil {
+		return false, fmt.Errorf("PortSync error: %w", err)
+	}
+	if s.cfg.OnChange != nil {
+		s.cfg.OnChange(current, port)
+	}
+	return true, nil
+}
@@ -0,0 +1,69 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPortSources(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "forwarded_port")
+	if port, err := (FilePortSource{Path: path}).Port(ctx); err != nil || port != 0 {
+		t.Errorf("expected port 0 for a missing file, got %d, %v", port, err)
+	}
+	os.WriteFile(path, []byte("51413\n"), 0o644)
+	if port, err := (FilePortSource{Path: path}).Port(ctx); err != nil || port != 51413 {
+		t.Errorf("expected port 51413, got %d, %v", port, err)
+	}
+	os.WriteFile(path, []byte("nope"), 0o644)
+	if _, err := (FilePortSource{Path: path}).Port(ctx); err == nil {
+		t.Error("expected an error for an invalid file")
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"port":40123}`))
+	}))
+	defer srv.Close()
+	if port, err := (HTTPPortSource{URL: srv.URL}).Port(ctx); err != nil || port != 40123 {
+		t.Errorf("expected port 40123, got %d, %v", port, err)
+	}
+}
+
+func TestPortSync_RunOnce(t *testing.T) {
+	var posted string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"listen_port":6881}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	port := 0
+	var changes [][2]int
+	s := NewPortSync(client, PortSyncConfig{
+		Source:   PortSourceFunc(func(context.Context) (int, error) { return port, nil }),
+		OnChange: func(old, port int) { changes = append(changes, [2]int{old, port}) },
+	})
+	for _, port = range []int{0, 6881, 40123} {
+		if _, err := s.RunOnce(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(changes) != 1 || changes[0] != [2]int{6881, 40123} {
+		t.Errorf("unexpected changes %v", changes)
+	}
+	if want := `{"listen_port":40123,"random_port":false}`; posted != want {
+		t.Errorf("expected %s, got %s", want, posted)
+	}
+}
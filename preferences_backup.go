@@ -0,0 +1,85 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// PreferencesBackupVersion is the version of the document written by AppPreferencesBackup
+const PreferencesBackupVersion = 1
+
+// PreferencesBackup is the document written by AppPreferencesBackup. The qBittorrent and Web API versions
+// record the schema the preferences follow, since releases add, rename and drop preferences.
+type PreferencesBackup struct {
+	Version       int         `json:"version"`
+	CreatedAt     time.Time   `json:"created_at"`
+	AppVersion    string      `json:"app_version"`
+	WebAPIVersion string      `json:"webapi_version"`
+	Preferences   Preferences `json:"preferences"`
+}
+
+// AppPreferencesBackup writes all preferences to w as an indented JSON PreferencesBackup, e.g. before a
+// risky change or a reinstall. Write-only preferences such as web_ui_password are not reported by the
+// server and therefore not part of the backup.
+func (c *Client) AppPreferencesBackup(w io.Writer) error {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return fmt.Errorf("AppPreferencesBackup error: %w", err)
+	}
+	appVersion, err := c.AppVersion()
+	if err != nil {
+		return fmt.Errorf("AppPreferencesBackup error: %w", err)
+	}
+	apiVersion, err := c.AppWebAPIVersion()
+	if err != nil {
+		return fmt.Errorf("AppPreferencesBackup error: %w", err)
+	}
+
+	backup := PreferencesBackup{
+		Version:       PreferencesBackupVersion,
+		CreatedAt:     time.Now().UTC(),
+		AppVersion:    appVersion,
+		WebAPIVersion: apiVersion,
+		Preferences:   prefs,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(backup); err != nil {
+		return fmt.Errorf("AppPreferencesBackup error: %w", err)
+	}
+	return nil
+}
+
+// AppPreferencesRestore applies the preferences of a backup written by AppPreferencesBackup and returns the
+// update that was sent. Only preferences that differ from the server's are sent, and preferences the
+// server does not know, e.g. because the backup was made with another qBittorrent release, are skipped.
+func (c *Client) AppPreferencesRestore(r io.Reader) (Preferences, error) {
+	var backup PreferencesBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("AppPreferencesRestore error: invalid backup: %w", err)
+	}
+	if backup.Version < 1 || backup.Version > PreferencesBackupVersion {
+		return nil, fmt.Errorf("AppPreferencesRestore error: unsupported backup version %d", backup.Version)
+	}
+
+	current, err := c.AppPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("AppPreferencesRestore error: %w", err)
+	}
+	known := Preferences{}
+	for key, value := range backup.Preferences {
+		if _, ok := current[key]; ok {
+			known[key] = value
+		}
+	}
+	diff := PreferencesDiff(current, known)
+	if len(diff) == 0 {
+		return diff, nil
+	}
+	if err := c.AppSetPreferences(diff); err != nil {
+		return nil, fmt.Errorf("AppPreferencesRestore error: %w", err)
+	}
+	return diff, nil
+}
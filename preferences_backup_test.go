@@ -0,0 +1,64 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClient_AppPreferencesBackupRestore(t *testing.T) {
+	prefs := `{"listen_port":6881,"dht":true,"save_path":"/downloads"}`
+	var posted []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(prefs))
+		case "/api/v2/app/version":
+			w.Write([]byte("v4.6.7"))
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.9.3"))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = append(posted, r.PostForm.Get("json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	var buf bytes.Buffer
+	if err := client.AppPreferencesBackup(&buf); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var backup PreferencesBackup
+	if err := json.Unmarshal(buf.Bytes(), &backup); err != nil {
+		t.Fatalf("invalid backup: %v", err)
+	}
+	if backup.Version != PreferencesBackupVersion || backup.AppVersion != "v4.6.7" || backup.WebAPIVersion != "2.9.3" || backup.Preferences["listen_port"] != 6881.0 {
+		t.Errorf("unexpected backup %+v", backup)
+	}
+
+	// The server changed since: only the differences known to it are restored
+	prefs = `{"listen_port":51413,"dht":true}`
+	diff, err := client.AppPreferencesRestore(&buf)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := (Preferences{"listen_port": 6881.0}); !reflect.DeepEqual(diff, want) {
+		t.Errorf("expected %v, got %v", want, diff)
+	}
+	if want := []string{`{"listen_port":6881}`}; !reflect.DeepEqual(posted, want) {
+		t.Errorf("expected %v, got %v", want, posted)
+	}
+
+	for _, invalid := range []string{`not json`, `{"version":2,"preferences":{}}`, `{"preferences":{}}`} {
+		if _, err := client.AppPreferencesRestore(strings.NewReader(invalid)); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
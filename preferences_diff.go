@@ -0,0 +1,62 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// PreferencesDiff returns the preferences of desired whose value differs from current, i.e. the minimal
+// update that AppSetPreferences needs to turn current into desired. Values are compared by their JSON
+// encoding, so an int in desired equals the float64 decoded from the server. Keys missing from current
+// are always part of the result. Preferences of current that desired does not mention are left out.
+func PreferencesDiff(current, desired Preferences) Preferences {
+	diff := Preferences{}
+	for key, want := range desired {
+		have, ok := current[key]
+		if !ok || !jsonEqual(have, want) {
+			diff[key] = want
+		}
+	}
+	return diff
+}
+
+// ReconcilePreferences applies the preferences of desired that differ from the server's and returns the
+// update that was sent, which is empty when the server already matched. Preferences not in desired are
+// left untouched, so desired can describe just the settings to manage. Write-only preferences such as
+// web_ui_password are never reported by the server and are therefore sent on every call.
+func (c *Client) ReconcilePreferences(desired Preferences) (Preferences, error) {
+	current, err := c.AppPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("ReconcilePreferences error: %w", err)
+	}
+	diff := PreferencesDiff(current, desired)
+	if len(diff) == 0 {
+		return diff, nil
+	}
+	if err := c.AppSetPreferences(diff); err != nil {
+		return nil, fmt.Errorf("ReconcilePreferences error: %w", err)
+	}
+	return diff, nil
+}
+
+// jsonEqual reports whether a and b have the same JSON representation
+func jsonEqual(a, b interface{}) bool {
+	na, errA := normalizeJSON(a)
+	nb, errB := normalizeJSON(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+// normalizeJSON converts v to the generic form encoding/json decodes into
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
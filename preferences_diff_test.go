@@ -0,0 +1,68 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestPreferencesDiff(t *testing.T) {
+	current := Preferences{
+		"save_path":      "/downloads",
+		"max_ratio":      1.5,
+		"dht":            true,
+		"max_active":     float64(5),
+		"scan_dirs":      map[string]interface{}{"/watch": float64(1)},
+		"not_in_desired": "x",
+	}
+	desired := Preferences{
+		"save_path":    "/downloads",
+		"max_ratio":    2.0,
+		"dht":          true,
+		"max_active":   5,
+		"scan_dirs":    map[string]int{"/watch": 1},
+		"new_settings": false,
+	}
+	want := Preferences{"max_ratio": 2.0, "new_settings": false}
+	if diff := PreferencesDiff(current, desired); !reflect.DeepEqual(diff, want) {
+		t.Errorf("expected %v, got %v", want, diff)
+	}
+}
+
+func TestClient_ReconcilePreferences(t *testing.T) {
+	var posted []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"save_path":"/downloads","dht":true,"max_ratio":1.5}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = append(posted, r.PostForm.Get("json"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	applied, err := client.ReconcilePreferences(Preferences{"dht": true, "max_ratio": 1.5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(applied) != 0 || len(posted) != 0 {
+		t.Errorf("expected no update, applied %v and posted %v", applied, posted)
+	}
+
+	applied, err = client.ReconcilePreferences(Preferences{"dht": false, "save_path": "/downloads"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := (Preferences{"dht": false}); !reflect.DeepEqual(applied, want) {
+		t.Errorf("expected %v applied, got %v", want, applied)
+	}
+	if want := []string{`{"dht":false}`}; !reflect.DeepEqual(posted, want) {
+		t.Errorf("expected %v posted, got %v", want, posted)
+	}
+}
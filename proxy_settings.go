@@ -0,0 +1,122 @@
+package qbittorrent
+
+import (
+	"fmt"
+)
+
+// ProxyType is the kind of proxy qBittorrent connects through, as in the proxy_type preference of
+// qBittorrent 4.6 and newer
+type ProxyType string
+
+const (
+	ProxyNone   ProxyType = "None"
+	ProxyHTTP   ProxyType = "HTTP"
+	ProxySOCKS5 ProxyType = "SOCKS5"
+	ProxySOCKS4 ProxyType = "SOCKS4"
+)
+
+// ProxySettings configure the outbound proxy of qBittorrent
+type ProxySettings struct {
+	Type           ProxyType
+	Host           string
+	Port           int
+	AuthEnabled    bool // not supported by SOCKS4
+	Username       string
+	Password       string
+	HostnameLookup bool // resolve hostnames through the proxy, qBittorrent 4.6 and newer
+	// PeerConnections routes connections to peers through the proxy too, not only those to trackers
+	PeerConnections bool
+}
+
+// legacyProxyTypes are the numeric proxy_type values of qBittorrent before 4.6, which combined the type
+// with authentication
+var legacyProxyTypes = []struct {
+	value int
+	typ   ProxyType
+	auth  bool
+}{
+	{0, ProxyNone, false},
+	{1, ProxyHTTP, false},
+	{2, ProxySOCKS5, false},
+	{3, ProxyHTTP, true},
+	{4, ProxySOCKS5, true},
+	{5, ProxySOCKS4, false},
+}
+
+// ProxySettings retrieves the proxy settings. Both the numeric proxy types of qBittorrent before 4.6 and
+// the named ones of newer releases are understood.
+func (c *Client) ProxySettings() (ProxySettings, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return ProxySettings{}, fmt.Errorf("ProxySettings error: %w", err)
+	}
+
+	s := ProxySettings{Type: ProxyNone}
+	s.Host, _ = prefs["proxy_ip"].(string)
+	port, _ := prefs["proxy_port"].(float64)
+	s.Port = int(port)
+	s.AuthEnabled, _ = prefs["proxy_auth_enabled"].(bool)
+	s.Username, _ = prefs["proxy_username"].(string)
+	s.Password, _ = prefs["proxy_password"].(string)
+	s.HostnameLookup, _ = prefs["proxy_hostname_lookup"].(bool)
+	s.PeerConnections, _ = prefs["proxy_peer_connections"].(bool)
+
+	switch typ := prefs["proxy_type"].(type) {
+	case string:
+		s.Type = ProxyType(typ)
+	case float64:
+		for _, legacy := range legacyProxyTypes {
+			if legacy.value == int(typ) {
+				s.Type = legacy.typ
+				s.AuthEnabled = s.AuthEnabled || legacy.auth
+			}
+		}
+	}
+	return s, nil
+}
+
+// SetProxySettings changes the proxy settings, writing the proxy type in the form the server uses. A proxy
+// other than ProxyNone needs a host and port.
+func (c *Client) SetProxySettings(s ProxySettings) error {
+	switch s.Type {
+	case ProxyNone:
+	case ProxyHTTP, ProxySOCKS5, ProxySOCKS4:
+		if s.Host == "" || s.Port < 1 || s.Port > 65535 {
+			return fmt.Errorf("SetProxySettings error: a %s proxy needs a host and port", s.Type)
+		}
+		if s.Type == ProxySOCKS4 && s.AuthEnabled {
+			return fmt.Errorf("SetProxySettings error: SOCKS4 proxies do not support authentication")
+		}
+	default:
+		return fmt.Errorf("SetProxySettings error: invalid proxy type %q", s.Type)
+	}
+
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return fmt.Errorf("SetProxySettings error: %w", err)
+	}
+	var typ any = string(s.Type)
+	if _, legacy := prefs["proxy_type"].(float64); legacy {
+		for _, l := range legacyProxyTypes {
+			if l.typ == s.Type && (l.auth == s.AuthEnabled || s.Type == ProxyNone || s.Type == ProxySOCKS4) {
+				typ = l.value
+				break
+			}
+		}
+	}
+
+	err = c.AppSetPreferences(Preferences{
+		"proxy_type":             typ,
+		"proxy_ip":               s.Host,
+		"proxy_port":             s.Port,
+		"proxy_auth_enabled":     s.AuthEnabled,
+		"proxy_username":         s.Username,
+		"proxy_password":         s.Password,
+		"proxy_hostname_lookup":  s.HostnameLookup,
+		"proxy_peer_connections": s.PeerConnections,
+	})
+	if err != nil {
+		return fmt.Errorf("SetProxySettings error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// proxyServer fakes a server reporting prefs and records the last update
+func proxyServer(t *testing.T, prefs string, posted *map[string]any) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(prefs))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			if err := json.Unmarshal([]byte(r.PostForm.Get("json")), posted); err != nil {
+				t.Errorf("invalid preferences: %v", err)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestClient_ProxySettings(t *testing.T) {
+	var posted map[string]any
+	mockServer := proxyServer(t, `{"proxy_type":"SOCKS5","proxy_ip":"10.0.0.1","proxy_port":1080,"proxy_auth_enabled":true,
+		"proxy_username":"u","proxy_password":"p","proxy_hostname_lookup":true,"proxy_peer_connections":true}`, &posted)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	s, err := client.ProxySettings()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := ProxySettings{Type: ProxySOCKS5, Host: "10.0.0.1", Port: 1080, AuthEnabled: true, Username: "u", Password: "p", HostnameLookup: true, PeerConnections: true}
+	if s != want {
+		t.Errorf("expected %+v, got %+v", want, s)
+	}
+
+	if err := client.SetProxySettings(ProxySettings{Type: ProxyHTTP, Host: "proxy", Port: 3128}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if posted["proxy_type"] != "HTTP" || posted["proxy_ip"] != "proxy" || posted["proxy_port"] != 3128.0 {
+		t.Errorf("unexpected update %v", posted)
+	}
+
+	for _, invalid := range []ProxySettings{
+		{Type: ProxyHTTP},
+		{Type: ProxySOCKS4, Host: "proxy", Port: 1080, AuthEnabled: true},
+		{Type: "FTP", Host: "proxy", Port: 21},
+	} {
+		if err := client.SetProxySettings(invalid); err == nil {
+			t.Errorf("%+v: expected an error", invalid)
+		}
+	}
+}
+
+func TestClient_ProxySettings_Legacy(t *testing.T) {
+	var posted map[string]any
+	mockServer := proxyServer(t, `{"proxy_type":4,"proxy_ip":"10.0.0.1","proxy_port":1080}`, &posted)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	s, err := client.ProxySettings()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if s.Type != ProxySOCKS5 || !s.AuthEnabled {
+		t.Errorf("expected SOCKS5 with authentication, got %+v", s)
+	}
+
+	tests := []struct {
+		settings ProxySettings
+		want     float64
+	}{
+		{ProxySettings{Type: ProxyHTTP, Host: "proxy", Port: 3128, AuthEnabled: true}, 3},
+		{ProxySettings{Type: ProxySOCKS4, Host: "proxy", Port: 1080}, 5},
+		{ProxySettings{Type: ProxyNone}, 0},
+	}
+	for _, tt := range tests {
+		if err := client.SetProxySettings(tt.settings); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if posted["proxy_type"] != tt.want {
+			t.Errorf("%s: expected proxy_type %v, got %v", tt.settings.Type, tt.want, posted["proxy_type"])
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package qbittorrenttest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// DockerImage is the image SpawnQBittorrent runs, tagged with the requested version
+var DockerImage = "lscr.io/linuxserver/qbittorrent"
+
+// Container is a real qBittorrent instance running in a Docker container
+type Container struct {
+	ID       string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Client   *qbittorrent.Client // logged in client for the instance
+}
+
+// temporaryPassword matches the password qBittorrent 4.6.1+ prints when no password is configured
+var temporaryPassword = regexp.MustCompile(`temporary password is provided for this session: (\S+)`)
+
+// defaultPassword is the WebUI password of qBittorrent versions before 4.6.1
+const defaultPassword = "adminadmin"
+
+// SpawnQBittorrent starts qBittorrent of the given version (an image tag such as "4.6.7" or "5.0.3") in Docker,
+// waits for the WebUI to accept a login and returns the running container. The caller must call Terminate.
+// It requires the docker CLI; use DockerAvailable to skip tests where it is missing.
+func SpawnQBittorrent(ctx context.Context, version string) (*Container, error) {
+	out, err := docker(ctx, "run", "-d", "--rm",
+		"-p", "127.0.0.1::8080",
+		"-e", "WEBUI_PORT=8080",
+		DockerImage+":"+version)
+	if err != nil {
+		return nil, fmt.Errorf("SpawnQBittorrent error: %v", err)
+	}
+	c := &Container{ID: strings.TrimSpace(out), Username: "admin"}
+
+	if err := c.start(ctx); err != nil {
+		c.Terminate(context.Background())
+		return nil, fmt.Errorf("SpawnQBittorrent error: %v", err)
+	}
+	return c, nil
+}
+
+// start waits for the container's WebUI and logs in
+func (c *Container) start(ctx context.Context) error {
+	out, err := docker(ctx, "port", c.ID, "8080/tcp")
+	if err != nil {
+		return err
+	}
+	// Several lines are printed when the port is published on IPv4 and IPv6
+	c.Host, c.Port, err = net.SplitHostPort(strings.TrimSpace(strings.Split(out, "\n")[0]))
+	if err != nil {
+		return fmt.Errorf("unexpected docker port output %q: %v", out, err)
+	}
+
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if c.Password == "" {
+			c.Password = c.findPassword(ctx)
+		}
+		if c.Password != "" && c.webUIUp(ctx) {
+			client, err := qbittorrent.NewClient(c.Username, c.Password, c.Host, c.Port)
+			if err == nil {
+				c.Client = client
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for the WebUI: %v", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// findPassword returns the WebUI password once the container logged it, or the legacy default password
+// once the WebUI is up without having logged one
+func (c *Container) findPassword(ctx context.Context) string {
+	logs, err := docker(ctx, "logs", c.ID)
+	if err != nil {
+		return ""
+	}
+	if password := parseTemporaryPassword(logs); password != "" {
+		return password
+	}
+	if strings.Contains(logs, "WebUI will be started shortly") && c.webUIUp(ctx) {
+		return defaultPassword
+	}
+	return ""
+}
+
+func parseTemporaryPassword(logs string) string {
+	if m := temporaryPassword.FindStringSubmatch(logs); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// webUIUp reports whether the WebUI answers HTTP requests
+func (c *Container) webUIUp(ctx context.Context) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+net.JoinHostPort(c.Host, c.Port)+"/", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Terminate stops and removes the container
+func (c *Container) Terminate(ctx context.Context) error {
+	if _, err := docker(ctx, "rm", "-f", c.ID); err != nil {
+		return fmt.Errorf("Terminate error: %v", err)
+	}
+	return nil
+}
+
+// DockerAvailable reports whether the docker CLI is installed and can reach a daemon
+func DockerAvailable() bool {
+	_, err := docker(context.Background(), "version", "--format", "{{.Server.Version}}")
+	return err == nil
+}
+
+func docker(ctx context.Context, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("docker %s: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("docker %s: %v", args[0], err)
+	}
+	// docker logs writes the container's stderr to stderr
+	return stdout.String() + stderr.String(), nil
+}
@@ -0,0 +1,50 @@
+package qbittorrenttest
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTemporaryPassword(t *testing.T) {
+	logs := "WebUI will be started shortly after internal preparations. Please wait...\n" +
+		"The WebUI administrator username is: admin\n" +
+		"The WebUI administrator password was not set. A temporary password is provided for this session: Ab3dEfGh1\n"
+	if got := parseTemporaryPassword(logs); got != "Ab3dEfGh1" {
+		t.Errorf("expected Ab3dEfGh1, got %q", got)
+	}
+	if got := parseTemporaryPassword("no password here"); got != "" {
+		t.Errorf("expected no password, got %q", got)
+	}
+}
+
+// TestSpawnQBittorrent runs against real qBittorrent versions. It is opt-in as it pulls images:
+// QBITTORRENT_DOCKER_VERSIONS=4.6.7,5.0.3 go test ./qbittorrenttest
+func TestSpawnQBittorrent(t *testing.T) {
+	versions := os.Getenv("QBITTORRENT_DOCKER_VERSIONS")
+	if versions == "" || !DockerAvailable() {
+		t.Skip("set QBITTORRENT_DOCKER_VERSIONS and make docker available to run")
+	}
+
+	for _, version := range strings.Split(versions, ",") {
+		t.Run(version, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+			defer cancel()
+
+			container, err := SpawnQBittorrent(ctx, version)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer container.Terminate(context.Background())
+
+			if _, err := container.Client.TorrentsInfo(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+			if _, err := container.Client.AppPreferences(); err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
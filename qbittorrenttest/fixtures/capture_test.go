@@ -0,0 +1,177 @@
+package fixtures_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest/fixtures"
+)
+
+// TestCapture replaces the fixtures with the responses of real servers. It is opt-in as it pulls images and
+// rewrites the data directory:
+// QBITTORRENT_CAPTURE_FIXTURES=4.6.7,5.0.3 go test ./qbittorrenttest/fixtures -run TestCapture
+func TestCapture(t *testing.T) {
+	versions := os.Getenv("QBITTORRENT_CAPTURE_FIXTURES")
+	if versions == "" || !qbittorrenttest.DockerAvailable() {
+		t.Skip("set QBITTORRENT_CAPTURE_FIXTURES and make docker available to run")
+	}
+
+	for _, version := range strings.Split(versions, ",") {
+		t.Run(version, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+			defer cancel()
+
+			container, err := qbittorrenttest.SpawnQBittorrent(ctx, version)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			defer container.Terminate(context.Background())
+
+			recorder := qbittorrenttest.NewRecordingTransport("", http.DefaultTransport)
+			client, err := qbittorrent.NewClientWithOptions(container.Username, container.Password,
+				container.Host, container.Port, qbittorrent.WithHTTPClient(&http.Client{Transport: recorder}))
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			hash := addCaptureTorrent(t, client)
+			exercise(client, hash)
+
+			dir := filepath.Join("data", version)
+			if err := os.RemoveAll(dir); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			captured := make(map[string]bool)
+			for _, interaction := range recorder.Interactions() {
+				endpoint, ok := captureEndpoint(interaction)
+				if !ok {
+					continue
+				}
+				captured[endpoint] = true
+				file := filepath.Join(dir, filepath.FromSlash(endpoint))
+				if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if err := os.WriteFile(file, []byte(interaction.Response), 0o644); err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			}
+			// Every endpoint the shipped fixtures cover must be captured, or the decode test loses coverage
+			for _, endpoint := range fixtures.Endpoints(fixtures.Versions[0]) {
+				if !captured[endpoint] {
+					t.Errorf("no successful %s response was captured", endpoint)
+				}
+			}
+		})
+	}
+}
+
+// exercise calls every binding that has a fixture, ignoring errors, which the decode test reports
+func exercise(client *qbittorrent.Client, hash string) {
+	client.AppVersion()
+	client.AppWebAPIVersion()
+	client.AppPreferences()
+	client.AppNetworkInterfaces()
+	client.TransferInfo()
+	client.TransferSpeedLimitsMode()
+	client.TorrentsInfo()
+	client.TorrentsProperties(hash)
+	client.TorrentsTrackers(hash)
+	client.TorrentsFiles(hash)
+	client.TorrentsPieceStates(hash)
+	client.TorrentsPieceHashes(hash)
+	client.TorrentsCategories()
+	client.TorrentsGetAllTags()
+	client.SyncMainData(0)
+	client.SyncTorrentPeers(hash, 0)
+	client.LogMain(true, true, true, true, -1)
+	client.LogPeers(-1)
+	client.RSSItems(false)
+	client.RSSRules()
+	client.SearchPlugins()
+}
+
+// captureEndpoint returns the endpoint a successful interaction is the fixture of
+func captureEndpoint(interaction qbittorrenttest.Interaction) (string, bool) {
+	u, err := url.Parse(interaction.URI)
+	if err != nil || interaction.Status != http.StatusOK || interaction.Response64 != "" {
+		return "", false
+	}
+	endpoint, ok := strings.CutPrefix(u.Path, "/api/v2/")
+	if !ok || strings.HasPrefix(endpoint, "auth/") || strings.HasPrefix(endpoint, "torrents/add") {
+		return "", false
+	}
+	return endpoint, true
+}
+
+// addCaptureTorrent adds a stopped single file torrent with a category and tags, so the per torrent endpoints
+// have something to describe, and returns its hash once the server lists it
+func addCaptureTorrent(t *testing.T, client *qbittorrent.Client) string {
+	t.Helper()
+	const pieceLength = 16384
+	var pieces bytes.Buffer
+	for i := 0; i < 4; i++ {
+		sum := sha1.Sum(make([]byte, pieceLength))
+		pieces.Write(sum[:])
+	}
+	info := bencode(map[string]interface{}{
+		"name":         "fixture.bin",
+		"length":       4 * pieceLength,
+		"piece length": pieceLength,
+		"pieces":       pieces.String(),
+	})
+	torrent := fmt.Sprintf("d8:announce31:http://tracker.example/announce4:info%se", info)
+	hash := fmt.Sprintf("%x", sha1.Sum(info))
+
+	if err := client.TorrentsCreateCategory("fixtures", "/downloads/fixtures"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	err := client.TorrentsAddWithOptions("fixture.torrent", []byte(torrent),
+		qbittorrent.WithCategory("fixtures"), qbittorrent.WithTags([]string{"fixture", "golden"}),
+		qbittorrent.WithStartPaused(true))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for i := 0; i < 50; i++ {
+		if torrents, err := client.TorrentsInfo(); err == nil && len(torrents) > 0 {
+			return hash
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatalf("torrent %s was not added", hash)
+	return ""
+}
+
+// bencode encodes strings, integers and dictionaries for building the capture torrent
+func bencode(v interface{}) []byte {
+	switch v := v.(type) {
+	case int:
+		return []byte(fmt.Sprintf("i%de", v))
+	case string:
+		return []byte(fmt.Sprintf("%d:%s", len(v), v))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := []byte("d")
+		for _, k := range keys {
+			out = append(out, bencode(k)...)
+			out = append(out, bencode(v[k])...)
+		}
+		return append(out, 'e')
+	}
+	panic(fmt.Sprintf("bencode: unsupported type %T", v))
+}
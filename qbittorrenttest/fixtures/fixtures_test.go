@@ -0,0 +1,163 @@
+package fixtures_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest/fixtures"
+)
+
+// newClient serves the fixtures of version and returns a strictly decoding client for them, and a function
+// returning the endpoints requested so far
+func newClient(t *testing.T, version string) (*qbittorrent.Client, func() map[string]bool) {
+	t.Helper()
+	var mu sync.Mutex
+	requested := make(map[string]bool)
+	handler := fixtures.Handler(version)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested[strings.TrimPrefix(r.URL.Path, "/api/v2/")] = true
+		mu.Unlock()
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(srv.URL, "http://"), ":")
+	client, err := qbittorrent.NewClientWithOptions("admin", "adminadmin", host, port,
+		qbittorrent.WithHTTPClient(srv.Client()), qbittorrent.WithStrictDecoding())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	return client, func() map[string]bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return requested
+	}
+}
+
+// TestDecodeFixtures decodes every fixture strictly, so fields whose type changed between releases fail.
+// It only checks properties that hold for any server, as the fixtures may be captured again.
+func TestDecodeFixtures(t *testing.T) {
+	for _, version := range fixtures.Versions {
+		t.Run(version, func(t *testing.T) {
+			client, requested := newClient(t, version)
+
+			appVersion, err := client.AppVersion()
+			if err != nil || appVersion != "v"+version {
+				t.Errorf("AppVersion: expected v%s, got %q, %v", version, appVersion, err)
+			}
+			webAPIVersion, err := client.AppWebAPIVersion()
+			if err != nil || !strings.HasPrefix(webAPIVersion, "2.") {
+				t.Errorf("AppWebAPIVersion: expected a 2.x version, got %q, %v", webAPIVersion, err)
+			}
+			prefs, err := client.AppPreferences()
+			if _, ok := prefs["save_path"].(string); err != nil || !ok {
+				t.Errorf("AppPreferences: expected a save_path, got %v, %v", prefs["save_path"], err)
+			}
+			if _, err := client.AppNetworkInterfaces(); err != nil {
+				t.Errorf("AppNetworkInterfaces: expected no error, got %v", err)
+			}
+			if _, err := client.TransferInfo(); err != nil {
+				t.Errorf("TransferInfo: expected no error, got %v", err)
+			}
+			if _, err := client.TransferSpeedLimitsMode(); err != nil {
+				t.Errorf("TransferSpeedLimitsMode: expected no error, got %v", err)
+			}
+
+			torrents, err := client.TorrentsInfo()
+			if err != nil || len(torrents) == 0 {
+				t.Fatalf("TorrentsInfo: expected torrents, got %d, %v", len(torrents), err)
+			}
+			// 5.0 renamed the paused states to stopped
+			renamed := []string{qbittorrent.StateStoppedDL, qbittorrent.StateStoppedUP}
+			if strings.HasPrefix(version, "4.") {
+				renamed = []string{qbittorrent.StatePausedDL, qbittorrent.StatePausedUP}
+			}
+			for _, torrent := range torrents {
+				if torrent.Hash == "" || torrent.Name == "" {
+					t.Errorf("TorrentsInfo: expected hash and name, got %+v", torrent)
+				}
+				if qbittorrent.IsPausedState(torrent.State) && !slices.Contains(renamed, torrent.State) {
+					t.Errorf("TorrentsInfo: unexpected state %s for qBittorrent %s", torrent.State, version)
+				}
+			}
+			hash := string(torrents[0].Hash)
+
+			props, err := client.TorrentsProperties(hash)
+			if err != nil || props.PieceSize <= 0 {
+				t.Errorf("TorrentsProperties: expected a piece size, got %+v, %v", props, err)
+			}
+			states, err := client.TorrentsPieceStates(hash)
+			if err != nil || int64(len(states)) != props.PiecesNum {
+				t.Errorf("TorrentsPieceStates: expected %d pieces, got %d, %v", props.PiecesNum, len(states), err)
+			}
+			hashes, err := client.TorrentsPieceHashes(hash)
+			if err != nil || int64(len(hashes)) != props.PiecesNum {
+				t.Errorf("TorrentsPieceHashes: expected %d hashes, got %d, %v", props.PiecesNum, len(hashes), err)
+			}
+			files, err := client.TorrentsFiles(hash)
+			if err != nil || len(files) == 0 || len(files[0].PieceRange) != 2 {
+				t.Errorf("TorrentsFiles: expected files with piece ranges, got %+v, %v", files, err)
+			}
+			if _, err := client.TorrentsTrackers(hash); err != nil {
+				t.Errorf("TorrentsTrackers: expected no error, got %v", err)
+			}
+			if _, err := client.TorrentsCategories(); err != nil {
+				t.Errorf("TorrentsCategories: expected no error, got %v", err)
+			}
+			if _, err := client.TorrentsGetAllTags(); err != nil {
+				t.Errorf("TorrentsGetAllTags: expected no error, got %v", err)
+			}
+
+			mainData, err := client.SyncMainData(0)
+			if err != nil || !mainData.FullUpdate || len(mainData.Torrents) != len(torrents) {
+				t.Errorf("SyncMainData: expected a full update with %d torrents, got %+v, %v", len(torrents), mainData, err)
+			}
+			if _, err := client.SyncTorrentPeers(hash, 0); err != nil {
+				t.Errorf("SyncTorrentPeers: expected no error, got %v", err)
+			}
+
+			entries, err := client.LogMain(true, true, true, true, -1)
+			if err != nil || len(entries) == 0 {
+				t.Errorf("LogMain: expected entries, got %+v, %v", entries, err)
+			}
+			if _, err := client.LogPeers(-1); err != nil {
+				t.Errorf("LogPeers: expected no error, got %v", err)
+			}
+			if _, err := client.RSSItems(false); err != nil {
+				t.Errorf("RSSItems: expected no error, got %v", err)
+			}
+			if _, err := client.RSSRules(); err != nil {
+				t.Errorf("RSSRules: expected no error, got %v", err)
+			}
+			if _, err := client.SearchPlugins(); err != nil {
+				t.Errorf("SearchPlugins: expected no error, got %v", err)
+			}
+
+			got := requested()
+			for _, endpoint := range fixtures.Endpoints(version) {
+				if !got[endpoint] {
+					t.Errorf("fixture %s is not decoded by the test", endpoint)
+				}
+			}
+		})
+	}
+}
+
+func TestResponse(t *testing.T) {
+	body, err := fixtures.Response("5.0.3", "/api/v2/app/version")
+	if err != nil || string(body) != "v5.0.3" {
+		t.Errorf("expected v5.0.3, got %q, %v", body, err)
+	}
+	if _, err := fixtures.Response("5.0.3", "torrents/unknown"); err == nil {
+		t.Error("expected an error for an endpoint without fixture")
+	}
+	if endpoints := fixtures.Endpoints("1.0.0"); len(endpoints) != 0 {
+		t.Errorf("expected no endpoints for an unknown version, got %q", endpoints)
+	}
+}
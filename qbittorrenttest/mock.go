@@ -0,0 +1,178 @@
+package qbittorrenttest
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// MockResponse is a canned response of a MockTransport
+type MockResponse struct {
+	Status int    // HTTP status, 200 if zero
+	Body   string // response body, e.g. JSON
+	Header http.Header
+}
+
+// ExpectedRequest is a request a MockTransport expects. Form and Query are only compared if set; Form holds
+// the url-encoded or multipart form fields of POST requests, files excluded.
+type ExpectedRequest struct {
+	Method string
+	Path   string // e.g. "/api/v2/torrents/info"
+	Form   url.Values
+	Query  url.Values
+}
+
+// MockTransport answers requests with canned responses and checks them against a sequence of expected
+// requests, so code wrapping qbittorrent.Client can be tested for the exact endpoints and parameters it
+// sends without a server. Mismatches are reported to the test, and expected requests that were not made
+// when the test ends fail it. Unlike Server, the mock keeps no state.
+type MockTransport struct {
+	t testing.TB
+
+	mu        sync.Mutex
+	responses map[string][]MockResponse
+	expected  []ExpectedRequest
+	requests  []ExpectedRequest
+}
+
+// NewMockTransport creates a MockTransport reporting to t
+func NewMockTransport(t testing.TB) *MockTransport {
+	m := &MockTransport{t: t, responses: make(map[string][]MockResponse)}
+	t.Cleanup(m.AssertDone)
+	return m
+}
+
+// On sets the responses to requests for path, e.g. "/api/v2/torrents/info", served in order; the last is
+// repeated for further requests. Paths without responses are answered with 200 and an empty body.
+func (m *MockTransport) On(path string, responses ...MockResponse) *MockTransport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[path] = append(m.responses[path], responses...)
+	return m
+}
+
+// Expect appends requests to the sequence of expected requests. Without expectations any request is
+// accepted.
+func (m *MockTransport) Expect(requests ...ExpectedRequest) *MockTransport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expected = append(m.expected, requests...)
+	return m
+}
+
+// NewClient creates a client sending its requests through the transport, without logging in
+func (m *MockTransport) NewClient(opts ...qbittorrent.ClientOption) (*qbittorrent.Client, error) {
+	opts = append([]qbittorrent.ClientOption{
+		qbittorrent.WithHTTPClient(&http.Client{Transport: m}),
+		qbittorrent.WithNoAuth(),
+	}, opts...)
+	return qbittorrent.NewClientWithOptions("", "", "localhost", "8080", opts...)
+}
+
+// Requests returns the requests received so far, with their forms and queries
+func (m *MockTransport) Requests() []ExpectedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]ExpectedRequest(nil), m.requests...)
+}
+
+// AssertDone fails the test if expected requests were not made. NewMockTransport registers it to run when
+// the test ends.
+func (m *MockTransport) AssertDone() {
+	m.t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, missing := range m.expected[min(len(m.requests), len(m.expected)):] {
+		m.t.Errorf("qbittorrenttest: expected request %s %s was not made", missing.Method, missing.Path)
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	got, err := readRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	index := len(m.requests)
+	m.requests = append(m.requests, got)
+	if len(m.expected) > 0 {
+		if index >= len(m.expected) {
+			m.t.Errorf("qbittorrenttest: unexpected request %s %s", got.Method, got.Path)
+		} else if diff := m.expected[index].diff(got); diff != "" {
+			m.t.Errorf("qbittorrenttest: request %d %s %s: %s", index+1, got.Method, got.Path, diff)
+		}
+	}
+	resp := MockResponse{}
+	if queue := m.responses[req.URL.Path]; len(queue) > 0 {
+		resp = queue[0]
+		if len(queue) > 1 {
+			m.responses[req.URL.Path] = queue[1:]
+		}
+	}
+	m.mu.Unlock()
+
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	header := resp.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", resp.Status, http.StatusText(resp.Status)),
+		StatusCode:    resp.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(resp.Body)),
+		ContentLength: int64(len(resp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readRequest returns the method, path, query and form fields of req
+func readRequest(req *http.Request) (ExpectedRequest, error) {
+	got := ExpectedRequest{Method: req.Method, Path: req.URL.Path, Query: req.URL.Query()}
+	if req.Body == nil {
+		return got, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := req.ParseForm(); err != nil {
+			return got, err
+		}
+		got.Form = req.PostForm
+	case "multipart/form-data":
+		if err := req.ParseMultipartForm(32 << 20); err != nil {
+			return got, err
+		}
+		got.Form = url.Values(req.MultipartForm.Value)
+	}
+	return got, nil
+}
+
+// diff describes how got differs from the expected request, "" if it matches
+func (e ExpectedRequest) diff(got ExpectedRequest) string {
+	var diffs []string
+	if e.Method != got.Method || e.Path != got.Path {
+		diffs = append(diffs, fmt.Sprintf("expected %s %s", e.Method, e.Path))
+	}
+	if e.Query != nil && e.Query.Encode() != got.Query.Encode() {
+		diffs = append(diffs, fmt.Sprintf("expected query %q, got %q", e.Query.Encode(), got.Query.Encode()))
+	}
+	if e.Form != nil && e.Form.Encode() != got.Form.Encode() {
+		diffs = append(diffs, fmt.Sprintf("expected form %q, got %q", e.Form.Encode(), got.Form.Encode()))
+	}
+	return strings.Join(diffs, ", ")
+}
@@ -0,0 +1,85 @@
+package qbittorrenttest_test
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+// recordingTB collects the errors reported to it instead of failing the test
+type recordingTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper()           {}
+func (r *recordingTB) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestMockTransport(t *testing.T) {
+	mock := qbittorrenttest.NewMockTransport(t).
+		On("/api/v2/torrents/info",
+			qbittorrenttest.MockResponse{Body: `[{"hash":"abc","name":"one"}]`},
+			qbittorrenttest.MockResponse{Body: `[]`}).
+		Expect(
+			qbittorrenttest.ExpectedRequest{Method: "GET", Path: "/api/v2/torrents/info", Query: url.Values{"category": {"tv"}}},
+			qbittorrenttest.ExpectedRequest{Method: "POST", Path: "/api/v2/torrents/setCategory",
+				Form: url.Values{"hashes": {"abc"}, "category": {"movies"}}},
+			qbittorrenttest.ExpectedRequest{Method: "GET", Path: "/api/v2/torrents/info"},
+		)
+	client, err := mock.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	torrents, err := client.TorrentsInfo(&qbittorrent.TorrentsInfoParams{Category: "tv"})
+	if err != nil || len(torrents) != 1 {
+		t.Fatalf("expected one torrent, got %v, %v", torrents, err)
+	}
+	if err := client.TorrentsSetCategory("abc", "movies"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	torrents, err = client.TorrentsInfo()
+	if err != nil || len(torrents) != 0 {
+		t.Fatalf("expected no torrents, got %v, %v", torrents, err)
+	}
+	if n := len(mock.Requests()); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestMockTransport_Mismatch(t *testing.T) {
+	tb := &recordingTB{}
+	mock := qbittorrenttest.NewMockTransport(tb).
+		On("/api/v2/torrents/setCategory", qbittorrenttest.MockResponse{Status: 409}).
+		Expect(
+			qbittorrenttest.ExpectedRequest{Method: "POST", Path: "/api/v2/torrents/setCategory", Form: url.Values{"hashes": {"abc"}, "category": {"tv"}}},
+			qbittorrenttest.ExpectedRequest{Method: "GET", Path: "/api/v2/torrents/info"},
+		)
+	client, err := mock.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := client.TorrentsSetCategory("abc", "movies"); err == nil {
+		t.Error("expected the canned 409 to fail the request")
+	}
+	for _, cleanup := range tb.cleanups {
+		cleanup()
+	}
+
+	want := []string{
+		`qbittorrenttest: request 1 POST /api/v2/torrents/setCategory: expected form "category=tv&hashes=abc", got "category=movies&hashes=abc"`,
+		"qbittorrenttest: expected request GET /api/v2/torrents/info was not made",
+	}
+	if !reflect.DeepEqual(tb.errors, want) {
+		t.Errorf("expected errors\n%q\ngot\n%q", want, tb.errors)
+	}
+}
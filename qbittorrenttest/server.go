@@ -0,0 +1,692 @@
+// Package qbittorrenttest provides an in-memory fake of the qBittorrent Web API for tests.
+//
+// The fake keeps state across requests: torrents added through the API are listed by torrents/info and
+// sync/maindata, tags and categories can be managed, and sync/maindata returns incremental updates based
+// on the rid sent by the client. Torrents never download; use UpdateTorrent to simulate progress.
+//
+// RecordingTransport and ReplayTransport capture interactions with a real server to a file and serve them
+// back, so tests for server specific responses can run offline. MockTransport answers with canned responses
+// and checks the exact sequence of endpoints and parameters a client sends.
+package qbittorrenttest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// Server is a fake qBittorrent Web API served by an httptest.Server
+type Server struct {
+	*httptest.Server
+
+	username string
+	password string
+
+	mu          sync.Mutex
+	sids        map[string]struct{}
+	torrents    map[qbittorrent.InfoHash]*torrent
+	categories  map[string]string // name to save path
+	tags        map[string]struct{}
+	preferences qbittorrent.Preferences
+	serverState qbittorrent.ServerState
+	rid         int
+	snapshots   map[int]snapshot // sync state sent for recent rids
+
+	appVersion    string
+	webAPIVersion string
+	startStop     bool // emulate qBittorrent 5.x stop/start endpoints and stopped states
+}
+
+// torrent is the stored state of a fake torrent
+type torrent struct {
+	info qbittorrent.TorrentInfo
+	file []byte // the .torrent file it was added from, if any
+}
+
+// NewServer starts a fake server. If username is empty, requests are accepted without logging in.
+func NewServer(username, password string) *Server {
+	s := &Server{
+		username:    username,
+		password:    password,
+		sids:        make(map[string]struct{}),
+		torrents:    make(map[qbittorrent.InfoHash]*torrent),
+		categories:  make(map[string]string),
+		tags:        make(map[string]struct{}),
+		preferences: qbittorrent.Preferences{"save_path": "/downloads/"},
+		serverState: qbittorrent.ServerState{
+			ConnectionStatus: "connected",
+			FreeSpaceOnDisk:  1 << 40,
+			RefreshInterval:  1500,
+		},
+		snapshots:     make(map[int]snapshot),
+		appVersion:    "v4.6.7",
+		webAPIVersion: "2.9.3",
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Addr returns the host and port the server listens on, as expected by qbittorrent.NewClient
+func (s *Server) Addr() (host, port string) {
+	host, port, _ = net.SplitHostPort(strings.TrimPrefix(s.URL, "http://"))
+	return host, port
+}
+
+// NewClient creates a client for the server, logged in with the server's credentials
+func (s *Server) NewClient() (*qbittorrent.Client, error) {
+	host, port := s.Addr()
+	return qbittorrent.NewClient(s.username, s.password, host, port, s.Client())
+}
+
+// AddTorrent stores a torrent directly, bypassing the API. The hash must be set.
+func (s *Server) AddTorrent(info qbittorrent.TorrentInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addTorrent(info, nil)
+}
+
+// UpdateTorrent modifies a stored torrent, e.g. to simulate download progress.
+// It reports whether the torrent exists.
+func (s *Server) UpdateTorrent(hash qbittorrent.InfoHash, update func(*qbittorrent.TorrentInfo)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.torrents[hash]
+	if ok {
+		update(&t.info)
+	}
+	return ok
+}
+
+// SetVersion sets the versions reported by the server. A Web API version of 2.11.0 or later, as shipped with
+// qBittorrent 5.x, makes the server use the stop/start endpoints and stopped states instead of pause/resume.
+func (s *Server) SetVersion(appVersion, webAPIVersion string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.appVersion = appVersion
+	s.webAPIVersion = webAPIVersion
+	v, err := qbittorrent.ParseAPIVersion(webAPIVersion)
+	s.startStop = err == nil && v.AtLeast(qbittorrent.APIVersionStartStop)
+}
+
+// UpdateServerState modifies the server state reported by sync/maindata
+func (s *Server) UpdateServerState(update func(*qbittorrent.ServerState)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	update(&s.serverState)
+}
+
+// Torrents returns the stored torrents sorted by hash
+func (s *Server) Torrents() []qbittorrent.TorrentInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sortedTorrents()
+}
+
+func (s *Server) addTorrent(info qbittorrent.TorrentInfo, file []byte) {
+	if info.Tags == nil {
+		info.Tags = []string{}
+	}
+	for _, tag := range info.Tags {
+		s.tags[tag] = struct{}{}
+	}
+	if info.Category != "" {
+		if _, ok := s.categories[info.Category]; !ok {
+			s.categories[info.Category] = ""
+		}
+	}
+	s.torrents[info.Hash] = &torrent{info: info, file: file}
+}
+
+func (s *Server) sortedTorrents() []qbittorrent.TorrentInfo {
+	out := make([]qbittorrent.TorrentInfo, 0, len(s.torrents))
+	for _, t := range s.torrents {
+		info := t.info
+		info.Tags = append([]string{}, t.info.Tags...)
+		out = append(out, info)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Hash < out[j].Hash })
+	return out
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api/v2/auth/login" {
+		s.login(w, r)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	handler, ok := s.routes()[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.ParseMultipartForm(32 << 20)
+	} else {
+		r.ParseForm()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	handler(w, r)
+}
+
+func (s *Server) routes() map[string]http.HandlerFunc {
+	return map[string]http.HandlerFunc{
+		"/api/v2/app/preferences":           s.appPreferences,
+		"/api/v2/app/setPreferences":        s.appSetPreferences,
+		"/api/v2/app/version":               s.appVersionHandler,
+		"/api/v2/app/webapiVersion":         s.appWebAPIVersion,
+		"/api/v2/sync/maindata":             s.syncMainData,
+		"/api/v2/torrents/info":             s.torrentsInfo,
+		"/api/v2/torrents/add":              s.torrentsAdd,
+		"/api/v2/torrents/delete":           s.torrentsDelete,
+		"/api/v2/torrents/export":           s.torrentsExport,
+		"/api/v2/torrents/trackers":         s.torrentsTrackers,
+		"/api/v2/torrents/files":            s.torrentsFiles,
+		"/api/v2/torrents/pause":            s.torrentsPause,
+		"/api/v2/torrents/resume":           s.torrentsResume,
+		"/api/v2/torrents/stop":             s.torrentsPause,
+		"/api/v2/torrents/start":            s.torrentsResume,
+		"/api/v2/torrents/tags":             s.torrentsTags,
+		"/api/v2/torrents/createTags":       s.torrentsCreateTags,
+		"/api/v2/torrents/deleteTags":       s.torrentsDeleteTags,
+		"/api/v2/torrents/addTags":          s.torrentsAddTags,
+		"/api/v2/torrents/removeTags":       s.torrentsRemoveTags,
+		"/api/v2/torrents/categories":       s.torrentsCategories,
+		"/api/v2/torrents/createCategory":   s.torrentsCreateCategory,
+		"/api/v2/torrents/editCategory":     s.torrentsEditCategory,
+		"/api/v2/torrents/removeCategories": s.torrentsRemoveCategories,
+		"/api/v2/torrents/setCategory":      s.torrentsSetCategory,
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	if r.PostForm.Get("username") != s.username || r.PostForm.Get("password") != s.password {
+		w.Write([]byte("Fails."))
+		return
+	}
+
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	sid := hex.EncodeToString(buf)
+
+	s.mu.Lock()
+	s.sids[sid] = struct{}{}
+	s.mu.Unlock()
+
+	http.SetCookie(w, &http.Cookie{Name: "SID", Value: sid, Path: "/"})
+	w.Write([]byte("Ok."))
+}
+
+// ExpireSessions invalidates all sessions, so clients have to log in again
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sids = make(map[string]struct{})
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	if s.username == "" {
+		return true
+	}
+	cookie, err := r.Cookie("SID")
+	if err != nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.sids[cookie.Value]
+	return ok
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// hashes returns the torrents selected by the "hashes" (or "hash") parameter, "all" selects every torrent
+func (s *Server) hashes(r *http.Request) []qbittorrent.InfoHash {
+	value := r.Form.Get("hashes")
+	if value == "" {
+		value = r.Form.Get("hash")
+	}
+	var out []qbittorrent.InfoHash
+	if value == "all" {
+		for hash := range s.torrents {
+			out = append(out, hash)
+		}
+		return out
+	}
+	for _, hash := range strings.Split(value, "|") {
+		if _, ok := s.torrents[qbittorrent.InfoHash(hash)]; ok {
+			out = append(out, qbittorrent.InfoHash(hash))
+		}
+	}
+	return out
+}
+
+// splitList splits a comma separated parameter, dropping empty entries
+func splitList(value string) []string {
+	var out []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (s *Server) appPreferences(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.preferences)
+}
+
+func (s *Server) appSetPreferences(w http.ResponseWriter, r *http.Request) {
+	var prefs qbittorrent.Preferences
+	if err := json.Unmarshal([]byte(r.Form.Get("json")), &prefs); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	for key, value := range prefs {
+		s.preferences[key] = value
+	}
+}
+
+func (s *Server) torrentsInfo(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var wantHashes map[string]struct{}
+	if hashes := query.Get("hashes"); hashes != "" {
+		wantHashes = make(map[string]struct{})
+		for _, hash := range strings.Split(hashes, "|") {
+			wantHashes[hash] = struct{}{}
+		}
+	}
+	category, hasCategory := query["category"]
+	tag, hasTag := query["tag"]
+
+	out := []json.RawMessage{}
+	for _, info := range s.sortedTorrents() {
+		if wantHashes != nil {
+			if _, ok := wantHashes[string(info.Hash)]; !ok {
+				continue
+			}
+		}
+		if hasCategory && info.Category != category[0] {
+			continue
+		}
+		if hasTag && !containsTag(info.Tags, tag[0]) {
+			continue
+		}
+		out = append(out, torrentJSON(info))
+	}
+	writeJSON(w, out)
+}
+
+func containsTag(tags []string, tag string) bool {
+	if tag == "" {
+		return len(tags) == 0
+	}
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// torrentJSON encodes a torrent the way the server does, with tags as a ", " separated string
+func torrentJSON(info qbittorrent.TorrentInfo) json.RawMessage {
+	fields := torrentFields(info)
+	data, _ := json.Marshal(fields)
+	return data
+}
+
+func torrentFields(info qbittorrent.TorrentInfo) map[string]interface{} {
+	data, _ := json.Marshal(info)
+	var fields map[string]interface{}
+	json.Unmarshal(data, &fields)
+	fields["tags"] = strings.Join(info.Tags, ", ")
+	return fields
+}
+
+func (s *Server) torrentsAdd(w http.ResponseWriter, r *http.Request) {
+	form := r.Form
+	if r.MultipartForm != nil {
+		form = url.Values(r.MultipartForm.Value)
+	}
+
+	template := qbittorrent.TorrentInfo{
+		AddedOn:  time.Now().Unix(),
+		Category: form.Get("category"),
+		SavePath: form.Get("savepath"),
+		State:    "downloading",
+		Tags:     splitList(form.Get("tags")),
+	}
+	if template.SavePath == "" {
+		template.SavePath, _ = s.preferences["save_path"].(string)
+	}
+	if form.Get("paused") == "true" || form.Get("stopped") == "true" {
+		template.State = qbittorrent.StatePausedDL
+		if s.startStop {
+			template.State = qbittorrent.StateStoppedDL
+		}
+	}
+
+	added := 0
+	if r.MultipartForm != nil {
+		for _, header := range r.MultipartForm.File["torrents"] {
+			f, err := header.Open()
+			if err != nil {
+				continue
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				continue
+			}
+
+			meta, err := qbittorrent.ParseMetainfo(data)
+			if err != nil {
+				continue
+			}
+			if _, exists := s.torrents[meta.InfoHash]; exists {
+				continue
+			}
+			info := template
+			info.Hash = meta.InfoHash
+			info.Name = meta.Name
+			info.Size = meta.Size
+			info.TotalSize = meta.Size
+			info.AmountLeft = meta.Size
+			info.IsPrivate = meta.Private
+			s.addTorrent(info, data)
+			added++
+		}
+	}
+	for _, link := range strings.Split(form.Get("urls"), "\n") {
+		hash, name, ok := parseMagnet(strings.TrimSpace(link))
+		if !ok {
+			continue
+		}
+		if _, exists := s.torrents[hash]; exists {
+			continue
+		}
+		info := template
+		info.Hash = hash
+		info.Name = name
+		info.MagnetURI = link
+		info.State = "metaDL"
+		s.addTorrent(info, nil)
+		added++
+	}
+
+	if added == 0 {
+		w.Write([]byte("Fails."))
+		return
+	}
+	w.Write([]byte("Ok."))
+}
+
+// parseMagnet extracts the v1 infohash and display name of a hex encoded magnet link
+func parseMagnet(link string) (qbittorrent.InfoHash, string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "magnet" {
+		return "", "", false
+	}
+	query, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return "", "", false
+	}
+	for _, xt := range query["xt"] {
+		if hash, ok := strings.CutPrefix(xt, "urn:btih:"); ok && len(hash) == 40 {
+			name := query.Get("dn")
+			if name == "" {
+				name = strings.ToLower(hash)
+			}
+			return qbittorrent.InfoHash(strings.ToLower(hash)), name, true
+		}
+	}
+	return "", "", false
+}
+
+func (s *Server) torrentsDelete(w http.ResponseWriter, r *http.Request) {
+	for _, hash := range s.hashes(r) {
+		delete(s.torrents, hash)
+	}
+}
+
+func (s *Server) torrentsExport(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[qbittorrent.InfoHash(r.Form.Get("hash"))]
+	if !ok || t.file == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/x-bittorrent")
+	w.Write(t.file)
+}
+
+func (s *Server) torrentsTrackers(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[qbittorrent.InfoHash(r.Form.Get("hash"))]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	trackers := []qbittorrent.TrackerInfo{
+		{URL: "** [DHT] **", Status: qbittorrent.TrackerWorking},
+		{URL: "** [PeX] **", Status: qbittorrent.TrackerWorking},
+		{URL: "** [LSD] **", Status: qbittorrent.TrackerWorking},
+	}
+	if t.info.Tracker != "" {
+		trackers = append(trackers, qbittorrent.TrackerInfo{URL: t.info.Tracker, Status: qbittorrent.TrackerWorking})
+	}
+	writeJSON(w, trackers)
+}
+
+// torrentsFiles lists the files of torrents added from a .torrent file, magnet links have none
+func (s *Server) torrentsFiles(w http.ResponseWriter, r *http.Request) {
+	t, ok := s.torrents[qbittorrent.InfoHash(r.Form.Get("hash"))]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	files := []qbittorrent.TorrentFile{}
+	if meta, err := qbittorrent.ParseMetainfo(t.file); err == nil {
+		for i, f := range meta.Files {
+			name := f.Path
+			if len(meta.Files) > 1 || name != meta.Name {
+				name = meta.Name + "/" + f.Path
+			}
+			files = append(files, qbittorrent.TorrentFile{Index: i, Name: name, Size: f.Length, Progress: t.info.Progress})
+		}
+	}
+	writeJSON(w, files)
+}
+
+func (s *Server) sortedTags() []string {
+	tags := make([]string, 0, len(s.tags))
+	for tag := range s.tags {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+func (s *Server) torrentsTags(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.sortedTags())
+}
+
+func (s *Server) torrentsCreateTags(w http.ResponseWriter, r *http.Request) {
+	for _, tag := range splitList(r.Form.Get("tags")) {
+		s.tags[tag] = struct{}{}
+	}
+}
+
+func (s *Server) torrentsDeleteTags(w http.ResponseWriter, r *http.Request) {
+	for _, tag := range splitList(r.Form.Get("tags")) {
+		delete(s.tags, tag)
+		for _, t := range s.torrents {
+			t.info.Tags = removeTag(t.info.Tags, tag)
+		}
+	}
+}
+
+func (s *Server) torrentsAddTags(w http.ResponseWriter, r *http.Request) {
+	tags := splitList(r.Form.Get("tags"))
+	for _, hash := range s.hashes(r) {
+		t := s.torrents[hash]
+		for _, tag := range tags {
+			s.tags[tag] = struct{}{}
+			if !containsTag(t.info.Tags, tag) {
+				t.info.Tags = append(t.info.Tags, tag)
+			}
+		}
+		sort.Strings(t.info.Tags)
+	}
+}
+
+func (s *Server) torrentsRemoveTags(w http.ResponseWriter, r *http.Request) {
+	tags := splitList(r.Form.Get("tags"))
+	for _, hash := range s.hashes(r) {
+		t := s.torrents[hash]
+		if len(tags) == 0 {
+			t.info.Tags = []string{}
+		}
+		for _, tag := range tags {
+			t.info.Tags = removeTag(t.info.Tags, tag)
+		}
+	}
+}
+
+func removeTag(tags []string, tag string) []string {
+	out := tags[:0]
+	for _, t := range tags {
+		if t != tag {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func (s *Server) categoriesJSON() map[string]qbittorrent.Category {
+	out := make(map[string]qbittorrent.Category, len(s.categories))
+	for name, savePath := range s.categories {
+		out[name] = qbittorrent.Category{"name": name, "savePath": savePath}
+	}
+	return out
+}
+
+func (s *Server) torrentsCategories(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.categoriesJSON())
+}
+
+func (s *Server) torrentsCreateCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("category")
+	if name == "" {
+		http.Error(w, "Invalid category name", http.StatusBadRequest)
+		return
+	}
+	if _, ok := s.categories[name]; ok {
+		http.Error(w, "Category already exists", http.StatusConflict)
+		return
+	}
+	s.categories[name] = r.Form.Get("savePath")
+}
+
+func (s *Server) torrentsEditCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("category")
+	if _, ok := s.categories[name]; !ok {
+		http.Error(w, "Category doesn't exist", http.StatusConflict)
+		return
+	}
+	s.categories[name] = r.Form.Get("savePath")
+}
+
+func (s *Server) torrentsRemoveCategories(w http.ResponseWriter, r *http.Request) {
+	for _, name := range strings.Split(r.Form.Get("categories"), "\n") {
+		delete(s.categories, name)
+		for _, t := range s.torrents {
+			if t.info.Category == name {
+				t.info.Category = ""
+			}
+		}
+	}
+}
+
+func (s *Server) torrentsSetCategory(w http.ResponseWriter, r *http.Request) {
+	name := r.Form.Get("category")
+	if _, ok := s.categories[name]; name != "" && !ok {
+		http.Error(w, "Category doesn't exist", http.StatusConflict)
+		return
+	}
+	for _, hash := range s.hashes(r) {
+		s.torrents[hash].info.Category = name
+	}
+}
+
+func (s *Server) appVersionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(s.appVersion))
+}
+
+func (s *Server) appWebAPIVersion(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(s.webAPIVersion))
+}
+
+// stateEndpointAvailable reports whether the pause/resume or stop/start endpoint requested exists in
+// the emulated version, writing a 404 if not
+func (s *Server) stateEndpointAvailable(w http.ResponseWriter, r *http.Request) bool {
+	startStop := strings.HasSuffix(r.URL.Path, "/stop") || strings.HasSuffix(r.URL.Path, "/start")
+	if startStop != s.startStop {
+		http.NotFound(w, r)
+		return false
+	}
+	return true
+}
+
+func (s *Server) torrentsPause(w http.ResponseWriter, r *http.Request) {
+	if !s.stateEndpointAvailable(w, r) {
+		return
+	}
+	for _, hash := range s.hashes(r) {
+		info := &s.torrents[hash].info
+		switch {
+		case info.Progress >= 1 && s.startStop:
+			info.State = qbittorrent.StateStoppedUP
+		case info.Progress >= 1:
+			info.State = qbittorrent.StatePausedUP
+		case s.startStop:
+			info.State = qbittorrent.StateStoppedDL
+		default:
+			info.State = qbittorrent.StatePausedDL
+		}
+	}
+}
+
+func (s *Server) torrentsResume(w http.ResponseWriter, r *http.Request) {
+	if !s.stateEndpointAvailable(w, r) {
+		return
+	}
+	for _, hash := range s.hashes(r) {
+		info := &s.torrents[hash].info
+		switch info.State {
+		case qbittorrent.StatePausedUP, qbittorrent.StateStoppedUP:
+			info.State = "stalledUP"
+		case qbittorrent.StatePausedDL, qbittorrent.StateStoppedDL:
+			info.State = "stalledDL"
+		}
+	}
+}
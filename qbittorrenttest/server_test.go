@@ -0,0 +1,151 @@
+package qbittorrenttest_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+const magnet = "magnet:?xt=urn:btih:0123456789abcdef0123456789abcdef01234567&dn=Example"
+
+func TestServer_TorrentsLifecycle(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := client.TorrentsCreateCategory("tv", "/tv"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := client.TorrentsAddURLs([]string{magnet}, qbittorrent.WithCategory("tv"), qbittorrent.WithTags([]string{"new"})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	torrents, err := client.TorrentsInfo(&qbittorrent.TorrentsInfoParams{Category: "tv"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(torrents) != 1 || torrents[0].Name != "Example" || torrents[0].State != "metaDL" {
+		t.Fatalf("unexpected torrents %+v", torrents)
+	}
+	hash := string(torrents[0].Hash)
+
+	if err := client.TorrentsAddTags(hash, []string{"hd"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	tags, err := client.TorrentsGetAllTags()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"hd", "new"}) {
+		t.Errorf("expected tags [hd new], got %v", tags)
+	}
+
+	if err := client.TorrentsDelete(hash); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(server.Torrents()) != 0 {
+		t.Errorf("expected no torrents after delete")
+	}
+}
+
+func TestServer_ReauthenticatesExpiredSession(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	server.ExpireSessions()
+	if _, err := client.TorrentsInfo(); err != nil {
+		t.Fatalf("expected the client to log in again, got %v", err)
+	}
+}
+
+func TestServer_SyncDeltas(t *testing.T) {
+	server := qbittorrenttest.NewServer("", "")
+	defer server.Close()
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "aaa", Name: "A", State: "downloading", Progress: 0.5})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	full, err := client.SyncMainData(0)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !full.FullUpdate || full.Torrents["aaa"].Name != "A" {
+		t.Fatalf("expected a full update with torrent A, got %+v", full)
+	}
+
+	server.UpdateTorrent("aaa", func(info *qbittorrent.TorrentInfo) {
+		info.Progress = 1
+		info.State = "uploading"
+	})
+	delta, err := client.SyncMainData(full.Rid)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if delta.FullUpdate || delta.Torrents["aaa"].Name != "" || delta.Torrents["aaa"].State != "uploading" {
+		t.Errorf("expected a delta with only the changed fields, got %+v", delta.Torrents["aaa"])
+	}
+
+	// The SyncWatcher turns the deltas into events
+	watcher := qbittorrent.NewSyncWatcher(client, qbittorrent.SyncWatcherConfig{})
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "bbb", Name: "B"})
+	events, err := watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Type != qbittorrent.EventTorrentAdded || events[0].Hash != "bbb" {
+		t.Errorf("expected an added event for bbb, got %+v", events)
+	}
+}
+
+func TestServer_StartStop(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+	server.SetVersion("v5.0.3", "2.11.2")
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "abc", Name: "Example", State: "uploading", Progress: 1})
+
+	host, port := server.Addr()
+	client, err := qbittorrent.NewClientWithOptions("admin", "secret", host, port,
+		qbittorrent.WithHTTPClient(server.Client()), qbittorrent.WithStateNormalization(qbittorrent.StatesPaused))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if err := client.TorrentsPause("abc"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if state := server.Torrents()[0].State; state != qbittorrent.StateStoppedUP {
+		t.Errorf("expected the server to report %s, got %s", qbittorrent.StateStoppedUP, state)
+	}
+	torrents, err := client.TorrentsInfo()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if torrents[0].State != qbittorrent.StatePausedUP {
+		t.Errorf("expected the client to report %s, got %s", qbittorrent.StatePausedUP, torrents[0].State)
+	}
+
+	if err := client.TorrentsResume("abc"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if state := server.Torrents()[0].State; state != "stalledUP" {
+		t.Errorf("expected stalledUP, got %s", state)
+	}
+}
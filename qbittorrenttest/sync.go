@@ -0,0 +1,158 @@
+package qbittorrenttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+)
+
+// maxSnapshots is the number of past sync states kept to answer incremental requests
+const maxSnapshots = 16
+
+// snapshot is the sync state sent to clients for a rid, in its JSON form so fields can be compared
+type snapshot struct {
+	torrents    map[string]map[string]interface{}
+	categories  map[string]qbittorrent.Category
+	tags        map[string]struct{}
+	trackers    map[string][]string
+	serverState map[string]interface{}
+}
+
+func (s *Server) snapshot() snapshot {
+	snap := snapshot{
+		torrents:   make(map[string]map[string]interface{}, len(s.torrents)),
+		categories: s.categoriesJSON(),
+		tags:       make(map[string]struct{}, len(s.tags)),
+		trackers:   make(map[string][]string),
+	}
+	state := s.serverState
+	for hash, t := range s.torrents {
+		fields := torrentFields(t.info)
+		delete(fields, "hash")
+		snap.torrents[string(hash)] = fields
+		state.DLInfoSpeed += int(t.info.DLSpeed)
+		state.UpInfoSpeed += int(t.info.UpSpeed)
+		if t.info.Tracker != "" {
+			snap.trackers[t.info.Tracker] = append(snap.trackers[t.info.Tracker], string(hash))
+		}
+	}
+	for tag := range s.tags {
+		snap.tags[tag] = struct{}{}
+	}
+	data, _ := json.Marshal(state)
+	json.Unmarshal(data, &snap.serverState)
+	return snap
+}
+
+func (s *Server) syncMainData(w http.ResponseWriter, r *http.Request) {
+	rid, _ := strconv.Atoi(r.URL.Query().Get("rid"))
+	current := s.snapshot()
+	previous, incremental := s.snapshots[rid]
+
+	s.rid++
+	s.snapshots[s.rid] = current
+	delete(s.snapshots, s.rid-maxSnapshots)
+
+	if !incremental {
+		tags := make([]string, 0, len(current.tags))
+		for tag := range current.tags {
+			tags = append(tags, tag)
+		}
+		writeJSON(w, map[string]interface{}{
+			"rid":          s.rid,
+			"full_update":  true,
+			"torrents":     current.torrents,
+			"categories":   current.categories,
+			"tags":         tags,
+			"trackers":     current.trackers,
+			"server_state": current.serverState,
+		})
+		return
+	}
+
+	resp := map[string]interface{}{"rid": s.rid}
+
+	torrents := make(map[string]map[string]interface{})
+	for hash, fields := range current.torrents {
+		if changed := changedFields(previous.torrents[hash], fields); len(changed) > 0 {
+			torrents[hash] = changed
+		}
+	}
+	var removed []string
+	for hash := range previous.torrents {
+		if _, ok := current.torrents[hash]; !ok {
+			removed = append(removed, hash)
+		}
+	}
+	setIfNotEmpty(resp, "torrents", torrents, len(torrents))
+	setIfNotEmpty(resp, "torrents_removed", removed, len(removed))
+
+	categories := make(map[string]qbittorrent.Category)
+	for name, category := range current.categories {
+		if !reflect.DeepEqual(previous.categories[name], category) {
+			categories[name] = category
+		}
+	}
+	var categoriesRemoved []string
+	for name := range previous.categories {
+		if _, ok := current.categories[name]; !ok {
+			categoriesRemoved = append(categoriesRemoved, name)
+		}
+	}
+	setIfNotEmpty(resp, "categories", categories, len(categories))
+	setIfNotEmpty(resp, "categories_removed", categoriesRemoved, len(categoriesRemoved))
+
+	var tags, tagsRemoved []string
+	for tag := range current.tags {
+		if _, ok := previous.tags[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	for tag := range previous.tags {
+		if _, ok := current.tags[tag]; !ok {
+			tagsRemoved = append(tagsRemoved, tag)
+		}
+	}
+	setIfNotEmpty(resp, "tags", tags, len(tags))
+	setIfNotEmpty(resp, "tags_removed", tagsRemoved, len(tagsRemoved))
+
+	trackers := make(map[string][]string)
+	for url, hashes := range current.trackers {
+		if !reflect.DeepEqual(previous.trackers[url], hashes) {
+			trackers[url] = hashes
+		}
+	}
+	var trackersRemoved []string
+	for url := range previous.trackers {
+		if _, ok := current.trackers[url]; !ok {
+			trackersRemoved = append(trackersRemoved, url)
+		}
+	}
+	setIfNotEmpty(resp, "trackers", trackers, len(trackers))
+	setIfNotEmpty(resp, "trackers_removed", trackersRemoved, len(trackersRemoved))
+
+	serverState := changedFields(previous.serverState, current.serverState)
+	setIfNotEmpty(resp, "server_state", serverState, len(serverState))
+
+	writeJSON(w, resp)
+}
+
+// changedFields returns the fields of current that differ from previous
+func changedFields(previous, current map[string]interface{}) map[string]interface{} {
+	changed := make(map[string]interface{})
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || !reflect.DeepEqual(old, value) {
+			changed[key] = value
+		}
+	}
+	return changed
+}
+
+func setIfNotEmpty(resp map[string]interface{}, key string, value interface{}, n int) {
+	if n > 0 {
+		resp[key] = value
+	}
+}
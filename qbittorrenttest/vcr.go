@@ -0,0 +1,251 @@
+package qbittorrenttest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// redacted replaces the password of recorded auth/login requests
+const redacted = "REDACTED"
+
+// Interaction is a single recorded request and the response the server sent for it
+type Interaction struct {
+	Method      string `json:"method"`
+	URI         string `json:"uri"` // path and query, the host is not recorded
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Response   string      `json:"response,omitempty"`
+	Response64 string      `json:"response_base64,omitempty"` // used instead of Response for binary bodies
+}
+
+// Cassette is the file format written by RecordingTransport and read by ReplayTransport
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// RecordingTransport forwards requests to Base and records every interaction.
+// Call Save to write the recording to its file. Passwords sent to auth/login and the session cookies it
+// sets are redacted.
+type RecordingTransport struct {
+	Base http.RoundTripper // transport used for the real requests, http.DefaultTransport if nil
+
+	path     string
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecordingTransport creates a RecordingTransport that saves to path
+func NewRecordingTransport(path string, base http.RoundTripper) *RecordingTransport {
+	return &RecordingTransport{Base: base, path: path}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Method:      req.Method,
+		URI:         req.URL.RequestURI(),
+		ContentType: req.Header.Get("Content-Type"),
+		Body:        redactBody(req.URL.Path, string(body)),
+		Status:      resp.StatusCode,
+		Header:      redactHeader(resp.Header),
+	}
+	if utf8.Valid(respBody) {
+		interaction.Response = string(respBody)
+	} else {
+		interaction.Response64 = base64.StdEncoding.EncodeToString(respBody)
+	}
+
+	t.mu.Lock()
+	t.cassette.Interactions = append(t.cassette.Interactions, interaction)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+// Interactions returns the interactions recorded so far
+func (t *RecordingTransport) Interactions() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Interaction(nil), t.cassette.Interactions...)
+}
+
+// Save writes the recorded interactions to the transport's file
+func (t *RecordingTransport) Save() error {
+	t.mu.Lock()
+	data, err := json.MarshalIndent(t.cassette, "", "  ")
+	t.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("Save error: %v", err)
+	}
+	if err := os.WriteFile(t.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("Save error: %v", err)
+	}
+	return nil
+}
+
+// ReplayTransport serves recorded interactions without a server.
+// A request is answered by the first unused interaction with the same method and URI and,
+// for url-encoded forms, the same body. Multipart bodies are not compared as their boundaries are random.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport loads a recording written by RecordingTransport
+func NewReplayTransport(path string) (*ReplayTransport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("NewReplayTransport error: %v", err)
+	}
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("NewReplayTransport error: %v", err)
+	}
+	return NewReplayTransportFromInteractions(cassette.Interactions), nil
+}
+
+// NewReplayTransportFromInteractions creates a ReplayTransport serving the given interactions
+func NewReplayTransportFromInteractions(interactions []Interaction) *ReplayTransport {
+	return &ReplayTransport{
+		interactions: interactions,
+		used:         make([]bool, len(interactions)),
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body string
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = redactBody(req.URL.Path, string(data))
+	}
+	uri := req.URL.RequestURI()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Method != req.Method || interaction.URI != uri {
+			continue
+		}
+		if isForm(interaction.ContentType) && interaction.Body != body {
+			continue
+		}
+		t.used[i] = true
+		return interaction.response(req)
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, uri)
+}
+
+// Unused returns the recorded interactions that have not been replayed
+func (t *ReplayTransport) Unused() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var unused []Interaction
+	for i, interaction := range t.interactions {
+		if !t.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+	return unused
+}
+
+// response builds the recorded response for req
+func (i Interaction) response(req *http.Request) (*http.Response, error) {
+	body := []byte(i.Response)
+	if i.Response64 != "" {
+		var err error
+		if body, err = base64.StdEncoding.DecodeString(i.Response64); err != nil {
+			return nil, fmt.Errorf("invalid recorded response for %s %s: %v", i.Method, i.URI, err)
+		}
+	}
+	header := i.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func isForm(contentType string) bool {
+	return strings.HasPrefix(contentType, "application/x-www-form-urlencoded")
+}
+
+// redactHeader returns a copy of header with the values of Set-Cookie hidden, as the session cookies set
+// by auth/login are credentials. Cookie names and attributes are kept, so clients still log in on replay.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for i, cookie := range header.Values("Set-Cookie") {
+		pair, attributes, _ := strings.Cut(cookie, ";")
+		if name, _, ok := strings.Cut(pair, "="); ok {
+			cookie = name + "=" + redacted
+			if attributes != "" {
+				cookie += ";" + attributes
+			}
+			header["Set-Cookie"][i] = cookie
+		}
+	}
+	return header
+}
+
+// redactBody hides the password of auth/login requests
+func redactBody(path, body string) string {
+	if !strings.HasSuffix(path, "/auth/login") {
+		return body
+	}
+	values, err := url.ParseQuery(body)
+	if err != nil || !values.Has("password") {
+		return body
+	}
+	values.Set("password", redacted)
+	return values.Encode()
+}
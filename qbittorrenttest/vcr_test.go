@@ -0,0 +1,105 @@
+package qbittorrenttest_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nathanaelcunningham/qbittorrent"
+	"github.com/nathanaelcunningham/qbittorrent/qbittorrenttest"
+)
+
+func TestRecordingAndReplayTransport(t *testing.T) {
+	server := qbittorrenttest.NewServer("admin", "secret")
+	defer server.Close()
+	server.AddTorrent(qbittorrent.TorrentInfo{Hash: "abc", Name: "Example", State: "uploading", Progress: 1})
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	recorder := qbittorrenttest.NewRecordingTransport(path, server.Client().Transport)
+	host, port := server.Addr()
+	client, err := qbittorrent.NewClientWithOptions("admin", "secret", host, port,
+		qbittorrent.WithHTTPClient(&http.Client{Transport: recorder}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	recorded, err := client.TorrentsInfo()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := recorder.Save(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("expected the password to be redacted, got %s", data)
+	}
+	cookies := recorder.Interactions()[0].Header.Values("Set-Cookie")
+	if len(cookies) == 0 || !strings.HasPrefix(cookies[0], "SID=REDACTED") {
+		t.Errorf("expected the session cookie to be redacted, got %q", cookies)
+	}
+	server.Close()
+
+	replay, err := qbittorrenttest.NewReplayTransport(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	client, err = qbittorrent.NewClientWithOptions("admin", "other", "offline", "1",
+		qbittorrent.WithHTTPClient(&http.Client{Transport: replay}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	replayed, err := client.TorrentsInfo()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(replayed) != 1 || replayed[0].Hash != recorded[0].Hash || replayed[0].Name != "Example" {
+		t.Errorf("unexpected torrents %+v", replayed)
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("expected every interaction to be replayed, got %+v", unused)
+	}
+
+	if _, err := client.TorrentsInfo(); err == nil {
+		t.Error("expected an error once the recording is exhausted")
+	}
+}
+
+func TestRecordingTransport_BinaryResponse(t *testing.T) {
+	payload := []byte{'d', 0xff, 0xfe, 'e'}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	recorder := qbittorrenttest.NewRecordingTransport(filepath.Join(t.TempDir(), "cassette.json"), nil)
+	resp, err := (&http.Client{Transport: recorder}).Get(server.URL + "/api/v2/torrents/export?hash=abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	resp.Body.Close()
+
+	interactions := recorder.Interactions()
+	if len(interactions) != 1 || interactions[0].Response != "" || interactions[0].Response64 == "" {
+		t.Fatalf("expected a base64 encoded response, got %+v", interactions)
+	}
+
+	replay := qbittorrenttest.NewReplayTransportFromInteractions(interactions)
+	resp, err = (&http.Client{Transport: replay}).Get("http://offline/api/v2/torrents/export?hash=abc")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if !bytes.Equal(body, payload) {
+		t.Errorf("expected %q, got %q", payload, body)
+	}
+}
@@ -0,0 +1,184 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReannouncerConfig configures a Reannouncer. Zero values select the defaults.
+type ReannouncerConfig struct {
+	Interval       time.Duration // how often torrents are checked, default 10s
+	Window         time.Duration // how long after being added a torrent is watched, default 10m
+	InitialBackoff time.Duration // delay before the second reannounce, default 5s
+	MaxBackoff     time.Duration // upper bound of the doubling delay between reannounces, default 2m
+
+	// OnReannounce, if set, is called after each reannounce of a torrent
+	OnReannounce func(hash InfoHash, attempt int)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// Reannouncer re-announces newly added torrents whose trackers report them as unregistered
+// or not working, working around trackers that have not yet registered a fresh upload.
+type Reannouncer struct {
+	client *Client
+	cfg    ReannouncerConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	tracked map[InfoHash]*reannounceState
+}
+
+type reannounceState struct {
+	attempts int
+	next     time.Time
+	backoff  time.Duration
+	done     bool
+}
+
+// NewReannouncer creates a Reannouncer for the given client
+func NewReannouncer(client *Client, cfg ReannouncerConfig) *Reannouncer {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 10 * time.Minute
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 5 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = 2 * time.Minute
+	}
+	return &Reannouncer{
+		client:  client,
+		cfg:     cfg,
+		now:     time.Now,
+		tracked: make(map[InfoHash]*reannounceState),
+	}
+}
+
+// Run checks torrents every Interval until ctx is cancelled
+func (r *Reannouncer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Transient API errors are reported and retried on the next tick
+		if err := r.RunOnce(ctx); err != nil && ctx.Err() == nil && r.cfg.OnError != nil {
+			r.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce performs a single pass over the torrents added within the watch window
+func (r *Reannouncer) RunOnce(ctx context.Context) error {
+	torrents, err := r.client.TorrentsInfo()
+	if err != nil {
+		return fmt.Errorf("Reannouncer error: %w", err)
+	}
+
+	now := r.now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	watched := make(map[InfoHash]struct{})
+	for _, torrent := range torrents {
+		if now.Sub(torrent.AddedAt()) > r.cfg.Window {
+			continue
+		}
+		watched[torrent.Hash] = struct{}{}
+
+		state, ok := r.tracked[torrent.Hash]
+		if !ok {
+			state = &reannounceState{backoff: r.cfg.InitialBackoff}
+			r.tracked[torrent.Hash] = state
+		}
+		if state.done || now.Before(state.next) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		trackers, err := r.client.TorrentsTrackers(string(torrent.Hash))
+		if err != nil {
+			return fmt.Errorf("Reannouncer error: %w", err)
+		}
+
+		switch trackersNeedReannounce(trackers) {
+		case reannounceDone:
+			state.done = true
+		case reannounceWait:
+		case reannounceNow:
+			if err := r.client.TorrentsReannounce(string(torrent.Hash)); err != nil {
+				return fmt.Errorf("Reannouncer error: %w", err)
+			}
+			state.attempts++
+			state.next = now.Add(state.backoff)
+			state.backoff *= 2
+			if state.backoff > r.cfg.MaxBackoff {
+				state.backoff = r.cfg.MaxBackoff
+			}
+			if r.cfg.OnReannounce != nil {
+				r.cfg.OnReannounce(torrent.Hash, state.attempts)
+			}
+		}
+	}
+
+	// Forget torrents that left the window or were removed
+	for hash := range r.tracked {
+		if _, ok := watched[hash]; !ok {
+			delete(r.tracked, hash)
+		}
+	}
+	return nil
+}
+
+type reannounceDecision int
+
+const (
+	reannounceWait reannounceDecision = iota
+	reannounceNow
+	reannounceDone
+)
+
+// trackersNeedReannounce decides what to do with a torrent based on its real (non DHT/PeX/LSD) trackers
+func trackersNeedReannounce(trackers []TrackerInfo) reannounceDecision {
+	failing := false
+	for _, tracker := range trackers {
+		if isPseudoTracker(tracker.URL) {
+			continue
+		}
+		if tracker.Status == TrackerNotWorking || isUnregisteredMessage(tracker.Msg) {
+			failing = true
+			continue
+		}
+		if tracker.Status == TrackerWorking {
+			return reannounceDone
+		}
+	}
+	if failing {
+		return reannounceNow
+	}
+	return reannounceWait
+}
+
+// isPseudoTracker reports whether url is one of the "** [DHT] **" style entries qBittorrent lists as trackers
+func isPseudoTracker(url string) bool {
+	return strings.HasPrefix(url, "** [")
+}
+
+// isUnregisteredMessage reports whether a tracker message indicates the torrent is unknown to the tracker
+func isUnregisteredMessage(msg string) bool {
+	msg = strings.ToLower(msg)
+	return strings.Contains(msg, "unregistered") || strings.Contains(msg, "not registered")
+}
@@ -0,0 +1,110 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestReannouncer_RunOnce(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	trackerResponses := map[string]string{
+		"fresh":   `[{"url":"** [DHT] **","status":2},{"url":"http://t/announce","status":4,"msg":"Torrent not registered"}]`,
+		"working": `[{"url":"http://t/announce","status":2}]`,
+	}
+	reannounced := map[string]int{}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"fresh","added_on":1699999990},
+				{"hash":"working","added_on":1699999990},
+				{"hash":"old","added_on":1600000000}
+			]`))
+		case "/api/v2/torrents/trackers":
+			w.Write([]byte(trackerResponses[r.URL.Query().Get("hash")]))
+		case "/api/v2/torrents/reannounce":
+			r.ParseForm()
+			reannounced[r.PostForm.Get("hashes")]++
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	var attempts []int
+	r := NewReannouncer(client, ReannouncerConfig{
+		InitialBackoff: 10 * time.Second,
+		OnReannounce: func(hash InfoHash, attempt int) {
+			attempts = append(attempts, attempt)
+		},
+	})
+	r.now = func() time.Time { return now }
+
+	// First pass reannounces the failing torrent only
+	if err := r.RunOnce(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if reannounced["fresh"] != 1 || reannounced["working"] != 0 || reannounced["old"] != 0 {
+		t.Fatalf("unexpected reannounces %v", reannounced)
+	}
+
+	// Within the backoff nothing happens
+	now = now.Add(5 * time.Second)
+	r.RunOnce(context.Background())
+	if reannounced["fresh"] != 1 {
+		t.Fatalf("expected backoff to suppress reannounce, got %d", reannounced["fresh"])
+	}
+
+	// After the backoff the torrent is reannounced again
+	now = now.Add(6 * time.Second)
+	r.RunOnce(context.Background())
+	if reannounced["fresh"] != 2 {
+		t.Fatalf("expected second reannounce, got %d", reannounced["fresh"])
+	}
+
+	// Once the tracker works the torrent is no longer reannounced
+	trackerResponses["fresh"] = `[{"url":"http://t/announce","status":2}]`
+	now = now.Add(time.Minute)
+	r.RunOnce(context.Background())
+	now = now.Add(time.Minute)
+	r.RunOnce(context.Background())
+	if reannounced["fresh"] != 2 {
+		t.Fatalf("expected no further reannounces, got %d", reannounced["fresh"])
+	}
+
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Errorf("unexpected attempts %v", attempts)
+	}
+}
+
+func TestTrackersNeedReannounce(t *testing.T) {
+	tests := []struct {
+		name     string
+		trackers []TrackerInfo
+		want     reannounceDecision
+	}{
+		{"no trackers", nil, reannounceWait},
+		{"not contacted", []TrackerInfo{{URL: "http://t", Status: 1}}, reannounceWait},
+		{"not working", []TrackerInfo{{URL: "http://t", Status: 4}}, reannounceNow},
+		{"unregistered message", []TrackerInfo{{URL: "http://t", Status: 3, Msg: "Unregistered torrent"}}, reannounceNow},
+		{"one working", []TrackerInfo{{URL: "http://a", Status: 4}, {URL: "http://b", Status: 2}}, reannounceDone},
+		{"only pseudo trackers", []TrackerInfo{{URL: "** [PeX] **", Status: 2}}, reannounceWait},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trackersNeedReannounce(tt.trackers); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
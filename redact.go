@@ -0,0 +1,148 @@
+package qbittorrent
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces secrets removed by Redact
+const Redacted = "REDACTED"
+
+// urlPattern matches the URLs and magnet links embedded in free text
+var urlPattern = regexp.MustCompile(`(?i)\b(?:https?|udp|wss?)://[^\s"'<>]+|\bmagnet:\?[^\s"'<>]+`)
+
+// secretParams are query parameters known to carry credentials, compared in lower case
+var secretParams = map[string]bool{
+	"passkey":      true,
+	"authkey":      true,
+	"torrent_pass": true,
+	"torrent_key":  true,
+	"pk":           true,
+	"key":          true,
+	"apikey":       true,
+	"api_key":      true,
+	"token":        true,
+	"access_token": true,
+	"secret":       true,
+	"password":     true,
+	"pass":         true,
+	"sid":          true,
+}
+
+// Redact removes announce keys and other credentials from the URLs and magnet links in s, which may be a
+// single URL or free text such as an error message. Passwords of URLs, query parameters such as passkey or
+// token, and path segments that look like keys are replaced with Redacted. The trackers of magnet links
+// are redacted the same way, their info hash and name are kept.
+func Redact(s string) string {
+	return urlPattern.ReplaceAllStringFunc(s, redactURL)
+}
+
+// redactURL redacts a single URL or magnet link, leaving it unchanged if it cannot be parsed
+func redactURL(raw string) string {
+	if strings.HasPrefix(strings.ToLower(raw), "magnet:") {
+		return redactMagnet(raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	changed := false
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+			changed = true
+		}
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if looksLikeKey(segment) {
+			segments[i] = Redacted
+			changed = true
+		}
+	}
+	if query, ok := redactQuery(u.RawQuery); ok {
+		u.RawQuery = query
+		changed = true
+	}
+	if !changed {
+		return raw
+	}
+	u.Path, u.RawPath = strings.Join(segments, "/"), ""
+	return u.String()
+}
+
+// redactMagnet redacts the tracker URLs of a magnet link
+func redactMagnet(raw string) string {
+	query := raw[len("magnet:?"):]
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(key) {
+		case "tr", "ws", "as", "xs":
+			decoded, err := url.QueryUnescape(value)
+			if err != nil {
+				continue
+			}
+			if redacted := redactURL(decoded); redacted != decoded {
+				params[i] = key + "=" + url.QueryEscape(redacted)
+			}
+		}
+	}
+	return raw[:len("magnet:?")] + strings.Join(params, "&")
+}
+
+// redactQuery replaces the values of secretParams in a raw query and redacts the URLs passed as values,
+// preserving the order of parameters, and reports whether it changed anything
+func redactQuery(rawQuery string) (string, bool) {
+	if rawQuery == "" {
+		return rawQuery, false
+	}
+	changed := false
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, value, _ := strings.Cut(param, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if secretParams[strings.ToLower(name)] && value != "" {
+			params[i] = key + "=" + Redacted
+			changed = true
+			continue
+		}
+		// Parameters may themselves hold URLs, e.g. a tracker URL passed to the Web API
+		if decoded, err := url.QueryUnescape(value); err == nil {
+			if redacted := Redact(decoded); redacted != decoded {
+				params[i] = key + "=" + url.QueryEscape(redacted)
+				changed = true
+			}
+		}
+	}
+	return strings.Join(params, "&"), changed
+}
+
+// looksLikeKey reports whether s looks like a generated key: at least 16 characters of letters, digits,
+// '-' and '_', with both letters and digits
+func looksLikeKey(s string) bool {
+	if len(s) < 16 {
+		return false
+	}
+	letters, digits := false, false
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits = true
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+			letters = true
+		case r == '-' || r == '_':
+		default:
+			return false
+		}
+	}
+	return letters && digits
+}
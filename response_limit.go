@@ -0,0 +1,116 @@
+package qbittorrent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxResponseSize is the response body size limit of clients without WithMaxResponseSize. It is
+// well above the size of a full sync/maindata response of tens of thousands of torrents.
+const DefaultMaxResponseSize = 128 << 20
+
+// errorBodyLimit bounds how much of an error response is read into the error message, error pages of
+// reverse proxies can be large
+const errorBodyLimit = 4 << 10
+
+// WithMaxResponseSize limits the size of the response bodies the client buffers to n bytes, larger
+// responses fail with ErrResponseTooLarge. A negative n removes the limit. Streaming methods such as
+// TorrentsInfoEach are not limited since they never hold the whole response.
+func WithMaxResponseSize(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// responseLimit returns the effective response size limit, negative for none
+func (c *Client) responseLimit() int64 {
+	if c.maxResponseSize == 0 {
+		return DefaultMaxResponseSize
+	}
+	return c.maxResponseSize
+}
+
+// readBody reads the body of a response to endpoint, failing with ErrResponseTooLarge beyond the limit
+func (c *Client) readBody(resp *http.Response, endpoint string) ([]byte, error) {
+	limit := c.responseLimit()
+	if limit < 0 {
+		return readAll(resp.Body, resp.ContentLength)
+	}
+	if resp.ContentLength > limit {
+		return nil, fmt.Errorf("%w: %s: %d bytes exceed the limit of %d", ErrResponseTooLarge, endpoint, resp.ContentLength, limit)
+	}
+	data, err := readAll(io.LimitReader(resp.Body, limit+1), resp.ContentLength)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s: more than %d bytes", ErrResponseTooLarge, endpoint, limit)
+	}
+	return data, nil
+}
+
+// maxPreallocation bounds the buffer readAll allocates upfront. The Content-Length is only a claim of the
+// server, so without a response size limit a larger body grows the buffer as it actually arrives.
+const maxPreallocation = DefaultMaxResponseSize
+
+// readAll reads r to the end like io.ReadAll, but allocates the buffer once when size, the Content-Length
+// of the response, is known, instead of growing it repeatedly for large responses such as sync/maindata
+func readAll(r io.Reader, size int64) ([]byte, error) {
+	if size <= 0 {
+		return io.ReadAll(r)
+	}
+	var buf bytes.Buffer
+	buf.Grow(int(min(size, maxPreallocation)) + bytes.MinRead)
+	_, err := buf.ReadFrom(r)
+	return buf.Bytes(), err
+}
+
+// readErrorBody reads the beginning of an error response for use in an error message
+func readErrorBody(resp *http.Response) string {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, errorBodyLimit+1))
+	if len(data) > errorBodyLimit {
+		return strings.ToValidUTF8(string(data[:errorBodyLimit]), "") + "..."
+	}
+	return string(data)
+}
+
+// TorrentsInfoEach calls fn for every torrent matching params, decoding the response as it arrives
+// instead of holding all torrents in memory, and stops at the first error returned by fn
+func (c *Client) TorrentsInfoEach(fn func(TorrentInfo) error, params ...*TorrentsInfoParams) error {
+	query, err := c.torrentsInfoQuery(params...)
+	if err != nil {
+		return fmt.Errorf("TorrentsInfoEach error: %w", err)
+	}
+	resp, err := c.doRequest("GET", "/api/v2/torrents/info", nil, "", withQuery(query))
+	if err != nil {
+		return fmt.Errorf("TorrentsInfoEach error: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("TorrentsInfoEach error: %w", c.newStatusError(resp, "/api/v2/torrents/info", query))
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return fmt.Errorf("TorrentsInfoEach error: failed to decode response: expected an array")
+	}
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return fmt.Errorf("TorrentsInfoEach error: failed to decode response: %v", err)
+		}
+		var torrent TorrentInfo
+		if err := c.decodeJSON(raw, &torrent); err != nil {
+			return fmt.Errorf("TorrentsInfoEach error: failed to decode response: %v", err)
+		}
+		torrent.State = c.stateNormalization.normalize(torrent.State)
+		if err := fn(torrent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWithMaxResponseSize(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/small":
+			w.Write([]byte("0123456789"))
+		case "/api/v2/chunked":
+			// Flushing before the end forces a chunked response without Content-Length
+			w.Write([]byte("0123456789"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("0123456789"))
+		case "/api/v2/proxy-error":
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte(strings.Repeat("x", 10000)))
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithMaxResponseSize(15)(client)
+
+	if data, err := client.doGet("/api/v2/small", nil); err != nil || string(data) != "0123456789" {
+		t.Errorf("expected the small response, got %q, %v", data, err)
+	}
+	if _, err := client.doGet("/api/v2/chunked", nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+	if _, err := client.doPostValues("/api/v2/chunked", nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("expected ErrResponseTooLarge, got %v", err)
+	}
+
+	_, err := client.doGet("/api/v2/proxy-error", nil)
+	if err == nil || len(err.Error()) > errorBodyLimit+100 || !strings.HasSuffix(err.Error(), "...") {
+		t.Errorf("expected a truncated error body, got %d bytes", len(err.Error()))
+	}
+
+	WithMaxResponseSize(-1)(client)
+	if _, err := client.doGet("/api/v2/chunked", nil); err != nil {
+		t.Errorf("expected no limit, got %v", err)
+	}
+}
+
+func TestClient_TorrentsInfoEach(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("category") != "tv" {
+			t.Errorf("expected the category filter, got %q", r.URL.RawQuery)
+		}
+		w.Write([]byte(`[{"hash": "a", "state": "stoppedUP", "tags": "x, y"}, {"hash": "b"}, {"hash": "c"}]`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client(), stateNormalization: StatesPaused}
+	WithMaxResponseSize(10)(client)
+
+	var seen []TorrentInfo
+	stop := errors.New("stop")
+	err := client.TorrentsInfoEach(func(torrent TorrentInfo) error {
+		seen = append(seen, torrent)
+		if torrent.Hash == "b" {
+			return stop
+		}
+		return nil
+	}, &TorrentsInfoParams{Category: "tv"})
+	if err != stop {
+		t.Errorf("expected the callback error, got %v", err)
+	}
+	if len(seen) != 2 || seen[0].State != StatePausedUP || len(seen[0].Tags) != 2 {
+		t.Errorf("unexpected torrents %+v", seen)
+	}
+}
+
+func TestReadAll_HugeContentLength(t *testing.T) {
+	// A bogus Content-Length must not be allocated upfront
+	data, err := readAll(strings.NewReader("[]"), 1<<62)
+	if err != nil || string(data) != "[]" {
+		t.Errorf("expected [], got %q, %v", data, err)
+	}
+}
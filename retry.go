@@ -0,0 +1,145 @@
+package qbittorrent
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RetryPolicy retries GET requests that failed with a network error or a 429, 502, 503 or 504 response,
+// e.g. while qBittorrent or the reverse proxy in front of it restarts. POST requests are never retried
+// since they may have been applied. The zero value disables retries.
+type RetryPolicy struct {
+	MaxAttempts int           // attempts per request including the first, 0 or 1 disables retries
+	Backoff     time.Duration // delay before the first retry, doubled for every following one, default 500ms
+	MaxBackoff  time.Duration // upper bound of the delay, default 10s
+}
+
+// delay returns the delay before the given retry, the first being 1
+func (p RetryPolicy) delay(retry int) time.Duration {
+	backoff, maxBackoff := p.Backoff, p.MaxBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = 10 * time.Second
+	}
+	for i := 1; i < retry && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// retryable reports whether a request that was attempted attempt times should be sent again
+func (p RetryPolicy) retryable(req *http.Request, attempt int, resp *http.Response, err error) bool {
+	if attempt >= p.MaxAttempts || req.Method != http.MethodGet || req.Context().Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// WithRetry retries failed GET requests according to policy
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// RateLimit bounds the rate of requests sent to the server. The zero value means no limit.
+type RateLimit struct {
+	RequestsPerSecond float64 // sustained rate, 0 for no limit
+	Burst             int     // requests that may be sent at once after a quiet period, default 1
+}
+
+// WithRateLimit limits the rate of requests, including logins and retries, to limit. Requests wait for
+// their turn, bounded by their timeout.
+func WithRateLimit(limit RateLimit) ClientOption {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(limit)
+	}
+}
+
+// rateLimiter is a token bucket
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newRateLimiter returns a limiter for limit, or nil if limit is unlimited
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.RequestsPerSecond <= 0 {
+		return nil
+	}
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{rate: limit.RequestsPerSecond, burst: burst, tokens: burst, now: time.Now}
+}
+
+// wait blocks until a request may be sent or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long until one is
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// discard drains and closes a response body so its connection can be reused
+func discard(resp *http.Response) {
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,69 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithRetry(t *testing.T) {
+	attempts := map[string]int{}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts[r.Method+" "+r.URL.Path]++
+		if attempts[r.Method+" "+r.URL.Path] < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("Ok."))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})(client)
+
+	if _, err := client.doGet("/api/v2/flaky", nil); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.doPostValues("/api/v2/flaky", nil); err == nil {
+		t.Error("expected the POST to fail without retries")
+	}
+	if attempts["GET /api/v2/flaky"] != 3 || attempts["POST /api/v2/flaky"] != 1 {
+		t.Errorf("unexpected attempts %v", attempts)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 10: 5 * time.Second} {
+		if got := policy.delay(retry); got != want {
+			t.Errorf("retry %d: expected %s, got %s", retry, want, got)
+		}
+	}
+	if got := (RetryPolicy{}).delay(1); got != 500*time.Millisecond {
+		t.Errorf("expected the default backoff, got %s", got)
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(RateLimit{RequestsPerSecond: 2, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if d := limiter.reserve(); d != 0 {
+			t.Fatalf("burst request %d: expected no delay, got %s", i, d)
+		}
+	}
+	if d := limiter.reserve(); d != 500*time.Millisecond {
+		t.Errorf("expected a 500ms delay, got %s", d)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if d := limiter.reserve(); d != 0 {
+		t.Errorf("expected a token after 500ms, got %s", d)
+	}
+
+	if newRateLimiter(RateLimit{}) != nil {
+		t.Error("expected no limiter for the zero RateLimit")
+	}
+}
@@ -0,0 +1,168 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SpeedSample is the global transfer speed at a point in time, in bytes per second
+type SpeedSample struct {
+	Time     time.Time
+	Download int64
+	Upload   int64
+}
+
+// SamplerConfig configures a Sampler. Zero values select the defaults.
+type SamplerConfig struct {
+	Interval time.Duration // how often Run samples the speeds, default 5s
+	Size     int           // number of samples kept, default 720 (an hour at the default interval)
+
+	// OnSample, if set, is called with every sample taken by Run or RunOnce
+	OnSample func(sample SpeedSample)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// Sampler polls the global transfer speeds and keeps the latest samples in a ring buffer, for graphs and
+// rules such as pausing torrents when the upload stays above a limit for some time. Its methods are safe
+// for concurrent use, so the samples can be read while Run is sampling.
+type Sampler struct {
+	client *Client
+	cfg    SamplerConfig
+
+	mu      sync.Mutex
+	samples []SpeedSample // ring buffer, next is the index of the oldest sample once it is full
+	next    int
+	now     func() time.Time
+}
+
+// NewSampler creates a Sampler for the given client
+func NewSampler(client *Client, cfg SamplerConfig) *Sampler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.Size <= 0 {
+		cfg.Size = 720
+	}
+	return &Sampler{client: client, cfg: cfg, samples: make([]SpeedSample, 0, cfg.Size), now: time.Now}
+}
+
+// Run samples the speeds every Interval until ctx is cancelled
+func (s *Sampler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil && s.cfg.OnError != nil {
+			s.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fetches the current speeds and adds them as a sample
+func (s *Sampler) RunOnce(ctx context.Context) (SpeedSample, error) {
+	info, err := s.client.TransferInfo()
+	if err != nil {
+		return SpeedSample{}, fmt.Errorf("Sampler error: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return SpeedSample{}, err
+	}
+
+	sample := SpeedSample{Time: s.now(), Download: info.DLInfoSpeed, Upload: info.UpInfoSpeed}
+	s.Add(sample)
+	if s.cfg.OnSample != nil {
+		s.cfg.OnSample(sample)
+	}
+	return sample, nil
+}
+
+// Add adds a sample taken elsewhere, e.g. from the server state of sync/maindata updates, replacing the
+// oldest one once the buffer is full. Samples are expected in chronological order.
+func (s *Sampler) Add(sample SpeedSample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.samples) < s.cfg.Size {
+		s.samples = append(s.samples, sample)
+		return
+	}
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % s.cfg.Size
+}
+
+// Samples returns the samples kept, oldest first
+func (s *Sampler) Samples() []SpeedSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	samples := make([]SpeedSample, 0, len(s.samples))
+	samples = append(samples, s.samples[s.next:]...)
+	return append(samples, s.samples[:s.next]...)
+}
+
+// Window returns the samples taken within window of the latest one, oldest first
+func (s *Sampler) Window(window time.Duration) []SpeedSample {
+	samples := s.Samples()
+	if len(samples) == 0 {
+		return nil
+	}
+	since := samples[len(samples)-1].Time.Add(-window)
+	for i, sample := range samples {
+		if !sample.Time.Before(since) {
+			return samples[i:]
+		}
+	}
+	return nil
+}
+
+// Covers reports whether the samples reach back at least window from the latest one, so that rules over
+// the window are not decided on the first few samples after startup
+func (s *Sampler) Covers(window time.Duration) bool {
+	samples := s.Samples()
+	return len(samples) > 0 && samples[len(samples)-1].Time.Sub(samples[0].Time) >= window
+}
+
+// Average returns the mean download and upload speeds over window, 0 without samples
+func (s *Sampler) Average(window time.Duration) (download, upload int64) {
+	samples := s.Window(window)
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	for _, sample := range samples {
+		download += sample.Download
+		upload += sample.Upload
+	}
+	return download / int64(len(samples)), upload / int64(len(samples))
+}
+
+// Peak returns the highest download and upload speeds over window
+func (s *Sampler) Peak(window time.Duration) (download, upload int64) {
+	for _, sample := range s.Window(window) {
+		download = max(download, sample.Download)
+		upload = max(upload, sample.Upload)
+	}
+	return download, upload
+}
+
+// Min returns the lowest download and upload speeds over window, 0 without samples. A speed was sustained
+// above a limit over the window if its minimum exceeds the limit and Covers(window) holds.
+func (s *Sampler) Min(window time.Duration) (download, upload int64) {
+	samples := s.Window(window)
+	if len(samples) == 0 {
+		return 0, 0
+	}
+	download, upload = samples[0].Download, samples[0].Upload
+	for _, sample := range samples[1:] {
+		download = min(download, sample.Download)
+		upload = min(upload, sample.Upload)
+	}
+	return download, upload
+}
@@ -0,0 +1,62 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSampler_RunOnce(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"dl_info_speed":100,"up_info_speed":200}`))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	var seen []SpeedSample
+	sampler := NewSampler(client, SamplerConfig{OnSample: func(sample SpeedSample) { seen = append(seen, sample) }})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	sampler.now = func() time.Time { return now }
+
+	sample, err := sampler.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := SpeedSample{Time: now, Download: 100, Upload: 200}
+	if sample != want || !reflect.DeepEqual(seen, []SpeedSample{want}) || !reflect.DeepEqual(sampler.Samples(), []SpeedSample{want}) {
+		t.Errorf("unexpected sample %+v, seen %+v", sample, seen)
+	}
+}
+
+func TestSampler_Window(t *testing.T) {
+	sampler := NewSampler(nil, SamplerConfig{Size: 4})
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, upload := range []int64{900, 100, 400, 300, 500, 200} {
+		sampler.Add(SpeedSample{Time: start.Add(time.Duration(i) * time.Minute), Download: int64(i), Upload: upload})
+	}
+
+	// The buffer keeps the last 4 samples, taken at minutes 2 to 5
+	samples := sampler.Samples()
+	if len(samples) != 4 || samples[0].Upload != 400 || samples[3].Upload != 200 {
+		t.Fatalf("unexpected samples %+v", samples)
+	}
+	if got := sampler.Window(2 * time.Minute); len(got) != 3 || got[0].Upload != 300 {
+		t.Errorf("unexpected window %+v", got)
+	}
+
+	if down, up := sampler.Average(2 * time.Minute); down != 4 || up != 333 {
+		t.Errorf("unexpected average %d, %d", down, up)
+	}
+	if down, up := sampler.Peak(time.Hour); down != 5 || up != 500 {
+		t.Errorf("unexpected peak %d, %d", down, up)
+	}
+	if down, up := sampler.Min(time.Hour); down != 2 || up != 200 {
+		t.Errorf("unexpected min %d, %d", down, up)
+	}
+	if !sampler.Covers(3*time.Minute) || sampler.Covers(4*time.Minute) {
+		t.Errorf("expected the samples to cover 3 but not 4 minutes")
+	}
+}
@@ -0,0 +1,139 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SavePathVars are the values substituted into a SavePathTemplate
+type SavePathVars struct {
+	Category string
+	Tracker  string // tracker URL or hostname, {tracker} expands to the hostname
+	Name     string
+	Time     time.Time // time for {year} and {month}, the current time if zero
+}
+
+// TorrentSavePathVars returns the values of torrent for a SavePathTemplate, dated by its completion or, if
+// it did not complete yet, by the time it was added
+func TorrentSavePathVars(torrent TorrentInfo) SavePathVars {
+	t := torrent.CompletedAt()
+	if t.IsZero() {
+		t = torrent.AddedAt()
+	}
+	return SavePathVars{Category: torrent.Category, Tracker: torrent.Tracker, Name: torrent.Name, Time: t}
+}
+
+// savePathPlaceholders expand a placeholder from the values
+var savePathPlaceholders = map[string]func(v SavePathVars) string{
+	"category": func(v SavePathVars) string { return v.Category },
+	"tracker": func(v SavePathVars) string {
+		if strings.Contains(v.Tracker, "://") {
+			return trackerHost(v.Tracker)
+		}
+		return strings.ToLower(v.Tracker)
+	},
+	"name":  func(v SavePathVars) string { return v.Name },
+	"year":  func(v SavePathVars) string { return v.Time.Format("2006") },
+	"month": func(v SavePathVars) string { return v.Time.Format("01") },
+}
+
+// SavePathTemplate builds save paths from placeholders, so the rules sorting torrents into directories live
+// in one place. The placeholders are {category}, {tracker}, {name}, {year} and {month}; a default for
+// empty values follows a colon, as in {category:uncategorized}. Values are made safe as a single path
+// segment, and segments that expand to nothing are dropped:
+//
+//	/data/{category}/{tracker}/{year}-{month}
+type SavePathTemplate struct {
+	parts []templatePart
+}
+
+// templatePart is literal text or, if placeholder is set, a placeholder with its default
+type templatePart struct {
+	text        string
+	placeholder string
+}
+
+// ParseSavePathTemplate parses a template, failing for unknown placeholders and unbalanced braces
+func ParseSavePathTemplate(s string) (*SavePathTemplate, error) {
+	t := &SavePathTemplate{}
+	for s != "" {
+		open := strings.IndexAny(s, "{}")
+		if open < 0 {
+			t.parts = append(t.parts, templatePart{text: s})
+			break
+		}
+		if s[open] == '}' {
+			return nil, fmt.Errorf("ParseSavePathTemplate error: unexpected } in %q", s)
+		}
+		if open > 0 {
+			t.parts = append(t.parts, templatePart{text: s[:open]})
+		}
+		end := strings.IndexAny(s[open+1:], "{}")
+		if end < 0 || s[open+1+end] != '}' {
+			return nil, fmt.Errorf("ParseSavePathTemplate error: unterminated placeholder in %q", s)
+		}
+		name, def, _ := strings.Cut(s[open+1:open+1+end], ":")
+		if _, ok := savePathPlaceholders[name]; !ok {
+			return nil, fmt.Errorf("ParseSavePathTemplate error: unknown placeholder {%s}", name)
+		}
+		t.parts = append(t.parts, templatePart{placeholder: name, text: def})
+		s = s[open+1+end+1:]
+	}
+	return t, nil
+}
+
+// MustParseSavePathTemplate is like ParseSavePathTemplate but panics on errors, for templates in variables
+func MustParseSavePathTemplate(s string) *SavePathTemplate {
+	t, err := ParseSavePathTemplate(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Expand returns the save path for vars
+func (t *SavePathTemplate) Expand(vars SavePathVars) string {
+	if vars.Time.IsZero() {
+		vars.Time = time.Now()
+	}
+	var b strings.Builder
+	for _, part := range t.parts {
+		if part.placeholder == "" {
+			b.WriteString(part.text)
+			continue
+		}
+		value := savePathSegment(savePathPlaceholders[part.placeholder](vars))
+		if value == "" {
+			value = part.text
+		}
+		b.WriteString(value)
+	}
+
+	// Drop the segments left empty, keeping the leading separator of absolute templates
+	var kept []string
+	for _, segment := range strings.Split(b.String(), "/") {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	if len(t.parts) > 0 && t.parts[0].placeholder == "" && strings.HasPrefix(t.parts[0].text, "/") {
+		return "/" + strings.Join(kept, "/")
+	}
+	return strings.Join(kept, "/")
+}
+
+// savePathSegment makes value usable as a single path segment
+func savePathSegment(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.NewReplacer("/", "_", "\\", "_").Replace(value)
+	if value == "." || value == ".." {
+		return "_"
+	}
+	return value
+}
+
+// WithSavePathTemplate saves the added torrent to the path t expands to for vars
+func WithSavePathTemplate(t *SavePathTemplate, vars SavePathVars) TorrentAddOption {
+	return WithSavePath(t.Expand(vars))
+}
@@ -0,0 +1,49 @@
+package qbittorrent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSavePathTemplate_Expand(t *testing.T) {
+	when := time.Date(2024, 3, 9, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		template string
+		vars     SavePathVars
+		want     string
+	}{
+		{"/data/{category}/{tracker}/{year}-{month}", SavePathVars{Category: "tv", Tracker: "https://Tracker.Example:8443/a/announce", Time: when}, "/data/tv/tracker.example/2024-03"},
+		{"/data/{category}/{name}", SavePathVars{Name: "Some/Show ", Time: when}, "/data/Some_Show"},
+		{"/data/{category:other}/", SavePathVars{Time: when}, "/data/other"},
+		{"{category}/incoming", SavePathVars{Time: when}, "incoming"},
+		{"/data/{name}", SavePathVars{Name: "..", Time: when}, "/data/_"},
+		{"/data/{tracker}", SavePathVars{Tracker: "Tracker.Example", Time: when}, "/data/tracker.example"},
+	}
+	for _, tt := range tests {
+		tmpl, err := ParseSavePathTemplate(tt.template)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.template, err)
+		}
+		if got := tmpl.Expand(tt.vars); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.template, tt.want, got)
+		}
+	}
+
+	for _, invalid := range []string{"/data/{unknown}", "/data/{category", "/data/category}", "/data/{{category}}"} {
+		if _, err := ParseSavePathTemplate(invalid); err == nil {
+			t.Errorf("%s: expected an error", invalid)
+		}
+	}
+}
+
+func TestTorrentSavePathVars(t *testing.T) {
+	torrent := TorrentInfo{Category: "tv", Tracker: "http://t.example/announce", Name: "show", AddedOn: 1700000000}
+	vars := TorrentSavePathVars(torrent)
+	if !vars.Time.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("expected the added time, got %s", vars.Time)
+	}
+	torrent.CompletionOn = 1710000000
+	if got := MustParseSavePathTemplate("/done/{tracker}/{year}").Expand(TorrentSavePathVars(torrent)); got != "/done/t.example/2024" {
+		t.Errorf("unexpected path %s", got)
+	}
+}
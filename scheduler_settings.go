@@ -0,0 +1,135 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"time"
+)
+
+// SchedulerDays selects the days qBittorrent's built-in bandwidth scheduler is active on, as stored in the
+// scheduler_days preference
+type SchedulerDays int
+
+const (
+	SchedulerEveryDay SchedulerDays = iota
+	SchedulerWeekdays
+	SchedulerWeekends
+	SchedulerMonday
+	SchedulerTuesday
+	SchedulerWednesday
+	SchedulerThursday
+	SchedulerFriday
+	SchedulerSaturday
+	SchedulerSunday
+)
+
+// SchedulerDay returns the SchedulerDays value selecting day only
+func SchedulerDay(day time.Weekday) SchedulerDays {
+	if day == time.Sunday {
+		return SchedulerSunday
+	}
+	return SchedulerMonday + SchedulerDays(day-time.Monday)
+}
+
+// Weekdays returns the days selected by d, in week order starting on Sunday
+func (d SchedulerDays) Weekdays() []time.Weekday {
+	var days []time.Weekday
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if d.Includes(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// Includes reports whether d selects day
+func (d SchedulerDays) Includes(day time.Weekday) bool {
+	weekend := day == time.Saturday || day == time.Sunday
+	switch d {
+	case SchedulerEveryDay:
+		return true
+	case SchedulerWeekdays:
+		return !weekend
+	case SchedulerWeekends:
+		return weekend
+	}
+	return d >= SchedulerMonday && d <= SchedulerSunday && SchedulerDay(day) == d
+}
+
+func (d SchedulerDays) String() string {
+	switch d {
+	case SchedulerEveryDay:
+		return "every day"
+	case SchedulerWeekdays:
+		return "weekdays"
+	case SchedulerWeekends:
+		return "weekends"
+	}
+	if d >= SchedulerMonday && d <= SchedulerSunday {
+		return time.Weekday((int(d-SchedulerMonday) + 1) % 7).String()
+	}
+	return fmt.Sprintf("SchedulerDays(%d)", int(d))
+}
+
+// SchedulerSettings is the configuration of qBittorrent's built-in bandwidth scheduler, which enables the
+// alternative speed limits from From to To on Days. It runs on the server, in the server's time zone; see
+// AltSpeedScheduler for a client-side alternative supporting several windows.
+type SchedulerSettings struct {
+	Enabled bool
+	From    TimeOfDay
+	To      TimeOfDay
+	Days    SchedulerDays
+}
+
+// Window returns the settings as an AltSpeedWindow
+func (s SchedulerSettings) Window() AltSpeedWindow {
+	w := AltSpeedWindow{From: s.From, To: s.To}
+	if s.Days != SchedulerEveryDay {
+		w.Days = s.Days.Weekdays()
+	}
+	return w
+}
+
+// SchedulerSettings retrieves the bandwidth scheduler configuration
+func (c *Client) SchedulerSettings() (SchedulerSettings, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return SchedulerSettings{}, fmt.Errorf("SchedulerSettings error: %w", err)
+	}
+
+	number := func(key string) int {
+		v, _ := prefs[key].(float64)
+		return int(v)
+	}
+	enabled, _ := prefs["scheduler_enabled"].(bool)
+	return SchedulerSettings{
+		Enabled: enabled,
+		From:    TimeOfDay{Hour: number("schedule_from_hour"), Minute: number("schedule_from_min")},
+		To:      TimeOfDay{Hour: number("schedule_to_hour"), Minute: number("schedule_to_min")},
+		Days:    SchedulerDays(number("scheduler_days")),
+	}, nil
+}
+
+// SetSchedulerSettings changes the bandwidth scheduler configuration
+func (c *Client) SetSchedulerSettings(s SchedulerSettings) error {
+	for _, t := range []TimeOfDay{s.From, s.To} {
+		if t.Hour < 0 || t.Hour > 23 || t.Minute < 0 || t.Minute > 59 {
+			return fmt.Errorf("SetSchedulerSettings error: invalid time of day %s", t)
+		}
+	}
+	if s.Days < SchedulerEveryDay || s.Days > SchedulerSunday {
+		return fmt.Errorf("SetSchedulerSettings error: invalid days %s", s.Days)
+	}
+
+	err := c.AppSetPreferences(Preferences{
+		"scheduler_enabled":  s.Enabled,
+		"schedule_from_hour": s.From.Hour,
+		"schedule_from_min":  s.From.Minute,
+		"schedule_to_hour":   s.To.Hour,
+		"schedule_to_min":    s.To.Minute,
+		"scheduler_days":     int(s.Days),
+	})
+	if err != nil {
+		return fmt.Errorf("SetSchedulerSettings error: %w", err)
+	}
+	return nil
+}
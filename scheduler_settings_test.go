@@ -0,0 +1,79 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedulerDays(t *testing.T) {
+	tests := []struct {
+		days SchedulerDays
+		want []time.Weekday
+		name string
+	}{
+		{SchedulerEveryDay, []time.Weekday{0, 1, 2, 3, 4, 5, 6}, "every day"},
+		{SchedulerWeekdays, []time.Weekday{1, 2, 3, 4, 5}, "weekdays"},
+		{SchedulerWeekends, []time.Weekday{0, 6}, "weekends"},
+		{SchedulerMonday, []time.Weekday{time.Monday}, "Monday"},
+		{SchedulerSunday, []time.Weekday{time.Sunday}, "Sunday"},
+		{SchedulerDays(42), nil, "SchedulerDays(42)"},
+	}
+	for _, tt := range tests {
+		if got := tt.days.Weekdays(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%d: expected %v, got %v", tt.days, tt.want, got)
+		}
+		if got := tt.days.String(); got != tt.name {
+			t.Errorf("%d: expected %q, got %q", tt.days, tt.name, got)
+		}
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if d := SchedulerDay(day); d.String() != day.String() {
+			t.Errorf("SchedulerDay(%s) = %s", day, d)
+		}
+	}
+}
+
+func TestClient_SchedulerSettings(t *testing.T) {
+	var posted string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"scheduler_enabled": true, "schedule_from_hour": 22, "schedule_from_min": 30,
+				"schedule_to_hour": 6, "schedule_to_min": 0, "scheduler_days": 2}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	settings, err := client.SchedulerSettings()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := SchedulerSettings{Enabled: true, From: TimeOfDay{22, 30}, To: TimeOfDay{6, 0}, Days: SchedulerWeekends}
+	if settings != want {
+		t.Errorf("expected %+v, got %+v", want, settings)
+	}
+	if w := settings.Window(); !reflect.DeepEqual(w.Days, []time.Weekday{time.Sunday, time.Saturday}) || w.From != want.From {
+		t.Errorf("unexpected window %+v", w)
+	}
+
+	if err := client.SetSchedulerSettings(SchedulerSettings{From: TimeOfDay{24, 0}}); err == nil {
+		t.Error("expected an error for an invalid time of day")
+	}
+	if err := client.SetSchedulerSettings(SchedulerSettings{Enabled: true, From: TimeOfDay{1, 5}, To: TimeOfDay{7, 0}, Days: SchedulerFriday}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantPosted := `{"schedule_from_hour":1,"schedule_from_min":5,"schedule_to_hour":7,"schedule_to_min":0,"scheduler_days":7,"scheduler_enabled":true}`
+	if posted != wantPosted {
+		t.Errorf("expected %s, got %s", wantPosted, posted)
+	}
+}
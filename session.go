@@ -0,0 +1,88 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// WithSessionRefresh logs in again shortly before the session expires, so the first request after a quiet
+// period doesn't pay for a rejected request and a retry. qBittorrent expires sessions after
+// web_ui_session_timeout seconds of inactivity; the timeout is read from the preferences after logging in.
+// The client logs in again when the session has been idle for longer than the timeout minus margin, which
+// defaults to a minute and is capped at half the timeout.
+func WithSessionRefresh(margin time.Duration) ClientOption {
+	return func(c *Client) {
+		c.sessionRefresh = true
+		c.sessionMargin = margin
+	}
+}
+
+// ensureSession logs in before a request if WithLazyLogin deferred the login, or if WithSessionRefresh is
+// set and the session is about to expire
+func (c *Client) ensureSession(ctx context.Context) error {
+	if !(c.lazyLogin || c.sessionRefresh) || !c.canLogin() {
+		return nil
+	}
+
+	c.mu.RLock()
+	loggedIn, idle, timeout := c.loggedIn, time.Since(c.lastActivity), c.sessionTimeout
+	c.mu.RUnlock()
+
+	switch {
+	case !loggedIn && c.lazyLogin:
+		return c.authLogin(ctx)
+	case loggedIn && c.sessionRefresh && timeout > 0 && idle > timeout-c.refreshMargin(timeout):
+		return c.authLogin(ctx)
+	}
+	return nil
+}
+
+// refreshMargin returns how long before a session of the given timeout expires it is refreshed
+func (c *Client) refreshMargin(timeout time.Duration) time.Duration {
+	margin := c.sessionMargin
+	if margin <= 0 {
+		margin = time.Minute
+	}
+	if margin > timeout/2 {
+		margin = timeout / 2
+	}
+	return margin
+}
+
+// touchSession records that the session was just used, which restarts the server's inactivity timeout
+func (c *Client) touchSession() {
+	if !c.sessionRefresh {
+		return
+	}
+	c.mu.Lock()
+	c.lastActivity = time.Now()
+	c.mu.Unlock()
+}
+
+// loadSessionTimeout reads the session timeout from the preferences. On failure the timeout stays unknown
+// and sessions are only renewed after a rejected request, as without WithSessionRefresh.
+func (c *Client) loadSessionTimeout(ctx context.Context) {
+	resp, err := c.doRequestContext(ctx, "GET", "/api/v2/app/preferences", nil, "")
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	data, err := c.readBody(resp, "/api/v2/app/preferences")
+	if err != nil {
+		return
+	}
+
+	var prefs struct {
+		SessionTimeout int64 `json:"web_ui_session_timeout"`
+	}
+	if err := c.decodeJSON(data, &prefs); err != nil || prefs.SessionTimeout <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.sessionTimeout = time.Duration(prefs.SessionTimeout) * time.Second
+	c.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithSessionRefresh(t *testing.T) {
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "sid"})
+			w.Write([]byte("Ok."))
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"web_ui_session_timeout": 3600}`))
+		case "/api/v2/app/version":
+			w.Write([]byte("v4.6.7"))
+		}
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClientWithOptions("admin", "adminadmin", "", "", WithBaseURL(mockServer.URL), WithHTTPClient(mockServer.Client()), WithSessionRefresh(0))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.sessionTimeout != time.Hour {
+		t.Fatalf("expected the session timeout from the preferences, got %v", client.sessionTimeout)
+	}
+
+	if _, err := client.AppVersion(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Idle for longer than the timeout minus the default one minute margin
+	client.lastActivity = time.Now().Add(-59*time.Minute - 30*time.Second)
+	if _, err := client.AppVersion(); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := []string{
+		"/api/v2/auth/login", "/api/v2/app/preferences",
+		"/api/v2/app/version",
+		"/api/v2/auth/login", "/api/v2/app/version",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+}
+
+func TestRefreshMargin(t *testing.T) {
+	client := &Client{}
+	if got := client.refreshMargin(time.Hour); got != time.Minute {
+		t.Errorf("expected the default margin, got %v", got)
+	}
+	client.sessionMargin = 5 * time.Minute
+	if got := client.refreshMargin(4 * time.Minute); got != 2*time.Minute {
+		t.Errorf("expected the margin capped at half the timeout, got %v", got)
+	}
+}
@@ -0,0 +1,113 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// APIVersionSetTags is the first Web API version with the torrents/setTags endpoint (qBittorrent 5.1)
+var APIVersionSetTags = APIVersion{Major: 2, Minor: 11, Patch: 4}
+
+// TorrentsSetTags makes tags the exact tag set of each of the specified torrents, adding missing tags and
+// removing all others. An empty tags removes every tag. Multiple hashes are separated by "|".
+// Servers without the setTags endpoint get the difference applied with addTags and removeTags.
+func (c *Client) TorrentsSetTags(hashes string, tags []string) error {
+	joined := ""
+	if len(tags) > 0 {
+		var err error
+		if joined, err = joinTags(tags); err != nil {
+			return fmt.Errorf("SetTags error: %w", err)
+		}
+	}
+
+	v, err := c.APIVersion()
+	if err != nil {
+		return fmt.Errorf("SetTags error: %w", err)
+	}
+	if v.AtLeast(APIVersionSetTags) {
+		data := url.Values{}
+		data.Set("hashes", hashes)
+		data.Set("tags", joined)
+		if _, err := c.doPostValues("/api/v2/torrents/setTags", data); err != nil {
+			return fmt.Errorf("SetTags error: %w", err)
+		}
+		return nil
+	}
+
+	current, err := c.TorrentsGetTags(hashes)
+	if err != nil {
+		return fmt.Errorf("SetTags error: %w", err)
+	}
+	for _, change := range tagChanges(current, splitTags(joined)) {
+		hashes := joinHashes(change.hashes)
+		if len(change.add) > 0 {
+			if err := c.TorrentsAddTags(hashes, change.add); err != nil {
+				return fmt.Errorf("SetTags error: %w", err)
+			}
+		}
+		if len(change.remove) > 0 {
+			if err := c.TorrentsRemoveTags(hashes, change.remove); err != nil {
+				return fmt.Errorf("SetTags error: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// tagChange is a set of tags to add and remove, shared by the torrents in hashes
+type tagChange struct {
+	hashes      []InfoHash
+	add, remove []string
+}
+
+// tagChanges computes the changes turning current into want for every torrent, grouping torrents
+// that need the same change so each group takes one request per direction
+func tagChanges(current map[InfoHash][]string, want []string) []tagChange {
+	wanted := make(map[string]struct{}, len(want))
+	for _, tag := range want {
+		wanted[tag] = struct{}{}
+	}
+
+	groups := make(map[string]*tagChange)
+	var keys []string
+	for hash, tags := range current {
+		have := make(map[string]struct{}, len(tags))
+		var remove []string
+		for _, tag := range tags {
+			have[tag] = struct{}{}
+			if _, ok := wanted[tag]; !ok {
+				remove = append(remove, tag)
+			}
+		}
+		var add []string
+		for _, tag := range want {
+			if _, ok := have[tag]; !ok {
+				add = append(add, tag)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			continue
+		}
+		sort.Strings(add)
+		sort.Strings(remove)
+
+		key := strings.Join(add, ",") + "\x00" + strings.Join(remove, ",")
+		group, ok := groups[key]
+		if !ok {
+			group = &tagChange{add: add, remove: remove}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.hashes = append(group.hashes, hash)
+	}
+
+	sort.Strings(keys)
+	changes := make([]tagChange, 0, len(keys))
+	for _, key := range keys {
+		sortHashes(groups[key].hashes)
+		changes = append(changes, *groups[key])
+	}
+	return changes
+}
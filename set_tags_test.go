@@ -0,0 +1,73 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_TorrentsSetTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		webAPIVersion string
+		want          []url.Values
+	}{
+		{
+			name:          "diff",
+			webAPIVersion: "2.9.3",
+			want: []url.Values{
+				{"path": {"/api/v2/torrents/addTags"}, "hashes": {"a|c"}, "tags": {"keep,new"}},
+				{"path": {"/api/v2/torrents/addTags"}, "hashes": {"b"}, "tags": {"new"}},
+				{"path": {"/api/v2/torrents/removeTags"}, "hashes": {"b"}, "tags": {"old"}},
+			},
+		},
+		{
+			name:          "setTags endpoint",
+			webAPIVersion: "2.11.4",
+			want: []url.Values{
+				{"path": {"/api/v2/torrents/setTags"}, "hashes": {"a|b|c|d"}, "tags": {"keep,new"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []url.Values
+			mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/api/v2/app/webapiVersion":
+					w.Write([]byte(tt.webAPIVersion))
+				case "/api/v2/torrents/info":
+					w.Write([]byte(`[
+						{"hash": "a", "tags": ""},
+						{"hash": "b", "tags": "keep, old"},
+						{"hash": "c", "tags": ""},
+						{"hash": "d", "tags": "new, keep"}
+					]`))
+				default:
+					r.ParseForm()
+					r.PostForm.Set("path", r.URL.Path)
+					calls = append(calls, r.PostForm)
+				}
+			}))
+			defer mockServer.Close()
+
+			client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+			if err := client.TorrentsSetTags("a|b|c|d", []string{"keep", "new"}); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(calls, tt.want) {
+				t.Errorf("expected calls %v, got %v", tt.want, calls)
+			}
+		})
+	}
+}
+
+func TestTagChanges_RemoveAll(t *testing.T) {
+	changes := tagChanges(map[InfoHash][]string{"a": {"x", "y"}, "b": {}}, nil)
+	want := []tagChange{{hashes: []InfoHash{"a"}, remove: []string{"x", "y"}}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected %+v, got %+v", want, changes)
+	}
+}
@@ -0,0 +1,100 @@
+package qbittorrent
+
+import (
+	"fmt"
+)
+
+// ShareLimitAction is what qBittorrent does with a torrent reaching its share limits, as stored in the
+// max_ratio_act preference
+type ShareLimitAction int
+
+const (
+	ShareLimitPause              ShareLimitAction = 0 // pause (4.x) or stop (5.x) the torrent
+	ShareLimitRemove             ShareLimitAction = 1 // remove the torrent, keeping its data
+	ShareLimitSuperSeeding       ShareLimitAction = 2 // enable super seeding
+	ShareLimitRemoveWithContents ShareLimitAction = 3 // remove the torrent and its data
+)
+
+func (a ShareLimitAction) String() string {
+	switch a {
+	case ShareLimitPause:
+		return "pause"
+	case ShareLimitRemove:
+		return "remove"
+	case ShareLimitSuperSeeding:
+		return "super seeding"
+	case ShareLimitRemoveWithContents:
+		return "remove with contents"
+	}
+	return fmt.Sprintf("ShareLimitAction(%d)", int(a))
+}
+
+// GlobalShareLimits are the share limits applied to torrents whose own limits are ShareLimitGlobal
+type GlobalShareLimits struct {
+	RatioEnabled       bool
+	Ratio              float64
+	SeedingTimeEnabled bool
+	SeedingTime        int64 // minutes
+	Action             ShareLimitAction
+}
+
+// Effective resolves the ShareLimitGlobal values of limits, the per-torrent limits, against g. Disabled
+// global limits resolve to ShareLimitUnlimited.
+func (g GlobalShareLimits) Effective(limits ShareLimits) ShareLimits {
+	if limits.Ratio == ShareLimitGlobal {
+		limits.Ratio = ShareLimitUnlimited
+		if g.RatioEnabled {
+			limits.Ratio = g.Ratio
+		}
+	}
+	if limits.SeedingTime == ShareLimitGlobal {
+		limits.SeedingTime = ShareLimitUnlimited
+		if g.SeedingTimeEnabled {
+			limits.SeedingTime = g.SeedingTime
+		}
+	}
+	return limits
+}
+
+// GlobalShareLimits retrieves the global ratio and seeding time limits and the action taken on reaching them
+func (c *Client) GlobalShareLimits() (GlobalShareLimits, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return GlobalShareLimits{}, fmt.Errorf("GlobalShareLimits error: %w", err)
+	}
+
+	ratioEnabled, _ := prefs["max_ratio_enabled"].(bool)
+	ratio, _ := prefs["max_ratio"].(float64)
+	seedingTimeEnabled, _ := prefs["max_seeding_time_enabled"].(bool)
+	seedingTime, _ := prefs["max_seeding_time"].(float64)
+	action, _ := prefs["max_ratio_act"].(float64)
+	return GlobalShareLimits{
+		RatioEnabled:       ratioEnabled,
+		Ratio:              ratio,
+		SeedingTimeEnabled: seedingTimeEnabled,
+		SeedingTime:        int64(seedingTime),
+		Action:             ShareLimitAction(action),
+	}, nil
+}
+
+// SetGlobalShareLimits changes the global ratio and seeding time limits and the action taken on reaching them
+func (c *Client) SetGlobalShareLimits(g GlobalShareLimits) error {
+	if g.Ratio < 0 || g.SeedingTime < 0 {
+		return fmt.Errorf("SetGlobalShareLimits error: negative limit")
+	}
+	if g.Action < ShareLimitPause || g.Action > ShareLimitRemoveWithContents {
+		return fmt.Errorf("SetGlobalShareLimits error: invalid action %s", g.Action)
+	}
+
+	err := c.AppSetPreferences(Preferences{
+		"max_ratio_enabled":        g.RatioEnabled,
+		"max_ratio":                g.Ratio,
+		"max_seeding_time_enabled": g.SeedingTimeEnabled,
+		"max_seeding_time":         g.SeedingTime,
+		"max_ratio_act":            int(g.Action),
+	})
+	if err != nil {
+		return fmt.Errorf("SetGlobalShareLimits error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGlobalShareLimits_Effective(t *testing.T) {
+	g := GlobalShareLimits{RatioEnabled: true, Ratio: 2, SeedingTime: 60}
+	got := g.Effective(ShareLimits{Ratio: ShareLimitGlobal, SeedingTime: ShareLimitGlobal})
+	if want := (ShareLimits{Ratio: 2, SeedingTime: ShareLimitUnlimited}); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	got = g.Effective(ShareLimits{Ratio: 1.5, SeedingTime: 30})
+	if want := (ShareLimits{Ratio: 1.5, SeedingTime: 30}); got != want {
+		t.Errorf("expected overrides kept, got %+v", got)
+	}
+}
+
+func TestClient_GlobalShareLimits(t *testing.T) {
+	var posted string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"max_ratio_enabled":true,"max_ratio":1.5,"max_seeding_time_enabled":false,"max_seeding_time":1440,"max_ratio_act":3}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	g, err := client.GlobalShareLimits()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := GlobalShareLimits{RatioEnabled: true, Ratio: 1.5, SeedingTime: 1440, Action: ShareLimitRemoveWithContents}
+	if g != want {
+		t.Errorf("expected %+v, got %+v", want, g)
+	}
+
+	g.SeedingTimeEnabled = true
+	g.Action = ShareLimitPause
+	if err := client.SetGlobalShareLimits(g); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantPosted := `{"max_ratio":1.5,"max_ratio_act":0,"max_ratio_enabled":true,"max_seeding_time":1440,"max_seeding_time_enabled":true}`
+	if posted != wantPosted {
+		t.Errorf("expected %s, got %s", wantPosted, posted)
+	}
+
+	if err := client.SetGlobalShareLimits(GlobalShareLimits{Action: 7}); err == nil {
+		t.Error("expected an error for an invalid action")
+	}
+}
@@ -0,0 +1,178 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SnapshotVersion is the version of the Snapshot document format written by SnapshotExport
+const SnapshotVersion = 1
+
+// Snapshot is a JSON serializable description of a qBittorrent session, without the torrent data
+type Snapshot struct {
+	Version     int                 `json:"version"`
+	CreatedAt   time.Time           `json:"created_at"`
+	Torrents    []SnapshotTorrent   `json:"torrents"`
+	Categories  map[string]Category `json:"categories"`
+	Tags        []string            `json:"tags"`
+	Preferences Preferences         `json:"preferences"`
+}
+
+// SnapshotTorrent is the per-torrent part of a Snapshot
+type SnapshotTorrent struct {
+	Hash             InfoHash `json:"hash"`
+	Name             string   `json:"name"`
+	Category         string   `json:"category"`
+	Tags             []string `json:"tags"`
+	SavePath         string   `json:"save_path"`
+	State            string   `json:"state"`
+	RatioLimit       float64  `json:"ratio_limit"`
+	SeedingTimeLimit int64    `json:"seeding_time_limit"`
+	// InactiveSeedingTimeLimit is nil in snapshots written before it was recorded, which leave the limit of
+	// the torrent on the server as it is
+	InactiveSeedingTimeLimit *int64 `json:"inactive_seeding_time_limit,omitempty"`
+}
+
+// SnapshotExport captures the torrents, categories, tags and preferences of the session
+func (c *Client) SnapshotExport() (*Snapshot, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("SnapshotExport error: %w", err)
+	}
+	categories, err := c.TorrentsCategories()
+	if err != nil {
+		return nil, fmt.Errorf("SnapshotExport error: %w", err)
+	}
+	tags, err := c.TorrentsGetAllTags()
+	if err != nil {
+		return nil, fmt.Errorf("SnapshotExport error: %w", err)
+	}
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("SnapshotExport error: %w", err)
+	}
+
+	snapshot := &Snapshot{
+		Version:     SnapshotVersion,
+		CreatedAt:   time.Now().UTC(),
+		Torrents:    make([]SnapshotTorrent, 0, len(torrents)),
+		Categories:  categories,
+		Tags:        tags,
+		Preferences: prefs,
+	}
+	for _, torrent := range torrents {
+		snapshot.Torrents = append(snapshot.Torrents, snapshotTorrent(torrent))
+	}
+	sort.Slice(snapshot.Torrents, func(i, j int) bool { return snapshot.Torrents[i].Hash < snapshot.Torrents[j].Hash })
+	return snapshot, nil
+}
+
+// snapshotTorrent captures the restorable settings of a torrent
+func snapshotTorrent(torrent TorrentInfo) SnapshotTorrent {
+	tags := append([]string{}, torrent.Tags...)
+	inactive := torrent.InactiveSeedingTimeLimit
+	return SnapshotTorrent{
+		Hash:             torrent.Hash,
+		Name:             torrent.Name,
+		Category:         torrent.Category,
+		Tags:             tags,
+		SavePath:         torrent.SavePath,
+		State:            torrent.State,
+		RatioLimit:       torrent.RatioLimit,
+		SeedingTimeLimit: torrent.SeedingTimeLimit,
+
+		InactiveSeedingTimeLimit: &inactive,
+	}
+}
+
+// SnapshotRestore re-applies the categories, tags and share limits of a snapshot. Categories and tags
+// missing on the server are created; torrents of the snapshot that exist on the server get their category,
+// tags and share limits restored. Torrents are not added and nothing is removed.
+func (c *Client) SnapshotRestore(snapshot *Snapshot) error {
+	if snapshot.Version > SnapshotVersion {
+		return fmt.Errorf("SnapshotRestore error: unsupported snapshot version %d", snapshot.Version)
+	}
+
+	categories, err := c.TorrentsCategories()
+	if err != nil {
+		return fmt.Errorf("SnapshotRestore error: %w", err)
+	}
+	names := make([]string, 0, len(snapshot.Categories))
+	for name := range snapshot.Categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := categories[name]; ok {
+			continue
+		}
+		savePath, _ := snapshot.Categories[name]["savePath"].(string)
+		if err := c.TorrentsCreateCategory(name, savePath); err != nil {
+			return fmt.Errorf("SnapshotRestore error: %w", err)
+		}
+	}
+
+	if len(snapshot.Tags) > 0 {
+		if err := c.TorrentsCreateTags(snapshot.Tags); err != nil {
+			return fmt.Errorf("SnapshotRestore error: %w", err)
+		}
+	}
+
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return fmt.Errorf("SnapshotRestore error: %w", err)
+	}
+	current := make(map[InfoHash]SnapshotTorrent, len(torrents))
+	for _, torrent := range torrents {
+		current[torrent.Hash] = snapshotTorrent(torrent)
+	}
+
+	for _, want := range snapshot.Torrents {
+		have, ok := current[want.Hash]
+		if !ok {
+			continue
+		}
+		if err := c.restoreTorrent(have, want); err != nil {
+			return fmt.Errorf("SnapshotRestore error: %s: %w", want.Hash, err)
+		}
+	}
+	return nil
+}
+
+// restoreTorrent applies the category, tags and share limits of want onto a torrent currently described by have
+func (c *Client) restoreTorrent(have, want SnapshotTorrent) error {
+	hash := string(want.Hash)
+	if have.Category != want.Category {
+		if err := c.TorrentsSetCategory(hash, want.Category); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for _, tag := range want.Tags {
+		found := false
+		for _, t := range have.Tags {
+			found = found || t == tag
+		}
+		if !found {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) > 0 {
+		if err := c.TorrentsAddTags(hash, missing); err != nil {
+			return err
+		}
+	}
+
+	inactive := *have.InactiveSeedingTimeLimit
+	if want.InactiveSeedingTimeLimit != nil {
+		inactive = *want.InactiveSeedingTimeLimit
+	}
+	if have.RatioLimit != want.RatioLimit || have.SeedingTimeLimit != want.SeedingTimeLimit || *have.InactiveSeedingTimeLimit != inactive {
+		if err := c.TorrentsSetShareLimitsInactive(hash, want.RatioLimit, want.SeedingTimeLimit, inactive); err != nil {
+			return err
+		}
+	}
+	return nil
+}
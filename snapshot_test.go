@@ -0,0 +1,124 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_SnapshotExportRestore(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"bbb","name":"B","category":"tv","tags":"hd, new","save_path":"/tv","state":"uploading","ratio_limit":2,"seeding_time_limit":-2,"inactive_seeding_time_limit":30},
+				{"hash":"aaa","name":"A","category":"","tags":"","save_path":"/dl","state":"pausedUP","ratio_limit":-2,"seeding_time_limit":-2}
+			]`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{"tv":{"name":"tv","savePath":"/tv"}}`))
+		case "/api/v2/torrents/tags":
+			w.Write([]byte(`["hd","new"]`))
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"save_path":"/dl"}`))
+		}
+	}))
+	defer source.Close()
+
+	client := &Client{
+		baseURL: source.URL,
+		client:  source.Client(),
+	}
+
+	snapshot, err := client.SnapshotExport()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if snapshot.Version != SnapshotVersion || len(snapshot.Torrents) != 2 || snapshot.Torrents[0].Hash != "aaa" {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if !reflect.DeepEqual(snapshot.Torrents[1].Tags, []string{"hd", "new"}) {
+		t.Errorf("expected tags [hd new], got %v", snapshot.Torrents[1].Tags)
+	}
+
+	// The snapshot survives a JSON round trip
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var decoded Snapshot
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var calls []url.Values
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"bbb","name":"B","tags":"hd","ratio_limit":-2,"seeding_time_limit":-2,"inactive_seeding_time_limit":-2}]`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{}`))
+		default:
+			r.ParseForm()
+			r.PostForm.Set("path", r.URL.Path)
+			calls = append(calls, r.PostForm)
+		}
+	}))
+	defer target.Close()
+
+	client = &Client{
+		baseURL: target.URL,
+		client:  target.Client(),
+	}
+
+	if err := client.SnapshotRestore(&decoded); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	wantCalls := []url.Values{
+		{"path": {"/api/v2/torrents/createCategory"}, "category": {"tv"}, "savePath": {"/tv"}},
+		{"path": {"/api/v2/torrents/createTags"}, "tags": {"hd,new"}},
+		{"path": {"/api/v2/torrents/setCategory"}, "hashes": {"bbb"}, "category": {"tv"}},
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"bbb"}, "tags": {"new"}},
+		{"path": {"/api/v2/torrents/setShareLimits"}, "hashes": {"bbb"}, "ratioLimit": {"2"}, "seedingTimeLimit": {"-2"}, "inactiveSeedingTimeLimit": {"30"}},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("expected calls %v, got %v", wantCalls, calls)
+	}
+}
+
+func TestClient_SnapshotRestoreShareLimits(t *testing.T) {
+	var calls []url.Values
+	status := http.StatusOK
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"bbb","name":"B","ratio_limit":-2,"seeding_time_limit":-2,"inactive_seeding_time_limit":45}]`))
+		case "/api/v2/torrents/categories":
+			w.Write([]byte(`{}`))
+		default:
+			r.ParseForm()
+			calls = append(calls, r.PostForm)
+			w.WriteHeader(status)
+		}
+	}))
+	defer target.Close()
+	client := &Client{baseURL: target.URL, client: target.Client()}
+
+	// Snapshots without the inactive seeding time limit keep the one on the server
+	snapshot := &Snapshot{Version: SnapshotVersion, Torrents: []SnapshotTorrent{{Hash: "bbb", RatioLimit: 2, SeedingTimeLimit: -2}}}
+	if err := client.SnapshotRestore(snapshot); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(calls) != 1 || calls[0].Get("ratioLimit") != "2" || calls[0].Get("inactiveSeedingTimeLimit") != "45" {
+		t.Errorf("expected the share limits to be set keeping the inactive limit of 45, got %v", calls)
+	}
+
+	status = http.StatusNotFound
+	if err := client.SnapshotRestore(snapshot); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
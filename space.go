@@ -0,0 +1,61 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+)
+
+// FreeSpace returns the free space on the disk of the default save path, as reported by sync maindata.
+// The Web API has no cheaper source: the server sends its whole state, every torrent included, which takes
+// megabytes on large libraries. With a SyncWatcher running, SyncWatcher.FreeSpace has the value for free.
+func (c *Client) FreeSpace() (int64, error) {
+	resp, err := c.syncMainDataRaw(context.Background(), 0)
+	if err != nil {
+		return 0, fmt.Errorf("FreeSpace error: %w", err)
+	}
+	// Only the server state is decoded, the torrents are skipped
+	var data struct {
+		ServerState struct {
+			FreeSpaceOnDisk int64 `json:"free_space_on_disk"`
+		} `json:"server_state"`
+	}
+	if err := c.decodeJSON(resp, &data); err != nil {
+		return 0, fmt.Errorf("failed to decode maindata response: %v", err)
+	}
+	return data.ServerState.FreeSpaceOnDisk, nil
+}
+
+// FreeSpace returns the free space on the disk of the default save path as of the last poll, and false
+// before the first poll
+func (w *SyncWatcher) FreeSpace() (int64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state.ServerState.FreeSpaceOnDisk, w.state.Rid > 0
+}
+
+// WithSpaceGuardWatcher makes WithSpaceGuard read the free space from the last poll of w instead of
+// fetching sync/maindata for every add, see Client.FreeSpace. Until w polled, the space is fetched.
+func WithSpaceGuardWatcher(w *SyncWatcher) TorrentAddOption {
+	return func(o *TorrentsAddOptions) {
+		o.SpaceWatcher = w
+	}
+}
+
+// checkFreeSpace returns ErrInsufficientSpace if a torrent of the given size plus margin does not fit on
+// disk. The free space is taken from watcher if it polled already.
+func (c *Client) checkFreeSpace(size, margin int64, watcher *SyncWatcher) error {
+	free, ok := int64(0), false
+	if watcher != nil {
+		free, ok = watcher.FreeSpace()
+	}
+	if !ok {
+		var err error
+		if free, err = c.FreeSpace(); err != nil {
+			return err
+		}
+	}
+	if size+margin > free {
+		return fmt.Errorf("%w: need %d bytes (including %d margin), %d available", ErrInsufficientSpace, size+margin, margin, free)
+	}
+	return nil
+}
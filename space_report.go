@@ -0,0 +1,125 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// SpaceReport is the disk usage of a server grouped by category, tag and tracker, as computed by
+// Client.Report
+type SpaceReport struct {
+	Total      SpaceUsage
+	Categories map[string]SpaceUsage // per category, "" for the uncategorized torrents
+	Tags       map[string]SpaceUsage // per tag, a torrent counts toward each of its tags, "" for untagged ones
+	Trackers   map[string]SpaceUsage // per tracker hostname, a torrent counts toward each, "" for trackerless ones
+	FreeSpace  int64                 // free space on the disk of the default save path
+}
+
+// SpaceUsage is the disk usage of a group of torrents
+type SpaceUsage struct {
+	Torrents   int
+	Incomplete int   // torrents with bytes left to download
+	Size       int64 // selected size, the space the torrents take once complete
+	OnDisk     int64 // bytes of the selected files downloaded so far
+	Remaining  int64 // bytes left to download, Size minus OnDisk
+	Downloaded int64 // bytes transferred, including discarded and redownloaded data
+	// Shared is the data of torrents reusing the content path of another torrent of the group, as
+	// cross-seeds do. It is not part of Size, OnDisk and Remaining, which count the content once.
+	Shared int64
+}
+
+// add counts torrent unless it shares its content path with a torrent counted before, seen tracks the
+// content paths
+func (u *SpaceUsage) add(torrent TorrentInfo, seen map[string]bool) {
+	u.Torrents++
+	u.Downloaded += torrent.Downloaded
+	if torrent.AmountLeft > 0 {
+		u.Incomplete++
+	}
+	if torrent.ContentPath != "" && seen[torrent.ContentPath] {
+		u.Shared += torrent.Completed
+		return
+	}
+	seen[torrent.ContentPath] = true
+	u.Size += torrent.Size
+	u.OnDisk += torrent.Completed
+	u.Remaining += torrent.AmountLeft
+}
+
+// Report fetches a full sync/maindata update and aggregates the disk usage of its torrents with
+// ComputeSpaceReport, for capacity planning
+func (c *Client) Report(ctx context.Context) (*SpaceReport, error) {
+	data, err := c.syncMainData(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Report error: %w", err)
+	}
+
+	torrents := make([]TorrentInfo, 0, len(data.Torrents))
+	for hash, torrent := range data.Torrents {
+		torrent.Hash = InfoHash(hash)
+		torrents = append(torrents, torrent)
+	}
+	slices.SortFunc(torrents, func(a, b TorrentInfo) int { return cmp.Compare(a.Hash, b.Hash) })
+	return ComputeSpaceReport(data.ServerState, torrents, data.Trackers), nil
+}
+
+// ComputeSpaceReport aggregates the disk usage of torrents, e.g. those kept by a SyncState. trackers maps
+// tracker URLs to the torrents using them, as in MainData.Trackers; without it torrents are grouped by their
+// current tracker only.
+func ComputeSpaceReport(state ServerState, torrents []TorrentInfo, trackers map[string][]InfoHash) *SpaceReport {
+	hosts := make(map[InfoHash][]string)
+	for trackerURL, hashes := range trackers {
+		host := trackerHost(trackerURL)
+		if host == "" {
+			continue
+		}
+		for _, hash := range hashes {
+			if !slices.Contains(hosts[hash], host) {
+				hosts[hash] = append(hosts[hash], host)
+			}
+		}
+	}
+
+	report := &SpaceReport{
+		Categories: make(map[string]SpaceUsage),
+		Tags:       make(map[string]SpaceUsage),
+		Trackers:   make(map[string]SpaceUsage),
+		FreeSpace:  state.FreeSpaceOnDisk,
+	}
+	total := make(map[string]bool)
+	seen := make(map[string]map[string]bool) // content paths per group
+	count := func(groups map[string]SpaceUsage, prefix, key string, torrent TorrentInfo) {
+		if seen[prefix+key] == nil {
+			seen[prefix+key] = make(map[string]bool)
+		}
+		usage := groups[key]
+		usage.add(torrent, seen[prefix+key])
+		groups[key] = usage
+	}
+	for _, torrent := range torrents {
+		report.Total.add(torrent, total)
+		count(report.Categories, "category:", torrent.Category, torrent)
+
+		tags := torrent.Tags
+		if len(tags) == 0 {
+			tags = []string{""}
+		}
+		for _, tag := range tags {
+			count(report.Tags, "tag:", tag, torrent)
+		}
+
+		torrentHosts := hosts[torrent.Hash]
+		if host := trackerHost(torrent.Tracker); host != "" && !slices.Contains(torrentHosts, host) {
+			torrentHosts = append(torrentHosts, host)
+		}
+		if len(torrentHosts) == 0 {
+			torrentHosts = []string{""}
+		}
+		for _, host := range torrentHosts {
+			count(report.Trackers, "tracker:", host, torrent)
+		}
+	}
+	return report
+}
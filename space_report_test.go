@@ -0,0 +1,67 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_Report(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"rid":1,"full_update":true,
+			"server_state":{"free_space_on_disk":500},
+			"torrents":{
+				"a":{"category":"tv","tags":"hd, keep","size":100,"completed":100,"downloaded":120,"content_path":"/d/show","tracker":"https://t1.example/announce"},
+				"b":{"category":"tv","tags":"hd","size":100,"completed":100,"content_path":"/d/show"},
+				"c":{"size":300,"completed":50,"amount_left":250,"downloaded":50,"content_path":"/d/movie"}},
+			"trackers":{"https://t1.example/announce":["a"],"udp://t2.example:80":["a","b"]}}`))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	report, err := client.Report(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := &SpaceReport{
+		Total: SpaceUsage{Torrents: 3, Incomplete: 1, Size: 400, OnDisk: 150, Remaining: 250, Downloaded: 170, Shared: 100},
+		Categories: map[string]SpaceUsage{
+			"tv": {Torrents: 2, Size: 100, OnDisk: 100, Downloaded: 120, Shared: 100},
+			"":   {Torrents: 1, Incomplete: 1, Size: 300, OnDisk: 50, Remaining: 250, Downloaded: 50},
+		},
+		Tags: map[string]SpaceUsage{
+			"hd":   {Torrents: 2, Size: 100, OnDisk: 100, Downloaded: 120, Shared: 100},
+			"keep": {Torrents: 1, Size: 100, OnDisk: 100, Downloaded: 120},
+			"":     {Torrents: 1, Incomplete: 1, Size: 300, OnDisk: 50, Remaining: 250, Downloaded: 50},
+		},
+		Trackers: map[string]SpaceUsage{
+			"t1.example": {Torrents: 1, Size: 100, OnDisk: 100, Downloaded: 120},
+			"t2.example": {Torrents: 2, Size: 100, OnDisk: 100, Downloaded: 120, Shared: 100},
+			"":           {Torrents: 1, Incomplete: 1, Size: 300, OnDisk: 50, Remaining: 250, Downloaded: 50},
+		},
+		FreeSpace: 500,
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("expected\n%+v\ngot\n%+v", want, report)
+	}
+}
+
+func TestClient_ReportContextDeadline(t *testing.T) {
+	// The server never answers, so only the deadline ends the request
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Report(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFreeSpace(t *testing.T) {
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":    {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/sync/maindata": {statusCode: http.StatusOK, responseBody: `{"server_state":{"free_space_on_disk":1024}}`},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "GET", url: "/api/v2/sync/maindata"},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	free, err := client.FreeSpace()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if free != 1024 {
+		t.Errorf("Expected 1024 bytes free, got %d", free)
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
+
+func TestTorrentsAddWithOptions_SpaceGuard(t *testing.T) {
+	torrent := makeTorrent("data", map[string]int64{"file.bin": 1000})
+
+	tests := []struct {
+		name         string
+		margin       int64
+		wantRequests []expectedRequest
+		wantErr      error
+	}{
+		{
+			name:   "fits",
+			margin: 24,
+			wantRequests: []expectedRequest{
+				{method: "POST", url: "/api/v2/auth/login"},
+				{method: "GET", url: "/api/v2/sync/maindata"},
+				{method: "POST", url: "/api/v2/torrents/add"},
+			},
+		},
+		{
+			name:   "exceeds margin",
+			margin: 25,
+			wantRequests: []expectedRequest{
+				{method: "POST", url: "/api/v2/auth/login"},
+				{method: "GET", url: "/api/v2/sync/maindata"},
+			},
+			wantErr: ErrInsufficientSpace,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpointResponses := map[string]mockResponse{
+				"/api/v2/auth/login":    {statusCode: http.StatusOK, responseBody: "Ok."},
+				"/api/v2/sync/maindata": {statusCode: http.StatusOK, responseBody: `{"server_state":{"free_space_on_disk":1024}}`},
+				"/api/v2/torrents/add":  {statusCode: http.StatusOK, responseBody: "Ok."},
+			}
+
+			client, mockTransport, err := newMockClient(endpointResponses, tt.wantRequests)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			err = client.TorrentsAddWithOptions("data.torrent", torrent, WithSpaceGuard(tt.margin))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+				t.Errorf("Not all expected requests were made")
+			}
+		})
+	}
+}
+
+func TestTorrentsAddWithOptions_SpaceGuardWatcher(t *testing.T) {
+	torrent := makeTorrent("data", map[string]int64{"file.bin": 1000})
+	var syncs, adds int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/sync/maindata":
+			syncs++
+			w.Write([]byte(`{"rid":1,"full_update":true,"server_state":{"free_space_on_disk":1024}}`))
+		case "/api/v2/torrents/add":
+			adds++
+			w.Write([]byte("Ok."))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	// Before the watcher polled, the free space is fetched
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{})
+	if err := client.TorrentsAddWithOptions("data.torrent", torrent, WithSpaceGuard(24), WithSpaceGuardWatcher(watcher)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if syncs != 1 {
+		t.Errorf("expected 1 sync request, got %d", syncs)
+	}
+
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if free, ok := watcher.FreeSpace(); !ok || free != 1024 {
+		t.Errorf("expected 1024 bytes free, got %d, %v", free, ok)
+	}
+	if err := client.TorrentsAddWithOptions("data.torrent", torrent, WithSpaceGuard(24), WithSpaceGuardWatcher(watcher)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	err := client.TorrentsAddWithOptions("data.torrent", torrent, WithSpaceGuard(25), WithSpaceGuardWatcher(watcher))
+	if !errors.Is(err, ErrInsufficientSpace) {
+		t.Errorf("expected ErrInsufficientSpace, got %v", err)
+	}
+	if syncs != 2 || adds != 2 {
+		t.Errorf("expected only the poll to sync and 2 adds, got %d syncs and %d adds", syncs, adds)
+	}
+}
+
+func TestTorrentsAddURLs_SpaceGuardSkipped(t *testing.T) {
+	var syncs, adds int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/sync/maindata":
+			syncs++
+			w.Write([]byte(`{"rid":1,"full_update":true,"server_state":{"free_space_on_disk":0}}`))
+		case "/api/v2/torrents/add":
+			adds++
+			w.Write([]byte("Ok."))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	// The size of a magnet link is unknown, so it is added without checking the free space
+	magnet := "magnet:?xt=urn:btih:aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	if err := client.TorrentsAddURLs([]string{magnet}, WithSpaceGuard(1<<30)); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if syncs != 0 || adds != 1 {
+		t.Errorf("expected 0 syncs and 1 add, got %d syncs and %d adds", syncs, adds)
+	}
+}
@@ -0,0 +1,135 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TimeOfDay is a wall clock time, interpreted in the location of the schedule it belongs to
+type TimeOfDay struct {
+	Hour   int
+	Minute int
+}
+
+// minutes returns the number of minutes since midnight
+func (t TimeOfDay) minutes() int {
+	return t.Hour*60 + t.Minute
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+}
+
+// AltSpeedWindow is a period during which the alternative speed limits are active.
+// A window whose To is not after From spans midnight and ends on the following day.
+type AltSpeedWindow struct {
+	Days []time.Weekday // days the window starts on, every day if empty
+	From TimeOfDay
+	To   TimeOfDay
+}
+
+// AltSpeedSchedulerConfig configures an AltSpeedScheduler. Zero values select the defaults.
+type AltSpeedSchedulerConfig struct {
+	Windows  []AltSpeedWindow
+	Location *time.Location // time zone the windows are expressed in, default time.Local
+	Interval time.Duration  // how often Run checks the schedule, default 1m
+
+	// OnChange, if set, is called after the mode was toggled
+	OnChange func(altEnabled bool)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// AltSpeedScheduler enables the alternative speed limits during the configured windows and disables them outside
+type AltSpeedScheduler struct {
+	client *Client
+	cfg    AltSpeedSchedulerConfig
+	now    func() time.Time
+}
+
+// NewAltSpeedScheduler creates an AltSpeedScheduler for the given client
+func NewAltSpeedScheduler(client *Client, cfg AltSpeedSchedulerConfig) *AltSpeedScheduler {
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	return &AltSpeedScheduler{client: client, cfg: cfg, now: time.Now}
+}
+
+// Active reports whether the alternative speed limits should be enabled at t
+func (s *AltSpeedScheduler) Active(t time.Time) bool {
+	t = t.In(s.cfg.Location)
+	now := t.Hour()*60 + t.Minute()
+	yesterday := t.AddDate(0, 0, -1).Weekday()
+
+	for _, w := range s.cfg.Windows {
+		from, to := w.From.minutes(), w.To.minutes()
+		if from < to {
+			if w.onDay(t.Weekday()) && now >= from && now < to {
+				return true
+			}
+			continue
+		}
+		// Overnight window
+		if (w.onDay(t.Weekday()) && now >= from) || (w.onDay(yesterday) && now < to) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w AltSpeedWindow) onDay(day time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, d := range w.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// Run applies the schedule every Interval until ctx is cancelled
+func (s *AltSpeedScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil && s.cfg.OnError != nil {
+			s.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce toggles the alternative speed limits if they don't match the schedule and reports whether it did
+func (s *AltSpeedScheduler) RunOnce(ctx context.Context) (bool, error) {
+	want := s.Active(s.now())
+
+	enabled, err := s.client.TransferSpeedLimitsMode()
+	if err != nil {
+		return false, fmt.Errorf("AltSpeedScheduler error: %w", err)
+	}
+	if enabled == want {
+		return false, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if err := s.client.TransferToggleSpeedLimitsMode(); err != nil {
+		return false, fmt.Errorf("AltSpeedScheduler error: %w", err)
+	}
+	if s.cfg.OnChange != nil {
+		s.cfg.OnChange(want)
+	}
+	return true, nil
+}
@@ -0,0 +1,89 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAltSpeedScheduler_Active(t *testing.T) {
+	berlin := time.FixedZone("CET", 3600)
+	s := NewAltSpeedScheduler(nil, AltSpeedSchedulerConfig{
+		Location: berlin,
+		Windows: []AltSpeedWindow{
+			{Days: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}, From: TimeOfDay{9, 0}, To: TimeOfDay{17, 30}},
+			{Days: []time.Weekday{time.Saturday}, From: TimeOfDay{22, 0}, To: TimeOfDay{2, 0}},
+		},
+	})
+
+	tests := []struct {
+		name string
+		t    time.Time
+		want bool
+	}{
+		{"monday work hours", time.Date(2024, 6, 3, 10, 0, 0, 0, berlin), true},
+		{"monday work hours in UTC", time.Date(2024, 6, 3, 8, 30, 0, 0, time.UTC), true},
+		{"monday before work in UTC", time.Date(2024, 6, 3, 7, 30, 0, 0, time.UTC), false},
+		{"end is exclusive", time.Date(2024, 6, 3, 17, 30, 0, 0, berlin), false},
+		{"sunday daytime", time.Date(2024, 6, 2, 10, 0, 0, 0, berlin), false},
+		{"saturday night", time.Date(2024, 6, 1, 23, 0, 0, 0, berlin), true},
+		{"sunday after midnight", time.Date(2024, 6, 2, 1, 0, 0, 0, berlin), true},
+		{"monday after midnight", time.Date(2024, 6, 3, 1, 0, 0, 0, berlin), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Active(tt.t); got != tt.want {
+				t.Errorf("Active(%v) = %v, want %v", tt.t, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAltSpeedScheduler_RunOnce(t *testing.T) {
+	mode := "0"
+	toggles := 0
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/transfer/speedLimitsMode":
+			w.Write([]byte(mode))
+		case "/api/v2/transfer/toggleSpeedLimitsMode":
+			toggles++
+			if mode == "0" {
+				mode = "1"
+			} else {
+				mode = "0"
+			}
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	s := NewAltSpeedScheduler(client, AltSpeedSchedulerConfig{
+		Location: time.UTC,
+		Windows:  []AltSpeedWindow{{From: TimeOfDay{9, 0}, To: TimeOfDay{17, 0}}},
+	})
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	changed, err := s.RunOnce(context.Background())
+	if err != nil || !changed || mode != "1" {
+		t.Fatalf("expected mode to be toggled on, got changed=%v mode=%s err=%v", changed, mode, err)
+	}
+
+	changed, err = s.RunOnce(context.Background())
+	if err != nil || changed || toggles != 1 {
+		t.Fatalf("expected no change, got changed=%v toggles=%d err=%v", changed, toggles, err)
+	}
+
+	now = now.Add(8 * time.Hour)
+	changed, err = s.RunOnce(context.Background())
+	if err != nil || !changed || mode != "0" {
+		t.Fatalf("expected mode to be toggled off, got changed=%v mode=%s err=%v", changed, mode, err)
+	}
+}
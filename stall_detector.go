@@ -0,0 +1,198 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// StallRemedy is an action a StallDetector takes on a stalled torrent
+type StallRemedy string
+
+const (
+	RemedyReannounce        StallRemedy = "reannounce"         // reannounce to the trackers
+	RemedyForceStart        StallRemedy = "force_start"        // force start, bypassing the queue
+	RemedyDisableSequential StallRemedy = "disable_sequential" // turn sequential downloading off, skipped if it is off
+	RemedyNotify            StallRemedy = "notify"             // only emit a StallEvent
+)
+
+// StallDetectorConfig configures a StallDetector. Zero values select the defaults.
+type StallDetectorConfig struct {
+	Interval   time.Duration // how often torrents are checked, default 1m
+	Threshold  time.Duration // how long a torrent must be stalled before the first remedy, default 30m
+	RetryAfter time.Duration // delay before the next remedy of a torrent that is still stalled, default Threshold
+	// Remedies are applied one after the other while a torrent stays stalled, default RemedyReannounce
+	Remedies []StallRemedy
+
+	// OnEvent, if set, is called after each remedy applied
+	OnEvent func(event StallEvent)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// StallEvent reports a remedy applied by a StallDetector
+type StallEvent struct {
+	Hash       InfoHash
+	Name       string
+	State      string
+	Remedy     StallRemedy
+	Attempt    int           // 1 for the first remedy applied to the torrent
+	StalledFor time.Duration // how long the torrent has been stalled
+	Skipped    bool          // the remedy did not apply to the torrent, e.g. sequential downloading was off
+}
+
+// StallDetector finds torrents stuck in stalledDL or downloading metadata for longer than a threshold and
+// applies remedies to them, escalating to the next remedy while they stay stuck.
+type StallDetector struct {
+	client *Client
+	cfg    StallDetectorConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	tracked map[InfoHash]*stallState
+}
+
+type stallState struct {
+	since   time.Time
+	applied int
+	next    time.Time
+}
+
+// NewStallDetector creates a StallDetector for the given client
+func NewStallDetector(client *Client, cfg StallDetectorConfig) *StallDetector {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = 30 * time.Minute
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = cfg.Threshold
+	}
+	if len(cfg.Remedies) == 0 {
+		cfg.Remedies = []StallRemedy{RemedyReannounce}
+	}
+	return &StallDetector{
+		client:  client,
+		cfg:     cfg,
+		now:     time.Now,
+		tracked: make(map[InfoHash]*stallState),
+	}
+}
+
+// Run checks torrents every Interval until ctx is cancelled
+func (d *StallDetector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.RunOnce(ctx); err != nil && ctx.Err() == nil && d.cfg.OnError != nil {
+			d.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce performs a single pass over the torrents, applying the next remedy to those stalled for too long
+func (d *StallDetector) RunOnce(ctx context.Context) error {
+	torrents, err := d.client.TorrentsInfo()
+	if err != nil {
+		return fmt.Errorf("StallDetector error: %w", err)
+	}
+
+	now := d.now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	stalled := make(map[InfoHash]struct{})
+	for _, torrent := range torrents {
+		if !isStalledState(torrent.State) {
+			continue
+		}
+		stalled[torrent.Hash] = struct{}{}
+
+		state, ok := d.tracked[torrent.Hash]
+		if !ok {
+			state = &stallState{since: stalledSince(torrent, now)}
+			d.tracked[torrent.Hash] = state
+		}
+		stalledFor := now.Sub(state.since)
+		if stalledFor < d.cfg.Threshold || now.Before(state.next) || state.applied >= len(d.cfg.Remedies) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		remedy := d.cfg.Remedies[state.applied]
+		skipped, err := d.apply(remedy, torrent)
+		if err != nil {
+			return fmt.Errorf("StallDetector error: %w", err)
+		}
+		state.applied++
+		state.next = now.Add(d.cfg.RetryAfter)
+		if d.cfg.OnEvent != nil {
+			d.cfg.OnEvent(StallEvent{
+				Hash:       torrent.Hash,
+				Name:       torrent.Name,
+				State:      torrent.State,
+				Remedy:     remedy,
+				Attempt:    state.applied,
+				StalledFor: stalledFor,
+				Skipped:    skipped,
+			})
+		}
+	}
+
+	// Forget torrents that recovered or were removed, a new stall starts over with the first remedy
+	for hash := range d.tracked {
+		if _, ok := stalled[hash]; !ok {
+			delete(d.tracked, hash)
+		}
+	}
+	return nil
+}
+
+// apply applies remedy to torrent, reporting whether it did not apply
+func (d *StallDetector) apply(remedy StallRemedy, torrent TorrentInfo) (skipped bool, err error) {
+	hash := string(torrent.Hash)
+	switch remedy {
+	case RemedyReannounce:
+		return false, d.client.TorrentsReannounce(hash)
+	case RemedyForceStart:
+		if torrent.ForceStart {
+			return true, nil
+		}
+		return false, d.client.SetForceStart(hash, true)
+	case RemedyDisableSequential:
+		if !torrent.SequentialDownload {
+			return true, nil
+		}
+		return false, d.client.TorrentsToggleSequentialDownload(hash)
+	case RemedyNotify:
+		return false, nil
+	}
+	return false, fmt.Errorf("unknown remedy %q", remedy)
+}
+
+// isStalledState reports whether state is stalledDL or one of the metadata download states
+func isStalledState(state string) bool {
+	switch state {
+	case "stalledDL", "metaDL", "forcedMetaDL":
+		return true
+	}
+	return false
+}
+
+// stalledSince estimates when torrent stalled: at its last activity if known, otherwise now
+func stalledSince(torrent TorrentInfo, now time.Time) time.Time {
+	if last := torrent.LastActivityAt(); !last.IsZero() && last.Before(now) {
+		return last
+	}
+	return now
+}
@@ -0,0 +1,67 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStallDetector_RunOnce(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	var requests []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[
+				{"hash":"stuck","state":"stalledDL","last_activity":1699990000,"seq_dl":true},
+				{"hash":"meta","state":"metaDL"},
+				{"hash":"fine","state":"downloading","last_activity":1600000000}
+			]`))
+		default:
+			requests = append(requests, r.URL.Path+" "+r.PostForm.Get("hashes"))
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	var events []StallEvent
+	d := NewStallDetector(client, StallDetectorConfig{
+		Threshold:  time.Hour,
+		RetryAfter: 10 * time.Minute,
+		Remedies:   []StallRemedy{RemedyReannounce, RemedyDisableSequential, RemedyNotify},
+		OnEvent:    func(event StallEvent) { events = append(events, event) },
+	})
+	d.now = func() time.Time { return now }
+
+	// stuck has been inactive for 10000s, meta was only just seen stalled
+	for i := 0; i < 5; i++ {
+		if err := d.RunOnce(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		now = now.Add(5 * time.Minute)
+	}
+	want := []string{
+		"/api/v2/torrents/reannounce stuck",
+		"/api/v2/torrents/toggleSequentialDownload stuck",
+	}
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf("expected requests %v, got %v", want, requests)
+	}
+	var remedies []StallRemedy
+	for _, event := range events {
+		if event.Hash != "stuck" {
+			t.Errorf("unexpected event %+v", event)
+		}
+		remedies = append(remedies, event.Remedy)
+	}
+	if want := []StallRemedy{RemedyReannounce, RemedyDisableSequential, RemedyNotify}; !reflect.DeepEqual(remedies, want) {
+		t.Errorf("expected remedies %v, got %v", want, remedies)
+	}
+	if events[0].Attempt != 1 || events[0].StalledFor != 10000*time.Second {
+		t.Errorf("unexpected first event %+v", events[0])
+	}
+}
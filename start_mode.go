@@ -0,0 +1,60 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// StartMode is how a torrent is started, see StartingState and SetStartMode
+type StartMode string
+
+const (
+	StartModeStopped StartMode = "stopped" // paused (4.x) or stopped (5.x)
+	StartModeNormal  StartMode = "normal"  // started and managed by the queue, which may hold it back
+	StartModeQueued  StartMode = "queued"  // started but held back by the queue, reported by StartingState only
+	StartModeForced  StartMode = "forced"  // started bypassing the queue
+)
+
+// StartingState returns how torrent is started. Normal torrents are auto-managed in libtorrent's terms: the
+// queueing limits decide whether they run, and StartModeQueued is reported while they wait.
+func StartingState(torrent TorrentInfo) StartMode {
+	switch {
+	case IsPausedState(torrent.State):
+		return StartModeStopped
+	case torrent.ForceStart || strings.HasPrefix(torrent.State, "forced"):
+		return StartModeForced
+	case torrent.State == "queuedDL" || torrent.State == "queuedUP":
+		return StartModeQueued
+	}
+	return StartModeNormal
+}
+
+// SetStartMode puts the specified torrents in mode, StartModeStopped, StartModeNormal or StartModeForced.
+// Multiple hashes are separated by "|". The endpoints interact: setForceStart starts torrents whichever way
+// it sets the flag, so a torrent is only stopped after its flag was cleared. Automatic torrent management,
+// set with TorrentsSetAutoManagement, decides save paths rather than starting and is left alone.
+func (c *Client) SetStartMode(hashes string, mode StartMode) error {
+	switch mode {
+	case StartModeStopped:
+		if err := c.SetForceStart(hashes, false); err != nil {
+			return fmt.Errorf("SetStartMode error: %w", err)
+		}
+		if err := c.TorrentsPause(hashes); err != nil {
+			return fmt.Errorf("SetStartMode error: %w", err)
+		}
+	case StartModeNormal:
+		if err := c.SetForceStart(hashes, false); err != nil {
+			return fmt.Errorf("SetStartMode error: %w", err)
+		}
+		if err := c.TorrentsResume(hashes); err != nil {
+			return fmt.Errorf("SetStartMode error: %w", err)
+		}
+	case StartModeForced:
+		if err := c.SetForceStart(hashes, true); err != nil {
+			return fmt.Errorf("SetStartMode error: %w", err)
+		}
+	default:
+		return fmt.Errorf("SetStartMode error: unsupported mode %q", mode)
+	}
+	return nil
+}
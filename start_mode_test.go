@@ -0,0 +1,64 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestStartingState(t *testing.T) {
+	tests := []struct {
+		torrent TorrentInfo
+		want    StartMode
+	}{
+		{TorrentInfo{State: StatePausedDL}, StartModeStopped},
+		{TorrentInfo{State: StateStoppedUP, ForceStart: true}, StartModeStopped},
+		{TorrentInfo{State: "forcedDL", ForceStart: true}, StartModeForced},
+		{TorrentInfo{State: "forcedMetaDL"}, StartModeForced},
+		{TorrentInfo{State: "queuedDL"}, StartModeQueued},
+		{TorrentInfo{State: "stalledUP"}, StartModeNormal},
+	}
+	for _, tt := range tests {
+		if got := StartingState(tt.torrent); got != tt.want {
+			t.Errorf("StartingState(%s) = %s, want %s", tt.torrent.State, got, tt.want)
+		}
+	}
+}
+
+func TestSetStartMode(t *testing.T) {
+	hashes := url.Values{"hashes": {"a|b"}}
+	endpointResponses := map[string]mockResponse{
+		"/api/v2/auth/login":             {statusCode: http.StatusOK, responseBody: "Ok."},
+		"/api/v2/app/webapiVersion":      {statusCode: http.StatusOK, responseBody: "2.9.3"},
+		"/api/v2/torrents/setForceStart": {statusCode: http.StatusOK},
+		"/api/v2/torrents/pause":         {statusCode: http.StatusOK},
+		"/api/v2/torrents/resume":        {statusCode: http.StatusOK},
+	}
+	expectedRequests := []expectedRequest{
+		{method: "POST", url: "/api/v2/auth/login"},
+		{method: "POST", url: "/api/v2/torrents/setForceStart", params: url.Values{"hashes": {"a|b"}, "value": {"false"}}},
+		{method: "GET", url: "/api/v2/app/webapiVersion"},
+		{method: "POST", url: "/api/v2/torrents/pause", params: hashes},
+		{method: "POST", url: "/api/v2/torrents/setForceStart", params: url.Values{"hashes": {"a|b"}, "value": {"false"}}},
+		{method: "POST", url: "/api/v2/torrents/resume", params: hashes},
+		{method: "POST", url: "/api/v2/torrents/setForceStart", params: url.Values{"hashes": {"a|b"}, "value": {"true"}}},
+	}
+
+	client, mockTransport, err := newMockClient(endpointResponses, expectedRequests)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, mode := range []StartMode{StartModeStopped, StartModeNormal, StartModeForced} {
+		if err := client.SetStartMode("a|b", mode); err != nil {
+			t.Fatalf("Expected no error for %s, got %v", mode, err)
+		}
+	}
+	if err := client.SetStartMode("a|b", StartModeQueued); err == nil {
+		t.Error("Expected an error for the queued mode")
+	}
+
+	if mockTransport.requestIndex != len(mockTransport.expectedRequests) {
+		t.Errorf("Not all expected requests were made")
+	}
+}
@@ -0,0 +1,99 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+)
+
+// Stats are the aggregate numbers of a server, as computed by Client.Stats
+type Stats struct {
+	Torrents   int                      // number of torrents
+	ByState    map[string]int           // number of torrents per state
+	Categories map[string]CategoryStats // per category, "" for the uncategorized torrents
+
+	TotalSize       int64 // selected size of all torrents
+	Remaining       int64 // bytes left to download
+	SeedingTorrents int   // complete torrents that are neither paused nor stopped
+	SeedingSize     int64 // selected size of the torrents being seeded
+
+	SessionDownloaded int64 // bytes downloaded since qBittorrent started
+	SessionUploaded   int64 // bytes uploaded since qBittorrent started
+	AllTimeDownloaded int64
+	AllTimeUploaded   int64
+	DownloadSpeed     int64 // bytes per second
+	UploadSpeed       int64
+
+	FreeSpace        int64 // free space on the disk of the default save path
+	ConnectionStatus ConnectionStatus
+}
+
+// CategoryStats are the aggregate numbers of the torrents of a category
+type CategoryStats struct {
+	Torrents int
+	Size     int64 // selected size
+	OnDisk   int64 // bytes of the selected files downloaded so far
+}
+
+// Stats fetches the transfer info and a full sync/maindata update, whose server state and torrents it
+// aggregates with ComputeStats. The torrents of the update are used instead of a separate TorrentsInfo
+// request, they carry the same fields.
+func (c *Client) Stats(ctx context.Context) (*Stats, error) {
+	transfer, err := c.transferInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("Stats error: %w", err)
+	}
+	data, err := c.syncMainData(ctx, 0)
+	if err != nil {
+		return nil, fmt.Errorf("Stats error: %w", err)
+	}
+
+	torrents := make([]TorrentInfo, 0, len(data.Torrents))
+	for hash, torrent := range data.Torrents {
+		torrent.Hash = InfoHash(hash)
+		torrents = append(torrents, torrent)
+	}
+	return ComputeStats(*transfer, data.ServerState, torrents), nil
+}
+
+// ComputeStats aggregates the transfer info, server state and torrents of a server, e.g. those kept by a
+// SyncState
+func ComputeStats(transfer TransferInfo, state ServerState, torrents []TorrentInfo) *Stats {
+	stats := &Stats{
+		Torrents:          len(torrents),
+		ByState:           make(map[string]int),
+		Categories:        make(map[string]CategoryStats),
+		SessionDownloaded: transfer.DLInfoData,
+		SessionUploaded:   transfer.UpInfoData,
+		AllTimeDownloaded: state.AllTimeDL,
+		AllTimeUploaded:   state.AllTimeUL,
+		DownloadSpeed:     transfer.DLInfoSpeed,
+		UploadSpeed:       transfer.UpInfoSpeed,
+		FreeSpace:         state.FreeSpaceOnDisk,
+		ConnectionStatus:  transfer.ConnectionStatus,
+	}
+	for _, torrent := range torrents {
+		stats.ByState[torrent.State]++
+		stats.TotalSize += torrent.Size
+		stats.Remaining += torrent.AmountLeft
+		if isSeedingState(torrent.State) {
+			stats.SeedingTorrents++
+			stats.SeedingSize += torrent.Size
+		}
+
+		category := stats.Categories[torrent.Category]
+		category.Torrents++
+		category.Size += torrent.Size
+		category.OnDisk += torrent.Completed
+		stats.Categories[torrent.Category] = category
+	}
+	return stats
+}
+
+// isSeedingState reports whether state is one of a complete torrent that is not paused or stopped
+func isSeedingState(state string) bool {
+	switch state {
+	case "uploading", "stalledUP", "forcedUP", "queuedUP":
+		return true
+	}
+	return false
+}
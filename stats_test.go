@@ -0,0 +1,77 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/transfer/info":
+			w.Write([]byte(`{"dl_info_speed":100,"dl_info_data":1000,"up_info_speed":50,"up_info_data":2000,"connection_status":"connected"}`))
+		case "/api/v2/sync/maindata":
+			w.Write([]byte(`{"rid":1,"full_update":true,
+				"server_state":{"alltime_dl":10000,"alltime_ul":20000,"free_space_on_disk":500},
+				"torrents":{
+					"a":{"state":"uploading","category":"tv","size":100,"completed":100},
+					"b":{"state":"stalledUP","category":"tv","size":200,"completed":200},
+					"c":{"state":"downloading","size":300,"completed":50,"amount_left":250},
+					"d":{"state":"pausedUP","category":"movies","size":400,"completed":400}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	stats, err := client.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := &Stats{
+		Torrents: 4,
+		ByState:  map[string]int{"uploading": 1, "stalledUP": 1, "downloading": 1, "pausedUP": 1},
+		Categories: map[string]CategoryStats{
+			"tv":     {Torrents: 2, Size: 300, OnDisk: 300},
+			"":       {Torrents: 1, Size: 300, OnDisk: 50},
+			"movies": {Torrents: 1, Size: 400, OnDisk: 400},
+		},
+		TotalSize:         1000,
+		Remaining:         250,
+		SeedingTorrents:   2,
+		SeedingSize:       300,
+		SessionDownloaded: 1000,
+		SessionUploaded:   2000,
+		AllTimeDownloaded: 10000,
+		AllTimeUploaded:   20000,
+		DownloadSpeed:     100,
+		UploadSpeed:       50,
+		FreeSpace:         500,
+		ConnectionStatus:  "connected",
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("expected %+v, got %+v", want, stats)
+	}
+}
+
+func TestStats_ContextDeadline(t *testing.T) {
+	// The server never answers, so only the deadline ends the request
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Stats(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// StatusError is returned when the server answers a request with a status other than 200 OK. It wraps
+// ErrNotFound, ErrConflict or ErrInvalidTorrentFile for the statuses the Web API documents,
+// so they can be tested with errors.Is, and carries the offending request parameters.
+type StatusError struct {
+	Endpoint   string
+	StatusCode int
+	Message    string // the beginning of the response body
+	Hash       string // the hash or hashes parameter of the request, if any
+	// Param names the parameter a 409 Conflict is documented for on Endpoint, e.g. "newUrl" for
+	// editTracker, with Value its value in the request. Both are empty for other statuses.
+	Param string
+	Value string
+
+	CorrelationID string // the correlation ID the request was sent with, see WithCorrelationID
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("unexpected response code: %d, response: %s", e.StatusCode, e.Message)
+	if e.Hash != "" {
+		msg += fmt.Sprintf(" (hash %s)", e.Hash)
+	}
+	if e.Param != "" {
+		msg += fmt.Sprintf(" (%s %q)", e.Param, e.Value)
+	}
+	if e.CorrelationID != "" {
+		msg += fmt.Sprintf(" (correlation ID %s)", e.CorrelationID)
+	}
+	return msg
+}
+
+// Unwrap returns the sentinel error matching the status, nil for undocumented ones
+func (e *StatusError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusUnsupportedMediaType:
+		return ErrInvalidTorrentFile
+	}
+	return nil
+}
+
+// conflictParams maps endpoints to the parameter their documented 409 Conflict is about
+var conflictParams = map[string]string{
+	"/api/v2/torrents/editTracker":    "newUrl",
+	"/api/v2/torrents/setCategory":    "category",
+	"/api/v2/torrents/createCategory": "category",
+	"/api/v2/torrents/editCategory":   "category",
+	"/api/v2/torrents/setLocation":    "location",
+	"/api/v2/torrents/renameFile":     "newPath",
+	"/api/v2/torrents/renameFolder":   "newPath",
+	"/api/v2/torrents/filePrio":       "id",
+	"/api/v2/rss/addFolder":           "path",
+	"/api/v2/rss/addFeed":             "path",
+	"/api/v2/rss/removeItem":          "path",
+	"/api/v2/rss/moveItem":            "destPath",
+}
+
+// newStatusError builds the StatusError for an unsuccessful response to endpoint, reading the beginning of
+// its body. params are the query or form parameters of the request, nil if unknown.
+func (c *Client) newStatusError(resp *http.Response, endpoint string, params url.Values) *StatusError {
+	e := &StatusError{
+		Endpoint:   endpoint,
+		StatusCode: resp.StatusCode,
+		Message:    readErrorBody(resp),
+	}
+	if resp.Request != nil {
+		e.CorrelationID = resp.Request.Header.Get(c.correlationIDHeader())
+	}
+	if params != nil {
+		e.Hash = params.Get("hash")
+		if e.Hash == "" {
+			e.Hash = params.Get("hashes")
+		}
+		if param, ok := conflictParams[endpoint]; ok && resp.StatusCode == http.StatusConflict {
+			e.Param = param
+			e.Value = params.Get(param)
+		}
+	}
+	return e
+}
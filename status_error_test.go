@@ -0,0 +1,58 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusError(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/trackers":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Not Found"))
+		case "/api/v2/torrents/editTracker":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("New URL already exists"))
+		case "/api/v2/torrents/add":
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			w.Write([]byte("Torrent file is not valid"))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	_, err := client.TorrentsTrackers("abc")
+	var statusErr *StatusError
+	if !errors.Is(err, ErrNotFound) || !errors.As(err, &statusErr) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if statusErr.Hash != "abc" || statusErr.Endpoint != "/api/v2/torrents/trackers" || statusErr.Message != "Not Found" {
+		t.Errorf("unexpected status error %+v", statusErr)
+	}
+
+	err = client.TorrentsEditTracker("abc", "http://a/announce", "http://b/announce")
+	if !errors.Is(err, ErrConflict) || !errors.As(err, &statusErr) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if statusErr.Param != "newUrl" || statusErr.Value != "http://b/announce" || statusErr.Hash != "abc" {
+		t.Errorf("unexpected status error %+v", statusErr)
+	}
+	want := `EditTracker error: unexpected response code: 409, response: New URL already exists (hash abc) (newUrl "http://b/announce")`
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+
+	if err := client.TorrentsAdd("a.torrent", []byte("invalid")); !errors.Is(err, ErrInvalidTorrentFile) {
+		t.Errorf("expected ErrInvalidTorrentFile, got %v", err)
+	}
+
+	err = client.TorrentsRecheck("abc")
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusInternalServerError || statusErr.Unwrap() != nil {
+		t.Errorf("expected an undocumented status error, got %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package qbittorrent
+
+import (
+	"fmt"
+)
+
+// StreamReadiness reports whether a file can be streamed while its torrent downloads, see IsStreamable
+type StreamReadiness struct {
+	Ready    bool
+	Buffered int64    // bytes downloaded contiguously from the start of the file
+	Reasons  []string // what keeps the file from being streamable, empty if Ready
+}
+
+// IsStreamable checks whether the file with index fileIndex, see TorrentFile.Index, can be played while the
+// torrent downloads: sequential download and first and last piece priority must be enabled, the file must
+// not be skipped and bufferBytes, or the whole file if smaller, must be downloaded from its start. The
+// reasons of files that are not streamable say how to fix them.
+func (c *Client) IsStreamable(hash string, fileIndex int, bufferBytes int64) (StreamReadiness, error) {
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Hashes: []string{hash}})
+	if err != nil {
+		return StreamReadiness{}, fmt.Errorf("IsStreamable error: %w", err)
+	}
+	if len(torrents) == 0 {
+		return StreamReadiness{}, fmt.Errorf("IsStreamable error: %w: torrent %s", ErrNotFound, hash)
+	}
+	files, err := c.TorrentsFiles(hash)
+	if err != nil {
+		return StreamReadiness{}, fmt.Errorf("IsStreamable error: %w", err)
+	}
+	var file *TorrentFile
+	for i := range files {
+		if files[i].Index == fileIndex {
+			file = &files[i]
+		}
+	}
+	if file == nil {
+		return StreamReadiness{}, fmt.Errorf("IsStreamable error: %w: file %d of torrent %s", ErrNotFound, fileIndex, hash)
+	}
+	pieces, err := c.TorrentsPieceMap(hash)
+	if err != nil {
+		return StreamReadiness{}, fmt.Errorf("IsStreamable error: %w", err)
+	}
+
+	var r StreamReadiness
+	r.Buffered = pieces.FileBuffered(*file)
+	if !torrents[0].SequentialDownload {
+		r.Reasons = append(r.Reasons, "sequential download is disabled, enable it with TorrentsToggleSequentialDownload")
+	}
+	if !torrents[0].FirstLastPiecePrio {
+		r.Reasons = append(r.Reasons, "first and last piece priority is disabled, enable it with TorrentsToggleFirstLastPiecePrio")
+	}
+	if file.Priority == FilePriorityDontDownload {
+		r.Reasons = append(r.Reasons, "the file is not downloaded, raise its priority with TorrentsSetFilePriority")
+	}
+	if want := min(bufferBytes, file.Size); r.Buffered < want {
+		r.Reasons = append(r.Reasons, fmt.Sprintf("%s of %s are buffered, wait for more to download", FormatBytes(r.Buffered, IEC), FormatBytes(want, IEC)))
+	}
+	r.Ready = len(r.Reasons) == 0
+	return r, nil
+}
@@ -0,0 +1,74 @@
+package qbittorrent
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// streamServer fakes a server with a torrent whose first file has two of four pieces downloaded
+func streamServer(info string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(info))
+		case "/api/v2/torrents/files":
+			w.Write([]byte(`[{"index":0,"name":"a.mkv","size":4096,"priority":1,"piece_range":[0,3]},` +
+				`{"index":1,"name":"b.nfo","size":10,"priority":0,"piece_range":[3,3]}]`))
+		case "/api/v2/torrents/properties":
+			w.Write([]byte(`{"piece_size":1024}`))
+		case "/api/v2/torrents/pieceStates":
+			w.Write([]byte(`[2,2,1,0]`))
+		case "/api/v2/torrents/pieceHashes":
+			w.Write([]byte(`["a","b","c","d"]`))
+		}
+	}))
+}
+
+func TestClient_IsStreamable(t *testing.T) {
+	mockServer := streamServer(`[{"hash":"abc","seq_dl":true,"f_l_piece_prio":true}]`)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	r, err := client.IsStreamable("abc", 0, 2048)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !r.Ready || r.Buffered != 2048 || len(r.Reasons) != 0 {
+		t.Errorf("expected a streamable file, got %+v", r)
+	}
+
+	r, err = client.IsStreamable("abc", 0, 3000)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if r.Ready || !reflect.DeepEqual(r.Reasons, []string{"2.0 KiB of 2.9 KiB are buffered, wait for more to download"}) {
+		t.Errorf("expected an insufficient buffer, got %+v", r)
+	}
+
+	if _, err := client.IsStreamable("abc", 5, 0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing file, got %v", err)
+	}
+}
+
+func TestClient_IsStreamable_Reasons(t *testing.T) {
+	mockServer := streamServer(`[{"hash":"abc"}]`)
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	r, err := client.IsStreamable("abc", 1, 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{
+		"sequential download is disabled, enable it with TorrentsToggleSequentialDownload",
+		"first and last piece priority is disabled, enable it with TorrentsToggleFirstLastPiecePrio",
+		"the file is not downloaded, raise its priority with TorrentsSetFilePriority",
+		"0 B of 1 B are buffered, wait for more to download",
+	}
+	if r.Ready || !reflect.DeepEqual(r.Reasons, want) {
+		t.Errorf("expected reasons\n%v\ngot\n%v", want, r.Reasons)
+	}
+}
@@ -0,0 +1,373 @@
+package qbittorrent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SyncEventType identifies what changed between two sync/maindata updates
+type SyncEventType int
+
+const (
+	EventTorrentAdded        SyncEventType = iota + 1 // a torrent appeared
+	EventTorrentRemoved                               // a torrent was removed, Torrent holds its last known value
+	EventTorrentCompleted                             // a torrent finished downloading
+	EventTorrentStateChanged                          // a torrent's State changed
+)
+
+func (t SyncEventType) String() string {
+	switch t {
+	case EventTorrentAdded:
+		return "added"
+	case EventTorrentRemoved:
+		return "removed"
+	case EventTorrentCompleted:
+		return "completed"
+	case EventTorrentStateChanged:
+		return "state changed"
+	}
+	return fmt.Sprintf("SyncEventType(%d)", int(t))
+}
+
+// SyncEvent describes a change to a torrent observed by a SyncWatcher
+type SyncEvent struct {
+	Type     SyncEventType
+	Hash     InfoHash
+	Torrent  TorrentInfo // the torrent after the change
+	Previous TorrentInfo // the torrent before the change, zero for EventTorrentAdded
+}
+
+// SyncState is the full view of the server accumulated from incremental sync/maindata updates
+type SyncState struct {
+	Rid         int
+	ServerState ServerState
+	Torrents    map[InfoHash]TorrentInfo
+	Categories  map[string]Category
+	Tags        []string
+	Trackers    map[string][]InfoHash
+}
+
+// rawMainData is a sync/maindata response with the partially updated objects left undecoded
+type rawMainData struct {
+	Rid               int                        `json:"rid"`
+	FullUpdate        bool                       `json:"full_update"`
+	ServerState       json.RawMessage            `json:"server_state"`
+	Torrents          map[string]json.RawMessage `json:"torrents"`
+	TorrentsRemoved   []string                   `json:"torrents_removed"`
+	Categories        map[string]Category        `json:"categories"`
+	CategoriesRemoved []string                   `json:"categories_removed"`
+	Tags              []string                   `json:"tags"`
+	TagsRemoved       []string                   `json:"tags_removed"`
+	Trackers          map[string][]InfoHash      `json:"trackers"`
+	TrackersRemoved   []string                   `json:"trackers_removed"`
+}
+
+// apply merges a sync/maindata response into the state and returns the resulting torrent events.
+// Torrent states are mapped according to normalization, strict selects strict decoding.
+// The first update only establishes the baseline and produces no events.
+func (s *SyncState) apply(resp []byte, normalization StateNormalization, strict bool) ([]SyncEvent, error) {
+	var data rawMainData
+	if err := decodeJSON(resp, &data, strict); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	baseline := s.Torrents == nil
+	reset := data.FullUpdate || baseline
+
+	// Everything is decoded before the state changes, so a malformed response leaves it untouched
+	serverState := s.ServerState
+	if reset {
+		serverState = ServerState{}
+	}
+	if len(data.ServerState) > 0 {
+		if err := decodeJSON(data.ServerState, &serverState, strict); err != nil {
+			return nil, fmt.Errorf("failed to decode server state: %w", err)
+		}
+	}
+
+	keys := make([]string, 0, len(data.Torrents))
+	for key := range data.Torrents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	previous := s.Torrents
+	updated := make([]TorrentInfo, len(keys))
+	for i, key := range keys {
+		hash := InfoHash(key)
+		// Partial updates only carry the changed fields, so they are decoded over the last known value
+		torrent := previous[hash]
+		if err := decodeJSON(data.Torrents[key], &torrent, strict); err != nil {
+			return nil, fmt.Errorf("failed to decode torrent %s: %w", hash, err)
+		}
+		torrent.Hash = hash
+		torrent.State = normalization.normalize(torrent.State)
+		updated[i] = torrent
+	}
+
+	if reset {
+		s.Torrents = make(map[InfoHash]TorrentInfo, len(data.Torrents))
+		s.Categories = make(map[string]Category)
+		s.Tags = nil
+		s.Trackers = make(map[string][]InfoHash)
+	}
+	s.Rid = data.Rid
+	s.ServerState = serverState
+
+	var events []SyncEvent
+	for _, torrent := range updated {
+		hash := torrent.Hash
+		old, existed := previous[hash]
+		s.Torrents[hash] = torrent
+
+		if baseline {
+			continue
+		}
+		if !existed {
+			events = append(events, SyncEvent{Type: EventTorrentAdded, Hash: hash, Torrent: torrent})
+			continue
+		}
+		if old.Progress < 1 && torrent.Progress >= 1 {
+			events = append(events, SyncEvent{Type: EventTorrentCompleted, Hash: hash, Torrent: torrent, Previous: old})
+		}
+		if old.State != torrent.State {
+			events = append(events, SyncEvent{Type: EventTorrentStateChanged, Hash: hash, Torrent: torrent, Previous: old})
+		}
+	}
+
+	// A full update replaces the torrent list, so anything not in it is gone
+	if data.FullUpdate && !baseline {
+		for hash, old := range previous {
+			if _, ok := s.Torrents[hash]; !ok {
+				events = append(events, SyncEvent{Type: EventTorrentRemoved, Hash: hash, Torrent: old})
+			}
+		}
+	}
+	for _, key := range data.TorrentsRemoved {
+		hash := InfoHash(key)
+		if old, ok := s.Torrents[hash]; ok {
+			delete(s.Torrents, hash)
+			events = append(events, SyncEvent{Type: EventTorrentRemoved, Hash: hash, Torrent: old})
+		}
+	}
+
+	for name, category := range data.Categories {
+		merged := s.Categories[name]
+		if merged == nil {
+			merged = make(Category)
+		}
+		for k, v := range category {
+			merged[k] = v
+		}
+		s.Categories[name] = merged
+	}
+	for _, name := range data.CategoriesRemoved {
+		delete(s.Categories, name)
+	}
+
+	s.Tags = mergeTags(s.Tags, data.Tags, data.TagsRemoved)
+
+	for url, hashes := range data.Trackers {
+		s.Trackers[url] = hashes
+	}
+	for _, url := range data.TrackersRemoved {
+		delete(s.Trackers, url)
+	}
+
+	return events, nil
+}
+
+// mergeTags returns tags with added appended and removed dropped, without duplicates
+func mergeTags(tags, added, removed []string) []string {
+	drop := make(map[string]struct{}, len(removed))
+	for _, tag := range removed {
+		drop[tag] = struct{}{}
+	}
+	seen := make(map[string]struct{})
+	var merged []string
+	for _, list := range [][]string{tags, added} {
+		for _, tag := range list {
+			if _, ok := drop[tag]; ok {
+				continue
+			}
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+			seen[tag] = struct{}{}
+			merged = append(merged, tag)
+		}
+	}
+	return merged
+}
+
+// clone returns a deep copy of the state
+func (s *SyncState) clone() SyncState {
+	out := SyncState{
+		Rid:         s.Rid,
+		ServerState: s.ServerState,
+		Torrents:    make(map[InfoHash]TorrentInfo, len(s.Torrents)),
+		Categories:  make(map[string]Category, len(s.Categories)),
+		Tags:        append([]string(nil), s.Tags...),
+		Trackers:    make(map[string][]InfoHash, len(s.Trackers)),
+	}
+	for hash, torrent := range s.Torrents {
+		torrent.Tags = append([]string(nil), torrent.Tags...)
+		out.Torrents[hash] = torrent
+	}
+	for name, category := range s.Categories {
+		c := make(Category, len(category))
+		for k, v := range category {
+			c[k] = v
+		}
+		out.Categories[name] = c
+	}
+	for url, hashes := range s.Trackers {
+		out.Trackers[url] = append([]InfoHash(nil), hashes...)
+	}
+	return out
+}
+
+// SyncWatcherConfig configures a SyncWatcher. Zero values select the defaults.
+type SyncWatcherConfig struct {
+	// Interval is how often sync/maindata is polled. By default the watcher follows the refresh_interval
+	// the server reports, the interval the WebUI polls at, clamped to MinInterval and MaxInterval.
+	Interval    time.Duration
+	MinInterval time.Duration // lower bound of the server's refresh interval, default 500ms
+	MaxInterval time.Duration // upper bound of the server's refresh interval and of the backoff, default 30s
+	// BusyIOJobs is the number of queued disk jobs from which the server is considered under load. Polling
+	// then backs off, doubling the interval on every poll up to MaxInterval until the queue drains.
+	// Default 100, negative to disable the backoff.
+	BusyIOJobs int
+
+	// OnEvent, if set, is called for every torrent change in the order they were observed
+	OnEvent func(SyncEvent)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// defaultRefreshInterval is the polling interval used until the server reported its refresh interval
+const defaultRefreshInterval = 2 * time.Second
+
+// SyncWatcher polls sync/maindata incrementally, keeps the merged server state and turns differences into events
+type SyncWatcher struct {
+	client *Client
+	cfg    SyncWatcherConfig
+
+	pollMu  sync.Mutex // serializes polls, each continues from the rid of the previous one
+	mu      sync.Mutex // guards state and backoff, not held while a poll waits for the server
+	state   SyncState
+	backoff int // consecutive polls that found the server under load
+}
+
+// NewSyncWatcher creates a SyncWatcher for the given client
+func NewSyncWatcher(client *Client, cfg SyncWatcherConfig) *SyncWatcher {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = 500 * time.Millisecond
+	}
+	if cfg.MaxInterval <= 0 {
+		cfg.MaxInterval = 30 * time.Second
+	}
+	if cfg.BusyIOJobs == 0 {
+		cfg.BusyIOJobs = 100
+	}
+	return &SyncWatcher{client: client, cfg: cfg}
+}
+
+// Run polls until ctx is cancelled, waiting NextInterval between polls
+func (w *SyncWatcher) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if _, err := w.Poll(ctx); err != nil && ctx.Err() == nil && w.cfg.OnError != nil {
+			w.cfg.OnError(err)
+		}
+		timer.Reset(w.NextInterval())
+	}
+}
+
+// NextInterval returns how long to wait before the next poll: Interval if set, otherwise the server's
+// refresh interval, lengthened while the server is under load
+func (w *SyncWatcher) NextInterval() time.Duration {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	interval := w.cfg.Interval
+	if interval <= 0 {
+		interval = defaultRefreshInterval
+		if ms := w.state.ServerState.RefreshInterval; ms > 0 {
+			interval = time.Duration(ms) * time.Millisecond
+		}
+		interval = min(max(interval, w.cfg.MinInterval), w.cfg.MaxInterval)
+	}
+	limit := max(interval, w.cfg.MaxInterval)
+	for i := 0; i < w.backoff && interval < limit; i++ {
+		interval *= 2
+	}
+	return min(interval, limit)
+}
+
+// Poll fetches the changes since the previous poll, applies them and returns the resulting events.
+// OnEvent is called for each event before Poll returns. The state is left as it was if the request or
+// decoding fails, so the next poll fetches the same changes again.
+func (w *SyncWatcher) Poll(ctx context.Context) ([]SyncEvent, error) {
+	events, err := w.poll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("SyncWatcher error: %w", err)
+	}
+
+	if w.cfg.OnEvent != nil {
+		for _, event := range events {
+			w.cfg.OnEvent(event)
+		}
+	}
+	return events, nil
+}
+
+// poll fetches and applies one update. The request is sent without holding mu, so State and the other
+// readers are not blocked by a slow server.
+func (w *SyncWatcher) poll(ctx context.Context) ([]SyncEvent, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	w.mu.Lock()
+	rid := w.state.Rid
+	w.mu.Unlock()
+
+	resp, err := w.client.syncMainDataRaw(ctx, rid)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	events, err := w.state.apply(resp, w.client.stateNormalization, w.client.strictDecoding)
+	if err != nil {
+		return nil, err
+	}
+	if w.cfg.BusyIOJobs > 0 && w.state.ServerState.QueuedIOJobs >= w.cfg.BusyIOJobs {
+		w.backoff++
+	} else {
+		w.backoff = 0
+	}
+	return events, nil
+}
+
+// State returns a copy of the current merged state
+func (w *SyncWatcher) State() SyncState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state.clone()
+}
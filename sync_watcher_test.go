@@ -0,0 +1,227 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSyncWatcher_Poll(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,
+			"server_state":{"dl_info_speed":100,"connection_status":"connected"},
+			"torrents":{"hash1":{"name":"one","state":"downloading","progress":0.5,"tags":"a"},"hash2":{"name":"two","state":"uploading","progress":1}},
+			"categories":{"tv":{"name":"tv","savePath":"/tv"}},
+			"tags":["a","b"],
+			"trackers":{"http://t":["hash1","hash2"]}}`,
+		"1": `{"rid":2,
+			"server_state":{"dl_info_speed":200},
+			"torrents":{"hash1":{"state":"uploading","progress":1},"hash3":{"name":"three","state":"metaDL"}},
+			"torrents_removed":["hash2"],
+			"tags_removed":["b"],
+			"trackers":{"http://t":["hash1"]}}`,
+	}
+	var rids []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.URL.Query().Get("rid")
+		rids = append(rids, rid)
+		w.Write([]byte(responses[rid]))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	var observed []SyncEvent
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{
+		OnEvent: func(event SyncEvent) { observed = append(observed, event) },
+	})
+
+	events, err := watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events for the baseline, got %v", events)
+	}
+
+	events, err = watcher.Poll(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var types []SyncEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	wantTypes := []SyncEventType{EventTorrentCompleted, EventTorrentStateChanged, EventTorrentAdded, EventTorrentRemoved}
+	if !reflect.DeepEqual(types, wantTypes) {
+		t.Fatalf("expected events %v, got %v", wantTypes, types)
+	}
+	if events[0].Hash != "hash1" || events[0].Previous.Progress != 0.5 || events[0].Torrent.Name != "one" {
+		t.Errorf("unexpected completion event %+v", events[0])
+	}
+	if len(observed) != len(events) {
+		t.Errorf("expected OnEvent to observe %d events, got %d", len(events), len(observed))
+	}
+
+	state := watcher.State()
+	if !reflect.DeepEqual(rids, []string{"0", "1"}) || state.Rid != 2 {
+		t.Errorf("unexpected rids %v, state rid %d", rids, state.Rid)
+	}
+	// Partial updates keep the fields that were not sent
+	if state.Torrents["hash1"].Name != "one" || !reflect.DeepEqual(state.Torrents["hash1"].Tags, []string{"a"}) {
+		t.Errorf("expected partial update to keep name and tags, got %+v", state.Torrents["hash1"])
+	}
+	if _, ok := state.Torrents["hash2"]; ok {
+		t.Errorf("expected hash2 to be removed")
+	}
+	if state.ServerState.DLInfoSpeed != 200 || state.ServerState.ConnectionStatus != "connected" {
+		t.Errorf("unexpected server state %+v", state.ServerState)
+	}
+	if !reflect.DeepEqual(state.Tags, []string{"a"}) {
+		t.Errorf("expected tags [a], got %v", state.Tags)
+	}
+	if state.Categories["tv"]["savePath"] != "/tv" {
+		t.Errorf("unexpected categories %v", state.Categories)
+	}
+	if !reflect.DeepEqual(state.Trackers["http://t"], []InfoHash{"hash1"}) {
+		t.Errorf("unexpected trackers %v", state.Trackers)
+	}
+}
+
+func TestSyncWatcher_NextInterval(t *testing.T) {
+	var response string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	poll := func(w *SyncWatcher, state string) time.Duration {
+		t.Helper()
+		response = `{"rid":1,"full_update":true,"server_state":` + state + `}`
+		if _, err := w.Poll(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		return w.NextInterval()
+	}
+
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{MaxInterval: 10 * time.Second})
+	if got := watcher.NextInterval(); got != 2*time.Second {
+		t.Errorf("expected the default interval before the first poll, got %s", got)
+	}
+	if got := poll(watcher, `{"refresh_interval":1500}`); got != 1500*time.Millisecond {
+		t.Errorf("expected the server's refresh interval, got %s", got)
+	}
+	if got := poll(watcher, `{"refresh_interval":100}`); got != 500*time.Millisecond {
+		t.Errorf("expected the minimum interval, got %s", got)
+	}
+	if got := poll(watcher, `{"refresh_interval":60000}`); got != 10*time.Second {
+		t.Errorf("expected the maximum interval, got %s", got)
+	}
+
+	// Polling backs off while the server is under load and recovers once it is not
+	var got []time.Duration
+	for _, jobs := range []string{"150", "150", "150", "150", "0"} {
+		got = append(got, poll(watcher, `{"refresh_interval":2000,"queued_io_jobs":`+jobs+`}`))
+	}
+	want := []time.Duration{4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second, 2 * time.Second}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected intervals %v, got %v", want, got)
+	}
+
+	fixed := NewSyncWatcher(client, SyncWatcherConfig{Interval: time.Minute, BusyIOJobs: -1})
+	if got := poll(fixed, `{"refresh_interval":1500,"queued_io_jobs":1000}`); got != time.Minute {
+		t.Errorf("expected the configured interval, got %s", got)
+	}
+}
+
+func TestSyncWatcher_PollError(t *testing.T) {
+	responses := map[string]string{
+		"0": `{"rid":1,"full_update":true,"torrents":{"hash1":{"name":"one","state":"downloading"}}}`,
+		"1": `{"rid":2,"full_update":true,"server_state":{"free_space_on_disk":1},"torrents":{"hash1":{"name":{"first":"one"}}}}`,
+	}
+	var rids []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.URL.Query().Get("rid")
+		rids = append(rids, rid)
+		w.Write([]byte(responses[rid]))
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{})
+
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// A malformed update leaves the state as it was, so the next poll asks for the same changes again
+	if _, err := watcher.Poll(context.Background()); err == nil {
+		t.Fatal("expected a decode error")
+	}
+	if _, err := watcher.Poll(context.Background()); err == nil {
+		t.Fatal("expected a decode error")
+	}
+	state := watcher.State()
+	if state.Rid != 1 || state.ServerState.FreeSpaceOnDisk != 0 || state.Torrents["hash1"].Name != "one" {
+		t.Errorf("expected the state of the first poll, got %+v", state)
+	}
+	if !reflect.DeepEqual(rids, []string{"0", "1", "1"}) {
+		t.Errorf("expected rids [0 1 1], got %v", rids)
+	}
+}
+
+func TestSyncWatcher_StateDuringPoll(t *testing.T) {
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("rid") == "1" {
+			close(requested)
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Write([]byte(`{"rid":1,"full_update":true,"server_state":{"free_space_on_disk":1024}}`))
+	}))
+	defer mockServer.Close()
+	defer close(release)
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+	watcher := NewSyncWatcher(client, SyncWatcherConfig{})
+	if _, err := watcher.Poll(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := watcher.Poll(ctx)
+		done <- err
+	}()
+	<-requested
+
+	// The state stays readable while the server is slow to answer
+	if free, ok := watcher.FreeSpace(); !ok || free != 1024 {
+		t.Errorf("expected 1024 bytes free, got %d, %v", free, ok)
+	}
+	watcher.State()
+	watcher.NextInterval()
+
+	// Cancelling ctx aborts the request in flight
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the poll to return once ctx was cancelled")
+	}
+}
@@ -0,0 +1,114 @@
+package qbittorrent
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tagRenameChunk is the number of torrents retagged per request by TagRename
+const tagRenameChunk = 100
+
+// TagRename renames a tag, which qBittorrent has no endpoint for. The new tag is created, added to every
+// torrent carrying the old tag and the old tag is removed from them before it is deleted. onProgress, if
+// not nil, is called with the number of torrents retagged so far after each request.
+//
+// When a step fails the torrents already retagged get the old tag back, the new tag is removed from those
+// that did not carry it before and is deleted again if TagRename created it. The returned error then also
+// reports a failed rollback.
+func (c *Client) TagRename(oldTag, newTag string, onProgress func(done, total int)) error {
+	oldTag, newTag = strings.TrimSpace(oldTag), strings.TrimSpace(newTag)
+	if _, err := joinTags([]string{oldTag, newTag}); err != nil {
+		return fmt.Errorf("TagRename error: %w", err)
+	}
+	if oldTag == newTag {
+		return nil
+	}
+
+	existing, err := c.TorrentsGetAllTags()
+	if err != nil {
+		return fmt.Errorf("TagRename error: %w", err)
+	}
+	if !slices.Contains(existing, oldTag) {
+		return fmt.Errorf("TagRename error: %w: tag %q does not exist", ErrInvalidTag, oldTag)
+	}
+	created := !slices.Contains(existing, newTag)
+
+	torrents, err := c.GetTorrentsByTag(oldTag)
+	if err != nil {
+		return fmt.Errorf("TagRename error: %w", err)
+	}
+	if created {
+		if err := c.TorrentsCreateTags([]string{newTag}); err != nil {
+			return fmt.Errorf("TagRename error: %w", err)
+		}
+	}
+
+	hashes := make([]InfoHash, len(torrents))
+	hadNew := make(map[InfoHash]bool)
+	for i, torrent := range torrents {
+		hashes[i] = torrent.Hash
+		if hasTag(torrent, newTag) {
+			hadNew[torrent.Hash] = true
+		}
+	}
+
+	// done holds the torrents tagged with newTag, retagged those that also lost oldTag
+	var done, retagged []InfoHash
+	fail := func(err error) error {
+		err = fmt.Errorf("TagRename error: %w", err)
+		if rerr := c.rollbackTagRename(oldTag, newTag, done, retagged, hadNew, created); rerr != nil {
+			return errors.Join(err, fmt.Errorf("TagRename rollback error: %v", rerr))
+		}
+		return err
+	}
+	for start := 0; start < len(hashes); start += tagRenameChunk {
+		chunk := hashes[start:min(start+tagRenameChunk, len(hashes))]
+		if err := c.TorrentsAddTags(joinHashes(chunk), []string{newTag}); err != nil {
+			return fail(err)
+		}
+		done = append(done, chunk...)
+		if err := c.TorrentsRemoveTags(joinHashes(chunk), []string{oldTag}); err != nil {
+			return fail(err)
+		}
+		retagged = append(retagged, chunk...)
+		if onProgress != nil {
+			onProgress(len(retagged), len(hashes))
+		}
+	}
+
+	if err := c.TorrentsDeleteTags([]string{oldTag}); err != nil {
+		return fail(err)
+	}
+	return nil
+}
+
+// rollbackTagRename undoes a partial TagRename, see there
+func (c *Client) rollbackTagRename(oldTag, newTag string, done, retagged []InfoHash, hadNew map[InfoHash]bool, created bool) error {
+	var errs []error
+	if len(retagged) > 0 {
+		if err := c.TorrentsAddTags(joinHashes(retagged), []string{oldTag}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if created {
+		// Deleting the tag also removes it from all torrents
+		if err := c.TorrentsDeleteTags([]string{newTag}); err != nil {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	}
+	var untag []InfoHash
+	for _, hash := range done {
+		if !hadNew[hash] {
+			untag = append(untag, hash)
+		}
+	}
+	if len(untag) > 0 {
+		if err := c.TorrentsRemoveTags(joinHashes(untag), []string{newTag}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,69 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTagRename(t *testing.T) {
+	var requests []string
+	failRemove := false
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.URL.Path {
+		case "/api/v2/torrents/tags":
+			w.Write([]byte(`["old","new"]`))
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"aaa","tags":"old"},{"hash":"bbb","tags":"new, old"}]`))
+		case "/api/v2/torrents/removeTags":
+			requests = append(requests, r.URL.Path+" "+r.Form.Encode())
+			if failRemove && r.Form.Get("tags") == "old" {
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		default:
+			requests = append(requests, r.URL.Path+" "+r.Form.Encode())
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	var progress [][2]int
+	err := client.TagRename("old", "new", func(done, total int) {
+		progress = append(progress, [2]int{done, total})
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantRequests := []string{
+		"/api/v2/torrents/addTags hashes=aaa%7Cbbb&tags=new",
+		"/api/v2/torrents/removeTags hashes=aaa%7Cbbb&tags=old",
+		"/api/v2/torrents/deleteTags tags=old",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+	if !reflect.DeepEqual(progress, [][2]int{{2, 2}}) {
+		t.Errorf("unexpected progress %v", progress)
+	}
+
+	// bbb already had the new tag and keeps it on rollback
+	requests = nil
+	failRemove = true
+	if err := client.TagRename("old", "new", nil); err == nil {
+		t.Fatal("expected an error")
+	}
+	wantRequests = []string{
+		"/api/v2/torrents/addTags hashes=aaa%7Cbbb&tags=new",
+		"/api/v2/torrents/removeTags hashes=aaa%7Cbbb&tags=old",
+		"/api/v2/torrents/removeTags hashes=aaa&tags=new",
+	}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("expected requests %v, got %v", wantRequests, requests)
+	}
+
+	if err := client.TagRename("missing", "new", nil); err == nil {
+		t.Error("expected an error for a missing tag")
+	}
+}
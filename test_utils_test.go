@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -93,3 +94,61 @@ func newMockClient(responses map[string]mockResponse, expectedRequests []expecte
 	client, err := NewClient("user", "pass", "localhost", "8080", httpClient)
 	return client, transport, err
 }
+
+// bencode encodes strings, integers, lists and dictionaries for building test .torrent files
+func bencode(v interface{}) []byte {
+	switch v := v.(type) {
+	case int:
+		return []byte(fmt.Sprintf("i%de", v))
+	case int64:
+		return []byte(fmt.Sprintf("i%de", v))
+	case string:
+		return []byte(fmt.Sprintf("%d:%s", len(v), v))
+	case []interface{}:
+		out := []byte("l")
+		for _, item := range v {
+			out = append(out, bencode(item)...)
+		}
+		return append(out, 'e')
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := []byte("d")
+		for _, k := range keys {
+			out = append(out, bencode(k)...)
+			out = append(out, bencode(v[k])...)
+		}
+		return append(out, 'e')
+	}
+	panic(fmt.Sprintf("bencode: unsupported type %T", v))
+}
+
+// makeTorrent builds a multi-file .torrent whose files are relative to a root directory called name
+func makeTorrent(name string, files map[string]int64) []byte {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var list []interface{}
+	for _, p := range paths {
+		var segments []interface{}
+		for _, s := range strings.Split(p, "/") {
+			segments = append(segments, s)
+		}
+		list = append(list, map[string]interface{}{"length": files[p], "path": segments})
+	}
+	return bencode(map[string]interface{}{
+		"announce": "http://tracker.example/announce",
+		"info": map[string]interface{}{
+			"name":         name,
+			"piece length": 16384,
+			"pieces":       "",
+			"files":        list,
+		},
+	})
+}
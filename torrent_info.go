@@ -0,0 +1,79 @@
+package qbittorrent
+
+import "time"
+
+// ProgressPercent returns the progress of the selected files as a percentage between 0 and 100
+func (t TorrentInfo) ProgressPercent() float64 {
+	return t.Progress * 100
+}
+
+// ETADuration returns the estimated time to completion and whether there is one: torrents that are complete,
+// paused or not expected to complete report ETAInfinite, for which ok is false
+func (t TorrentInfo) ETADuration() (eta time.Duration, ok bool) {
+	if t.ETA < 0 || t.ETA >= ETAInfinite {
+		return 0, false
+	}
+	return time.Duration(t.ETA) * time.Second, true
+}
+
+// SeedingDuration returns how long the torrent has been seeded in total
+func (t TorrentInfo) SeedingDuration() time.Duration {
+	return secondsDuration(t.SeedingTime)
+}
+
+// ActiveDuration returns how long the torrent has been active, downloading or seeding, in total
+func (t TorrentInfo) ActiveDuration() time.Duration {
+	return secondsDuration(t.TimeActive)
+}
+
+// MaxSeedingDuration returns the seeding time limit in effect for the torrent, its own or the global one,
+// and whether there is one
+func (t TorrentInfo) MaxSeedingDuration() (limit time.Duration, ok bool) {
+	// Unlike the other durations, seeding time limits are reported in minutes
+	if t.MaxSeedingTime < 0 {
+		return 0, false
+	}
+	return time.Duration(t.MaxSeedingTime) * time.Minute, true
+}
+
+// AddedAt returns the time the torrent was added
+func (t TorrentInfo) AddedAt() time.Time {
+	return unixTime(t.AddedOn)
+}
+
+// CompletedAt returns the time the torrent completed, or the zero time if it has not
+func (t TorrentInfo) CompletedAt() time.Time {
+	return unixTime(t.CompletionOn)
+}
+
+// LastActivityAt returns the time data was last sent or received, or the zero time if it never was
+func (t TorrentInfo) LastActivityAt() time.Time {
+	return unixTime(t.LastActivity)
+}
+
+// SeenCompleteAt returns the time a complete copy of the torrent was last seen, or the zero time if it never was
+func (t TorrentInfo) SeenCompleteAt() time.Time {
+	return unixTime(t.SeenComplete)
+}
+
+// Remaining returns the number of bytes of the selected files left to download
+func (t TorrentInfo) Remaining() int64 {
+	return max(t.AmountLeft, 0)
+}
+
+// secondsDuration converts seconds as reported by qBittorrent to a duration, negative values meaning none
+func secondsDuration(seconds int64) time.Duration {
+	return time.Duration(max(seconds, 0)) * time.Second
+}
+
+// unixNever is the "never" timestamp of older qBittorrent versions, -1 as an unsigned 32-bit integer
+const unixNever = 1<<32 - 1
+
+// unixTime converts unix seconds as reported by qBittorrent to a time. 0, negative values and unixNever
+// all mean never and convert to the zero time.
+func unixTime(unix int64) time.Time {
+	if unix <= 0 || unix == unixNever {
+		return time.Time{}
+	}
+	return time.Unix(unix, 0)
+}
@@ -0,0 +1,56 @@
+package qbittorrent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTorrentInfo_Accessors(t *testing.T) {
+	torrent := TorrentInfo{Progress: 0.25, ETA: 90, AddedOn: 1700000000, CompletionOn: -1, AmountLeft: 300}
+	if got := torrent.ProgressPercent(); got != 25 {
+		t.Errorf("expected 25%%, got %v", got)
+	}
+	if eta, ok := torrent.ETADuration(); !ok || eta != 90*time.Second {
+		t.Errorf("expected 90s, got %v, %v", eta, ok)
+	}
+	if got := torrent.AddedAt(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected added time %v", got)
+	}
+	if got := torrent.CompletedAt(); !got.IsZero() {
+		t.Errorf("expected the zero time for an incomplete torrent, got %v", got)
+	}
+	if got := torrent.Remaining(); got != 300 {
+		t.Errorf("expected 300 bytes remaining, got %d", got)
+	}
+
+	for _, eta := range []int64{ETAInfinite, -1} {
+		if _, ok := (TorrentInfo{ETA: eta}).ETADuration(); ok {
+			t.Errorf("expected no ETA for %d", eta)
+		}
+	}
+
+	for _, unix := range []int64{0, -1, unixNever} {
+		if got := (TorrentInfo{LastActivity: unix, SeenComplete: unix}); !got.LastActivityAt().IsZero() || !got.SeenCompleteAt().IsZero() {
+			t.Errorf("expected the zero time for %d", unix)
+		}
+	}
+	if got := (TorrentInfo{SeenComplete: 1700000000}).SeenCompleteAt(); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected seen complete time %v", got)
+	}
+}
+
+func TestTorrentInfo_Durations(t *testing.T) {
+	torrent := TorrentInfo{SeedingTime: 3600, TimeActive: 7200, MaxSeedingTime: 1440}
+	if got := torrent.SeedingDuration(); got != time.Hour {
+		t.Errorf("expected 1h seeding, got %v", got)
+	}
+	if got := torrent.ActiveDuration(); got != 2*time.Hour {
+		t.Errorf("expected 2h active, got %v", got)
+	}
+	if limit, ok := torrent.MaxSeedingDuration(); !ok || limit != 24*time.Hour {
+		t.Errorf("expected a 24h limit, got %v, %v", limit, ok)
+	}
+	if _, ok := (TorrentInfo{MaxSeedingTime: ShareLimitUnlimited}).MaxSeedingDuration(); ok {
+		t.Errorf("expected no limit")
+	}
+}
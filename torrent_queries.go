@@ -0,0 +1,92 @@
+package qbittorrent
+
+import "fmt"
+
+// GetTorrentsByTag retrieves the torrents carrying tag
+func (c *Client) GetTorrentsByTag(tag string) ([]TorrentInfo, error) {
+	if tag == "" {
+		return nil, fmt.Errorf("GetTorrentsByTag error: %w: empty tag", ErrInvalidTag)
+	}
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Tag: tag})
+	if err != nil {
+		return nil, fmt.Errorf("GetTorrentsByTag error: %w", err)
+	}
+	return torrents, nil
+}
+
+// GetTorrentsByCategory retrieves the torrents in category, an empty category selects the uncategorized
+// torrents. Subcategories are not included, see TorrentsInCategoryTree for that.
+func (c *Client) GetTorrentsByCategory(category string) ([]TorrentInfo, error) {
+	if category == "" {
+		// an empty category parameter means "any category" to TorrentsInfo
+		torrents, err := c.TorrentsInfo()
+		if err != nil {
+			return nil, fmt.Errorf("GetTorrentsByCategory error: %w", err)
+		}
+		return FilterTorrents(torrents, func(t TorrentInfo) bool { return t.Category == "" }), nil
+	}
+	torrents, err := c.TorrentsInfo(&TorrentsInfoParams{Category: category})
+	if err != nil {
+		return nil, fmt.Errorf("GetTorrentsByCategory error: %w", err)
+	}
+	return torrents, nil
+}
+
+// GetTorrentsByTracker retrieves the torrents announcing to a tracker on host or one of its subdomains.
+// Unlike TorrentInfo.Tracker, which only holds the current working tracker, every tracker of a torrent
+// is considered. Torrents are returned ordered by hash.
+func (c *Client) GetTorrentsByTracker(host string) ([]TorrentInfo, error) {
+	data, err := c.SyncMainData(0)
+	if err != nil {
+		return nil, fmt.Errorf("GetTorrentsByTracker error: %w", err)
+	}
+
+	var torrents []TorrentInfo
+	for _, hash := range data.TrackerIndex().TorrentsForTrackerHost(host) {
+		torrent, ok := data.Torrents[string(hash)]
+		if !ok {
+			continue
+		}
+		// maindata keys torrents by hash and omits it from the torrent itself
+		torrent.Hash = hash
+		torrents = append(torrents, torrent)
+	}
+	return torrents, nil
+}
+
+// GetTorrentsWhere retrieves the torrents for which match returns true
+func (c *Client) GetTorrentsWhere(match func(TorrentInfo) bool) ([]TorrentInfo, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("GetTorrentsWhere error: %w", err)
+	}
+	return FilterTorrents(torrents, match), nil
+}
+
+// GetTorrentsInState retrieves the torrents whose state is one of states, as reported after state
+// normalization. See IsPausedState to match paused torrents across qBittorrent versions.
+func (c *Client) GetTorrentsInState(states ...string) ([]TorrentInfo, error) {
+	torrents, err := c.TorrentsInfo()
+	if err != nil {
+		return nil, fmt.Errorf("GetTorrentsInState error: %w", err)
+	}
+	return FilterTorrents(torrents, func(t TorrentInfo) bool {
+		for _, state := range states {
+			if t.State == state {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// FilterTorrents returns the torrents for which match returns true, preserving their order
+func FilterTorrents(torrents []TorrentInfo, match func(TorrentInfo) bool) []TorrentInfo {
+	var out []TorrentInfo
+	for _, torrent := range torrents {
+		if match(torrent) {
+			out = append(out, torrent)
+		}
+	}
+	return out
+}
@@ -0,0 +1,94 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func newQueryTestClient(t *testing.T) *Client {
+	t.Helper()
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			switch {
+			case r.URL.Query().Get("tag") == "keep":
+				w.Write([]byte(`[{"hash": "a", "tags": "keep"}]`))
+			case r.URL.Query().Get("category") == "tv":
+				w.Write([]byte(`[{"hash": "b", "category": "tv"}]`))
+			default:
+				w.Write([]byte(`[
+					{"hash": "a", "category": "", "state": "uploading", "tags": "keep"},
+					{"hash": "b", "category": "tv", "state": "pausedUP"},
+					{"hash": "c", "category": "", "state": "stalledDL"}
+				]`))
+			}
+		case "/api/v2/sync/maindata":
+			w.Write([]byte(`{
+				"rid": 1,
+				"full_update": true,
+				"torrents": {"a": {"name": "A"}, "b": {"name": "B"}, "c": {"name": "C"}},
+				"trackers": {
+					"https://tracker.example.org/announce": ["c", "a"],
+					"udp://open.example.org:1337/announce": ["a"],
+					"https://other.net/announce": ["b"],
+					"https://gone.example.org/announce": ["z"]
+				}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(mockServer.Close)
+	return &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+}
+
+func torrentHashes(torrents []TorrentInfo) []InfoHash {
+	var hashes []InfoHash
+	for _, torrent := range torrents {
+		hashes = append(hashes, torrent.Hash)
+	}
+	return hashes
+}
+
+func TestClient_GetTorrentsBy(t *testing.T) {
+	client := newQueryTestClient(t)
+
+	tests := []struct {
+		name  string
+		query func() ([]TorrentInfo, error)
+		want  []InfoHash
+	}{
+		{"tag", func() ([]TorrentInfo, error) { return client.GetTorrentsByTag("keep") }, []InfoHash{"a"}},
+		{"category", func() ([]TorrentInfo, error) { return client.GetTorrentsByCategory("tv") }, []InfoHash{"b"}},
+		{"uncategorized", func() ([]TorrentInfo, error) { return client.GetTorrentsByCategory("") }, []InfoHash{"a", "c"}},
+		{"tracker", func() ([]TorrentInfo, error) { return client.GetTorrentsByTracker("example.org") }, []InfoHash{"a", "c"}},
+		{"tracker subdomain", func() ([]TorrentInfo, error) { return client.GetTorrentsByTracker("open.example.org") }, []InfoHash{"a"}},
+		{"state", func() ([]TorrentInfo, error) { return client.GetTorrentsInState(StatePausedUP, "stalledDL") }, []InfoHash{"b", "c"}},
+		{"where", func() ([]TorrentInfo, error) {
+			return client.GetTorrentsWhere(func(t TorrentInfo) bool { return len(t.Tags) > 0 })
+		}, []InfoHash{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			torrents, err := tt.query()
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := torrentHashes(torrents); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClient_GetTorrentsByTracker_SetsHash(t *testing.T) {
+	torrents, err := newQueryTestClient(t).GetTorrentsByTracker("other.net")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(torrents) != 1 || torrents[0].Hash != "b" || torrents[0].Name != "B" {
+		t.Errorf("unexpected torrents %+v", torrents)
+	}
+}
@@ -0,0 +1,64 @@
+package qbittorrent
+
+import (
+	"cmp"
+	"sort"
+)
+
+// TorrentOrder compares two torrents, returning a negative number when a sorts before b, a positive
+// number when it sorts after b and 0 when they are equal under that order
+type TorrentOrder func(a, b TorrentInfo) int
+
+// Orders usable with SortTorrents, all ascending; wrap them in Descending to reverse them
+var (
+	ByName     TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.Name, b.Name) }
+	ByHash     TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.Hash, b.Hash) }
+	BySize     TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.Size, b.Size) }
+	ByProgress TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.Progress, b.Progress) }
+	ByRatio    TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.Ratio, b.Ratio) }
+	ByAddedOn  TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.AddedOn, b.AddedOn) }
+	// ByActivity orders by the time of the last activity, the least recently active first
+	ByActivity TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(a.LastActivity, b.LastActivity) }
+	// BySpeed orders by combined download and upload speed
+	BySpeed TorrentOrder = func(a, b TorrentInfo) int {
+		return cmp.Compare(a.DLSpeed+a.UpSpeed, b.DLSpeed+b.UpSpeed)
+	}
+	// ByETA orders by estimated time to completion, torrents without an ETA last
+	ByETA TorrentOrder = func(a, b TorrentInfo) int { return cmp.Compare(etaKey(a.ETA), etaKey(b.ETA)) }
+)
+
+// ETAInfinite is the ETA qBittorrent reports, in seconds, for torrents that are not expected to complete
+const ETAInfinite = 8640000
+
+// etaKey maps the infinite and negative ETAs past every real ETA
+func etaKey(eta int64) int64 {
+	if eta < 0 || eta >= ETAInfinite {
+		return ETAInfinite
+	}
+	return eta
+}
+
+// Descending reverses order
+func Descending(order TorrentOrder) TorrentOrder {
+	return func(a, b TorrentInfo) int { return order(b, a) }
+}
+
+// CombineOrders combines orders into one: torrents are compared by the first order, ties are broken by the
+// next one and so on
+func CombineOrders(orders ...TorrentOrder) TorrentOrder {
+	return func(a, b TorrentInfo) int {
+		for _, order := range orders {
+			if c := order(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
+
+// SortTorrents sorts torrents in place by orders, see CombineOrders. The sort is stable, so torrents equal
+// under every order keep their relative position.
+func SortTorrents(torrents []TorrentInfo, orders ...TorrentOrder) {
+	order := CombineOrders(orders...)
+	sort.SliceStable(torrents, func(i, j int) bool { return order(torrents[i], torrents[j]) < 0 })
+}
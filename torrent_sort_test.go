@@ -0,0 +1,37 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortTorrents(t *testing.T) {
+	torrents := []TorrentInfo{
+		{Hash: "a", Name: "beta", Ratio: 1.5, ETA: ETAInfinite, DLSpeed: 10, AddedOn: 3},
+		{Hash: "b", Name: "alpha", Ratio: 0.5, ETA: 60, UpSpeed: 30, AddedOn: 1},
+		{Hash: "c", Name: "gamma", Ratio: 1.5, ETA: 30, AddedOn: 2},
+		{Hash: "d", Name: "delta", Ratio: 0.5, ETA: -1, DLSpeed: 5, UpSpeed: 5, AddedOn: 4},
+	}
+
+	tests := []struct {
+		name   string
+		orders []TorrentOrder
+		want   []InfoHash
+	}{
+		{"name", []TorrentOrder{ByName}, []InfoHash{"b", "a", "d", "c"}},
+		{"eta infinite last", []TorrentOrder{ByETA}, []InfoHash{"c", "b", "a", "d"}},
+		{"added on descending", []TorrentOrder{Descending(ByAddedOn)}, []InfoHash{"d", "a", "c", "b"}},
+		{"ratio then name", []TorrentOrder{Descending(ByRatio), ByName}, []InfoHash{"a", "c", "b", "d"}},
+		{"speed is stable", []TorrentOrder{BySpeed}, []InfoHash{"c", "a", "d", "b"}},
+		{"no orders", nil, []InfoHash{"a", "b", "c", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := append([]TorrentInfo{}, torrents...)
+			SortTorrents(sorted, tt.orders...)
+			if got := torrentHashes(sorted); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
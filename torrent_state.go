@@ -0,0 +1,78 @@
+package qbittorrent
+
+// Torrent states whose names differ between qBittorrent 4.x and 5.x
+const (
+	StatePausedDL  = "pausedDL"  // 4.x: paused while downloading
+	StatePausedUP  = "pausedUP"  // 4.x: paused after completing
+	StateStoppedDL = "stoppedDL" // 5.x name of pausedDL
+	StateStoppedUP = "stoppedUP" // 5.x name of pausedUP
+)
+
+// StateNormalization selects how a Client reports the torrent states renamed in qBittorrent 5.x,
+// so code can be written against one set of names and work with both versions
+type StateNormalization int
+
+const (
+	StatesAsReported StateNormalization = iota // states are passed through unchanged
+	StatesPaused                               // stoppedDL and stoppedUP are reported as pausedDL and pausedUP
+	StatesStopped                              // pausedDL and pausedUP are reported as stoppedDL and stoppedUP
+)
+
+// normalize maps state according to n
+func (n StateNormalization) normalize(state string) string {
+	switch n {
+	case StatesPaused:
+		switch state {
+		case StateStoppedDL:
+			return StatePausedDL
+		case StateStoppedUP:
+			return StatePausedUP
+		}
+	case StatesStopped:
+		switch state {
+		case StatePausedDL:
+			return StateStoppedDL
+		case StatePausedUP:
+			return StateStoppedUP
+		}
+	}
+	return state
+}
+
+// IsPausedState reports whether state is a paused (4.x) or stopped (5.x) state
+func IsPausedState(state string) bool {
+	switch state {
+	case StatePausedDL, StatePausedUP, StateStoppedDL, StateStoppedUP:
+		return true
+	}
+	return false
+}
+
+// translateFilter maps the TorrentsInfo state filters renamed in 5.x to the names the server understands
+func translateFilter(filter string, startStop bool) string {
+	if startStop {
+		switch filter {
+		case "paused":
+			return "stopped"
+		case "resumed":
+			return "running"
+		}
+		return filter
+	}
+	switch filter {
+	case "stopped":
+		return "paused"
+	case "running":
+		return "resumed"
+	}
+	return filter
+}
+
+// needsFilterTranslation reports whether filter is one of the renamed state filters
+func needsFilterTranslation(filter string) bool {
+	switch filter {
+	case "paused", "resumed", "stopped", "running":
+		return true
+	}
+	return false
+}
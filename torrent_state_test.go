@@ -0,0 +1,70 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    APIVersion
+		wantErr bool
+	}{
+		{in: "2.11.2", want: APIVersion{2, 11, 2}},
+		{in: "2.8", want: APIVersion{2, 8, 0}},
+		{in: "v2.9.3", want: APIVersion{2, 9, 3}},
+		{in: "2.9.3.1", wantErr: true},
+		{in: "2.x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAPIVersion(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAPIVersion(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+
+	if !(APIVersion{2, 11, 0}).AtLeast(APIVersionStartStop) || (APIVersion{2, 10, 9}).AtLeast(APIVersionStartStop) {
+		t.Error("unexpected AtLeast result")
+	}
+}
+
+func TestWithStateNormalization(t *testing.T) {
+	var filters []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.11.2"))
+		case "/api/v2/torrents/info":
+			filters = append(filters, r.URL.Query().Get("filter"))
+			w.Write([]byte(`[{"hash":"a","state":"stoppedDL"},{"hash":"b","state":"stoppedUP"},{"hash":"c","state":"uploading"}]`))
+		}
+	}))
+	defer mockServer.Close()
+
+	client, err := NewClientWithOptions("", "", "", "", WithHTTPClient(mockServer.Client()), WithStateNormalization(StatesPaused))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	u, _ := url.Parse(mockServer.URL)
+	client.baseURL = "http://" + u.Host
+
+	torrents, err := client.TorrentsInfo(&TorrentsInfoParams{Filter: "paused"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{StatePausedDL, StatePausedUP, "uploading"}
+	for i, torrent := range torrents {
+		if torrent.State != want[i] {
+			t.Errorf("expected state %s, got %s", want[i], torrent.State)
+		}
+	}
+	if len(filters) != 1 || filters[0] != "stopped" {
+		t.Errorf("expected the paused filter to be sent as stopped, got %v", filters)
+	}
+	if !IsPausedState(StateStoppedUP) || IsPausedState("uploading") {
+		t.Error("unexpected IsPausedState result")
+	}
+}
@@ -0,0 +1,81 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TrackerHealthSummary aggregates the status of one tracker URL across torrents
+type TrackerHealthSummary struct {
+	URL      string
+	Statuses map[TrackerStatus]int // number of torrents per status
+	Failures map[string][]InfoHash // failure message to the torrents that reported it
+}
+
+// TrackerHealthReport maps tracker URLs to their aggregated health
+type TrackerHealthReport map[string]*TrackerHealthSummary
+
+// TrackerHealth fetches the trackers of the given torrents, or of the whole library if hashes is empty,
+// and aggregates their statuses and failure messages per tracker URL.
+// DHT, PeX and LSD entries are skipped.
+func (c *Client) TrackerHealth(hashes []string) (TrackerHealthReport, error) {
+	if len(hashes) == 0 {
+		torrents, err := c.TorrentsInfo()
+		if err != nil {
+			return nil, fmt.Errorf("TrackerHealth error: %w", err)
+		}
+		for _, torrent := range torrents {
+			hashes = append(hashes, string(torrent.Hash))
+		}
+	}
+
+	report := make(TrackerHealthReport)
+	for _, hash := range hashes {
+		trackers, err := c.TorrentsTrackers(hash)
+		if err != nil {
+			return nil, fmt.Errorf("TrackerHealth error: %w", err)
+		}
+		for _, tracker := range trackers {
+			if isPseudoTracker(tracker.URL) {
+				continue
+			}
+			summary, ok := report[tracker.URL]
+			if !ok {
+				summary = &TrackerHealthSummary{
+					URL:      tracker.URL,
+					Statuses: make(map[TrackerStatus]int),
+					Failures: make(map[string][]InfoHash),
+				}
+				report[tracker.URL] = summary
+			}
+			summary.Statuses[tracker.Status]++
+			if tracker.Status == TrackerNotWorking && tracker.Msg != "" {
+				summary.Failures[tracker.Msg] = append(summary.Failures[tracker.Msg], InfoHash(hash))
+			}
+		}
+	}
+	return report, nil
+}
+
+// TorrentsWithMessage returns the torrents, across all trackers, whose failure message
+// contains substr (case-insensitive), e.g. "not registered"
+func (r TrackerHealthReport) TorrentsWithMessage(substr string) []InfoHash {
+	substr = strings.ToLower(substr)
+	seen := make(map[InfoHash]struct{})
+	var hashes []InfoHash
+	for _, summary := range r {
+		for msg, failed := range summary.Failures {
+			if !strings.Contains(strings.ToLower(msg), substr) {
+				continue
+			}
+			for _, hash := range failed {
+				if _, ok := seen[hash]; !ok {
+					seen[hash] = struct{}{}
+					hashes = append(hashes, hash)
+				}
+			}
+		}
+	}
+	sortHashes(hashes)
+	return hashes
+}
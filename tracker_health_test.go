@@ -0,0 +1,70 @@
+package qbittorrent
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestTrackerStatus_UnmarshalJSON(t *testing.T) {
+	var trackers []TrackerInfo
+	if err := json.Unmarshal([]byte(`[{"url":"http://t","status":4,"msg":"down"}]`), &trackers); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if trackers[0].Status != TrackerNotWorking {
+		t.Errorf("expected status %v, got %v", TrackerNotWorking, trackers[0].Status)
+	}
+	if trackers[0].Status.String() != "not working" {
+		t.Errorf("expected 'not working', got '%s'", trackers[0].Status.String())
+	}
+}
+
+func TestClient_TrackerHealth(t *testing.T) {
+	trackers := map[string]string{
+		"hash1": `[{"url":"** [DHT] **","status":0},{"url":"http://a/announce","status":4,"msg":"Torrent not registered"}]`,
+		"hash2": `[{"url":"http://a/announce","status":2}]`,
+		"hash3": `[{"url":"http://b/announce","status":4,"msg":"unregistered torrent"}]`,
+	}
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"hash1"},{"hash":"hash2"},{"hash":"hash3"}]`))
+		case "/api/v2/torrents/trackers":
+			w.Write([]byte(trackers[r.URL.Query().Get("hash")]))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	report, err := client.TrackerHealth(nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("expected 2 trackers, got %d", len(report))
+	}
+	a := report["http://a/announce"]
+	if a.Statuses[TrackerWorking] != 1 || a.Statuses[TrackerNotWorking] != 1 {
+		t.Errorf("unexpected statuses %v", a.Statuses)
+	}
+	if !reflect.DeepEqual(a.Failures["Torrent not registered"], []InfoHash{"hash1"}) {
+		t.Errorf("unexpected failures %v", a.Failures)
+	}
+
+	if got := report.TorrentsWithMessage("NOT REGISTERED"); !reflect.DeepEqual(got, []InfoHash{"hash1"}) {
+		t.Errorf("expected [hash1], got %v", got)
+	}
+	if got := report.TorrentsWithMessage("registered"); !reflect.DeepEqual(got, []InfoHash{"hash1", "hash3"}) {
+		t.Errorf("expected [hash1 hash3], got %v", got)
+	}
+}
@@ -0,0 +1,110 @@
+package qbittorrent
+
+import "sort"
+
+// TrackerIndex indexes the tracker URL to torrents map of sync/maindata by tracker hostname, in both
+// directions. Hostnames carry no announce keys, so unlike the URLs they are safe to log and report, and
+// URL variants of a tracker (http and https, different paths or passkeys) collapse into one entry.
+type TrackerIndex struct {
+	hosts    map[InfoHash][]string // sorted hostnames per torrent
+	torrents map[string][]InfoHash // sorted torrents per hostname
+}
+
+// NewTrackerIndex builds a TrackerIndex from a tracker URL to torrents map, such as MainData.Trackers.
+// URLs without a hostname, such as the DHT, PeX and LSD pseudo trackers, are skipped.
+func NewTrackerIndex(trackers map[string][]InfoHash) *TrackerIndex {
+	hostSets := make(map[InfoHash]map[string]bool)
+	torrentSets := make(map[string]map[InfoHash]bool)
+	for trackerURL, hashes := range trackers {
+		host := trackerHost(trackerURL)
+		if host == "" {
+			continue
+		}
+		if torrentSets[host] == nil {
+			torrentSets[host] = make(map[InfoHash]bool)
+		}
+		for _, hash := range hashes {
+			if hostSets[hash] == nil {
+				hostSets[hash] = make(map[string]bool)
+			}
+			hostSets[hash][host] = true
+			torrentSets[host][hash] = true
+		}
+	}
+
+	index := &TrackerIndex{
+		hosts:    make(map[InfoHash][]string, len(hostSets)),
+		torrents: make(map[string][]InfoHash, len(torrentSets)),
+	}
+	for hash, set := range hostSets {
+		hosts := make([]string, 0, len(set))
+		for host := range set {
+			hosts = append(hosts, host)
+		}
+		sort.Strings(hosts)
+		index.hosts[hash] = hosts
+	}
+	for host, set := range torrentSets {
+		hashes := make([]InfoHash, 0, len(set))
+		for hash := range set {
+			hashes = append(hashes, hash)
+		}
+		sortHashes(hashes)
+		index.torrents[host] = hashes
+	}
+	return index
+}
+
+// TrackerIndex indexes the trackers of the update. Incremental updates only list changed trackers, use
+// SyncState.TrackerIndex for the full picture.
+func (m *MainData) TrackerIndex() *TrackerIndex {
+	return NewTrackerIndex(m.Trackers)
+}
+
+// TrackerIndex indexes the trackers of the state
+func (s *SyncState) TrackerIndex() *TrackerIndex {
+	return NewTrackerIndex(s.Trackers)
+}
+
+// TrackerForTorrent returns the tracker hostname of the torrent, the first in alphabetical order if it has
+// several, or "" if it has none
+func (i *TrackerIndex) TrackerForTorrent(hash InfoHash) string {
+	if hosts := i.hosts[hash]; len(hosts) > 0 {
+		return hosts[0]
+	}
+	return ""
+}
+
+// TrackerHostsForTorrent returns the sorted tracker hostnames of the torrent
+func (i *TrackerIndex) TrackerHostsForTorrent(hash InfoHash) []string {
+	return append([]string(nil), i.hosts[hash]...)
+}
+
+// TorrentsForTrackerHost returns the sorted hashes of the torrents announcing to host or one of its
+// subdomains. host is compared case-insensitively.
+func (i *TrackerIndex) TorrentsForTrackerHost(host string) []InfoHash {
+	set := make(map[InfoHash]bool)
+	for h, hashes := range i.torrents {
+		if hostMatches(h, host) {
+			for _, hash := range hashes {
+				set[hash] = true
+			}
+		}
+	}
+	hashes := make([]InfoHash, 0, len(set))
+	for hash := range set {
+		hashes = append(hashes, hash)
+	}
+	sortHashes(hashes)
+	return hashes
+}
+
+// Hosts returns the sorted tracker hostnames in the index
+func (i *TrackerIndex) Hosts() []string {
+	hosts := make([]string, 0, len(i.torrents))
+	for host := range i.torrents {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
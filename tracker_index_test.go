@@ -0,0 +1,45 @@
+package qbittorrent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrackerIndex(t *testing.T) {
+	index := NewTrackerIndex(map[string][]InfoHash{
+		"https://tracker.example.org/0123456789abcdef/announce": {"b", "a"},
+		"http://TRACKER.example.org:8080/announce?passkey=x":    {"a"},
+		"udp://open.example.org:1337/announce":                  {"c"},
+		"https://other.net/announce.php?passkey=secret":         {"a"},
+		"** [DHT] **": {"a", "b", "c"},
+	})
+
+	if got, want := index.Hosts(), []string{"open.example.org", "other.net", "tracker.example.org"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hosts %v, got %v", want, got)
+	}
+	if got, want := index.TrackerHostsForTorrent("a"), []string{"other.net", "tracker.example.org"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected hosts of a %v, got %v", want, got)
+	}
+	if got := index.TrackerForTorrent("c"); got != "open.example.org" {
+		t.Errorf("expected open.example.org, got %q", got)
+	}
+	if got := index.TrackerForTorrent("unknown"); got != "" {
+		t.Errorf("expected no tracker, got %q", got)
+	}
+
+	tests := []struct {
+		host string
+		want []InfoHash
+	}{
+		{"tracker.example.org", []InfoHash{"a", "b"}},
+		{"Example.org", []InfoHash{"a", "b", "c"}},
+		{"other.net", []InfoHash{"a"}},
+		{"net", []InfoHash{"a"}},
+		{"missing.org", []InfoHash{}},
+	}
+	for _, tt := range tests {
+		if got := index.TorrentsForTrackerHost(tt.host); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.host, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TrackerTagRule tags torrents announcing to Host, or any of its subdomains, with Tag
+type TrackerTagRule struct {
+	Host string
+	Tag  string
+}
+
+// TrackerTaggerConfig configures a TrackerTagger. Zero values select the defaults.
+type TrackerTaggerConfig struct {
+	Rules       []TrackerTagRule
+	RemoveStale bool          // remove rule tags from torrents that no longer match the rule
+	Interval    time.Duration // how often Run applies the rules, default 5m
+
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// TrackerTagChange describes the torrents a TrackerTagger added a tag to or removed it from
+type TrackerTagChange struct {
+	Tag     string
+	Added   []InfoHash
+	Removed []InfoHash
+}
+
+// TrackerTagger keeps torrent tags in sync with the hosts of their trackers
+type TrackerTagger struct {
+	client *Client
+	cfg    TrackerTaggerConfig
+}
+
+// NewTrackerTagger creates a TrackerTagger for the given client
+func NewTrackerTagger(client *Client, cfg TrackerTaggerConfig) *TrackerTagger {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	return &TrackerTagger{client: client, cfg: cfg}
+}
+
+// Run applies the rules every Interval until ctx is cancelled
+func (t *TrackerTagger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := t.RunOnce(ctx); err != nil && ctx.Err() == nil && t.cfg.OnError != nil {
+			t.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce applies the rules to all torrents and returns the changes made, one entry per changed tag
+func (t *TrackerTagger) RunOnce(ctx context.Context) ([]TrackerTagChange, error) {
+	data, err := t.client.SyncMainData(0)
+	if err != nil {
+		return nil, fmt.Errorf("TrackerTagger error: %w", err)
+	}
+
+	// Compute the torrents each tag should be on, several rules may share a tag
+	desired := make(map[string]map[InfoHash]struct{})
+	for _, rule := range t.cfg.Rules {
+		if desired[rule.Tag] == nil {
+			desired[rule.Tag] = make(map[InfoHash]struct{})
+		}
+		for trackerURL, hashes := range data.Trackers {
+			if !hostMatches(trackerHost(trackerURL), rule.Host) {
+				continue
+			}
+			for _, hash := range hashes {
+				desired[rule.Tag][hash] = struct{}{}
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(desired))
+	for tag := range desired {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	var changes []TrackerTagChange
+	for _, tag := range tags {
+		change := TrackerTagChange{Tag: tag}
+		for hash := range desired[tag] {
+			if torrent, ok := data.Torrents[string(hash)]; ok && !hasTag(torrent, tag) {
+				change.Added = append(change.Added, hash)
+			}
+		}
+		if t.cfg.RemoveStale {
+			for hash, torrent := range data.Torrents {
+				if _, ok := desired[tag][InfoHash(hash)]; !ok && hasTag(torrent, tag) {
+					change.Removed = append(change.Removed, InfoHash(hash))
+				}
+			}
+		}
+		if len(change.Added) == 0 && len(change.Removed) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return changes, err
+		}
+
+		sortHashes(change.Added)
+		sortHashes(change.Removed)
+		if len(change.Added) > 0 {
+			if err := t.client.TorrentsAddTags(joinHashes(change.Added), []string{tag}); err != nil {
+				return changes, fmt.Errorf("TrackerTagger error: %w", err)
+			}
+		}
+		if len(change.Removed) > 0 {
+			if err := t.client.TorrentsRemoveTags(joinHashes(change.Removed), []string{tag}); err != nil {
+				return changes, fmt.Errorf("TrackerTagger error: %w", err)
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// trackerHost returns the lower-cased hostname of a tracker URL, or "" if it has none
+func trackerHost(trackerURL string) string {
+	u, err := url.Parse(trackerURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// hostMatches reports whether host equals domain or is a subdomain of it
+func hostMatches(host, domain string) bool {
+	domain = strings.ToLower(domain)
+	return host != "" && (host == domain || strings.HasSuffix(host, "."+domain))
+}
+
+// hasTag reports whether the torrent carries tag
+func hasTag(torrent TorrentInfo, tag string) bool {
+	for _, t := range torrent.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// joinHashes joins hashes into the "|" separated form the API expects
+func joinHashes(hashes []InfoHash) string {
+	parts := make([]string, len(hashes))
+	for i, hash := range hashes {
+		parts[i] = string(hash)
+	}
+	return strings.Join(parts, "|")
+}
+
+func sortHashes(hashes []InfoHash) {
+	sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+}
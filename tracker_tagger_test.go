@@ -0,0 +1,91 @@
+package qbittorrent
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestTrackerTagger_RunOnce(t *testing.T) {
+	var calls []url.Values
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/sync/maindata":
+			w.Write([]byte(`{
+				"torrents": {
+					"hash1": {"tags": ""},
+					"hash2": {"tags": "ptp"},
+					"hash3": {"tags": "other, ptp"},
+					"hash4": {"tags": ""}
+				},
+				"trackers": {
+					"https://please.passthepopcorn.me/abc/announce": ["hash1", "hash2"],
+					"udp://tracker.opentrackr.org:1337/announce": ["hash3", "hash4"]
+				}
+			}`))
+		case "/api/v2/torrents/addTags", "/api/v2/torrents/removeTags":
+			r.ParseForm()
+			r.PostForm.Set("path", r.URL.Path)
+			calls = append(calls, r.PostForm)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	tagger := NewTrackerTagger(client, TrackerTaggerConfig{
+		Rules: []TrackerTagRule{
+			{Host: "passthepopcorn.me", Tag: "ptp"},
+			{Host: "opentrackr.org", Tag: "public"},
+		},
+		RemoveStale: true,
+	})
+
+	changes, err := tagger.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	wantChanges := []TrackerTagChange{
+		{Tag: "ptp", Added: []InfoHash{"hash1"}, Removed: []InfoHash{"hash3"}},
+		{Tag: "public", Added: []InfoHash{"hash3", "hash4"}},
+	}
+	if !reflect.DeepEqual(changes, wantChanges) {
+		t.Errorf("expected changes %+v, got %+v", wantChanges, changes)
+	}
+
+	wantCalls := []url.Values{
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"hash1"}, "tags": {"ptp"}},
+		{"path": {"/api/v2/torrents/removeTags"}, "hashes": {"hash3"}, "tags": {"ptp"}},
+		{"path": {"/api/v2/torrents/addTags"}, "hashes": {"hash3|hash4"}, "tags": {"public"}},
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("expected calls %v, got %v", wantCalls, calls)
+	}
+}
+
+func TestHostMatches(t *testing.T) {
+	tests := []struct {
+		host, domain string
+		want         bool
+	}{
+		{"tracker.example.org", "example.org", true},
+		{"example.org", "Example.org", true},
+		{"badexample.org", "example.org", false},
+		{"", "example.org", false},
+	}
+	for _, tt := range tests {
+		if got := hostMatches(tt.host, tt.domain); got != tt.want {
+			t.Errorf("hostMatches(%q, %q) = %v, want %v", tt.host, tt.domain, got, tt.want)
+		}
+	}
+}
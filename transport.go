@@ -0,0 +1,62 @@
+package qbittorrent
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportSettings tune the connection handling of the transport built by NewTransport. Zero values select
+// the defaults, which keep enough idle connections to a single server for frequent sync polling.
+type TransportSettings struct {
+	MaxIdleConnsPerHost   int           // idle connections kept per host, default 8
+	MaxConnsPerHost       int           // bound on connections per host, default none
+	IdleConnTimeout       time.Duration // how long idle connections are kept, default 90s
+	DialTimeout           time.Duration // bound on establishing a connection, default 10s
+	KeepAlive             time.Duration // TCP keep-alive period, default 30s
+	TLSHandshakeTimeout   time.Duration // default 10s
+	ResponseHeaderTimeout time.Duration // bound on waiting for response headers, default none
+}
+
+// NewTransport returns a transport for one qBittorrent server tuned by s. Unlike http.DefaultTransport,
+// which keeps only 2 idle connections per host, it reuses connections across concurrent polling requests.
+// Proxy settings are taken from the environment.
+func NewTransport(s TransportSettings) *http.Transport {
+	if s.MaxIdleConnsPerHost <= 0 {
+		s.MaxIdleConnsPerHost = 8
+	}
+	if s.IdleConnTimeout <= 0 {
+		s.IdleConnTimeout = 90 * time.Second
+	}
+	if s.DialTimeout <= 0 {
+		s.DialTimeout = 10 * time.Second
+	}
+	if s.KeepAlive <= 0 {
+		s.KeepAlive = 30 * time.Second
+	}
+	if s.TLSHandshakeTimeout <= 0 {
+		s.TLSHandshakeTimeout = 10 * time.Second
+	}
+
+	dialer := &net.Dialer{Timeout: s.DialTimeout, KeepAlive: s.KeepAlive}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   s.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       s.MaxConnsPerHost,
+		IdleConnTimeout:       s.IdleConnTimeout,
+		TLSHandshakeTimeout:   s.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: s.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// WithTransportSettings replaces the http.Client used for requests with one using NewTransport(s). It
+// overrides an earlier WithHTTPClient and is overridden by a later one.
+func WithTransportSettings(s TransportSettings) ClientOption {
+	return func(c *Client) {
+		c.client = &http.Client{Transport: NewTransport(s)}
+	}
+}
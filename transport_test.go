@@ -0,0 +1,36 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewTransport(t *testing.T) {
+	tr := NewTransport(TransportSettings{})
+	if tr.MaxIdleConnsPerHost != 8 || tr.IdleConnTimeout != 90*time.Second || tr.TLSHandshakeTimeout != 10*time.Second {
+		t.Errorf("unexpected defaults %+v", tr)
+	}
+	tr = NewTransport(TransportSettings{MaxIdleConnsPerHost: 32, MaxConnsPerHost: 64, ResponseHeaderTimeout: time.Minute})
+	if tr.MaxIdleConnsPerHost != 32 || tr.MaxConnsPerHost != 64 || tr.ResponseHeaderTimeout != time.Minute {
+		t.Errorf("settings not applied %+v", tr)
+	}
+}
+
+func TestDefaultTransport(t *testing.T) {
+	client, err := NewClientWithOptions("", "", "localhost", "8080")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if client.client == http.DefaultClient || client.client.Transport == http.DefaultTransport {
+		t.Error("expected a client owning its transport")
+	}
+
+	client, err = NewClientWithOptions("", "", "localhost", "8080", WithTransportSettings(TransportSettings{MaxIdleConnsPerHost: 4}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if tr, ok := client.client.Transport.(*http.Transport); !ok || tr.MaxIdleConnsPerHost != 4 {
+		t.Errorf("expected the transport settings applied, got %#v", client.client.Transport)
+	}
+}
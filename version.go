@@ -0,0 +1,82 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// APIVersion is a Web API version as reported by AppWebAPIVersion
+type APIVersion struct {
+	Major, Minor, Patch int
+}
+
+// APIVersionStartStop is the first Web API version, shipped with qBittorrent 5.0, that replaced the
+// pause/resume endpoints and the paused states with stop/start and stopped
+var APIVersionStartStop = APIVersion{Major: 2, Minor: 11, Patch: 0}
+
+// ParseAPIVersion parses a version such as "2.11.2"; missing components are zero
+func ParseAPIVersion(s string) (APIVersion, error) {
+	var v APIVersion
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("invalid API version %q", s)
+	}
+	fields := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return APIVersion{}, fmt.Errorf("invalid API version %q", s)
+		}
+		*fields[i] = n
+	}
+	return v, nil
+}
+
+func (v APIVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// AtLeast reports whether v is o or newer
+func (v APIVersion) AtLeast(o APIVersion) bool {
+	if v.Major != o.Major {
+		return v.Major > o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor > o.Minor
+	}
+	return v.Patch >= o.Patch
+}
+
+// APIVersion returns the Web API version of the server. It is fetched on first use and cached.
+func (c *Client) APIVersion() (APIVersion, error) {
+	c.mu.RLock()
+	cached := c.apiVersion
+	c.mu.RUnlock()
+	if cached != nil {
+		return *cached, nil
+	}
+
+	raw, err := c.AppWebAPIVersion()
+	if err != nil {
+		return APIVersion{}, err
+	}
+	v, err := ParseAPIVersion(raw)
+	if err != nil {
+		return APIVersion{}, fmt.Errorf("APIVersion error: %w", err)
+	}
+
+	c.mu.Lock()
+	c.apiVersion = &v
+	c.mu.Unlock()
+	return v, nil
+}
+
+// usesStartStop reports whether the server has the qBittorrent 5.x stop/start endpoints
+func (c *Client) usesStartStop() (bool, error) {
+	v, err := c.APIVersion()
+	if err != nil {
+		return false, err
+	}
+	return v.AtLeast(APIVersionStartStop), nil
+}
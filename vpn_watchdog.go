@@ -0,0 +1,160 @@
+package qbittorrent
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// VPNWatchdogConfig configures a VPNWatchdog. Zero values select the defaults.
+type VPNWatchdogConfig struct {
+	// Interface is the network interface to watch, by default the one qBittorrent is bound to
+	// (the current_network_interface preference)
+	Interface string
+	// Address, if set, must be assigned to Interface for the link to be up. By default it is the address
+	// qBittorrent is bound to (the current_interface_address preference), if any.
+	Address  string
+	Interval time.Duration // how often Run checks the link, default 30s
+
+	// OnChange, if set, is called when the link goes down or comes back, after torrents were paused or resumed
+	OnChange func(up bool)
+	// OnError, if set, is called with errors encountered by Run
+	OnError func(err error)
+}
+
+// VPNWatchdog pauses the active torrents when the network interface qBittorrent is bound to, typically a
+// VPN tunnel, goes away or loses its address, and resumes them when it returns. Torrents that were already
+// paused are left paused. The link is checked through the Web API, so it is seen as qBittorrent's host sees it.
+type VPNWatchdog struct {
+	client *Client
+	cfg    VPNWatchdogConfig
+
+	down   bool
+	paused []InfoHash // torrents paused by the watchdog, resumed when the link returns
+}
+
+// NewVPNWatchdog creates a VPNWatchdog for the given client
+func NewVPNWatchdog(client *Client, cfg VPNWatchdogConfig) *VPNWatchdog {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 30 * time.Second
+	}
+	return &VPNWatchdog{client: client, cfg: cfg}
+}
+
+// Run checks the link every Interval until ctx is cancelled
+func (w *VPNWatchdog) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil && w.cfg.OnError != nil {
+			w.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce checks the link and pauses or resumes torrents accordingly, reporting whether the link is up.
+// While the link is down, torrents started in the meantime are paused as well. Nothing is changed if the
+// link state cannot be determined.
+func (w *VPNWatchdog) RunOnce(ctx context.Context) (bool, error) {
+	up, err := w.LinkUp()
+	if err != nil {
+		return false, err
+	}
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	if up {
+		if !w.down {
+			return true, nil
+		}
+		if len(w.paused) > 0 {
+			if err := w.client.TorrentsResume(joinHashes(w.paused)); err != nil {
+				return true, fmt.Errorf("VPNWatchdog error: %w", err)
+			}
+		}
+		w.down, w.paused = false, nil
+		if w.cfg.OnChange != nil {
+			w.cfg.OnChange(true)
+		}
+		return true, nil
+	}
+
+	torrents, err := w.client.TorrentsInfo()
+	if err != nil {
+		return false, fmt.Errorf("VPNWatchdog error: %w", err)
+	}
+	var active []InfoHash
+	for _, torrent := range torrents {
+		if !IsPausedState(torrent.State) {
+			active = append(active, torrent.Hash)
+		}
+	}
+	if len(active) > 0 {
+		sortHashes(active)
+		if err := w.client.TorrentsPause(joinHashes(active)); err != nil {
+			return false, fmt.Errorf("VPNWatchdog error: %w", err)
+		}
+		w.paused = append(w.paused, active...)
+	}
+	if !w.down {
+		w.down = true
+		if w.cfg.OnChange != nil {
+			w.cfg.OnChange(false)
+		}
+	}
+	return false, nil
+}
+
+// LinkUp reports whether the watched interface exists and has the expected address. It returns
+// ErrNoInterfaceBound if no interface is configured and qBittorrent is bound to all of them.
+func (w *VPNWatchdog) LinkUp() (bool, error) {
+	iface, address := w.cfg.Interface, w.cfg.Address
+	if iface == "" || address == "" {
+		prefs, err := w.client.AppPreferences()
+		if err != nil {
+			return false, fmt.Errorf("VPNWatchdog error: %w", err)
+		}
+		if iface == "" {
+			iface, _ = prefs["current_network_interface"].(string)
+		}
+		if address == "" {
+			address, _ = prefs["current_interface_address"].(string)
+		}
+	}
+	if iface == "" {
+		return false, fmt.Errorf("VPNWatchdog error: %w", ErrNoInterfaceBound)
+	}
+
+	interfaces, err := w.client.AppNetworkInterfaces()
+	if err != nil {
+		return false, fmt.Errorf("VPNWatchdog error: %w", err)
+	}
+	found := false
+	for _, i := range interfaces {
+		found = found || i.Value == iface
+	}
+	if !found {
+		return false, nil
+	}
+
+	addresses, err := w.client.AppNetworkInterfaceAddresses(iface)
+	if err != nil {
+		return false, fmt.Errorf("VPNWatchdog error: %w", err)
+	}
+	if address == "" {
+		return len(addresses) > 0, nil
+	}
+	for _, a := range addresses {
+		if a == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,100 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestVPNWatchdog(t *testing.T) {
+	var (
+		bound     = "wg0"
+		linkUp    = true
+		torrents  = `[{"hash": "b", "state": "uploading"}, {"hash": "a", "state": "downloading"}, {"hash": "c", "state": "pausedUP"}]`
+		calls     []string
+		addresses = `["10.2.0.2"]`
+	)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"current_network_interface": "` + bound + `", "current_interface_address": ""}`))
+		case "/api/v2/app/networkInterfaceList":
+			if linkUp {
+				w.Write([]byte(`[{"name": "eth0", "value": "eth0"}, {"name": "wg0", "value": "wg0"}]`))
+			} else {
+				w.Write([]byte(`[{"name": "eth0", "value": "eth0"}]`))
+			}
+		case "/api/v2/app/networkInterfaceAddress":
+			if r.URL.Query().Get("iface") != "wg0" {
+				t.Errorf("unexpected iface %q", r.URL.Query().Get("iface"))
+			}
+			w.Write([]byte(addresses))
+		case "/api/v2/app/webapiVersion":
+			w.Write([]byte("2.9.3"))
+		case "/api/v2/torrents/info":
+			w.Write([]byte(torrents))
+		case "/api/v2/torrents/pause", "/api/v2/torrents/resume":
+			r.ParseForm()
+			calls = append(calls, r.URL.Path+" "+r.PostForm.Get("hashes"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	var changes []bool
+	watchdog := NewVPNWatchdog(&Client{baseURL: mockServer.URL, client: mockServer.Client()}, VPNWatchdogConfig{
+		OnChange: func(up bool) { changes = append(changes, up) },
+	})
+	ctx := context.Background()
+
+	check := func(wantUp bool, wantCalls ...string) {
+		t.Helper()
+		calls = nil
+		up, err := watchdog.RunOnce(ctx)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if up != wantUp {
+			t.Errorf("expected up=%v, got %v", wantUp, up)
+		}
+		if !reflect.DeepEqual(calls, wantCalls) {
+			t.Errorf("expected calls %v, got %v", wantCalls, calls)
+		}
+	}
+
+	check(true)
+	linkUp = false
+	check(false, "/api/v2/torrents/pause a|b")
+
+	// a torrent started while the link is down gets paused too
+	torrents = `[{"hash": "b", "state": "pausedUP"}, {"hash": "a", "state": "pausedDL"}, {"hash": "c", "state": "pausedUP"}, {"hash": "d", "state": "downloading"}]`
+	check(false, "/api/v2/torrents/pause d")
+	torrents = `[{"hash": "b", "state": "pausedUP"}, {"hash": "a", "state": "pausedDL"}, {"hash": "c", "state": "pausedUP"}, {"hash": "d", "state": "pausedDL"}]`
+
+	// the interface is back but has no address yet
+	linkUp, addresses = true, `[]`
+	check(false)
+
+	addresses = `["10.2.0.2"]`
+	check(true, "/api/v2/torrents/resume a|b|d")
+	check(true)
+
+	if want := []bool{false, true}; !reflect.DeepEqual(changes, want) {
+		t.Errorf("expected changes %v, got %v", want, changes)
+	}
+
+	watchdog.cfg.Address = "10.2.0.3"
+	if up, err := watchdog.LinkUp(); err != nil || up {
+		t.Errorf("expected link down for a missing address, got %v, %v", up, err)
+	}
+
+	bound = ""
+	watchdog.cfg.Address = ""
+	if _, err := watchdog.RunOnce(ctx); !errors.Is(err, ErrNoInterfaceBound) {
+		t.Errorf("expected ErrNoInterfaceBound, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package qbittorrent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WatchFolder is a local directory whose .torrent and .magnet files are added with Options
+type WatchFolder struct {
+	Path    string
+	Options []TorrentAddOption
+}
+
+// WatchDirConfig configures a WatchDir. Zero values select the defaults.
+type WatchDirConfig struct {
+	Folders   []WatchFolder
+	DoneDir   string        // subfolder processed files are moved to, default "done"
+	FailedDir string        // subfolder invalid or rejected files are moved to, default "failed"
+	Interval  time.Duration // how often the folders are scanned, default 5s
+	MinAge    time.Duration // files modified more recently are left alone as they may still be written, default 1s
+
+	// OnAdded, if set, is called for every file that was added
+	OnAdded func(path string)
+	// OnError, if set, is called with errors encountered by Run, including files that failed to add
+	OnError func(err error)
+}
+
+// WatchDir polls local folders for .torrent and .magnet files, adds them and moves them out of the way
+type WatchDir struct {
+	client TorrentsAPI
+	cfg    WatchDirConfig
+	now    func() time.Time
+}
+
+// NewWatchDir creates a WatchDir for the given client
+func NewWatchDir(client TorrentsAPI, cfg WatchDirConfig) *WatchDir {
+	if cfg.DoneDir == "" {
+		cfg.DoneDir = "done"
+	}
+	if cfg.FailedDir == "" {
+		cfg.FailedDir = "failed"
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.MinAge <= 0 {
+		cfg.MinAge = time.Second
+	}
+	return &WatchDir{client: client, cfg: cfg, now: time.Now}
+}
+
+// Run scans the folders every Interval until ctx is cancelled
+func (w *WatchDir) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Scan(ctx); err != nil && ctx.Err() == nil && w.cfg.OnError != nil {
+			w.cfg.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan processes the files currently in the folders. Files that fail to add are reported through OnError.
+// Those that are unreadable or invalid, or that the server or an add check such as WithDuplicateCheck
+// rejected, are moved to FailedDir; the others, e.g. after connection errors while qBittorrent restarts, are
+// left in place for the next scan. The returned error is only set for problems with the folders themselves.
+func (w *WatchDir) Scan(ctx context.Context) error {
+	for _, folder := range w.cfg.Folders {
+		entries, err := os.ReadDir(folder.Path)
+		if err != nil {
+			return fmt.Errorf("WatchDir error: %w", err)
+		}
+
+		var names []string
+		for _, entry := range entries {
+			ext := strings.ToLower(filepath.Ext(entry.Name()))
+			if entry.Type().IsRegular() && (ext == ".torrent" || ext == ".magnet") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			path := filepath.Join(folder.Path, name)
+			info, err := os.Stat(path)
+			if err != nil || w.now().Sub(info.ModTime()) < w.cfg.MinAge {
+				continue
+			}
+
+			target := w.cfg.DoneDir
+			addErr := w.add(path, folder.Options)
+			if addErr != nil {
+				if w.cfg.OnError != nil {
+					w.cfg.OnError(fmt.Errorf("WatchDir error: %s: %w", path, addErr))
+				}
+				if !terminalAddError(addErr) {
+					continue
+				}
+				target = w.cfg.FailedDir
+			} else if w.cfg.OnAdded != nil {
+				w.cfg.OnAdded(path)
+			}
+
+			if err := moveInto(path, filepath.Join(folder.Path, target)); err != nil {
+				return fmt.Errorf("WatchDir error: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// add adds a single .torrent or .magnet file
+func (w *WatchDir) add(path string, opts []TorrentAddOption) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errInvalidWatchFile, err)
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".torrent" {
+		err := w.client.TorrentsAddWithOptions(filepath.Base(path), data, opts...)
+		if err != nil {
+			if _, parseErr := ParseMetainfo(data); parseErr != nil {
+				return fmt.Errorf("%w: %v", errInvalidWatchFile, parseErr)
+			}
+		}
+		return err
+	}
+
+	// A .magnet file holds one magnet link per line
+	var links []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			links = append(links, line)
+		}
+	}
+	if len(links) == 0 {
+		return fmt.Errorf("%w: no magnet links in %s", errInvalidWatchFile, path)
+	}
+	for _, link := range links {
+		if !strings.HasPrefix(link, "magnet:") {
+			return fmt.Errorf("%w: not a magnet link: %q", errInvalidWatchFile, Redact(link))
+		}
+	}
+	return w.client.TorrentsAddURLs(links, opts...)
+}
+
+// errInvalidWatchFile marks add errors caused by the file itself, which retrying does not fix
+var errInvalidWatchFile = errors.New("invalid file")
+
+// terminalAddError reports whether adding a file failed for good: the file is unreadable or invalid, the
+// server rejected it, or one of the add checks of the folder's options refused it. Connection errors,
+// timeouts, an open circuit breaker and server errors are not terminal.
+func terminalAddError(err error) bool {
+	for _, target := range []error{
+		errInvalidWatchFile, ErrAddFailed, ErrInvalidTorrentFile,
+		ErrAlreadyExists, ErrInsufficientSpace, ErrContentPathCollision,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnsupportedMediaType
+}
+
+// moveInto moves the file at path into dir, creating dir if needed. A file of the same name already in dir
+// is kept, the moved file gets a numbered name such as "show (1).torrent" instead.
+func moveInto(path, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Base(path)
+	ext := filepath.Ext(name)
+	target := filepath.Join(dir, name)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(target); errors.Is(err, os.ErrNotExist) {
+			break
+		} else if err != nil {
+			return err
+		}
+		target = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(name, ext), i, ext))
+	}
+	return os.Rename(path, target)
+}
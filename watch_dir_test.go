@@ -0,0 +1,191 @@
+package qbittorrent
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchDir_Scan(t *testing.T) {
+	var added []string
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("failed to parse form: %v", err)
+		}
+		if r.FormValue("category") != "tv" {
+			t.Errorf("expected category tv, got %q", r.FormValue("category"))
+		}
+		if urls := r.FormValue("urls"); urls != "" {
+			added = append(added, urls)
+		}
+		for _, file := range r.MultipartForm.File["torrents"] {
+			added = append(added, file.Filename)
+		}
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"show.torrent":   "d4:infod6:lengthi1e4:name1:aee",
+		"link.magnet":    "magnet:?xt=urn:btih:abc\n\nmagnet:?xt=urn:btih:def\n",
+		"broken.magnet":  "http://not-a-magnet",
+		"notes.txt":      "ignored",
+		"writing.magnet": "magnet:?xt=urn:btih:partial",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-time.Minute)
+	for _, name := range []string{"show.torrent", "link.magnet", "broken.magnet", "notes.txt"} {
+		os.Chtimes(filepath.Join(dir, name), old, old)
+	}
+
+	var errs []error
+	watcher := NewWatchDir(client, WatchDirConfig{
+		Folders: []WatchFolder{{Path: dir, Options: []TorrentAddOption{WithCategory("tv")}}},
+		MinAge:  10 * time.Second,
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	if err := watcher.Scan(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	wantAdded := []string{"magnet:?xt=urn:btih:abc\nmagnet:?xt=urn:btih:def", "show.torrent"}
+	if !reflect.DeepEqual(added, wantAdded) {
+		t.Errorf("expected added %q, got %q", wantAdded, added)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected one error for broken.magnet, got %v", errs)
+	}
+
+	for path, want := range map[string]bool{
+		"done/show.torrent":    true,
+		"done/link.magnet":     true,
+		"failed/broken.magnet": true,
+		"notes.txt":            true,
+		"writing.magnet":       true,
+		"show.torrent":         false,
+	} {
+		_, err := os.Stat(filepath.Join(dir, path))
+		if exists := !errors.Is(err, os.ErrNotExist); exists != want {
+			t.Errorf("expected %s to exist: %v", path, want)
+		}
+	}
+}
+
+func TestWatchDir_ScanRetriesTransientErrors(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		baseURL: mockServer.URL,
+		client:  mockServer.Client(),
+	}
+
+	dir := t.TempDir()
+	for _, name := range []string{"show.torrent", "done/show.torrent"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte("d4:infod6:lengthi1e4:name1:aee"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		old := time.Now().Add(-time.Minute)
+		os.Chtimes(path, old, old)
+	}
+
+	var errs []error
+	watcher := NewWatchDir(client, WatchDirConfig{
+		Folders: []WatchFolder{{Path: dir}},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	if err := watcher.Scan(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("expected one error for the unavailable server, got %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "show.torrent")); err != nil {
+		t.Errorf("expected show.torrent to be left for the next scan, got %v", err)
+	}
+
+	status = http.StatusOK
+	if err := watcher.Scan(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for path, want := range map[string]bool{
+		"show.torrent":          false,
+		"done/show.torrent":     true,
+		"done/show (1).torrent": true,
+		"failed/show.torrent":   false,
+	} {
+		_, err := os.Stat(filepath.Join(dir, path))
+		if exists := !errors.Is(err, os.ErrNotExist); exists != want {
+			t.Errorf("expected %s to exist: %v", path, want)
+		}
+	}
+}
+
+func TestWatchDir_ScanAddCheckRejections(t *testing.T) {
+	data := []byte("d4:infod6:lengthi1e4:name1:aee")
+	meta, err := ParseMetainfo(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var adds int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/torrents/info":
+			w.Write([]byte(`[{"hash":"` + string(meta.InfoHash) + `","name":"a"}]`))
+		case "/api/v2/torrents/add":
+			adds++
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "show.torrent")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Minute)
+	os.Chtimes(path, old, old)
+
+	var errs []error
+	watcher := NewWatchDir(client, WatchDirConfig{
+		Folders: []WatchFolder{{Path: dir, Options: []TorrentAddOption{WithDuplicateCheck(true)}}},
+		OnError: func(err error) { errs = append(errs, err) },
+	})
+
+	// A torrent refused by an add check is not retried on the next scan
+	for i := 0; i < 2; i++ {
+		if err := watcher.Scan(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], ErrAlreadyExists) {
+		t.Errorf("expected one ErrAlreadyExists, got %v", errs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "failed", "show.torrent")); err != nil || adds != 0 {
+		t.Errorf("expected show.torrent to be moved to failed without adding, got %v and %d adds", err, adds)
+	}
+}
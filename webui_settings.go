@@ -0,0 +1,136 @@
+package qbittorrent
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// WebUISettings are the security related settings of qBittorrent's WebUI and Web API
+type WebUISettings struct {
+	AlternativeWebUIEnabled bool
+	AlternativeWebUIPath    string // directory, on the server, of the alternative WebUI files
+
+	UseHTTPS      bool
+	HTTPSCertPath string // path, on the server, of the certificate
+	HTTPSKeyPath  string // path, on the server, of the private key
+
+	CSRFProtection         bool
+	ClickjackingProtection bool
+	SecureCookie           bool // only effective with HTTPS
+
+	HostHeaderValidation bool
+	Domains              []string // accepted Host header values, "*" matches any
+
+	BypassLocalAuth          bool           // no login required from localhost
+	BypassAuthSubnetsEnabled bool           // no login required from BypassAuthSubnets
+	BypassAuthSubnets        []netip.Prefix // single addresses are full-length prefixes
+}
+
+// WebUISettings retrieves the WebUI security settings
+func (c *Client) WebUISettings() (*WebUISettings, error) {
+	prefs, err := c.AppPreferences()
+	if err != nil {
+		return nil, fmt.Errorf("WebUISettings error: %w", err)
+	}
+
+	flag := func(key string) bool {
+		v, _ := prefs[key].(bool)
+		return v
+	}
+	text := func(key string) string {
+		v, _ := prefs[key].(string)
+		return v
+	}
+	subnets, err := ParseSubnets(text("bypass_auth_subnet_whitelist"))
+	if err != nil {
+		return nil, fmt.Errorf("WebUISettings error: %w", err)
+	}
+	var domains []string
+	for _, domain := range strings.Split(text("web_ui_domain_list"), ";") {
+		if domain = strings.TrimSpace(domain); domain != "" {
+			domains = append(domains, domain)
+		}
+	}
+	return &WebUISettings{
+		AlternativeWebUIEnabled:  flag("alternative_webui_enabled"),
+		AlternativeWebUIPath:     text("alternative_webui_path"),
+		UseHTTPS:                 flag("use_https"),
+		HTTPSCertPath:            text("web_ui_https_cert_path"),
+		HTTPSKeyPath:             text("web_ui_https_key_path"),
+		CSRFProtection:           flag("web_ui_csrf_protection_enabled"),
+		ClickjackingProtection:   flag("web_ui_clickjacking_protection_enabled"),
+		SecureCookie:             flag("web_ui_secure_cookie_enabled"),
+		HostHeaderValidation:     flag("web_ui_host_header_validation_enabled"),
+		Domains:                  domains,
+		BypassLocalAuth:          flag("bypass_local_auth"),
+		BypassAuthSubnetsEnabled: flag("bypass_auth_subnet_whitelist_enabled"),
+		BypassAuthSubnets:        subnets,
+	}, nil
+}
+
+// SetWebUISettings changes the WebUI security settings. Enabling HTTPS requires a certificate and key path and
+// enabling the alternative WebUI requires its path, as qBittorrent would otherwise lock the WebUI out.
+func (c *Client) SetWebUISettings(s *WebUISettings) error {
+	if s.UseHTTPS && (s.HTTPSCertPath == "" || s.HTTPSKeyPath == "") {
+		return fmt.Errorf("SetWebUISettings error: HTTPS requires a certificate and key path")
+	}
+	if s.AlternativeWebUIEnabled && s.AlternativeWebUIPath == "" {
+		return fmt.Errorf("SetWebUISettings error: the alternative WebUI requires a path")
+	}
+
+	err := c.AppSetPreferences(Preferences{
+		"alternative_webui_enabled":              s.AlternativeWebUIEnabled,
+		"alternative_webui_path":                 s.AlternativeWebUIPath,
+		"use_https":                              s.UseHTTPS,
+		"web_ui_https_cert_path":                 s.HTTPSCertPath,
+		"web_ui_https_key_path":                  s.HTTPSKeyPath,
+		"web_ui_csrf_protection_enabled":         s.CSRFProtection,
+		"web_ui_clickjacking_protection_enabled": s.ClickjackingProtection,
+		"web_ui_secure_cookie_enabled":           s.SecureCookie,
+		"web_ui_host_header_validation_enabled":  s.HostHeaderValidation,
+		"web_ui_domain_list":                     strings.Join(s.Domains, ";"),
+		"bypass_local_auth":                      s.BypassLocalAuth,
+		"bypass_auth_subnet_whitelist_enabled":   s.BypassAuthSubnetsEnabled,
+		"bypass_auth_subnet_whitelist":           FormatSubnets(s.BypassAuthSubnets),
+	})
+	if err != nil {
+		return fmt.Errorf("SetWebUISettings error: %w", err)
+	}
+	return nil
+}
+
+// ParseSubnets parses the bypass_auth_subnet_whitelist preference, subnets separated by newlines or commas.
+// Single addresses are returned as full-length prefixes.
+func ParseSubnets(list string) ([]netip.Prefix, error) {
+	var subnets []netip.Prefix
+	for _, field := range strings.FieldsFunc(list, func(r rune) bool { return r == '\n' || r == ',' }) {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			addr, err := netip.ParseAddr(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid subnet %q: %v", field, err)
+			}
+			subnets = append(subnets, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q: %v", field, err)
+		}
+		subnets = append(subnets, prefix)
+	}
+	return subnets, nil
+}
+
+// FormatSubnets formats subnets as the newline separated bypass_auth_subnet_whitelist preference
+func FormatSubnets(subnets []netip.Prefix) string {
+	lines := make([]string, len(subnets))
+	for i, subnet := range subnets {
+		lines[i] = subnet.String()
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,84 @@
+package qbittorrent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseSubnets(t *testing.T) {
+	subnets, err := ParseSubnets("192.168.1.0/24, 10.0.0.5\n\nfd00::/8")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []netip.Prefix{
+		netip.MustParsePrefix("192.168.1.0/24"),
+		netip.MustParsePrefix("10.0.0.5/32"),
+		netip.MustParsePrefix("fd00::/8"),
+	}
+	if !reflect.DeepEqual(subnets, want) {
+		t.Errorf("expected %v, got %v", want, subnets)
+	}
+	if got := FormatSubnets(want); got != "192.168.1.0/24\n10.0.0.5/32\nfd00::/8" {
+		t.Errorf("unexpected format %q", got)
+	}
+	if _, err := ParseSubnets("10.0.0.0/33"); err == nil {
+		t.Error("expected an error for an invalid subnet")
+	}
+}
+
+func TestClient_WebUISettings(t *testing.T) {
+	var posted string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/app/preferences":
+			w.Write([]byte(`{"use_https":true,"web_ui_https_cert_path":"/c.pem","web_ui_https_key_path":"/k.pem",
+				"web_ui_csrf_protection_enabled":true,"web_ui_domain_list":"a.example; b.example",
+				"bypass_auth_subnet_whitelist_enabled":true,"bypass_auth_subnet_whitelist":"10.0.0.0/8"}`))
+		case "/api/v2/app/setPreferences":
+			r.ParseForm()
+			posted = r.PostForm.Get("json")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+	client := &Client{baseURL: mockServer.URL, client: mockServer.Client()}
+
+	s, err := client.WebUISettings()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := &WebUISettings{
+		UseHTTPS:                 true,
+		HTTPSCertPath:            "/c.pem",
+		HTTPSKeyPath:             "/k.pem",
+		CSRFProtection:           true,
+		Domains:                  []string{"a.example", "b.example"},
+		BypassAuthSubnetsEnabled: true,
+		BypassAuthSubnets:        []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("expected %+v, got %+v", want, s)
+	}
+
+	s.ClickjackingProtection = true
+	s.BypassAuthSubnetsEnabled = false
+	if err := client.SetWebUISettings(s); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantPosted := `{"alternative_webui_enabled":false,"alternative_webui_path":"","bypass_auth_subnet_whitelist":"10.0.0.0/8",` +
+		`"bypass_auth_subnet_whitelist_enabled":false,"bypass_local_auth":false,"use_https":true,` +
+		`"web_ui_clickjacking_protection_enabled":true,"web_ui_csrf_protection_enabled":true,` +
+		`"web_ui_domain_list":"a.example;b.example","web_ui_host_header_validation_enabled":false,` +
+		`"web_ui_https_cert_path":"/c.pem","web_ui_https_key_path":"/k.pem","web_ui_secure_cookie_enabled":false}`
+	if posted != wantPosted {
+		t.Errorf("expected %s, got %s", wantPosted, posted)
+	}
+
+	if err := client.SetWebUISettings(&WebUISettings{UseHTTPS: true}); err == nil {
+		t.Error("expected an error for HTTPS without a certificate")
+	}
+}